@@ -1,18 +1,32 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
 	"net/http"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sirupsen/logrus"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"mobgran-importer-go/internal/auth"
 	"mobgran-importer-go/internal/config"
 	"mobgran-importer-go/internal/handlers"
+	"mobgran-importer-go/internal/metricas"
 	"mobgran-importer-go/internal/middleware"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/rastreamento"
 	"mobgran-importer-go/internal/services"
+	"mobgran-importer-go/internal/versao"
 	"mobgran-importer-go/pkg/database"
+	"mobgran-importer-go/pkg/notificacao"
+	"mobgran-importer-go/pkg/storage"
 	_ "mobgran-importer-go/docs"
 )
 
@@ -36,36 +50,128 @@ func main() {
 	// Carregar configuração
 	cfg, err := config.LoadConfig()
 	if err != nil {
-		log.Fatalf("Erro ao carregar configuração: %v", err)
+		logrus.WithError(err).Fatal("Erro ao carregar configuração")
 	}
 
 	// Configurar logger
 	logger := config.SetupLogger(cfg.LogLevel)
 
+	// Configurar tracing antes de abrir o banco, para os comandos SQL gerarem spans
+	encerrarTraces, err := rastreamento.Configurar(context.Background(), cfg.TracesExportador)
+	if err != nil {
+		logrus.WithError(err).Fatal("Erro ao configurar tracing")
+	}
+
 	// Inicializar cliente PostgreSQL com migrations automáticas
 	connString := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s",
 		cfg.DBHost, cfg.DBPort, cfg.DBName, cfg.DBUser, cfg.DBPassword, cfg.DBSSLMode)
 	
 	dbClient, err := database.NewPostgresClient(connString)
 	if err != nil {
-		log.Fatalf("Erro ao inicializar cliente PostgreSQL: %v", err)
+		logrus.WithError(err).Fatal("Erro ao inicializar cliente PostgreSQL")
 	}
-	defer dbClient.Close()
 
 	// Executar migrations automáticas
-	log.Println("🔄 Chamando RunMigrations()...")
 	if err := dbClient.RunMigrations(); err != nil {
-		log.Fatalf("Erro ao executar migrations: %v", err)
+		logrus.WithError(err).Fatal("Erro ao executar migrations")
+	}
+
+	// Armazenamento das imagens espelhadas (local por padrão, S3 opcional)
+	blobStore, err := storage.Novo(storage.Config{
+		Driver:            cfg.StorageDriver,
+		DiretorioLocal:    cfg.StorageLocalDir,
+		URLPublica:        cfg.StoragePublicURL,
+		S3Endpoint:        cfg.S3Endpoint,
+		S3Regiao:          cfg.S3Region,
+		S3Bucket:          cfg.S3Bucket,
+		S3AccessKeyID:     cfg.S3AccessKeyID,
+		S3SecretAccessKey: cfg.S3SecretAccessKey,
+		S3PathStyle:       cfg.S3PathStyle,
+	})
+	if err != nil {
+		logrus.WithError(err).Fatal("Erro ao inicializar armazenamento")
 	}
-	log.Println("✅ RunMigrations() concluído com sucesso!")
 
 	// Inicializar serviços
-	produtosService := services.NewProdutosService(dbClient.DB)
+	auditoriaService := services.NewAuditoriaService(dbClient.DB)
+	smtpNotificacoes := &notificacao.SMTP{
+		Host:      cfg.SMTPHost,
+		Porta:     cfg.SMTPPort,
+		Usuario:   cfg.SMTPUsuario,
+		Senha:     cfg.SMTPSenha,
+		Remetente: cfg.SMTPRemetente,
+	}
+	webhookNotificacoes := notificacao.NovoWebhook(10*time.Second, cfg.WebhookPermitirRedeInterna)
+	notificacoesService := services.NewNotificacoesService(dbClient.DB, auditoriaService, webhookNotificacoes, smtpNotificacoes, cfg.NotificacaoAvisoReserva)
+	eventosService := services.NewEventosService(dbClient.DB, notificacoesService)
+	produtosService := services.NewProdutosService(dbClient.DB, auditoriaService, eventosService)
 	supabaseAuthService := services.NewSupabaseAuthService(cfg, logger)
+	apiKeyService := services.NewAPIKeyService(dbClient.DB, auditoriaService)
+	arquivoService := services.NewArquivoService(dbClient.DB, auditoriaService, eventosService)
+	buscaService := services.NewBuscaService(dbClient.DB)
+	imagemService := services.NewImagemService(dbClient.DB, blobStore, logger)
+	galeriaService := services.NewGaleriaService(dbClient.DB, blobStore, auditoriaService)
+	reservasService := services.NewReservasService(dbClient.DB, auditoriaService, eventosService, cfg.ReservaValidadePadrao)
+	orcamentosService := services.NewOrcamentosService(dbClient.DB, auditoriaService, reservasService)
+	clientesService := services.NewClientesService(dbClient.DB, auditoriaService, orcamentosService)
+	marcaService := services.NewMarcaService(dbClient.DB, blobStore, auditoriaService)
+	pdfService := services.NewPDFService(dbClient.DB, blobStore, marcaService, orcamentosService)
+	medidasService := services.NewMedidasService(dbClient.DB)
+	catalogoService := services.NewCatalogoService(dbClient.DB, auditoriaService)
+	fornecedoresService := services.NewFornecedoresService(dbClient.DB, auditoriaService)
+	historicoService := services.NewHistoricoService(dbClient.DB)
+	estatisticasService := services.NewEstatisticasService(dbClient.DB)
+	imagensImportacao := imagemService
+	if !cfg.EspelharImagens {
+		imagensImportacao = nil
+	}
+	importerService := services.NewMobgranImporter(database.NewClientFromDB(dbClient.DB, logger), auditoriaService, imagensImportacao, catalogoService, fornecedoresService, historicoService, eventosService, logger)
+	saudeService := services.NewSaudeService(dbClient, supabaseAuthService, importerService, notificacoesService)
 
 	// Inicializar handlers
 	produtosHandler := handlers.NewProdutosHandler(produtosService)
 	supabaseAuthHandler := handlers.NewSupabaseAuthHandler(supabaseAuthService, logger)
+	apiKeysHandler := handlers.NewAPIKeysHandler(apiKeyService)
+	importerHandler := handlers.NewImporterHandler(importerService, logger)
+	auditoriaHandler := handlers.NewAuditoriaHandler(auditoriaService)
+	arquivoHandler := handlers.NewArquivoHandler(arquivoService)
+	buscaHandler := handlers.NewBuscaHandler(buscaService)
+	imagensHandler := handlers.NewImagensHandler(imagemService)
+	galeriaHandler := handlers.NewGaleriaHandler(galeriaService)
+	reservasHandler := handlers.NewReservasHandler(reservasService)
+	orcamentosHandler := handlers.NewOrcamentosHandler(orcamentosService)
+	clientesHandler := handlers.NewClientesHandler(clientesService)
+	marcaHandler := handlers.NewMarcaHandler(marcaService)
+	pdfHandler := handlers.NewPDFHandler(pdfService)
+	medidasHandler := handlers.NewMedidasHandler(medidasService)
+	catalogoHandler := handlers.NewCatalogoHandler(catalogoService)
+	fornecedoresHandler := handlers.NewFornecedoresHandler(fornecedoresService)
+	historicoHandler := handlers.NewHistoricoHandler(historicoService)
+	estatisticasHandler := handlers.NewEstatisticasHandler(estatisticasService)
+	notificacoesHandler := handlers.NewNotificacoesHandler(notificacoesService)
+	eventosHandler := handlers.NewEventosHandler(eventosService)
+	saudeHandler := handlers.NewSaudeHandler(saudeService)
+
+	// Workers em segundo plano, interrompidos no encerramento
+	ctxWorkers, pararWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	iniciarWorker := func(executar func()) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			executar()
+		}()
+	}
+	iniciarWorker(func() { reservasService.ExecutarExpiracao(ctxWorkers, cfg.ReservaIntervaloExpiracao) })
+	iniciarWorker(func() { medidasService.ExecutarNormalizacao(ctxWorkers) })
+	iniciarWorker(func() { fornecedoresService.ExecutarVinculacao(ctxWorkers) })
+	iniciarWorker(func() { notificacoesService.ExecutarEntregas(ctxWorkers, cfg.NotificacaoIntervalo) })
+	iniciarWorker(func() { catalogoService.ExecutarReaplicacao(ctxWorkers) })
+	iniciarWorker(func() { eventosService.ExecutarEscuta(ctxWorkers, connString) })
+	iniciarWorker(func() { eventosService.ExecutarLimpeza(ctxWorkers, cfg.EventosRetencao) })
+
+	// Autenticação por token do Supabase ou chave de API (integrações)
+	apiKeyAuth := middleware.APIKeyOuSupabaseAuthMiddleware(apiKeyService)
 
 	// Configurar Gin
 	if cfg.LogLevel != "debug" {
@@ -75,35 +181,27 @@ func main() {
 	router := gin.New()
 
 	// Middlewares
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
-	router.Use(middleware.SecurityHeadersMiddleware()) // Adicionar headers de segurança
-	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.RequestIDMiddleware())
+	router.Use(middleware.RastreamentoMiddleware()...)
+	router.Use(middleware.MetricasMiddleware())
 	router.Use(middleware.LoggerMiddleware())
 	router.Use(middleware.RecoveryMiddleware())
+	router.Use(middleware.SecurityHeadersMiddleware()) // Adicionar headers de segurança
+	router.Use(middleware.CORSMiddleware())
 
-	// Rotas de saúde
-	router.GET("/health", func(c *gin.Context) {
-		// Verificar saúde do banco de dados
-		if err := dbClient.HealthCheck(); err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"status": "unhealthy",
-				"error":  "database connection failed",
-			})
-			return
-		}
+	// Rotas de saúde (liveness e readiness); /health é mantida como readiness
+	router.GET("/health/live", saudeHandler.Live)
+	router.GET("/health/ready", saudeHandler.Ready)
+	router.GET("/health", saudeHandler.Ready)
 
-		c.JSON(http.StatusOK, gin.H{
-			"status":   "healthy",
-			"database": "connected",
-			"version":  "1.0.0",
-		})
-	})
+	// Métricas Prometheus (HTTP, importações, API do Mobgran e pool do banco)
+	metricas.RegistrarBanco(dbClient.DB, cfg.DBName)
+	router.GET("/metrics", middleware.MetricasTokenMiddleware(cfg.MetricasToken), gin.WrapH(promhttp.Handler()))
 
 	router.GET("/", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "Mobgran Importer API - PostgreSQL 🔥 HOT RELOAD ATIVO!",
-			"version": "1.0.0",
+			"version": versao.Versao,
 			"status":  "running",
 		})
 	})
@@ -119,30 +217,280 @@ func main() {
 		supabaseAuth.POST("/logout", supabaseAuthHandler.Logout)
 	}
 
+	// Rotas de chaves de API (apenas sessões de usuário podem gerenciar chaves)
+	apiKeys := router.Group("/api-keys")
+	{
+		apiKeys.POST("", middleware.SupabaseAuthMiddleware(), apiKeysHandler.CriarAPIKey)
+		apiKeys.GET("", middleware.SupabaseAuthMiddleware(), apiKeysHandler.ListarAPIKeys)
+		apiKeys.DELETE("/:id", middleware.SupabaseAuthMiddleware(), apiKeysHandler.RevogarAPIKey)
+	}
+
+	// Rotas de importação
+	importacao := router.Group("/api")
+	{
+		importacao.POST("/importar", apiKeyAuth, middleware.RequireEscopo(models.EscopoImportacaoEscrita), importerHandler.ImportarOferta)
+		importacao.POST("/validar-url", importerHandler.ValidarURL)
+		importacao.POST("/extrair-uuid", importerHandler.ExtrairUUID)
+	}
+
 	// Rotas de produtos
 	produtos := router.Group("/produtos")
 	{
-		produtos.GET("/cavaletes", middleware.SupabaseAuthMiddleware(), produtosHandler.ListarCavaletesDisponiveis)
-		produtos.POST("/aprovar", middleware.SupabaseAuthMiddleware(), produtosHandler.AprovarProduto)
-		produtos.GET("/", middleware.SupabaseAuthMiddleware(), produtosHandler.ListarProdutosAprovados)
-		produtos.PUT("/:id", middleware.SupabaseAuthMiddleware(), produtosHandler.AtualizarProduto)
-		produtos.GET("/:id", middleware.SupabaseAuthMiddleware(), produtosHandler.BuscarProduto)
-		produtos.DELETE("/:id", middleware.SupabaseAuthMiddleware(), produtosHandler.RemoverProduto)
-		produtos.GET("/estatisticas", middleware.SupabaseAuthMiddleware(), produtosHandler.ObterEstatisticas)
-		produtos.DELETE("/limpar", middleware.SupabaseAuthMiddleware(), produtosHandler.LimparTodosRegistros)
+		produtos.GET("/cavaletes", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), produtosHandler.ListarCavaletesDisponiveis)
+		produtos.POST("/aprovar", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), produtosHandler.AprovarProduto)
+		produtos.GET("/", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), produtosHandler.ListarProdutosAprovados)
+		produtos.PUT("/:id", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), produtosHandler.AtualizarProduto)
+		produtos.GET("/:id", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), produtosHandler.BuscarProduto)
+		produtos.DELETE("/:id", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), produtosHandler.RemoverProduto)
+		produtos.GET("/estatisticas", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), produtosHandler.ObterEstatisticas)
+
+		// Galeria de imagens do produto
+		produtos.POST("/:id/imagens", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), galeriaHandler.AdicionarImagens)
+		produtos.GET("/:id/imagens", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), galeriaHandler.ListarImagens)
+		produtos.PUT("/:id/imagens/ordem", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), galeriaHandler.ReordenarImagens)
+		produtos.PUT("/:id/imagens/:imagemId/capa", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), galeriaHandler.DefinirCapa)
+		produtos.DELETE("/:id/imagens/:imagemId", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), galeriaHandler.RemoverImagem)
+
+		// Ciclo de venda: reserva, liberação e venda
+		produtos.POST("/:id/reservar", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), reservasHandler.Reservar)
+		produtos.POST("/:id/liberar", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), reservasHandler.Liberar)
+		produtos.POST("/:id/vender", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosEscrita), reservasHandler.MarcarVendido)
+
+		// Ficha técnica em PDF
+		produtos.GET("/:id/ficha", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), pdfHandler.FichaProduto)
+
+		// Linha do tempo de preço e estoque
+		produtos.GET("/:id/historico", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), historicoHandler.HistoricoProduto)
+	}
+
+	// Rotas do histórico de importações das ofertas
+	ofertas := router.Group("/ofertas", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura))
+	{
+		ofertas.GET("/:id/importacoes", historicoHandler.ListarImportacoes)
 	}
 
-	// Rotas públicas
-	router.GET("/vitrine/publica", produtosHandler.ListarVitrinePublica)
+	// Rotas de orçamentos
+	orcamentosLeitura := middleware.RequireEscopo(models.EscopoOrcamentosLeitura)
+	orcamentosEscrita := middleware.RequireEscopo(models.EscopoOrcamentosEscrita)
+	orcamentos := router.Group("/orcamentos", apiKeyAuth)
+	{
+		orcamentos.POST("", orcamentosEscrita, orcamentosHandler.Criar)
+		orcamentos.GET("", orcamentosLeitura, orcamentosHandler.Listar)
+		orcamentos.GET("/:id", orcamentosLeitura, orcamentosHandler.Buscar)
+		orcamentos.PUT("/:id", orcamentosEscrita, orcamentosHandler.Atualizar)
+		orcamentos.DELETE("/:id", orcamentosEscrita, orcamentosHandler.Remover)
+		orcamentos.POST("/:id/itens", orcamentosEscrita, orcamentosHandler.AdicionarItem)
+		orcamentos.PUT("/:id/itens/:itemId", orcamentosEscrita, orcamentosHandler.AtualizarItem)
+		orcamentos.DELETE("/:id/itens/:itemId", orcamentosEscrita, orcamentosHandler.RemoverItem)
+		orcamentos.POST("/:id/enviar", orcamentosEscrita, orcamentosHandler.Enviar)
+		orcamentos.POST("/:id/aceitar", orcamentosEscrita, orcamentosHandler.Aceitar)
+		orcamentos.POST("/:id/recusar", orcamentosEscrita, orcamentosHandler.Recusar)
+		orcamentos.POST("/:id/converter", orcamentosEscrita, orcamentosHandler.Converter)
+		orcamentos.GET("/:id/pdf", orcamentosLeitura, pdfHandler.Orcamento)
+	}
+
+	// Rotas do cadastro de clientes (compradores)
+	clientesLeitura := middleware.RequireEscopo(models.EscopoClientesLeitura)
+	clientesEscrita := middleware.RequireEscopo(models.EscopoClientesEscrita)
+	clientes := router.Group("/clientes", apiKeyAuth)
+	{
+		clientes.POST("", clientesEscrita, clientesHandler.Criar)
+		clientes.GET("", clientesLeitura, clientesHandler.Listar)
+		clientes.GET("/:id", clientesLeitura, clientesHandler.Buscar)
+		clientes.PUT("/:id", clientesEscrita, clientesHandler.Atualizar)
+		clientes.DELETE("/:id", clientesEscrita, clientesHandler.Remover)
+		clientes.GET("/:id/historico", clientesLeitura, clientesHandler.Historico)
+	}
+
+	// Rotas do catálogo de materiais e dos mapeamentos do trader
+	catalogo := router.Group("/catalogo", apiKeyAuth)
+	{
+		catalogo.GET("/materiais", middleware.RequireEscopo(models.EscopoProdutosLeitura), catalogoHandler.ListarMateriais)
+		catalogo.GET("/materiais/:id", middleware.RequireEscopo(models.EscopoProdutosLeitura), catalogoHandler.BuscarMaterial)
+		catalogo.GET("/espessuras", middleware.RequireEscopo(models.EscopoProdutosLeitura), catalogoHandler.ListarEspessuras)
+		catalogo.GET("/mapeamentos", middleware.RequireEscopo(models.EscopoProdutosLeitura), catalogoHandler.ListarMapeamentos)
+		catalogo.PUT("/mapeamentos", middleware.RequireEscopo(models.EscopoProdutosEscrita), catalogoHandler.DefinirMapeamento)
+		catalogo.DELETE("/mapeamentos", middleware.RequireEscopo(models.EscopoProdutosEscrita), catalogoHandler.RemoverMapeamento)
+	}
+
+	// Rotas do cadastro de fornecedores
+	fornecedores := router.Group("/fornecedores", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura))
+	{
+		fornecedores.GET("", fornecedoresHandler.Listar)
+		fornecedores.GET("/:id", fornecedoresHandler.Buscar)
+	}
+
+	// Painel de estatísticas do trader
+	router.GET("/estatisticas", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura), estatisticasHandler.Obter)
+
+	// Rotas dos dados de marca do trader (cabeçalho dos PDFs)
+	marca := router.Group("/marca", middleware.SupabaseAuthMiddleware())
+	{
+		marca.GET("", marcaHandler.Buscar)
+		marca.PUT("", marcaHandler.Atualizar)
+		marca.PUT("/logo", marcaHandler.AtualizarLogo)
+		marca.DELETE("/logo", marcaHandler.RemoverLogo)
+	}
+
+	// Rotas das notificações (webhooks e e-mail) do trader
+	notificacoes := router.Group("/notificacoes", middleware.SupabaseAuthMiddleware())
+	{
+		notificacoes.GET("", notificacoesHandler.ListarNotificacoes)
+		notificacoes.GET("/:id", notificacoesHandler.BuscarNotificacao)
+		notificacoes.POST("/:id/reenviar", notificacoesHandler.Reenviar)
+		notificacoes.GET("/assinaturas", notificacoesHandler.ListarAssinaturas)
+		notificacoes.POST("/assinaturas", notificacoesHandler.CriarAssinatura)
+		notificacoes.PUT("/assinaturas/:id", notificacoesHandler.AtualizarAssinatura)
+		notificacoes.DELETE("/assinaturas/:id", notificacoesHandler.RemoverAssinatura)
+		notificacoes.POST("/assinaturas/:id/testar", notificacoesHandler.TestarAssinatura)
+		notificacoes.POST("/assinaturas/:id/reenviar", notificacoesHandler.ReenviarAssinatura)
+	}
+
+	// Rotas dos eventos de alteração de dados (consulta e stream SSE)
+	eventos := router.Group("/eventos", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura))
+	{
+		eventos.GET("", eventosHandler.Listar)
+		eventos.GET("/stream", eventosHandler.Stream)
+	}
+
+	// Rotas de busca textual e por facetas
+	busca := router.Group("/busca", apiKeyAuth, middleware.RequireEscopo(models.EscopoProdutosLeitura))
+	{
+		busca.GET("/cavaletes", buscaHandler.BuscarCavaletes)
+		busca.GET("/produtos", buscaHandler.BuscarProdutos)
+	}
+
+	// Rotas de arquivamento (soft delete) e restauração
+	arquivo := router.Group("/arquivo", middleware.SupabaseAuthMiddleware())
+	{
+		arquivo.POST("/arquivar", arquivoHandler.Arquivar)
+		arquivo.POST("/restaurar", arquivoHandler.Restaurar)
+	}
+
+	// Rotas administrativas
+	admin := router.Group("/admin", middleware.SupabaseAuthMiddleware(), handlers.RequireRole(auth.RoleAdmin))
+	{
+		admin.GET("/auditoria", auditoriaHandler.ListarRegistros)
+		admin.POST("/purgar", arquivoHandler.Purgar)
+		admin.POST("/imagens/espelhar", imagensHandler.EspelharPendentes)
+		admin.POST("/reservas/expirar", reservasHandler.ExpirarReservas)
+		admin.POST("/medidas/normalizar", medidasHandler.NormalizarPendentes)
+		admin.POST("/catalogo/materiais", catalogoHandler.CriarMaterial)
+		admin.PUT("/catalogo/materiais/:id", catalogoHandler.AtualizarMaterial)
+		admin.DELETE("/catalogo/materiais/:id", catalogoHandler.RemoverMaterial)
+		admin.POST("/catalogo/materiais/:id/sinonimos", catalogoHandler.AdicionarSinonimo)
+		admin.DELETE("/catalogo/materiais/:id/sinonimos/:sinonimoId", catalogoHandler.RemoverSinonimo)
+		admin.POST("/catalogo/reaplicar", catalogoHandler.Reaplicar)
+		admin.GET("/fornecedores", fornecedoresHandler.ListarTodos)
+		admin.PUT("/fornecedores/:id", fornecedoresHandler.Atualizar)
+		admin.POST("/fornecedores/:id/mesclar", fornecedoresHandler.Mesclar)
+		admin.POST("/fornecedores/vincular", fornecedoresHandler.VincularPendentes)
+	}
+
+	// Arquivos do armazenamento local (imagens espelhadas e miniaturas)
+	if local, ok := blobStore.(*storage.Local); ok {
+		router.Static(storage.RotaLocal, local.Diretorio())
+	}
+
+	// Rotas da vitrine
+	vitrine := router.Group("/vitrine")
+	{
+		vitrine.GET("/publica", produtosHandler.ListarVitrinePublica)
+		vitrine.GET("/:slug", produtosHandler.ListarVitrineTrader)
+		vitrine.PUT("/slug", middleware.SupabaseAuthMiddleware(), produtosHandler.AtualizarSlugVitrine)
+	}
 
 	// Rota do Swagger
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Iniciar servidor
 	port := cfg.Port
-	logger.WithField("port", port).Info("Iniciando servidor PostgreSQL")
+	servidor := &http.Server{
+		Addr:    ":" + port,
+		Handler: router,
+	}
+	// Os streams SSE só terminam quando o cliente desconecta: são fechados ao iniciar o
+	// encerramento para não segurar o Shutdown até o prazo
+	servidor.RegisterOnShutdown(eventosService.Encerrar)
+
+	logger.WithFields(logrus.Fields{
+		"port":   port,
+		"versao": versao.Versao,
+		"commit": versao.Atual().Commit,
+	}).Info("Iniciando servidor PostgreSQL")
+
+	erroServidor := make(chan error, 1)
+	go func() {
+		if err := servidor.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			erroServidor <- err
+		}
+	}()
 
-	if err := router.Run(":" + port); err != nil {
+	// SIGTERM (docker stop, Kubernetes) ou SIGINT (Ctrl+C) iniciam o encerramento; um
+	// segundo sinal interrompe o processo imediatamente
+	ctxSinal, pararSinal := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	select {
+	case err := <-erroServidor:
 		logger.WithError(err).Fatal("Erro ao iniciar servidor")
+	case <-ctxSinal.Done():
+	}
+	pararSinal()
+
+	logger.WithFields(logrus.Fields{
+		"prazo":                    cfg.ShutdownTimeout.String(),
+		"importacoes_em_andamento": importerService.ImportacoesEmAndamento(),
+	}).Info("Encerrando servidor: aguardando requisições e importações em andamento")
+
+	// Para de aceitar conexões e espera as requisições em andamento, inclusive as
+	// importações, até o prazo; depois disso as conexões restantes são fechadas
+	ctxEncerramento, cancelarEncerramento := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancelarEncerramento()
+	if err := servidor.Shutdown(ctxEncerramento); err != nil {
+		logger.WithError(err).Warn("Prazo de encerramento esgotado; interrompendo as requisições restantes")
+		servidor.Close()
+	}
+
+	// Importações interrompidas desfazem a transação ao perder o contexto: espera o
+	// rollback antes de fechar o pool, para a oferta anterior continuar íntegra
+	ctxImportacoes, cancelarImportacoes := context.WithTimeout(context.Background(), prazoFinalEncerramento)
+	defer cancelarImportacoes()
+	if err := importerService.AguardarImportacoes(ctxImportacoes); err != nil {
+		logger.WithError(err).Error("Importações não terminaram no encerramento")
+	}
+
+	pararWorkers()
+	if !aguardar(&workers, prazoFinalEncerramento) {
+		logger.Warn("Workers em segundo plano não terminaram no prazo de encerramento")
+	}
+
+	ctxTraces, cancelarTraces := context.WithTimeout(context.Background(), prazoFinalEncerramento)
+	defer cancelarTraces()
+	if err := encerrarTraces(ctxTraces); err != nil {
+		logger.WithError(err).Warn("Erro ao enviar os traces pendentes")
+	}
+
+	if err := dbClient.Close(); err != nil {
+		logger.WithError(err).Warn("Erro ao fechar conexões com o banco")
+	}
+	logger.Info("Servidor encerrado")
+}
+
+// prazoFinalEncerramento limita cada etapa após o Shutdown (rollback das importações
+// interrompidas, workers e envio dos traces)
+const prazoFinalEncerramento = 5 * time.Second
+
+// aguardar espera o grupo terminar, até o prazo; retorna false se o prazo esgotar
+func aguardar(grupo *sync.WaitGroup, prazo time.Duration) bool {
+	concluido := make(chan struct{})
+	go func() {
+		grupo.Wait()
+		close(concluido)
+	}()
+
+	select {
+	case <-concluido:
+		return true
+	case <-time.After(prazo):
+		return false
 	}
-}
\ No newline at end of file
+}
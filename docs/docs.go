@@ -24,21 +24,91 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/admin/limpar-dados": {
-            "delete": {
+        "/admin/auditoria": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove todos os registros de produtos, cavaletes, ofertas e dados relacionados do banco de dados",
+                "description": "Lista operações que alteraram dados, com filtros e paginação (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "admin"
                 ],
-                "summary": "Limpar todos os registros do banco de dados",
+                "summary": "Consultar trilha de auditoria",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtrar por ator",
+                        "name": "ator_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por ação (ex.: produto.atualizado)",
+                        "name": "acao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por entidade (ex.: produto, oferta)",
+                        "name": "entidade",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por ID da entidade",
+                        "name": "entidade_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por ID da requisição",
+                        "name": "request_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data inicial (RFC3339)",
+                        "name": "desde",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final (RFC3339)",
+                        "name": "ate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -47,6 +117,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
@@ -54,6 +131,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -64,9 +148,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/extrair-uuid": {
+        "/admin/catalogo/materiais": {
             "post": {
-                "description": "Extrai o UUID de uma URL do Mobgran",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Inclui um material com sinônimos no catálogo curado e reaplica o catálogo aos cavaletes (apenas administradores)",
                 "consumes": [
                     "application/json"
                 ],
@@ -74,29 +163,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "utilidades"
+                    "admin"
                 ],
-                "summary": "Extrai UUID da URL",
+                "summary": "Incluir material no catálogo",
                 "parameters": [
                     {
-                        "description": "URL para extrair UUID",
-                        "name": "request",
+                        "description": "Material",
+                        "name": "material",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "$ref": "#/definitions/models.MaterialCatalogoRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.MaterialCatalogo"
                         }
                     },
                     "400": {
@@ -105,13 +190,46 @@ const docTemplate = `{
                             "type": "object",
                             "additionalProperties": true
                         }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
                     }
                 }
             }
         },
-        "/api/importar": {
-            "post": {
-                "description": "Importa dados de uma oferta do Mobgran para o Supabase",
+        "/admin/catalogo/materiais/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera o nome ou o tipo de um material do catálogo (apenas administradores)",
                 "consumes": [
                     "application/json"
                 ],
@@ -119,17 +237,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "importacao"
+                    "admin"
                 ],
-                "summary": "Importa uma oferta do Mobgran",
+                "summary": "Atualizar material do catálogo",
                 "parameters": [
                     {
-                        "description": "Dados da importação",
-                        "name": "request",
+                        "type": "string",
+                        "description": "ID do material",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a alterar",
+                        "name": "material",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ImportRequest"
+                            "$ref": "#/definitions/models.MaterialCatalogoAtualizarRequest"
                         }
                     }
                 ],
@@ -137,124 +262,82 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ImportResponse"
+                            "$ref": "#/definitions/models.MaterialCatalogo"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ImportResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ImportResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/api/validar-url": {
-            "post": {
-                "description": "Valida se uma URL é um link válido do Mobgran",
-                "consumes": [
-                    "application/json"
-                ],
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "validacao"
-                ],
-                "summary": "Valida URL do Mobgran",
-                "parameters": [
-                    {
-                        "description": "URL para validar",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                    },
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
-                    }
-                }
-            }
-        },
-        "/health": {
-            "get": {
-                "description": "Verifica se a aplicação está funcionando",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "health"
-                ],
-                "summary": "Health check",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
                     }
                 }
-            }
-        },
-        "/produtos/aprovados": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lista produtos aprovados pelo trader",
+                "description": "Exclui o material com os sinônimos, desfazendo as associações dos cavaletes e os mapeamentos dos traders (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Listar produtos aprovados",
+                "summary": "Remover material do catálogo",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limite de resultados",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset para paginação",
-                        "name": "offset",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID do material",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -267,6 +350,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -277,14 +374,14 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/aprovar": {
+        "/admin/catalogo/materiais/{id}/sinonimos": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Aprova um cavalete como produto para venda",
+                "description": "Inclui uma grafia alternativa do material, usada para associar os nomes importados (apenas administradores)",
                 "consumes": [
                     "application/json"
                 ],
@@ -292,25 +389,32 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Aprovar produto",
+                "summary": "Incluir sinônimo",
                 "parameters": [
                     {
-                        "description": "Dados do produto",
-                        "name": "produto",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.ProdutoAprovarRequest"
-                        }
+                        "type": "string",
+                        "description": "ID do material",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Sinônimo",
+                        "name": "sinonimo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SinonimoRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProdutoAprovado"
+                            "$ref": "#/definitions/models.MaterialCatalogo"
                         }
                     },
                     "400": {
@@ -327,6 +431,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "409": {
                         "description": "Conflict",
                         "schema": {
@@ -344,43 +462,42 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/cavaletes": {
-            "get": {
+        "/admin/catalogo/materiais/{id}/sinonimos/{sinonimoId}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Lista cavaletes disponíveis para aprovação pelo trader",
+                "description": "Exclui um sinônimo do material (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Listar cavaletes disponíveis",
+                "summary": "Remover sinônimo",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limite de resultados",
-                        "name": "limit",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID do material",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset para paginação",
-                        "name": "offset",
-                        "in": "query"
+                        "type": "string",
+                        "description": "ID do sinônimo",
+                        "name": "sinonimoId",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.MaterialCatalogo"
                         }
                     },
                     "400": {
@@ -397,6 +514,20 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -407,26 +538,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/estatisticas": {
-            "get": {
+        "/admin/catalogo/reaplicar": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtém estatísticas dos produtos do trader",
+                "description": "Recalcula a associação de todos os cavaletes ao catálogo e aos mapeamentos dos traders (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Estatísticas de produtos",
+                "summary": "Reaplicar catálogo",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.EstatisticasProdutos"
+                            "$ref": "#/definitions/models.AplicacaoCatalogoResultado"
                         }
                     },
                     "401": {
@@ -436,6 +567,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -446,43 +584,91 @@ const docTemplate = `{
                 }
             }
         },
-        "/produtos/{id}": {
+        "/admin/fornecedores": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Busca um produto específico do trader",
+                "description": "Lista todos os fornecedores cadastrados, com as estatísticas das ofertas de todos os traders (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Buscar produto",
+                "summary": "Listar todos os fornecedores",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do produto",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Trecho do nome do fornecedor",
+                        "name": "busca",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProdutoAprovado"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
                         }
+                    }
+                }
+            }
+        },
+        "/admin/fornecedores/vincular": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Vincula aos fornecedores as ofertas importadas antes do cadastro de fornecedores, sem aguardar o processamento em segundo plano (apenas administradores)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Vincular ofertas pendentes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 500,
+                        "description": "Quantidade máxima de ofertas processadas",
+                        "name": "limite",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VinculacaoFornecedoresResultado"
+                        }
                     },
                     "401": {
                         "description": "Unauthorized",
@@ -491,8 +677,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -506,14 +692,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/admin/fornecedores/{id}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Atualiza um produto aprovado",
+                "description": "Altera nome, logotipo e dados de contato do fornecedor. Campos ausentes são mantidos; campos vazios são apagados (apenas administradores).",
                 "consumes": [
                     "application/json"
                 ],
@@ -521,24 +709,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Atualizar produto",
+                "summary": "Atualizar fornecedor",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do produto",
+                        "description": "ID do fornecedor",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Dados para atualização",
-                        "name": "produto",
+                        "description": "Campos a alterar",
+                        "name": "fornecedor",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProdutoAtualizarRequest"
+                            "$ref": "#/definitions/models.FornecedorAtualizarRequest"
                         }
                     }
                 ],
@@ -546,7 +734,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProdutoAprovado"
+                            "$ref": "#/definitions/models.Fornecedor"
                         }
                     },
                     "400": {
@@ -563,6 +751,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -578,36 +773,49 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/admin/fornecedores/{id}/mesclar": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Remove um produto aprovado",
+                "description": "Incorpora fornecedores duplicados ao fornecedor da rota: ofertas e grafias do nome passam para ele e os duplicados são excluídos (apenas administradores)",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "admin"
                 ],
-                "summary": "Remover produto",
+                "summary": "Mesclar fornecedores",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "ID do produto",
+                        "description": "ID do fornecedor mantido",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Fornecedores duplicados",
+                        "name": "mescla",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.MesclarFornecedoresRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/models.Fornecedor"
                         }
                     },
                     "400": {
@@ -624,6 +832,13 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -641,118 +856,124 @@ const docTemplate = `{
                 }
             }
         },
-        "/supabase/auth/admin/create": {
+        "/admin/imagens/espelhar": {
             "post": {
-                "description": "Cria um novo usuário admin pré-confirmado usando Supabase Auth",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Copia para o armazenamento próprio as imagens de cavaletes já importados que ainda apontam para o Mobgran, gerando as miniaturas (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "supabase-auth"
+                    "admin"
                 ],
-                "summary": "Criar usuário admin no Supabase",
+                "summary": "Espelhar imagens pendentes",
                 "parameters": [
                     {
-                        "description": "Dados do usuário admin",
-                        "name": "user",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SupabaseRegistroRequest"
-                        }
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Quantidade máxima de cavaletes processados",
+                        "name": "limite",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SupabaseAuthResponse"
+                            "$ref": "#/definitions/models.EspelhamentoResultado"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "409": {
-                        "description": "Conflict",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/supabase/auth/login": {
+        "/admin/medidas/normalizar": {
             "post": {
-                "description": "Autentica um usuário usando Supabase Auth",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Calcula espessura em mm, dimensões em m, metragem em m² e peso em kg dos cavaletes importados antes da normalização, sem aguardar o processamento em segundo plano (apenas administradores)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "supabase-auth"
+                    "admin"
                 ],
-                "summary": "Login no Supabase",
+                "summary": "Normalizar medidas pendentes",
                 "parameters": [
                     {
-                        "description": "Credenciais do usuário",
-                        "name": "credentials",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handlers.SupabaseLoginRequest"
-                        }
+                        "type": "integer",
+                        "default": 500,
+                        "description": "Quantidade máxima de cavaletes processados",
+                        "name": "limite",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SupabaseAuthResponse"
+                            "$ref": "#/definitions/models.NormalizacaoMedidasResultado"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/supabase/auth/logout": {
+        "/admin/purgar": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Faz logout do usuário no Supabase",
+                "description": "Remove definitivamente ofertas, cavaletes e produtos arquivados (apenas administradores). Exige confirmacao = \"PURGAR DADOS ARQUIVADOS\"; use simular = true para apenas contar. Produtos vendidos, reservados ou em orçamentos fora de rascunho são mantidos, com seus cavaletes e ofertas.",
                 "consumes": [
                     "application/json"
                 ],
@@ -760,37 +981,185 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "supabase-auth"
+                    "admin"
+                ],
+                "summary": "Purgar registros arquivados",
+                "parameters": [
+                    {
+                        "description": "Confirmação e filtros da purga",
+                        "name": "purga",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PurgaRequest"
+                        }
+                    }
                 ],
-                "summary": "Logout",
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PurgaResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
+                            "additionalProperties": true
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/supabase/auth/refresh": {
+        "/admin/reservas/expirar": {
             "post": {
-                "description": "Renova o token de acesso usando o refresh token",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Libera imediatamente as reservas vencidas, sem aguardar o próximo ciclo do worker (apenas administradores)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Expirar reservas vencidas",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ExpiracaoReservasResultado"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as chaves de API do trader, da mais recente para a mais antiga (o valor da chave não é retornado)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "api-keys"
+                ],
+                "summary": "Listar chaves de API",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria uma chave de API para integrações (ERP, site). A chave é exibida apenas nesta resposta.",
                 "consumes": [
                     "application/json"
                 ],
@@ -798,118 +1167,139 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "supabase-auth"
+                    "api-keys"
                 ],
-                "summary": "Renovar token",
+                "summary": "Criar chave de API",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "refresh",
+                        "description": "Dados da chave",
+                        "name": "api_key",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handlers.RefreshTokenRequest"
+                            "$ref": "#/definitions/models.APIKeyCriarRequest"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.SupabaseSession"
+                            "$ref": "#/definitions/models.APIKeyCriadaResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/supabase/auth/user": {
-            "get": {
+        "/api-keys/{id}": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Obtém informações do usuário autenticado no Supabase",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Revoga uma chave de API do trader",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "supabase-auth"
+                    "api-keys"
+                ],
+                "summary": "Revogar chave de API",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da chave de API",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Obter usuário atual",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SupabaseUser"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
+                            "type": "object",
+                            "additionalProperties": true
                         }
                     }
                 }
             }
         },
-        "/vitrine/publica": {
-            "get": {
-                "description": "Lista produtos na vitrine pública (não requer autenticação)",
+        "/api/extrair-uuid": {
+            "post": {
+                "description": "Extrai o UUID de uma URL do Mobgran",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "produtos"
+                    "utilidades"
                 ],
-                "summary": "Vitrine pública",
+                "summary": "Extrai UUID da URL",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limite de resultados",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 0,
-                        "description": "Offset para paginação",
-                        "name": "offset",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "Filtrar por trader específico",
-                        "name": "trader_id",
-                        "in": "query"
+                        "description": "URL para extrair UUID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 ],
                 "responses": {
@@ -920,8 +1310,8 @@ const docTemplate = `{
                             "additionalProperties": true
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": true
@@ -929,242 +1319,7245 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handlers.RefreshTokenRequest": {
-            "type": "object",
-            "required": [
-                "refresh_token"
-            ],
-            "properties": {
-                "refresh_token": {
+        },
+        "/api/importar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Importa dados de uma oferta do Mobgran para o Supabase",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "importacao"
+                ],
+                "summary": "Importa uma oferta do Mobgran",
+                "parameters": [
+                    {
+                        "description": "Dados da importação",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ImportResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/validar-url": {
+            "post": {
+                "description": "Valida se uma URL é um link válido do Mobgran",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "validacao"
+                ],
+                "summary": "Valida URL do Mobgran",
+                "parameters": [
+                    {
+                        "description": "URL para validar",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/arquivo/arquivar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Arquiva (soft delete) uma oferta do trader, ou todas quando oferta_id não é informado, junto com seus cavaletes e produtos",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "arquivo"
+                ],
+                "summary": "Arquivar ofertas",
+                "parameters": [
+                    {
+                        "description": "Escopo do arquivamento",
+                        "name": "escopo",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArquivoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArquivoResultado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/arquivo/restaurar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Restaura uma oferta arquivada do trader, ou todas quando oferta_id não é informado, junto com os cavaletes e produtos arquivados com ela",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "arquivo"
+                ],
+                "summary": "Restaurar ofertas arquivadas",
+                "parameters": [
+                    {
+                        "description": "Escopo da restauração",
+                        "name": "escopo",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArquivoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ArquivoResultado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/busca/cavaletes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Busca textual (português, ignorando acentos) sobre material, classificação, acabamento, espessura, bloco e observações dos cavaletes do trader, com facetas por material, espessura e classificação",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "busca"
+                ],
+                "summary": "Buscar cavaletes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termo de busca (ex.: branco siena polido)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por material",
+                        "name": "material",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)",
+                        "name": "espessura",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por classificação",
+                        "name": "classificacao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por acabamento",
+                        "name": "acabamento",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por fornecedor",
+                        "name": "fornecedor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Comprimento mínimo em metros",
+                        "name": "comprimento_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Comprimento máximo em metros",
+                        "name": "comprimento_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Altura mínima em metros",
+                        "name": "altura_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Altura máxima em metros",
+                        "name": "altura_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Metragem mínima em m²",
+                        "name": "metragem_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Metragem máxima em m²",
+                        "name": "metragem_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas cavaletes ainda não aprovados",
+                        "name": "disponiveis",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação (a busca não aceita cursor)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResultadoBuscaCavaletes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/busca/produtos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Busca textual sobre o nome e a descrição dos produtos aprovados do trader e sobre os dados dos seus cavaletes, com facetas por material, espessura e classificação",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "busca"
+                ],
+                "summary": "Buscar produtos",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Termo de busca (ex.: branco siena polido)",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por material",
+                        "name": "material",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)",
+                        "name": "espessura",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por classificação",
+                        "name": "classificacao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por acabamento",
+                        "name": "acabamento",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por fornecedor",
+                        "name": "fornecedor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Comprimento mínimo em metros",
+                        "name": "comprimento_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Comprimento máximo em metros",
+                        "name": "comprimento_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Altura mínima em metros",
+                        "name": "altura_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Altura máxima em metros",
+                        "name": "altura_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Metragem mínima em m²",
+                        "name": "metragem_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Metragem máxima em m²",
+                        "name": "metragem_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação (a busca não aceita cursor)",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ResultadoBuscaProdutos"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/catalogo/espessuras": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as espessuras padrão às quais as espessuras importadas são associadas",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalogo"
+                ],
+                "summary": "Listar espessuras do catálogo",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/catalogo/mapeamentos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista os nomes de material e espessura importados nos cavaletes do trader, com a quantidade de cavaletes e o item do catálogo associado. Com pendentes=true, retorna apenas os nomes ainda não confirmados pelo trader.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalogo"
+                ],
+                "summary": "Listar mapeamentos do trader",
+                "parameters": [
+                    {
+                        "enum": [
+                            "material",
+                            "espessura"
+                        ],
+                        "type": "string",
+                        "description": "Tipo do mapeamento",
+                        "name": "tipo",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas nomes sem mapeamento confirmado",
+                        "name": "pendentes",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Confirma ou corrige o material ou a espessura do catálogo associado a um nome importado. Vale para todas as grafias equivalentes do nome, nos cavaletes existentes e nas próximas importações.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalogo"
+                ],
+                "summary": "Definir mapeamento",
+                "parameters": [
+                    {
+                        "description": "Nome importado e item do catálogo",
+                        "name": "mapeamento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.DefinirMapeamentoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MapeamentoDefinido"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Desfaz o mapeamento confirmado pelo trader; o nome volta a ser associado automaticamente pelo catálogo",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalogo"
+                ],
+                "summary": "Remover mapeamento",
+                "parameters": [
+                    {
+                        "enum": [
+                            "material",
+                            "espessura"
+                        ],
+                        "type": "string",
+                        "description": "Tipo do mapeamento",
+                        "name": "tipo",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Nome importado",
+                        "name": "valor_origem",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AplicacaoCatalogoResultado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/catalogo/materiais": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista o catálogo curado de materiais com os sinônimos. A busca considera nome e sinônimos, ignorando acentos.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalogo"
+                ],
+                "summary": "Listar materiais do catálogo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trecho do nome ou sinônimo",
+                        "name": "busca",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/catalogo/materiais/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna um material do catálogo com os sinônimos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "catalogo"
+                ],
+                "summary": "Buscar material do catálogo",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do material",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MaterialCatalogo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista os clientes do trader, do mais recente para o mais antigo. A busca considera nome, e-mail e início do documento.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Listar clientes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Parte do nome, e-mail ou documento",
+                        "name": "busca",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cadastra um comprador com CPF ou CNPJ (validados pelos dígitos verificadores), endereço e pessoas de contato",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Cadastrar cliente",
+                "parameters": [
+                    {
+                        "description": "Dados do cliente",
+                        "name": "cliente",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClienteCriarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o cadastro do cliente com endereço e contatos",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Buscar cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera o cadastro do cliente. Campos ausentes são mantidos; contatos, quando enviados, substituem a lista inteira.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Atualizar cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados para atualização",
+                        "name": "cliente",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ClienteAtualizarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Cliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exclui o cadastro do cliente. Orçamentos e produtos vinculados mantêm o nome do cliente, sem o vínculo com o cadastro.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Remover cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/clientes/{id}/historico": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o resumo do relacionamento, os orçamentos e os produtos reservados ou comprados pelo cliente",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "clientes"
+                ],
+                "summary": "Histórico do cliente",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do cliente",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoricoCliente"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/estatisticas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Estoque importado e aprovado (m²), valor do estoque pelo preço de venda por m², distribuição por material e por fornecedor e série de importações. O período considera a última importação dos cavaletes, a aprovação dos produtos e a data das vendas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "estatisticas"
+                ],
+                "summary": "Painel de estatísticas do trader",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Data inicial (RFC3339 ou AAAA-MM-DD)",
+                        "name": "desde",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Data final (RFC3339 ou AAAA-MM-DD, inclusive)",
+                        "name": "ate",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "mes",
+                        "description": "Agrupamento da série de importações (dia, semana, mes)",
+                        "name": "agrupamento",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EstatisticasTrader"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/eventos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Lista os eventos de alteração de dados do trader (importações e produtos) posteriores ao cursor desde_id, em ordem. Use o ultimo_id da resposta como próximo cursor.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "eventos"
+                ],
+                "summary": "Listar eventos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Retorna apenas eventos com id maior que este",
+                        "name": "desde_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Máximo de eventos (padrão 100, máximo 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/eventos/stream": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Abre um stream Server-Sent Events com as alterações de dados do trader (importacao.*, oferta.importada, produto.*, cavalete.removido). Cada mensagem traz o id do evento, o tipo em event e o evento em data. A retomada usa o cabeçalho Last-Event-ID ou desde_id; sem cursor, apenas eventos novos são enviados.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "eventos"
+                ],
+                "summary": "Stream de eventos",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Envia os eventos com id maior que este antes dos novos",
+                        "name": "desde_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Último evento recebido (reconexão)",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Stream de eventos",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/fornecedores": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista os fornecedores das ofertas importadas pelo trader, com ofertas, cavaletes, m² importados e data da última importação. A busca considera o nome e as grafias conhecidas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Listar fornecedores",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Trecho do nome do fornecedor",
+                        "name": "busca",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/fornecedores/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o fornecedor com os dados de contato e as estatísticas das ofertas do trader",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "fornecedores"
+                ],
+                "summary": "Buscar fornecedor",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do fornecedor",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Fornecedor"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health/live": {
+            "get": {
+                "description": "Indica que o processo está no ar, sem consultar dependências. Usado pelo orquestrador para decidir se a instância deve ser reiniciada. Traz a versão e o commit do build.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "Verifica as dependências da API: conexão e migrations do banco (críticas), saturação do pool de conexões, Supabase Auth, circuito da API do Mobgran e fila de notificações. Responde 503 quando uma dependência crítica falha; as demais apenas marcam a API como degraded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prontidao"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/models.Prontidao"
+                        }
+                    }
+                }
+            }
+        },
+        "/marca": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna os dados da empresa e o logotipo exibidos nos PDFs do trader",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marca"
+                ],
+                "summary": "Obter dados de marca",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarcaTrader"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera empresa, documento, telefone, endereço e site exibidos nos PDFs. Campos ausentes são mantidos; campos vazios são apagados.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marca"
+                ],
+                "summary": "Atualizar dados de marca",
+                "parameters": [
+                    {
+                        "description": "Dados da empresa",
+                        "name": "marca",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AtualizarMarcaRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarcaTrader"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/marca/logo": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Substitui o logotipo do trader (JPEG ou PNG, até 2 MB) usado no cabeçalho dos PDFs",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marca"
+                ],
+                "summary": "Enviar logotipo",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "Arquivo do logotipo",
+                        "name": "logo",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarcaTrader"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove o logotipo do trader; os PDFs passam a exibir apenas os dados da empresa",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "marca"
+                ],
+                "summary": "Remover logotipo",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.MarcaTrader"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registro das notificações do trader, da mais recente para a mais antiga, com a situação da entrega (pendente, entregue, falhou), tentativas e último erro",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Listar notificações",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtrar pela assinatura",
+                        "name": "assinatura_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar pelo evento",
+                        "name": "evento",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar pela situação (pendente, entregue, falhou)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes/assinaturas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista os destinos (webhook ou e-mail) cadastrados pelo trader e os eventos de cada um",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Listar assinaturas de notificação",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cadastra um webhook (URL http/https) ou e-mail para os eventos importacao.concluida, importacao.falhou, cavalete.removido e reserva.expirando ou, para integrações, produto.aprovado, produto.atualizado, produto.removido e oferta.importada. O segredo do HMAC dos webhooks é retornado apenas nesta resposta.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Cadastrar assinatura de notificação",
+                "parameters": [
+                    {
+                        "description": "Canal, destino e eventos",
+                        "name": "assinatura",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssinaturaCriarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.AssinaturaNotificacao"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes/assinaturas/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera destino, eventos ou ativação. Com regenerar_segredo, um novo segredo do webhook é gerado e retornado.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Atualizar assinatura de notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Campos a alterar",
+                        "name": "assinatura",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AssinaturaAtualizarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AssinaturaNotificacao"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exclui a assinatura e o registro das suas notificações",
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Remover assinatura de notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes/assinaturas/{id}/reenviar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devolve à fila as notificações da assinatura já entregues ou que falharam, opcionalmente só as de uma situação ou criadas a partir de uma data. Cada uma é entregue de novo com o mesmo id (Idempotency-Key).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Reenviar notificações da assinatura",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Situação (entregue ou falhou) e data inicial",
+                        "name": "filtro",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReenvioRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes/assinaturas/{id}/testar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Enfileira um evento \"teste\" apenas para esta assinatura, mesmo inativa, para validar o receptor. Acompanhe a entrega em GET /notificacoes/{id}.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Testar assinatura de notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da assinatura",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Notificacao"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna a notificação com o registro de cada tentativa de entrega (status HTTP, erro e duração)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Buscar notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da notificação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Notificacao"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/notificacoes/{id}/reenviar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Devolve à fila uma notificação já entregue ou que falhou. A entrega é refeita com o mesmo id (Idempotency-Key) e nova contagem de tentativas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "notificacoes"
+                ],
+                "summary": "Reenviar notificação",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da notificação",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/models.Notificacao"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/ofertas/{id}/importacoes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as importações da oferta, da mais recente para a mais antiga, com os totais do estoque e os cavaletes adicionados, removidos e alterados em relação à importação anterior",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "ofertas"
+                ],
+                "summary": "Listar importações da oferta",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID da oferta",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista os orçamentos do trader com totais, do mais recente para o mais antigo",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Listar orçamentos",
+                "parameters": [
+                    {
+                        "enum": [
+                            "rascunho",
+                            "enviado",
+                            "aceito",
+                            "recusado"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Parte do nome do cliente",
+                        "name": "cliente",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do cliente cadastrado",
+                        "name": "cliente_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cria um orçamento em rascunho para um cliente. Os produtos são incluídos em seguida por POST /orcamentos/{id}/itens.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Criar orçamento",
+                "parameters": [
+                    {
+                        "description": "Dados do orçamento",
+                        "name": "orcamento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrcamentoCriarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retorna o orçamento com itens, totais e o status atual de cada produto",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Buscar orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera cliente, validade, observações e descontos de um orçamento em rascunho",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Atualizar orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados para atualização",
+                        "name": "orcamento",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrcamentoAtualizarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Exclui um orçamento em rascunho ou recusado",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Remover orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/aceitar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registra o aceite do cliente para um orçamento enviado e dentro da validade",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Aceitar orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/converter": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserva para o cliente todos os produtos de um orçamento aceito. Se algum produto não estiver disponível, nenhuma reserva é criada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Converter orçamento em reservas",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Expiração das reservas",
+                        "name": "conversao",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConverterOrcamentoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ConversaoOrcamentoResultado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/enviar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Marca o rascunho como enviado ao cliente. Orçamentos enviados não podem mais ser editados.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Enviar orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/itens": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Inclui um produto aprovado no orçamento em rascunho. Sem quantidade_m2, usa a metragem do cavalete; sem preco_m2, o preço de venda do produto.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Adicionar item ao orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Produto, quantidade e preço",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrcamentoItemRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/itens/{itemId}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Altera a quantidade em m² ou o preço negociado por m² de um item",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Atualizar item do orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do item",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Quantidade e preço",
+                        "name": "item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.OrcamentoItemAtualizarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Retira um item do orçamento em rascunho",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Remover item do orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID do item",
+                        "name": "itemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/pdf": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gera o orçamento com itens, totais, validade e observações, com os dados de marca do trader",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Orçamento em PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/orcamentos/{id}/recusar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registra a recusa do cliente para um orçamento enviado",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orcamentos"
+                ],
+                "summary": "Recusar orçamento",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do orçamento",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Orcamento"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/aprovados": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista produtos aprovados pelo trader",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Listar produtos aprovados",
+                "parameters": [
+                    {
+                        "enum": [
+                            "disponivel",
+                            "reservado",
+                            "vendido"
+                        ],
+                        "type": "string",
+                        "description": "Status de venda",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/aprovar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Aprova um cavalete como produto para venda",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Aprovar produto",
+                "parameters": [
+                    {
+                        "description": "Dados do produto",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/cavaletes": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista cavaletes disponíveis para aprovação pelo trader",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Listar cavaletes disponíveis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filtrar por fornecedor",
+                        "name": "fornecedor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/estatisticas": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtém as contagens de produtos do trader e os cavaletes das suas ofertas ativas ainda não aprovados. O painel completo está em GET /estatisticas.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Estatísticas de produtos",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.EstatisticasProdutos"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Busca um produto específico do trader",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Buscar produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Atualiza um produto aprovado",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Atualizar produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados para atualização",
+                        "name": "produto",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAtualizarRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove um produto aprovado",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Remover produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/ficha": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Gera a ficha técnica do produto (material, espessura, dimensões, metragem, imagem e preço) com os dados de marca do trader",
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Ficha técnica do produto em PDF",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/historico": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Linha do tempo do produto aprovado: alterações de preço de venda e importações da oferta em que o cavalete entrou, saiu ou mudou de metragem ou de quantidade de chapas, em ordem cronológica",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Histórico do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.HistoricoProduto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/imagens": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Lista as imagens da galeria do produto na ordem de exibição",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Listar imagens do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Envia uma ou mais imagens (JPEG, PNG ou WebP, até 10 MB e 40 megapixels cada) para a galeria do produto. As imagens entram no final da galeria e a primeira imagem de um produto sem capa vira a capa. Limite de 20 imagens por produto.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Enviar imagens do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Arquivos de imagem (campo repetível)",
+                        "name": "imagens",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/imagens/ordem": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a ordem de exibição da galeria. A lista deve conter todas as imagens do produto, uma única vez cada.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Reordenar imagens do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IDs das imagens na nova ordem",
+                        "name": "ordem",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReordenarImagensRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/imagens/{imagemId}": {
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Remove a imagem da galeria e seus arquivos. Se era a capa, a próxima imagem da galeria assume a capa.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Remover imagem do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID da imagem",
+                        "name": "imagemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/imagens/{imagemId}/capa": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define a imagem exibida como principal do produto na vitrine",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Definir capa do produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID da imagem",
+                        "name": "imagemId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/liberar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Cancela a reserva do produto, que volta a ficar disponível",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Liberar reserva",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/reservar": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Reserva um produto disponível para um cliente. A reserva expira em expira_em, em validade_horas ou, sem nenhum dos dois, na validade padrão configurada; reservas vencidas voltam a disponível automaticamente.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Reservar produto",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da reserva",
+                        "name": "reserva",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReservarProdutoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/produtos/{id}/vender": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Registra a venda de um produto disponível ou reservado. Produtos vendidos deixam de aparecer na vitrine pública.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "produtos"
+                ],
+                "summary": "Marcar produto como vendido",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "ID do produto",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dados da venda",
+                        "name": "venda",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/models.VenderProdutoRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProdutoAprovado"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/supabase/auth/admin/create": {
+            "post": {
+                "description": "Cria um novo usuário admin pré-confirmado usando Supabase Auth",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supabase-auth"
+                ],
+                "summary": "Criar usuário admin no Supabase",
+                "parameters": [
+                    {
+                        "description": "Dados do usuário admin",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SupabaseRegistroRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupabaseAuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/supabase/auth/login": {
+            "post": {
+                "description": "Autentica um usuário usando Supabase Auth",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supabase-auth"
+                ],
+                "summary": "Login no Supabase",
+                "parameters": [
+                    {
+                        "description": "Credenciais do usuário",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.SupabaseLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupabaseAuthResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/supabase/auth/logout": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Faz logout do usuário no Supabase",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supabase-auth"
+                ],
+                "summary": "Logout",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/supabase/auth/refresh": {
+            "post": {
+                "description": "Renova o token de acesso usando o refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supabase-auth"
+                ],
+                "summary": "Renovar token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handlers.RefreshTokenRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupabaseSession"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/supabase/auth/user": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Obtém informações do usuário autenticado no Supabase",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "supabase-auth"
+                ],
+                "summary": "Obter usuário atual",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SupabaseUser"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/vitrine/publica": {
+            "get": {
+                "description": "Lista produtos na vitrine pública com filtros, busca e ordenação (não requer autenticação)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vitrine"
+                ],
+                "summary": "Vitrine pública",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior); apenas com ordenacao=recentes, outras ordenações respondem 400",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por trader específico",
+                        "name": "trader_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por fornecedor",
+                        "name": "fornecedor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas produtos em destaque",
+                        "name": "destaque",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por material (busca parcial)",
+                        "name": "material",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)",
+                        "name": "espessura",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por classificação",
+                        "name": "classificacao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por acabamento",
+                        "name": "acabamento",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Comprimento mínimo em metros",
+                        "name": "comprimento_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Comprimento máximo em metros",
+                        "name": "comprimento_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Altura mínima em metros",
+                        "name": "altura_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Altura máxima em metros",
+                        "name": "altura_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Metragem mínima em m²",
+                        "name": "metragem_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Metragem máxima em m²",
+                        "name": "metragem_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço mínimo",
+                        "name": "preco_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço máximo",
+                        "name": "preco_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Busca no nome e na descrição",
+                        "name": "busca",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "destaque",
+                        "description": "Ordenação: destaque, preco_asc, preco_desc, recentes, metragem_desc, nome",
+                        "name": "ordenacao",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/vitrine/slug": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Define o slug usado na URL da vitrine pública do trader (/vitrine/{slug})",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vitrine"
+                ],
+                "summary": "Alterar slug da vitrine",
+                "parameters": [
+                    {
+                        "description": "Novo slug",
+                        "name": "slug",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AtualizarSlugRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.VitrineTrader"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        },
+        "/vitrine/{slug}": {
+            "get": {
+                "description": "Lista os produtos da vitrine pública de um trader pelo slug, com os mesmos filtros de /vitrine/publica (não requer autenticação)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "vitrine"
+                ],
+                "summary": "Vitrine do trader",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Slug da vitrine do trader",
+                        "name": "slug",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limite de resultados",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Offset para paginação",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior); apenas com ordenacao=recentes, outras ordenações respondem 400",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Incluir o total de registros (consulta adicional)",
+                        "name": "incluir_total",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Apenas produtos em destaque",
+                        "name": "destaque",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por material (busca parcial)",
+                        "name": "material",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)",
+                        "name": "espessura",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por classificação",
+                        "name": "classificacao",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por acabamento",
+                        "name": "acabamento",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filtrar por fornecedor",
+                        "name": "fornecedor_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço mínimo",
+                        "name": "preco_min",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Preço máximo",
+                        "name": "preco_max",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Busca no nome e na descrição",
+                        "name": "busca",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "destaque",
+                        "description": "Ordenação: destaque, preco_asc, preco_desc, recentes, metragem_desc, nome",
+                        "name": "ordenacao",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handlers.RefreshTokenRequest": {
+            "type": "object",
+            "required": [
+                "refresh_token"
+            ],
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SupabaseLoginRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                }
+            }
+        },
+        "handlers.SupabaseRegistroRequest": {
+            "type": "object",
+            "required": [
+                "email",
+                "password"
+            ],
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "email": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string",
+                    "minLength": 6
+                }
+            }
+        },
+        "models.APIError": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.ErrorType"
+                }
+            }
+        },
+        "models.APIKeyCriadaResponse": {
+            "type": "object",
+            "properties": {
+                "chave": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "escopos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expira_em": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "prefixo": {
+                    "type": "string"
+                },
+                "revogada_em": {
+                    "type": "string"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "ultimo_uso_em": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.APIKeyCriarRequest": {
+            "type": "object",
+            "required": [
+                "escopos",
+                "nome"
+            ],
+            "properties": {
+                "escopos": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "expira_em": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                }
+            }
+        },
+        "models.AplicacaoCatalogoResultado": {
+            "type": "object",
+            "properties": {
+                "cavaletes_atualizados": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ArquivoRequest": {
+            "type": "object",
+            "properties": {
+                "oferta_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ArquivoResultado": {
+            "type": "object",
+            "properties": {
+                "cavaletes": {
+                    "type": "integer"
+                },
+                "ofertas": {
+                    "type": "integer"
+                },
+                "produtos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AssinaturaAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "destino": {
+                    "type": "string"
+                },
+                "eventos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "regenerar_segredo": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.AssinaturaCriarRequest": {
+            "type": "object",
+            "required": [
+                "canal",
+                "destino",
+                "eventos"
+            ],
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "canal": {
+                    "type": "string"
+                },
+                "destino": {
+                    "type": "string"
+                },
+                "eventos": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.AssinaturaNotificacao": {
+            "type": "object",
+            "properties": {
+                "ativa": {
+                    "type": "boolean"
+                },
+                "canal": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "destino": {
+                    "type": "string"
+                },
+                "eventos": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "segredo": {
+                    "type": "string"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AtualizarMarcaRequest": {
+            "type": "object",
+            "properties": {
+                "documento": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "empresa": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "endereco": {
+                    "type": "string",
+                    "maxLength": 500
+                },
+                "site": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "models.AtualizarSlugRequest": {
+            "type": "object",
+            "required": [
+                "slug"
+            ],
+            "properties": {
+                "slug": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 3
+                }
+            }
+        },
+        "models.CavaleteEncontrado": {
+            "type": "object",
+            "properties": {
+                "altura": {
+                    "type": "number"
+                },
+                "bloco": {
+                    "type": "string"
+                },
+                "codigo": {
+                    "type": "string"
+                },
+                "comprimento": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "espessura_cliente": {
+                    "type": "string"
+                },
+                "fornecedor_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "imagem_principal": {
+                    "type": "object"
+                },
+                "imagens_adicionais": {
+                    "type": "object"
+                },
+                "ja_aprovado": {
+                    "type": "boolean"
+                },
+                "largura": {
+                    "type": "number"
+                },
+                "medidas": {
+                    "$ref": "#/definitions/models.Medidas"
+                },
+                "metragem": {
+                    "type": "number"
+                },
+                "nome_acabamento": {
+                    "type": "string"
+                },
+                "nome_classificacao": {
+                    "type": "string"
+                },
+                "nome_empresa": {
+                    "type": "string"
+                },
+                "nome_espessura": {
+                    "type": "string"
+                },
+                "nome_material": {
+                    "type": "string"
+                },
+                "oferta_id": {
+                    "type": "string"
+                },
+                "peso": {
+                    "type": "number"
+                },
+                "produto_cliente": {
+                    "type": "string"
+                },
+                "relevancia": {
+                    "type": "number"
+                },
+                "tipo_metragem": {
+                    "type": "string"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Cliente": {
+            "type": "object",
+            "properties": {
+                "contatos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ContatoCliente"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "documento": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "endereco": {
+                    "$ref": "#/definitions/models.EnderecoCliente"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                },
+                "tipo_documento": {
+                    "type": "string"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ClienteAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "contatos": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/models.ContatoClienteRequest"
+                    }
+                },
+                "documento": {
+                    "type": "string",
+                    "maxLength": 18
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "endereco": {
+                    "$ref": "#/definitions/models.EnderecoCliente"
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "models.ClienteCriarRequest": {
+            "type": "object",
+            "required": [
+                "nome"
+            ],
+            "properties": {
+                "contatos": {
+                    "type": "array",
+                    "maxItems": 20,
+                    "items": {
+                        "$ref": "#/definitions/models.ContatoClienteRequest"
+                    }
+                },
+                "documento": {
+                    "type": "string",
+                    "maxLength": 18,
+                    "example": "11.222.333/0001-81"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "endereco": {
+                    "$ref": "#/definitions/models.EnderecoCliente"
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "models.ContatoCliente": {
+            "type": "object",
+            "properties": {
+                "cargo": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "principal": {
+                    "type": "boolean"
+                },
+                "telefone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ContatoClienteRequest": {
+            "type": "object",
+            "required": [
+                "nome"
+            ],
+            "properties": {
+                "cargo": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "principal": {
+                    "type": "boolean"
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 20
+                }
+            }
+        },
+        "models.ConversaoOrcamentoResultado": {
+            "type": "object",
+            "properties": {
+                "orcamento": {
+                    "$ref": "#/definitions/models.Orcamento"
+                },
+                "reservados": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProdutoAprovado"
+                    }
+                }
+            }
+        },
+        "models.ConverterOrcamentoRequest": {
+            "type": "object",
+            "properties": {
+                "expira_em": {
+                    "type": "string"
+                },
+                "validade_horas": {
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 1
+                }
+            }
+        },
+        "models.DefinirMapeamentoRequest": {
+            "type": "object",
+            "required": [
+                "catalogo_id",
+                "tipo",
+                "valor_origem"
+            ],
+            "properties": {
+                "catalogo_id": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string",
+                    "enum": [
+                        "material",
+                        "espessura"
+                    ]
+                },
+                "valor_origem": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "BRANCO SIENNA"
+                }
+            }
+        },
+        "models.EnderecoCliente": {
+            "type": "object",
+            "properties": {
+                "bairro": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "cep": {
+                    "type": "string",
+                    "maxLength": 9
+                },
+                "cidade": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "complemento": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "logradouro": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "numero": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "uf": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EntregaNotificacao": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "duracao_ms": {
+                    "type": "integer"
+                },
+                "erro": {
+                    "type": "string"
+                },
+                "status_http": {
+                    "type": "integer"
+                },
+                "sucesso": {
+                    "type": "boolean"
+                },
+                "tentativa": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/models.APIError"
+                }
+            }
+        },
+        "models.ErrorType": {
+            "type": "string",
+            "enum": [
+                "validation_error",
+                "authentication_error",
+                "authorization_error",
+                "not_found_error",
+                "conflict_error",
+                "internal_error",
+                "bad_request_error"
+            ],
+            "x-enum-varnames": [
+                "ErrorTypeValidation",
+                "ErrorTypeAuthentication",
+                "ErrorTypeAuthorization",
+                "ErrorTypeNotFound",
+                "ErrorTypeConflict",
+                "ErrorTypeInternal",
+                "ErrorTypeBadRequest"
+            ]
+        },
+        "models.EspelhamentoResultado": {
+            "type": "object",
+            "properties": {
+                "espelhados": {
+                    "type": "integer"
+                },
+                "falhas": {
+                    "type": "integer"
+                },
+                "processados": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EstatisticaGrupo": {
+            "type": "object",
+            "properties": {
+                "cavaletes": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "metragem_aprovada_m2": {
+                    "type": "number"
+                },
+                "metragem_importada_m2": {
+                    "type": "number"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "produtos": {
+                    "type": "integer"
+                },
+                "valor_estoque": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.EstatisticasFornecedor": {
+            "type": "object",
+            "properties": {
+                "cavaletes": {
+                    "type": "integer"
+                },
+                "metragem_m2": {
+                    "type": "number"
+                },
+                "ofertas": {
+                    "type": "integer"
+                },
+                "ofertas_ativas": {
+                    "type": "integer"
+                },
+                "ultima_importacao": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EstatisticasProdutos": {
+            "type": "object",
+            "properties": {
+                "cavaletes_disponiveis": {
+                    "type": "integer"
+                },
+                "produtos_destaque": {
+                    "type": "integer"
+                },
+                "produtos_visiveis": {
+                    "type": "integer"
+                },
+                "total_produtos": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EstatisticasTrader": {
+            "type": "object",
+            "properties": {
+                "agrupamento": {
+                    "type": "string"
+                },
+                "ate": {
+                    "type": "string"
+                },
+                "desde": {
+                    "type": "string"
+                },
+                "estoque": {
+                    "$ref": "#/definitions/models.ResumoEstoquePainel"
+                },
+                "importacoes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PontoSerieImportacao"
+                    }
+                },
+                "por_fornecedor": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstatisticaGrupo"
+                    }
+                },
+                "por_material": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EstatisticaGrupo"
+                    }
+                },
+                "produtos": {
+                    "$ref": "#/definitions/models.ResumoProdutosPainel"
+                }
+            }
+        },
+        "models.EventoHistoricoProduto": {
+            "type": "object",
+            "properties": {
+                "chapas": {
+                    "type": "integer"
+                },
+                "data": {
+                    "type": "string"
+                },
+                "disponivel": {
+                    "type": "boolean"
+                },
+                "importacao_id": {
+                    "type": "string"
+                },
+                "metragem_m2": {
+                    "type": "number"
+                },
+                "preco_anterior": {
+                    "type": "number"
+                },
+                "preco_novo": {
+                    "type": "number"
+                },
+                "tipo": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ExpiracaoReservasResultado": {
+            "type": "object",
+            "properties": {
+                "expiradas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Faceta": {
+            "type": "object",
+            "properties": {
+                "quantidade": {
+                    "type": "integer"
+                },
+                "valor": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FacetasBusca": {
+            "type": "object",
+            "properties": {
+                "classificacoes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Faceta"
+                    }
+                },
+                "espessuras": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Faceta"
+                    }
+                },
+                "materiais": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Faceta"
+                    }
+                }
+            }
+        },
+        "models.Fornecedor": {
+            "type": "object",
+            "properties": {
+                "cidade": {
+                    "type": "string"
+                },
+                "contato": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "documento": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "estatisticas": {
+                    "$ref": "#/definitions/models.EstatisticasFornecedor"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "nomes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "site": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                },
+                "uf": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url_logo": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.FornecedorAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "cidade": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "contato": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "documento": {
+                    "type": "string",
+                    "maxLength": 18,
+                    "example": "11.222.333/0001-81"
+                },
+                "email": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "site": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "telefone": {
+                    "type": "string",
+                    "maxLength": 20
+                },
+                "uf": {
+                    "type": "string",
+                    "maxLength": 2
+                },
+                "url_logo": {
+                    "type": "string",
+                    "maxLength": 2048
+                }
+            }
+        },
+        "models.HistoricoCliente": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "$ref": "#/definitions/models.Cliente"
+                },
+                "orcamentos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Orcamento"
+                    }
+                },
+                "produtos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProdutoAprovado"
+                    }
+                },
+                "resumo": {
+                    "$ref": "#/definitions/models.ResumoCliente"
+                }
+            }
+        },
+        "models.HistoricoProduto": {
+            "type": "object",
+            "properties": {
+                "codigo": {
+                    "type": "string"
+                },
+                "eventos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EventoHistoricoProduto"
+                    }
+                },
+                "oferta_id": {
+                    "type": "string"
+                },
+                "produto_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "atualizar_existente": {
+                    "type": "boolean"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ImportResponse": {
+            "type": "object",
+            "properties": {
+                "mensagem": {
+                    "type": "string"
+                },
+                "oferta_id": {
+                    "type": "string"
+                },
+                "sucesso": {
+                    "type": "boolean"
+                },
+                "uuid_link": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MapeamentoDefinido": {
+            "type": "object",
+            "properties": {
+                "catalogo_id": {
+                    "type": "string"
+                },
+                "cavaletes_atualizados": {
+                    "type": "integer"
+                },
+                "nome_catalogo": {
+                    "type": "string"
+                },
+                "tipo": {
+                    "type": "string"
+                },
+                "valor_origem": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MarcaTrader": {
+            "type": "object",
+            "properties": {
+                "documento": {
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "empresa": {
+                    "type": "string"
+                },
+                "endereco": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "site": {
+                    "type": "string"
+                },
+                "telefone": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MaterialCatalogo": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "sinonimos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SinonimoMaterial"
+                    }
+                },
+                "tipo": {
+                    "type": "string",
+                    "example": "granito"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MaterialCatalogoAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "tipo": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.MaterialCatalogoRequest": {
+            "type": "object",
+            "required": [
+                "nome"
+            ],
+            "properties": {
+                "nome": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1,
+                    "example": "Branco Siena"
+                },
+                "sinonimos": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tipo": {
+                    "type": "string",
+                    "maxLength": 50
+                }
+            }
+        },
+        "models.Medidas": {
+            "type": "object",
+            "properties": {
+                "altura_m": {
+                    "type": "number"
+                },
+                "comprimento_m": {
+                    "type": "number"
+                },
+                "espessura_mm": {
+                    "type": "number"
+                },
+                "metragem_m2": {
+                    "type": "number"
+                },
+                "peso_kg": {
+                    "type": "number"
+                },
+                "unidade_dimensoes": {
+                    "type": "string",
+                    "example": "cm"
+                }
+            }
+        },
+        "models.MesclarFornecedoresRequest": {
+            "type": "object",
+            "required": [
+                "fornecedor_ids"
+            ],
+            "properties": {
+                "fornecedor_ids": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.NormalizacaoMedidasResultado": {
+            "type": "object",
+            "properties": {
+                "cavaletes": {
+                    "type": "integer"
+                },
+                "falhas": {
+                    "description": "Falhas são os cavaletes que não puderam ser normalizados, marcados em medidas_erro",
+                    "type": "integer"
+                },
+                "itens": {
+                    "type": "integer"
+                },
+                "sem_espessura": {
+                    "type": "integer"
+                },
+                "sem_metragem": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Notificacao": {
+            "type": "object",
+            "properties": {
+                "assinatura_id": {
+                    "type": "string"
+                },
+                "canal": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "dados": {
+                    "type": "object"
+                },
+                "destino": {
+                    "type": "string"
+                },
+                "entregas": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.EntregaNotificacao"
+                    }
+                },
+                "entregue_em": {
+                    "type": "string"
+                },
+                "evento": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "proxima_tentativa": {
+                    "type": "string"
+                },
+                "reenviada_em": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tentativas": {
+                    "type": "integer"
+                },
+                "ultimo_erro": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Orcamento": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "type": "string"
+                },
+                "cliente_contato": {
+                    "type": "string"
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "convertido_em": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "desconto_percentual": {
+                    "type": "number"
+                },
+                "desconto_valor": {
+                    "type": "number"
+                },
+                "enviado_em": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "itens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrcamentoItem"
+                    }
+                },
+                "numero": {
+                    "type": "integer"
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "respondido_em": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "totais": {
+                    "$ref": "#/definitions/models.TotaisOrcamento"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "validade": {
+                    "type": "string",
+                    "example": "2026-12-31"
+                }
+            }
+        },
+        "models.OrcamentoAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "cliente_contato": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "desconto_percentual": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "desconto_valor": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "validade": {
+                    "type": "string",
+                    "example": "2026-12-31"
+                }
+            }
+        },
+        "models.OrcamentoCriarRequest": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cliente_contato": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "desconto_percentual": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "desconto_valor": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "observacoes": {
+                    "type": "string"
+                },
+                "validade": {
+                    "type": "string",
+                    "example": "2026-12-31"
+                }
+            }
+        },
+        "models.OrcamentoItem": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "preco_m2": {
+                    "type": "number"
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade_m2": {
+                    "type": "number"
+                },
+                "status_produto": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OrcamentoItemAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "preco_m2": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "quantidade_m2": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.OrcamentoItemRequest": {
+            "type": "object",
+            "required": [
+                "produto_id"
+            ],
+            "properties": {
+                "preco_m2": {
+                    "type": "number",
+                    "minimum": 0
+                },
+                "produto_id": {
+                    "type": "string"
+                },
+                "quantidade_m2": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.PontoSerieImportacao": {
+            "type": "object",
+            "properties": {
+                "cavaletes": {
+                    "type": "integer"
+                },
+                "importacoes": {
+                    "type": "integer"
+                },
+                "metragem_m2": {
+                    "type": "number"
+                },
+                "ofertas": {
+                    "type": "integer"
+                },
+                "periodo": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProdutoAprovado": {
+            "type": "object",
+            "required": [
+                "nome_customizado",
+                "preco_venda"
+            ],
+            "properties": {
+                "cavalete_id": {
+                    "type": "string"
+                },
+                "cliente": {
+                    "type": "string"
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "destaque": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome_customizado": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "ordem_exibicao": {
+                    "type": "integer"
+                },
+                "preco_venda": {
+                    "type": "number"
+                },
+                "removido_na_origem": {
+                    "type": "boolean"
+                },
+                "reserva_expira_em": {
+                    "type": "string"
+                },
+                "reserva_observacao": {
+                    "type": "string"
+                },
+                "reservado_em": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valor_venda": {
+                    "type": "number"
+                },
+                "vendido_em": {
+                    "type": "string"
+                },
+                "visivel": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ProdutoAprovarRequest": {
+            "type": "object",
+            "required": [
+                "cavalete_id",
+                "nome_customizado",
+                "preco_venda"
+            ],
+            "properties": {
+                "cavalete_id": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "destaque": {
+                    "type": "boolean"
+                },
+                "nome_customizado": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "preco_venda": {
+                    "type": "number"
+                },
+                "visivel": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ProdutoAtualizarRequest": {
+            "type": "object",
+            "properties": {
+                "descricao": {
+                    "type": "string"
+                },
+                "destaque": {
+                    "type": "boolean"
+                },
+                "nome_customizado": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "ordem_exibicao": {
+                    "type": "integer"
+                },
+                "preco_venda": {
+                    "type": "number"
+                },
+                "visivel": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ProdutoEncontrado": {
+            "type": "object",
+            "required": [
+                "nome_customizado",
+                "preco_venda"
+            ],
+            "properties": {
+                "altura": {
+                    "type": "number"
+                },
+                "bloco": {
+                    "type": "string"
+                },
+                "cavalete_id": {
+                    "type": "string"
+                },
+                "cliente": {
+                    "type": "string"
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "codigo": {
+                    "type": "string"
+                },
+                "comprimento": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "descricao": {
+                    "type": "string"
+                },
+                "destaque": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "medidas": {
+                    "$ref": "#/definitions/models.Medidas"
+                },
+                "metragem": {
+                    "type": "number"
+                },
+                "nome_acabamento": {
+                    "type": "string"
+                },
+                "nome_classificacao": {
+                    "type": "string"
+                },
+                "nome_customizado": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "nome_espessura": {
+                    "type": "string"
+                },
+                "nome_material": {
+                    "type": "string"
+                },
+                "ordem_exibicao": {
+                    "type": "integer"
+                },
+                "preco_venda": {
+                    "type": "number"
+                },
+                "relevancia": {
+                    "type": "number"
+                },
+                "removido_na_origem": {
+                    "type": "boolean"
+                },
+                "reserva_expira_em": {
+                    "type": "string"
+                },
+                "reserva_observacao": {
+                    "type": "string"
+                },
+                "reservado_em": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "trader_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "valor_venda": {
+                    "type": "number"
+                },
+                "vendido_em": {
+                    "type": "string"
+                },
+                "visivel": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.Prontidao": {
+            "type": "object",
+            "properties": {
+                "commit": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "verificacoes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/models.Verificacao"
+                    }
+                },
+                "verificado_em": {
+                    "type": "string"
+                },
+                "versao": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurgaRequest": {
+            "type": "object",
+            "required": [
+                "confirmacao"
+            ],
+            "properties": {
+                "arquivados_antes_de": {
+                    "type": "string"
+                },
+                "confirmacao": {
+                    "type": "string"
+                },
+                "simular": {
+                    "type": "boolean"
+                },
+                "trader_id": {
                     "type": "string"
                 }
             }
         },
-        "handlers.SupabaseLoginRequest": {
+        "models.PurgaResponse": {
+            "type": "object",
+            "properties": {
+                "cavaletes": {
+                    "type": "integer"
+                },
+                "ofertas": {
+                    "type": "integer"
+                },
+                "produtos": {
+                    "type": "integer"
+                },
+                "produtos_mantidos": {
+                    "type": "integer"
+                },
+                "simulacao": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "models.ReenvioRequest": {
             "type": "object",
-            "required": [
-                "email",
-                "password"
-            ],
             "properties": {
-                "email": {
+                "desde": {
                     "type": "string"
                 },
-                "password": {
+                "status": {
                     "type": "string"
                 }
             }
         },
-        "handlers.SupabaseRegistroRequest": {
+        "models.ReordenarImagensRequest": {
             "type": "object",
             "required": [
-                "email",
-                "password"
+                "ids"
             ],
             "properties": {
-                "data": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "email": {
-                    "type": "string"
-                },
-                "password": {
-                    "type": "string",
-                    "minLength": 6
+                "ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "models.APIError": {
+        "models.ReservarProdutoRequest": {
             "type": "object",
             "properties": {
-                "details": {
+                "cliente": {
+                    "type": "string",
+                    "maxLength": 255
+                },
+                "cliente_id": {
                     "type": "string"
                 },
-                "message": {
+                "expira_em": {
                     "type": "string"
                 },
-                "type": {
-                    "$ref": "#/definitions/models.ErrorType"
-                }
-            }
-        },
-        "models.ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "$ref": "#/definitions/models.APIError"
+                "observacao": {
+                    "type": "string"
+                },
+                "validade_horas": {
+                    "type": "integer",
+                    "maximum": 720,
+                    "minimum": 1
                 }
             }
         },
-        "models.ErrorType": {
-            "type": "string",
-            "enum": [
-                "validation_error",
-                "authentication_error",
-                "authorization_error",
-                "not_found_error",
-                "conflict_error",
-                "internal_error",
-                "bad_request_error"
-            ],
-            "x-enum-varnames": [
-                "ErrorTypeValidation",
-                "ErrorTypeAuthentication",
-                "ErrorTypeAuthorization",
-                "ErrorTypeNotFound",
-                "ErrorTypeConflict",
-                "ErrorTypeInternal",
-                "ErrorTypeBadRequest"
-            ]
-        },
-        "models.EstatisticasProdutos": {
+        "models.ResultadoBuscaCavaletes": {
             "type": "object",
             "properties": {
-                "cavaletes_disponiveis": {
-                    "type": "integer"
+                "cavaletes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CavaleteEncontrado"
+                    }
                 },
-                "produtos_destaque": {
+                "facetas": {
+                    "$ref": "#/definitions/models.FacetasBusca"
+                },
+                "limit": {
                     "type": "integer"
                 },
-                "produtos_visiveis": {
+                "offset": {
                     "type": "integer"
                 },
-                "total_produtos": {
+                "total": {
                     "type": "integer"
                 }
             }
         },
-        "models.ImportRequest": {
+        "models.ResultadoBuscaProdutos": {
             "type": "object",
-            "required": [
-                "url"
-            ],
             "properties": {
-                "atualizar_existente": {
-                    "type": "boolean"
+                "facetas": {
+                    "$ref": "#/definitions/models.FacetasBusca"
                 },
-                "url": {
-                    "type": "string"
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "produtos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ProdutoEncontrado"
+                    }
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ImportResponse": {
+        "models.ResumoCliente": {
             "type": "object",
             "properties": {
-                "mensagem": {
-                    "type": "string"
+                "orcamentos": {
+                    "type": "integer"
                 },
-                "oferta_id": {
-                    "type": "string"
+                "orcamentos_aceitos": {
+                    "type": "integer"
                 },
-                "sucesso": {
-                    "type": "boolean"
+                "produtos_comprados": {
+                    "type": "integer"
                 },
-                "uuid_link": {
-                    "type": "string"
+                "produtos_reservados": {
+                    "type": "integer"
+                },
+                "valor_comprado": {
+                    "type": "number"
                 }
             }
         },
-        "models.ProdutoAprovado": {
+        "models.ResumoEstoquePainel": {
             "type": "object",
-            "required": [
-                "nome_customizado",
-                "preco_venda"
-            ],
             "properties": {
-                "cavalete_id": {
-                    "type": "string"
-                },
-                "created_at": {
-                    "type": "string"
+                "cavaletes_disponiveis": {
+                    "type": "integer"
                 },
-                "descricao": {
-                    "type": "string"
+                "cavaletes_importados": {
+                    "type": "integer"
                 },
-                "destaque": {
-                    "type": "boolean"
+                "metragem_aprovada_m2": {
+                    "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "metragem_importada_m2": {
+                    "type": "number"
                 },
-                "nome_customizado": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 1
+                "ofertas": {
+                    "type": "integer"
                 },
-                "ordem_exibicao": {
+                "ofertas_ativas": {
                     "type": "integer"
                 },
-                "preco_venda": {
+                "percentual_aprovado": {
                     "type": "number"
                 },
-                "trader_id": {
-                    "type": "string"
+                "produtos_sem_metragem": {
+                    "type": "integer"
                 },
-                "updated_at": {
-                    "type": "string"
+                "valor_estoque": {
+                    "type": "number"
                 },
-                "visivel": {
-                    "type": "boolean"
+                "valor_reservado": {
+                    "type": "number"
+                },
+                "valor_vendido": {
+                    "type": "number"
                 }
             }
         },
-        "models.ProdutoAprovarRequest": {
+        "models.ResumoProdutosPainel": {
             "type": "object",
-            "required": [
-                "cavalete_id",
-                "nome_customizado",
-                "preco_venda"
-            ],
             "properties": {
-                "cavalete_id": {
-                    "type": "string"
+                "destaque": {
+                    "type": "integer"
                 },
-                "descricao": {
-                    "type": "string"
+                "disponiveis": {
+                    "type": "integer"
                 },
-                "destaque": {
-                    "type": "boolean"
+                "reservados": {
+                    "type": "integer"
                 },
-                "nome_customizado": {
-                    "type": "string",
-                    "maxLength": 255,
-                    "minLength": 1
+                "total": {
+                    "type": "integer"
                 },
-                "preco_venda": {
-                    "type": "number"
+                "vendidos": {
+                    "type": "integer"
                 },
-                "visivel": {
-                    "type": "boolean"
+                "visiveis": {
+                    "type": "integer"
                 }
             }
         },
-        "models.ProdutoAtualizarRequest": {
+        "models.SinonimoMaterial": {
             "type": "object",
             "properties": {
-                "descricao": {
+                "id": {
                     "type": "string"
                 },
-                "destaque": {
-                    "type": "boolean"
-                },
-                "nome_customizado": {
+                "sinonimo": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SinonimoRequest": {
+            "type": "object",
+            "required": [
+                "sinonimo"
+            ],
+            "properties": {
+                "sinonimo": {
                     "type": "string",
                     "maxLength": 255,
-                    "minLength": 1
-                },
-                "ordem_exibicao": {
-                    "type": "integer"
-                },
-                "preco_venda": {
-                    "type": "number"
-                },
-                "visivel": {
-                    "type": "boolean"
+                    "minLength": 1,
+                    "example": "Siena Branco"
                 }
             }
         },
@@ -1203,6 +8596,88 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.TotaisOrcamento": {
+            "type": "object",
+            "properties": {
+                "desconto": {
+                    "type": "number"
+                },
+                "subtotal": {
+                    "type": "number"
+                },
+                "total": {
+                    "type": "number"
+                },
+                "total_m2": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.VenderProdutoRequest": {
+            "type": "object",
+            "properties": {
+                "cliente": {
+                    "type": "string",
+                    "maxLength": 255,
+                    "minLength": 1
+                },
+                "cliente_id": {
+                    "type": "string"
+                },
+                "valor_venda": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Verificacao": {
+            "type": "object",
+            "properties": {
+                "critica": {
+                    "type": "boolean"
+                },
+                "detalhes": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "erro": {
+                    "type": "string"
+                },
+                "latencia_ms": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.VinculacaoFornecedoresResultado": {
+            "type": "object",
+            "properties": {
+                "fornecedores_criados": {
+                    "type": "integer"
+                },
+                "ofertas": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.VitrineTrader": {
+            "type": "object",
+            "properties": {
+                "empresa": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "nome": {
+                    "type": "string"
+                },
+                "slug": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
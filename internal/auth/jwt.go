@@ -34,12 +34,16 @@ type UserContext struct {
 	Nome      string
 	Role      string
 	SessionID string
+	APIKeyID  string
 }
 
 type contextKey string
 
 const UserContextKey contextKey = "user"
 
+// RoleAdmin identifica usuários administradores (definido em app_metadata.role no Supabase)
+const RoleAdmin = "admin"
+
 // ParseSupabaseJWT valida um token JWT do Supabase
 func ParseSupabaseJWT(tokenString string) (*SupabaseClaims, error) {
 	jwtSecret := os.Getenv("SUPABASE_JWT_SECRET")
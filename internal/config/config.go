@@ -3,6 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"time"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/rastreamento"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -12,6 +16,8 @@ import (
 type Config struct {
 	// Servidor
 	Port string
+	// Prazo para concluir as requisições e importações em andamento ao encerrar
+	ShutdownTimeout time.Duration
 
 	// PostgreSQL Database
 	DBHost     string
@@ -31,6 +37,47 @@ type Config struct {
 
 	// Mobgran API
 	MobgranAPIURL string
+
+	// Armazenamento de arquivos (imagens espelhadas e miniaturas)
+	StorageDriver     string
+	StorageLocalDir   string
+	StoragePublicURL  string
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PathStyle       bool
+
+	// Espelhamento das imagens dos cavaletes durante a importação
+	EspelharImagens bool
+
+	// Reservas de produtos: validade padrão e intervalo do worker de expiração
+	ReservaValidadePadrao     time.Duration
+	ReservaIntervaloExpiracao time.Duration
+
+	// Notificações: servidor SMTP dos e-mails, intervalo do worker de entregas e
+	// antecedência do aviso de reserva perto de vencer
+	SMTPHost                string
+	SMTPPort                string
+	SMTPUsuario             string
+	SMTPSenha               string
+	SMTPRemetente           string
+	NotificacaoIntervalo    time.Duration
+	NotificacaoAvisoReserva time.Duration
+	// WebhookPermitirRedeInterna libera webhooks para loopback e redes privadas; só para
+	// desenvolvimento, em produção permite alcançar serviços internos (SSRF)
+	WebhookPermitirRedeInterna bool
+
+	// Eventos: por quanto tempo os eventos do stream ficam disponíveis para retomada
+	EventosRetencao time.Duration
+
+	// Métricas: token exigido em /metrics (vazio, sem autenticação)
+	MetricasToken string
+
+	// Tracing: exportador dos spans (otlp, stdout ou none); o destino do OTLP segue as
+	// variáveis padrão OTEL_EXPORTER_OTLP_*
+	TracesExportador string
 }
 
 // LoadConfig carrega a configuração da aplicação
@@ -53,6 +100,30 @@ func LoadConfig() (*Config, error) {
 		SupabaseServiceKey: getEnvOrDefault("SUPABASE_SERVICE_KEY", ""),
 		LogLevel:      getEnvOrDefault("LOG_LEVEL", "info"),
 		MobgranAPIURL: getEnvOrDefault("MOBGRAN_API_URL", "https://api.mobgran.com.br/api/v1/ofertas/"),
+		StorageDriver:     getEnvOrDefault("STORAGE_DRIVER", "local"),
+		StorageLocalDir:   getEnvOrDefault("STORAGE_LOCAL_DIR", "./data/midia"),
+		StoragePublicURL:  getEnvOrDefault("STORAGE_PUBLIC_URL", ""),
+		S3Endpoint:        getEnvOrDefault("S3_ENDPOINT", ""),
+		S3Region:          getEnvOrDefault("S3_REGION", "us-east-1"),
+		S3Bucket:          getEnvOrDefault("S3_BUCKET", ""),
+		S3AccessKeyID:     getEnvOrDefault("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey: getEnvOrDefault("S3_SECRET_ACCESS_KEY", ""),
+		S3PathStyle:       getEnvOrDefault("S3_PATH_STYLE", "false") == "true",
+		EspelharImagens:   getEnvOrDefault("ESPELHAR_IMAGENS", "true") == "true",
+		ReservaValidadePadrao:     getEnvDuration("RESERVA_VALIDADE_PADRAO", 72*time.Hour),
+		ReservaIntervaloExpiracao: getEnvDuration("RESERVA_INTERVALO_EXPIRACAO", time.Minute),
+		SMTPHost:                  getEnvOrDefault("SMTP_HOST", ""),
+		SMTPPort:                  getEnvOrDefault("SMTP_PORT", "587"),
+		SMTPUsuario:               getEnvOrDefault("SMTP_USUARIO", ""),
+		SMTPSenha:                 getEnvOrDefault("SMTP_SENHA", ""),
+		SMTPRemetente:             getEnvOrDefault("SMTP_REMETENTE", ""),
+		NotificacaoIntervalo:      getEnvDuration("NOTIFICACAO_INTERVALO", 15*time.Second),
+		NotificacaoAvisoReserva:   getEnvDuration("NOTIFICACAO_AVISO_RESERVA", 24*time.Hour),
+		WebhookPermitirRedeInterna: getEnvOrDefault("WEBHOOK_PERMITIR_REDE_INTERNA", "false") == "true",
+		EventosRetencao:           getEnvDuration("EVENTOS_RETENCAO", 7*24*time.Hour),
+		MetricasToken:             getEnvOrDefault("METRICAS_TOKEN", ""),
+		TracesExportador:          getEnvOrDefault("OTEL_TRACES_EXPORTER", "none"),
+		ShutdownTimeout:           getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
 	}
 
 	// Validar configurações obrigatórias do PostgreSQL
@@ -83,15 +154,39 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
-// SetupLogger configura o logger baseado no nível de log
+// getEnvDuration lê uma duração (ex.: "72h", "30s") da variável de ambiente, usando o
+// padrão quando ausente, inválida ou não positiva
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duracao, err := time.ParseDuration(value)
+	if err != nil || duracao <= 0 {
+		logrus.WithField("variavel", key).Warnf("Duração inválida, usando %s", defaultValue)
+		return defaultValue
+	}
+	return duracao
+}
+
+// SetupLogger configura o logger global da aplicação (JSON, nível, campos da
+// requisição, IDs do trace e remoção de credenciais) e o retorna, para que os
+// serviços que recebem o logger e os que usam o logrus global escrevam igual
 func SetupLogger(logLevel string) *logrus.Logger {
-	logger := logrus.New()
+	logger := logrus.StandardLogger()
 
 	// Configurar formato
 	logger.SetFormatter(&logrus.JSONFormatter{
 		TimestampFormat: "2006-01-02T15:04:05.000Z07:00",
 	})
 
+	// Campos do contexto primeiro; a redação por último, sobre todos os campos
+	logger.ReplaceHooks(logrus.LevelHooks{})
+	logger.AddHook(logs.HookContexto{})
+	logger.AddHook(rastreamento.HookLogs{})
+	logger.AddHook(logs.HookRedacao{})
+
 	// Configurar nível
 	level, err := logrus.ParseLevel(logLevel)
 	if err != nil {
@@ -101,4 +196,4 @@ func SetupLogger(logLevel string) *logrus.Logger {
 	logger.SetLevel(level)
 
 	return logger
-}
\ No newline at end of file
+}
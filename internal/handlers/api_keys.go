@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/middleware"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type APIKeysHandler struct {
+	apiKeyService *services.APIKeyService
+}
+
+func NewAPIKeysHandler(apiKeyService *services.APIKeyService) *APIKeysHandler {
+	return &APIKeysHandler{
+		apiKeyService: apiKeyService,
+	}
+}
+
+// @Summary Criar chave de API
+// @Description Cria uma chave de API para integrações (ERP, site). A chave é exibida apenas nesta resposta.
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param api_key body models.APIKeyCriarRequest true "Dados da chave"
+// @Success 201 {object} models.APIKeyCriadaResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api-keys [post]
+func (h *APIKeysHandler) CriarAPIKey(c *gin.Context) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return
+	}
+
+	// Converte userID string para UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
+		return
+	}
+
+	var req models.APIKeyCriarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	apiKey, err := h.apiKeyService.CriarAPIKey(c.Request.Context(), userID, &req)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao criar chave de API")
+		if strings.HasPrefix(err.Error(), "escopo inválido") || err.Error() == "data de expiração deve ser futura" {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, apiKey)
+}
+
+// @Summary Listar chaves de API
+// @Description Lista as chaves de API do trader, da mais recente para a mais antiga (o valor da chave não é retornado)
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api-keys [get]
+func (h *APIKeysHandler) ListarAPIKeys(c *gin.Context) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return
+	}
+
+	// Converte userID string para UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
+		return
+	}
+
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	apiKeys, pagina, err := h.apiKeyService.ListarAPIKeys(c.Request.Context(), userID, params)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar chaves de API")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"api_keys":  apiKeys,
+		"paginacao": pagina,
+	})
+}
+
+// @Summary Revogar chave de API
+// @Description Revoga uma chave de API do trader
+// @Tags api-keys
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da chave de API"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api-keys/{id} [delete]
+func (h *APIKeysHandler) RevogarAPIKey(c *gin.Context) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return
+	}
+
+	// Converte userID string para UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
+		return
+	}
+
+	apiKeyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID da chave de API inválido"})
+		return
+	}
+
+	err = h.apiKeyService.RevogarAPIKey(c.Request.Context(), userID, apiKeyID)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao revogar chave de API")
+		if err.Error() == "chave de API não encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"erro": "Chave de API não encontrada"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mensagem": "Chave de API revogada com sucesso"})
+}
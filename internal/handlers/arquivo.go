@@ -0,0 +1,174 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/middleware"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+type ArquivoHandler struct {
+	arquivoService *services.ArquivoService
+}
+
+func NewArquivoHandler(arquivoService *services.ArquivoService) *ArquivoHandler {
+	return &ArquivoHandler{
+		arquivoService: arquivoService,
+	}
+}
+
+// @Summary Arquivar ofertas
+// @Description Arquiva (soft delete) uma oferta do trader, ou todas quando oferta_id não é informado, junto com seus cavaletes e produtos
+// @Tags arquivo
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param escopo body models.ArquivoRequest false "Escopo do arquivamento"
+// @Success 200 {object} models.ArquivoResultado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /arquivo/arquivar [post]
+func (h *ArquivoHandler) Arquivar(c *gin.Context) {
+	userID, req, ok := h.parseArquivoRequest(c)
+	if !ok {
+		return
+	}
+
+	resultado, err := h.arquivoService.Arquivar(c.Request.Context(), userID, req.OfertaID)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao arquivar registros")
+		h.responderErro(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// @Summary Restaurar ofertas arquivadas
+// @Description Restaura uma oferta arquivada do trader, ou todas quando oferta_id não é informado, junto com os cavaletes e produtos arquivados com ela
+// @Tags arquivo
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param escopo body models.ArquivoRequest false "Escopo da restauração"
+// @Success 200 {object} models.ArquivoResultado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /arquivo/restaurar [post]
+func (h *ArquivoHandler) Restaurar(c *gin.Context) {
+	userID, req, ok := h.parseArquivoRequest(c)
+	if !ok {
+		return
+	}
+
+	resultado, err := h.arquivoService.Restaurar(c.Request.Context(), userID, req.OfertaID)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao restaurar registros")
+		h.responderErro(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// @Summary Purgar registros arquivados
+// @Description Remove definitivamente ofertas, cavaletes e produtos arquivados (apenas administradores). Exige confirmacao = "PURGAR DADOS ARQUIVADOS"; use simular = true para apenas contar. Produtos vendidos, reservados ou em orçamentos fora de rascunho são mantidos, com seus cavaletes e ofertas.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purga body models.PurgaRequest true "Confirmação e filtros da purga"
+// @Success 200 {object} models.PurgaResponse
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/purgar [post]
+func (h *ArquivoHandler) Purgar(c *gin.Context) {
+	userID, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return
+	}
+
+	var req models.PurgaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
+		"user_id":   userID,
+		"trader_id": req.TraderID,
+		"simular":   req.Simular,
+	}).Warn("Iniciando purga de registros arquivados")
+
+	resultado, err := h.arquivoService.Purgar(c.Request.Context(), &req)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao purgar registros")
+		if err.Error() == "confirmação inválida" {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"erro":                 "Confirmação inválida",
+				"confirmacao_esperada": models.ConfirmacaoPurga,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// parseArquivoRequest extrai o trader do contexto e o escopo opcional do corpo
+func (h *ArquivoHandler) parseArquivoRequest(c *gin.Context) (uuid.UUID, *models.ArquivoRequest, bool) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return uuid.Nil, nil, false
+	}
+
+	// Converte userID string para UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
+		return uuid.Nil, nil, false
+	}
+
+	// O corpo é opcional: sem ele, a operação se aplica a todas as ofertas do trader
+	var req models.ArquivoRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return uuid.Nil, nil, false
+	}
+
+	return userID, &req, true
+}
+
+// responderErro converte os erros do ArquivoService em respostas HTTP
+func (h *ArquivoHandler) responderErro(c *gin.Context, err error) {
+	switch err.Error() {
+	case "oferta não encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Oferta não encontrada"})
+	case "oferta já está arquivada", "oferta não está arquivada":
+		c.JSON(http.StatusConflict, gin.H{"erro": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditoriaHandler struct {
+	auditoriaService *services.AuditoriaService
+}
+
+func NewAuditoriaHandler(auditoriaService *services.AuditoriaService) *AuditoriaHandler {
+	return &AuditoriaHandler{
+		auditoriaService: auditoriaService,
+	}
+}
+
+// @Summary Consultar trilha de auditoria
+// @Description Lista operações que alteraram dados, com filtros e paginação (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param ator_id query string false "Filtrar por ator"
+// @Param acao query string false "Filtrar por ação (ex.: produto.atualizado)"
+// @Param entidade query string false "Filtrar por entidade (ex.: produto, oferta)"
+// @Param entidade_id query string false "Filtrar por ID da entidade"
+// @Param request_id query string false "Filtrar por ID da requisição"
+// @Param desde query string false "Data inicial (RFC3339)"
+// @Param ate query string false "Data final (RFC3339)"
+// @Param limit query int false "Limite de resultados" default(50)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/auditoria [get]
+func (h *AuditoriaHandler) ListarRegistros(c *gin.Context) {
+	filtro := models.FiltroAuditoria{
+		AtorID:     c.Query("ator_id"),
+		Acao:       c.Query("acao"),
+		Entidade:   c.Query("entidade"),
+		EntidadeID: c.Query("entidade_id"),
+		RequestID:  c.Query("request_id"),
+	}
+
+	params, ok := parsePaginacao(c, 50, 200)
+	if !ok {
+		return
+	}
+	filtro.Paginacao = *params
+
+	if desdeStr := c.Query("desde"); desdeStr != "" {
+		desde, err := time.Parse(time.RFC3339, desdeStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Parâmetro 'desde' inválido, use RFC3339"})
+			return
+		}
+		filtro.Desde = &desde
+	}
+
+	if ateStr := c.Query("ate"); ateStr != "" {
+		ate, err := time.Parse(time.RFC3339, ateStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Parâmetro 'ate' inválido, use RFC3339"})
+			return
+		}
+		filtro.Ate = &ate
+	}
+
+	registros, pagina, err := h.auditoriaService.ListarRegistros(c.Request.Context(), &filtro)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar registros de auditoria")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"registros": registros,
+		"paginacao": pagina,
+	})
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/middleware"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BuscaHandler struct {
+	buscaService *services.BuscaService
+}
+
+func NewBuscaHandler(buscaService *services.BuscaService) *BuscaHandler {
+	return &BuscaHandler{
+		buscaService: buscaService,
+	}
+}
+
+// @Summary Buscar cavaletes
+// @Description Busca textual (português, ignorando acentos) sobre material, classificação, acabamento, espessura, bloco e observações dos cavaletes do trader, com facetas por material, espessura e classificação
+// @Tags busca
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Termo de busca (ex.: branco siena polido)"
+// @Param material query string false "Filtrar por material"
+// @Param espessura query string false "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)"
+// @Param classificacao query string false "Filtrar por classificação"
+// @Param acabamento query string false "Filtrar por acabamento"
+// @Param fornecedor_id query string false "Filtrar por fornecedor"
+// @Param comprimento_min query number false "Comprimento mínimo em metros"
+// @Param comprimento_max query number false "Comprimento máximo em metros"
+// @Param altura_min query number false "Altura mínima em metros"
+// @Param altura_max query number false "Altura máxima em metros"
+// @Param metragem_min query number false "Metragem mínima em m²"
+// @Param metragem_max query number false "Metragem máxima em m²"
+// @Param disponiveis query bool false "Apenas cavaletes ainda não aprovados"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação (a busca não aceita cursor)" default(0)
+// @Success 200 {object} models.ResultadoBuscaCavaletes
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /busca/cavaletes [get]
+func (h *BuscaHandler) BuscarCavaletes(c *gin.Context) {
+	userID, filtro, ok := parseFiltroBusca(c)
+	if !ok {
+		return
+	}
+
+	if disponiveis, err := strconv.ParseBool(c.Query("disponiveis")); err == nil {
+		filtro.ApenasDisponiveis = disponiveis
+	}
+
+	resultado, err := h.buscaService.BuscarCavaletes(c.Request.Context(), userID, filtro)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao buscar cavaletes")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// @Summary Buscar produtos
+// @Description Busca textual sobre o nome e a descrição dos produtos aprovados do trader e sobre os dados dos seus cavaletes, com facetas por material, espessura e classificação
+// @Tags busca
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Termo de busca (ex.: branco siena polido)"
+// @Param material query string false "Filtrar por material"
+// @Param espessura query string false "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)"
+// @Param classificacao query string false "Filtrar por classificação"
+// @Param acabamento query string false "Filtrar por acabamento"
+// @Param fornecedor_id query string false "Filtrar por fornecedor"
+// @Param comprimento_min query number false "Comprimento mínimo em metros"
+// @Param comprimento_max query number false "Comprimento máximo em metros"
+// @Param altura_min query number false "Altura mínima em metros"
+// @Param altura_max query number false "Altura máxima em metros"
+// @Param metragem_min query number false "Metragem mínima em m²"
+// @Param metragem_max query number false "Metragem máxima em m²"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação (a busca não aceita cursor)" default(0)
+// @Success 200 {object} models.ResultadoBuscaProdutos
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /busca/produtos [get]
+func (h *BuscaHandler) BuscarProdutos(c *gin.Context) {
+	userID, filtro, ok := parseFiltroBusca(c)
+	if !ok {
+		return
+	}
+
+	resultado, err := h.buscaService.BuscarProdutos(c.Request.Context(), userID, filtro)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao buscar produtos")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// parseFiltroBusca extrai o trader do contexto e os filtros da busca da query string
+func parseFiltroBusca(c *gin.Context) (uuid.UUID, *models.FiltroBusca, bool) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return uuid.Nil, nil, false
+	}
+
+	// Converte userID string para UUID
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
+		return uuid.Nil, nil, false
+	}
+
+	// Com termo, a busca é ordenada por relevância, que o cursor de (created_at, id) não
+	// representa; por consistência, ela pagina sempre por offset
+	if c.Query("cursor") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Paginação por cursor não disponível na busca; use limit e offset"})
+		return uuid.Nil, nil, false
+	}
+
+	filtro := &models.FiltroBusca{
+		Termo:         strings.TrimSpace(c.Query("q")),
+		Material:      strings.TrimSpace(c.Query("material")),
+		Espessura:     strings.TrimSpace(c.Query("espessura")),
+		Classificacao: strings.TrimSpace(c.Query("classificacao")),
+		Acabamento:    strings.TrimSpace(c.Query("acabamento")),
+		Limit:         20,
+		Offset:        0,
+	}
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= 100 {
+		filtro.Limit = l
+	}
+
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		filtro.Offset = o
+	}
+
+	var ok bool
+	if filtro.FornecedorID, ok = uuidDaQuery(c, "fornecedor_id"); !ok {
+		return uuid.Nil, nil, false
+	}
+
+	ok = lerFaixasQuery(c, []faixaQuery{
+		{"comprimento_min", &filtro.ComprimentoMin},
+		{"comprimento_max", &filtro.ComprimentoMax},
+		{"altura_min", &filtro.AlturaMin},
+		{"altura_max", &filtro.AlturaMax},
+		{"metragem_min", &filtro.MetragemMin},
+		{"metragem_max", &filtro.MetragemMax},
+	})
+	if !ok {
+		return uuid.Nil, nil, false
+	}
+
+	return userID, filtro, true
+}
+
+// faixaQuery associa um parâmetro numérico opcional da query string ao campo do filtro
+type faixaQuery struct {
+	param   string
+	destino **float64
+}
+
+// lerFaixasQuery lê parâmetros numéricos opcionais, respondendo 400 em caso de valor inválido
+func lerFaixasQuery(c *gin.Context, faixas []faixaQuery) bool {
+	for _, faixa := range faixas {
+		valorStr := c.Query(faixa.param)
+		if valorStr == "" {
+			continue
+		}
+		valor, err := strconv.ParseFloat(valorStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": fmt.Sprintf("Parâmetro '%s' inválido", faixa.param)})
+			return false
+		}
+		*faixa.destino = &valor
+	}
+	return true
+}
@@ -0,0 +1,398 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CatalogoHandler struct {
+	catalogoService *services.CatalogoService
+}
+
+func NewCatalogoHandler(catalogoService *services.CatalogoService) *CatalogoHandler {
+	return &CatalogoHandler{
+		catalogoService: catalogoService,
+	}
+}
+
+// @Summary Listar materiais do catálogo
+// @Description Lista o catálogo curado de materiais com os sinônimos. A busca considera nome e sinônimos, ignorando acentos.
+// @Tags catalogo
+// @Produce json
+// @Security BearerAuth
+// @Param busca query string false "Trecho do nome ou sinônimo"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /catalogo/materiais [get]
+func (h *CatalogoHandler) ListarMateriais(c *gin.Context) {
+	materiais, err := h.catalogoService.ListarMateriais(c.Request.Context(), strings.TrimSpace(c.Query("busca")))
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar materiais do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"materiais": materiais})
+}
+
+// @Summary Buscar material do catálogo
+// @Description Retorna um material do catálogo com os sinônimos
+// @Tags catalogo
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do material"
+// @Success 200 {object} models.MaterialCatalogo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /catalogo/materiais/{id} [get]
+func (h *CatalogoHandler) BuscarMaterial(c *gin.Context) {
+	materialID, ok := uuidDaRota(c, "id", "ID do material inválido")
+	if !ok {
+		return
+	}
+
+	material, err := h.catalogoService.BuscarMaterial(c.Request.Context(), materialID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar material do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, material)
+}
+
+// @Summary Listar espessuras do catálogo
+// @Description Lista as espessuras padrão às quais as espessuras importadas são associadas
+// @Tags catalogo
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /catalogo/espessuras [get]
+func (h *CatalogoHandler) ListarEspessuras(c *gin.Context) {
+	espessuras, err := h.catalogoService.ListarEspessuras(c.Request.Context())
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar espessuras do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"espessuras": espessuras})
+}
+
+// @Summary Listar mapeamentos do trader
+// @Description Lista os nomes de material e espessura importados nos cavaletes do trader, com a quantidade de cavaletes e o item do catálogo associado. Com pendentes=true, retorna apenas os nomes ainda não confirmados pelo trader.
+// @Tags catalogo
+// @Produce json
+// @Security BearerAuth
+// @Param tipo query string false "Tipo do mapeamento" Enums(material, espessura)
+// @Param pendentes query bool false "Apenas nomes sem mapeamento confirmado"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /catalogo/mapeamentos [get]
+func (h *CatalogoHandler) ListarMapeamentos(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	filtro := &models.FiltroMapeamentos{
+		Tipo:      c.Query("tipo"),
+		Pendentes: c.Query("pendentes") == "true",
+	}
+	if filtro.Tipo != "" && filtro.Tipo != models.TipoMapeamentoMaterial && filtro.Tipo != models.TipoMapeamentoEspessura {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "tipo deve ser material ou espessura"})
+		return
+	}
+
+	mapeamentos, err := h.catalogoService.ListarMapeamentos(c.Request.Context(), userID, filtro)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar mapeamentos do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mapeamentos": mapeamentos})
+}
+
+// @Summary Definir mapeamento
+// @Description Confirma ou corrige o material ou a espessura do catálogo associado a um nome importado. Vale para todas as grafias equivalentes do nome, nos cavaletes existentes e nas próximas importações.
+// @Tags catalogo
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param mapeamento body models.DefinirMapeamentoRequest true "Nome importado e item do catálogo"
+// @Success 200 {object} models.MapeamentoDefinido
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /catalogo/mapeamentos [put]
+func (h *CatalogoHandler) DefinirMapeamento(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	var req models.DefinirMapeamentoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	resultado, err := h.catalogoService.DefinirMapeamento(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao definir mapeamento do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// @Summary Remover mapeamento
+// @Description Desfaz o mapeamento confirmado pelo trader; o nome volta a ser associado automaticamente pelo catálogo
+// @Tags catalogo
+// @Produce json
+// @Security BearerAuth
+// @Param tipo query string true "Tipo do mapeamento" Enums(material, espessura)
+// @Param valor_origem query string true "Nome importado"
+// @Success 200 {object} models.AplicacaoCatalogoResultado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /catalogo/mapeamentos [delete]
+func (h *CatalogoHandler) RemoverMapeamento(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	tipo := c.Query("tipo")
+	valorOrigem := strings.TrimSpace(c.Query("valor_origem"))
+	if (tipo != models.TipoMapeamentoMaterial && tipo != models.TipoMapeamentoEspessura) || valorOrigem == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Informe tipo (material ou espessura) e valor_origem"})
+		return
+	}
+
+	resultado, err := h.catalogoService.RemoverMapeamento(c.Request.Context(), userID, tipo, valorOrigem)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao remover mapeamento do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// @Summary Incluir material no catálogo
+// @Description Inclui um material com sinônimos no catálogo curado e reaplica o catálogo aos cavaletes (apenas administradores)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param material body models.MaterialCatalogoRequest true "Material"
+// @Success 201 {object} models.MaterialCatalogo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/catalogo/materiais [post]
+func (h *CatalogoHandler) CriarMaterial(c *gin.Context) {
+	var req models.MaterialCatalogoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	material, err := h.catalogoService.CriarMaterial(c.Request.Context(), &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao incluir material no catálogo")
+		return
+	}
+
+	c.JSON(http.StatusCreated, material)
+}
+
+// @Summary Atualizar material do catálogo
+// @Description Altera o nome ou o tipo de um material do catálogo (apenas administradores)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do material"
+// @Param material body models.MaterialCatalogoAtualizarRequest true "Campos a alterar"
+// @Success 200 {object} models.MaterialCatalogo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/catalogo/materiais/{id} [put]
+func (h *CatalogoHandler) AtualizarMaterial(c *gin.Context) {
+	materialID, ok := uuidDaRota(c, "id", "ID do material inválido")
+	if !ok {
+		return
+	}
+
+	var req models.MaterialCatalogoAtualizarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	material, err := h.catalogoService.AtualizarMaterial(c.Request.Context(), materialID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar material do catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, material)
+}
+
+// @Summary Remover material do catálogo
+// @Description Exclui o material com os sinônimos, desfazendo as associações dos cavaletes e os mapeamentos dos traders (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do material"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/catalogo/materiais/{id} [delete]
+func (h *CatalogoHandler) RemoverMaterial(c *gin.Context) {
+	materialID, ok := uuidDaRota(c, "id", "ID do material inválido")
+	if !ok {
+		return
+	}
+
+	if err := h.catalogoService.RemoverMaterial(c.Request.Context(), materialID); err != nil {
+		h.responderErro(c, err, "Erro ao remover material do catálogo")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Incluir sinônimo
+// @Description Inclui uma grafia alternativa do material, usada para associar os nomes importados (apenas administradores)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do material"
+// @Param sinonimo body models.SinonimoRequest true "Sinônimo"
+// @Success 200 {object} models.MaterialCatalogo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/catalogo/materiais/{id}/sinonimos [post]
+func (h *CatalogoHandler) AdicionarSinonimo(c *gin.Context) {
+	materialID, ok := uuidDaRota(c, "id", "ID do material inválido")
+	if !ok {
+		return
+	}
+
+	var req models.SinonimoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	material, err := h.catalogoService.AdicionarSinonimo(c.Request.Context(), materialID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao incluir sinônimo")
+		return
+	}
+
+	c.JSON(http.StatusOK, material)
+}
+
+// @Summary Remover sinônimo
+// @Description Exclui um sinônimo do material (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do material"
+// @Param sinonimoId path string true "ID do sinônimo"
+// @Success 200 {object} models.MaterialCatalogo
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/catalogo/materiais/{id}/sinonimos/{sinonimoId} [delete]
+func (h *CatalogoHandler) RemoverSinonimo(c *gin.Context) {
+	materialID, ok := uuidDaRota(c, "id", "ID do material inválido")
+	if !ok {
+		return
+	}
+	sinonimoID, ok := uuidDaRota(c, "sinonimoId", "ID do sinônimo inválido")
+	if !ok {
+		return
+	}
+
+	material, err := h.catalogoService.RemoverSinonimo(c.Request.Context(), materialID, sinonimoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao remover sinônimo")
+		return
+	}
+
+	c.JSON(http.StatusOK, material)
+}
+
+// @Summary Reaplicar catálogo
+// @Description Recalcula a associação de todos os cavaletes ao catálogo e aos mapeamentos dos traders (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AplicacaoCatalogoResultado
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/catalogo/reaplicar [post]
+func (h *CatalogoHandler) Reaplicar(c *gin.Context) {
+	resultado, err := h.catalogoService.Reaplicar(c.Request.Context())
+	if err != nil {
+		h.responderErro(c, err, "Erro ao reaplicar catálogo")
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// responderErro traduz os erros do CatalogoService para o status HTTP correspondente
+func (h *CatalogoHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "material não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Material não encontrado"})
+	case err.Error() == "sinônimo não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Sinônimo não encontrado"})
+	case err.Error() == "mapeamento não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Mapeamento não encontrado"})
+	case strings.HasPrefix(err.Error(), "nome já cadastrado no catálogo"):
+		c.JSON(http.StatusConflict, gin.H{"erro": err.Error()})
+	case err.Error() == "item do catálogo não encontrado",
+		err.Error() == "nenhum campo para atualizar",
+		err.Error() == "valor_origem inválido",
+		strings.HasPrefix(err.Error(), "nome inválido"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
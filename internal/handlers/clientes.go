@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ClientesHandler struct {
+	clientesService *services.ClientesService
+}
+
+func NewClientesHandler(clientesService *services.ClientesService) *ClientesHandler {
+	return &ClientesHandler{
+		clientesService: clientesService,
+	}
+}
+
+// @Summary Cadastrar cliente
+// @Description Cadastra um comprador com CPF ou CNPJ (validados pelos dígitos verificadores), endereço e pessoas de contato
+// @Tags clientes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param cliente body models.ClienteCriarRequest true "Dados do cliente"
+// @Success 201 {object} models.Cliente
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clientes [post]
+func (h *ClientesHandler) Criar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	var req models.ClienteCriarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	cliente, err := h.clientesService.Criar(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao cadastrar cliente")
+		return
+	}
+
+	c.JSON(http.StatusCreated, cliente)
+}
+
+// @Summary Listar clientes
+// @Description Lista os clientes do trader, do mais recente para o mais antigo. A busca considera nome, e-mail e início do documento.
+// @Tags clientes
+// @Produce json
+// @Security BearerAuth
+// @Param busca query string false "Parte do nome, e-mail ou documento"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clientes [get]
+func (h *ClientesHandler) Listar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	filtro := models.FiltroClientes{
+		Busca: strings.TrimSpace(c.Query("busca")),
+	}
+
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
+	}
+	filtro.Paginacao = *params
+
+	clientes, pagina, err := h.clientesService.Listar(c.Request.Context(), userID, &filtro)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar clientes")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"clientes":  clientes,
+		"paginacao": pagina,
+	})
+}
+
+// @Summary Buscar cliente
+// @Description Retorna o cadastro do cliente com endereço e contatos
+// @Tags clientes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cliente"
+// @Success 200 {object} models.Cliente
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clientes/{id} [get]
+func (h *ClientesHandler) Buscar(c *gin.Context) {
+	userID, clienteID, ok := traderEClienteDaRota(c)
+	if !ok {
+		return
+	}
+
+	cliente, err := h.clientesService.Buscar(c.Request.Context(), userID, clienteID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, cliente)
+}
+
+// @Summary Atualizar cliente
+// @Description Altera o cadastro do cliente. Campos ausentes são mantidos; contatos, quando enviados, substituem a lista inteira.
+// @Tags clientes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cliente"
+// @Param cliente body models.ClienteAtualizarRequest true "Dados para atualização"
+// @Success 200 {object} models.Cliente
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clientes/{id} [put]
+func (h *ClientesHandler) Atualizar(c *gin.Context) {
+	userID, clienteID, ok := traderEClienteDaRota(c)
+	if !ok {
+		return
+	}
+
+	var req models.ClienteAtualizarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	cliente, err := h.clientesService.Atualizar(c.Request.Context(), userID, clienteID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, cliente)
+}
+
+// @Summary Remover cliente
+// @Description Exclui o cadastro do cliente. Orçamentos e produtos vinculados mantêm o nome do cliente, sem o vínculo com o cadastro.
+// @Tags clientes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cliente"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clientes/{id} [delete]
+func (h *ClientesHandler) Remover(c *gin.Context) {
+	userID, clienteID, ok := traderEClienteDaRota(c)
+	if !ok {
+		return
+	}
+
+	if err := h.clientesService.Remover(c.Request.Context(), userID, clienteID); err != nil {
+		h.responderErro(c, err, "Erro ao remover cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mensagem": "Cliente removido com sucesso"})
+}
+
+// @Summary Histórico do cliente
+// @Description Retorna o resumo do relacionamento, os orçamentos e os produtos reservados ou comprados pelo cliente
+// @Tags clientes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do cliente"
+// @Success 200 {object} models.HistoricoCliente
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /clientes/{id}/historico [get]
+func (h *ClientesHandler) Historico(c *gin.Context) {
+	userID, clienteID, ok := traderEClienteDaRota(c)
+	if !ok {
+		return
+	}
+
+	historico, err := h.clientesService.Historico(c.Request.Context(), userID, clienteID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar histórico do cliente")
+		return
+	}
+
+	c.JSON(http.StatusOK, historico)
+}
+
+// responderErro traduz os erros do ClientesService para o status HTTP correspondente
+func (h *ClientesHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "cliente não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Cliente não encontrado"})
+	case err.Error() == "já existe um cliente com este documento":
+		c.JSON(http.StatusConflict, gin.H{"erro": err.Error()})
+	case err.Error() == "nenhum campo para atualizar",
+		err.Error() == "e-mail inválido",
+		strings.HasPrefix(err.Error(), "documento inválido"),
+		strings.HasPrefix(err.Error(), "endereço inválido"),
+		strings.HasPrefix(err.Error(), "contatos inválidos"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
+
+// traderEClienteDaRota obtém o trader autenticado e o cliente do parâmetro :id
+func traderEClienteDaRota(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	clienteID, ok := uuidDaRota(c, "id", "ID do cliente inválido")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, clienteID, true
+}
@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"mobgran-importer-go/internal/auth"
+	"mobgran-importer-go/internal/middleware"
 	"mobgran-importer-go/internal/models"
 	"net/http"
 )
@@ -69,4 +71,61 @@ func RequireRole(requiredRole string) gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// traderDoContexto obtém o ID do trader autenticado, respondendo o erro quando ausente ou inválido
+func traderDoContexto(c *gin.Context) (uuid.UUID, bool) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
+		return uuid.Nil, false
+	}
+
+	return userID, true
+}
+
+// uuidDaRota lê um parâmetro UUID da rota, respondendo 400 com a mensagem informada quando inválido
+func uuidDaRota(c *gin.Context, param, mensagem string) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": mensagem})
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// uuidDaQuery lê o UUID opcional do parâmetro da query string, respondendo 400 quando
+// o valor é inválido
+func uuidDaQuery(c *gin.Context, param string) (*uuid.UUID, bool) {
+	valor := c.Query(param)
+	if valor == "" {
+		return nil, true
+	}
+	id, err := uuid.Parse(valor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": param + " inválido"})
+		return nil, false
+	}
+	return &id, true
+}
+
+// traderEProdutoDaRota obtém o trader autenticado e o produto do parâmetro :id
+func traderEProdutoDaRota(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	produtoID, ok := uuidDaRota(c, "id", "ID do produto inválido")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, produtoID, true
+}
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type EstatisticasHandler struct {
+	estatisticasService *services.EstatisticasService
+}
+
+func NewEstatisticasHandler(estatisticasService *services.EstatisticasService) *EstatisticasHandler {
+	return &EstatisticasHandler{
+		estatisticasService: estatisticasService,
+	}
+}
+
+// @Summary Painel de estatísticas do trader
+// @Description Estoque importado e aprovado (m²), valor do estoque pelo preço de venda por m², distribuição por material e por fornecedor e série de importações. O período considera a última importação dos cavaletes, a aprovação dos produtos e a data das vendas.
+// @Tags estatisticas
+// @Produce json
+// @Security BearerAuth
+// @Param desde query string false "Data inicial (RFC3339 ou AAAA-MM-DD)"
+// @Param ate query string false "Data final (RFC3339 ou AAAA-MM-DD, inclusive)"
+// @Param agrupamento query string false "Agrupamento da série de importações (dia, semana, mes)" default(mes)
+// @Success 200 {object} models.EstatisticasTrader
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /estatisticas [get]
+func (h *EstatisticasHandler) Obter(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	filtro := models.FiltroEstatisticas{
+		Agrupamento: strings.TrimSpace(c.Query("agrupamento")),
+	}
+	if filtro.Desde, ok = dataDaQuery(c, "desde", false); !ok {
+		return
+	}
+	if filtro.Ate, ok = dataDaQuery(c, "ate", true); !ok {
+		return
+	}
+
+	estatisticas, err := h.estatisticasService.Obter(c.Request.Context(), userID, &filtro)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao obter estatísticas")
+		return
+	}
+
+	c.JSON(http.StatusOK, estatisticas)
+}
+
+// dataDaQuery lê uma data em RFC3339 ou AAAA-MM-DD; datas sem horário valem desde o
+// início do dia ou, com fimDoDia, até o último instante dele. Responde 400 quando inválida.
+func dataDaQuery(c *gin.Context, param string, fimDoDia bool) (*time.Time, bool) {
+	valor := strings.TrimSpace(c.Query(param))
+	if valor == "" {
+		return nil, true
+	}
+
+	if data, err := time.Parse(time.RFC3339, valor); err == nil {
+		return &data, true
+	}
+
+	data, err := time.Parse("2006-01-02", valor)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Parâmetro '" + param + "' inválido, use RFC3339 ou AAAA-MM-DD"})
+		return nil, false
+	}
+	if fimDoDia {
+		data = data.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &data, true
+}
+
+// responderErro traduz os erros do EstatisticasService para o status HTTP correspondente
+func (h *EstatisticasHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case strings.HasPrefix(err.Error(), "agrupamento inválido"),
+		strings.HasPrefix(err.Error(), "período inválido"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
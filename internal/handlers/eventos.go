@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventosPorLote é o máximo de eventos lidos por consulta do stream
+	eventosPorLote = 100
+	// eventosConsultaPeriodica garante a entrega mesmo sem o aviso do LISTEN/NOTIFY
+	eventosConsultaPeriodica = 5 * time.Second
+	// eventosKeepalive mantém a conexão aberta através de proxies com timeout de inatividade
+	eventosKeepalive = 25 * time.Second
+)
+
+type EventosHandler struct {
+	eventosService *services.EventosService
+}
+
+func NewEventosHandler(eventosService *services.EventosService) *EventosHandler {
+	return &EventosHandler{
+		eventosService: eventosService,
+	}
+}
+
+// @Summary Listar eventos
+// @Description Lista os eventos de alteração de dados do trader (importações e produtos) posteriores ao cursor desde_id, em ordem. Use o ultimo_id da resposta como próximo cursor.
+// @Tags eventos
+// @Produce json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param desde_id query int false "Retorna apenas eventos com id maior que este"
+// @Param limit query int false "Máximo de eventos (padrão 100, máximo 500)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /eventos [get]
+func (h *EventosHandler) Listar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	desdeID, ok := cursorDeEventos(c, c.Query("desde_id"))
+	if !ok {
+		return
+	}
+
+	params, ok := parsePaginacao(c, eventosPorLote, 500)
+	if !ok {
+		return
+	}
+
+	eventos, err := h.eventosService.Listar(c.Request.Context(), userID, desdeID, params.Limit)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar eventos")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	ultimoID := desdeID
+	if len(eventos) > 0 {
+		ultimoID = eventos[len(eventos)-1].ID
+	}
+
+	c.JSON(http.StatusOK, gin.H{"eventos": eventos, "ultimo_id": ultimoID})
+}
+
+// @Summary Stream de eventos
+// @Description Abre um stream Server-Sent Events com as alterações de dados do trader (importacao.*, oferta.importada, produto.*, cavalete.removido). Cada mensagem traz o id do evento, o tipo em event e o evento em data. A retomada usa o cabeçalho Last-Event-ID ou desde_id; sem cursor, apenas eventos novos são enviados.
+// @Tags eventos
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param desde_id query int false "Envia os eventos com id maior que este antes dos novos"
+// @Param Last-Event-ID header string false "Último evento recebido (reconexão)"
+// @Success 200 {string} string "Stream de eventos"
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /eventos/stream [get]
+func (h *EventosHandler) Stream(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	cursor := c.GetHeader("Last-Event-ID")
+	if cursor == "" {
+		cursor = c.Query("desde_id")
+	}
+	ultimoID, ok := cursorDeEventos(c, cursor)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+
+	// Registrar antes de ler o último id para não perder o aviso de um evento gravado entre os dois
+	sinal, encerrar := h.eventosService.Ouvir(userID)
+	defer encerrar()
+
+	if cursor == "" {
+		var err error
+		if ultimoID, err = h.eventosService.UltimoID(ctx, userID); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao iniciar stream de eventos")
+			c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+			return
+		}
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", (3 * time.Second).Milliseconds())
+	c.Writer.Flush()
+
+	consulta := time.NewTicker(eventosConsultaPeriodica)
+	defer consulta.Stop()
+	keepalive := time.NewTicker(eventosKeepalive)
+	defer keepalive.Stop()
+
+	for {
+		// Esvazia os eventos pendentes em lotes antes de esperar o próximo aviso
+		for {
+			eventos, err := h.eventosService.Listar(ctx, userID, ultimoID, eventosPorLote)
+			if err != nil {
+				if ctx.Err() == nil {
+					logs.Do(ctx).WithError(err).Warn("Erro ao ler eventos do stream; nova tentativa na próxima consulta")
+				}
+				break
+			}
+
+			for _, evento := range eventos {
+				corpo, err := json.Marshal(evento)
+				if err != nil {
+					logs.Do(ctx).WithError(err).WithField("evento_id", evento.ID).Error("Erro ao serializar evento do stream")
+					continue
+				}
+				fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", evento.ID, evento.Tipo, corpo)
+				ultimoID = evento.ID
+			}
+			if len(eventos) > 0 {
+				c.Writer.Flush()
+			}
+			if len(eventos) < eventosPorLote {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.eventosService.Encerrando():
+			return
+		case <-sinal:
+		case <-consulta.C:
+		case <-keepalive.C:
+			fmt.Fprint(c.Writer, ": keepalive\n\n")
+			c.Writer.Flush()
+		}
+	}
+}
+
+// cursorDeEventos interpreta o id do último evento recebido; vazio equivale a 0
+func cursorDeEventos(c *gin.Context, valor string) (int64, bool) {
+	if valor == "" {
+		return 0, true
+	}
+	id, err := strconv.ParseInt(valor, 10, 64)
+	if err != nil || id < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "desde_id inválido"})
+		return 0, false
+	}
+	return id, true
+}
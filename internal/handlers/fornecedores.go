@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FornecedoresHandler struct {
+	fornecedoresService *services.FornecedoresService
+}
+
+func NewFornecedoresHandler(fornecedoresService *services.FornecedoresService) *FornecedoresHandler {
+	return &FornecedoresHandler{
+		fornecedoresService: fornecedoresService,
+	}
+}
+
+// @Summary Listar fornecedores
+// @Description Lista os fornecedores das ofertas importadas pelo trader, com ofertas, cavaletes, m² importados e data da última importação. A busca considera o nome e as grafias conhecidas.
+// @Tags fornecedores
+// @Produce json
+// @Security BearerAuth
+// @Param busca query string false "Trecho do nome do fornecedor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /fornecedores [get]
+func (h *FornecedoresHandler) Listar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	fornecedores, err := h.fornecedoresService.Listar(c.Request.Context(), &userID, strings.TrimSpace(c.Query("busca")))
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar fornecedores")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fornecedores": fornecedores})
+}
+
+// @Summary Buscar fornecedor
+// @Description Retorna o fornecedor com os dados de contato e as estatísticas das ofertas do trader
+// @Tags fornecedores
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do fornecedor"
+// @Success 200 {object} models.Fornecedor
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /fornecedores/{id} [get]
+func (h *FornecedoresHandler) Buscar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	fornecedorID, ok := uuidDaRota(c, "id", "ID do fornecedor inválido")
+	if !ok {
+		return
+	}
+
+	fornecedor, err := h.fornecedoresService.Buscar(c.Request.Context(), &userID, fornecedorID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar fornecedor")
+		return
+	}
+
+	c.JSON(http.StatusOK, fornecedor)
+}
+
+// @Summary Listar todos os fornecedores
+// @Description Lista todos os fornecedores cadastrados, com as estatísticas das ofertas de todos os traders (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param busca query string false "Trecho do nome do fornecedor"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/fornecedores [get]
+func (h *FornecedoresHandler) ListarTodos(c *gin.Context) {
+	fornecedores, err := h.fornecedoresService.Listar(c.Request.Context(), nil, strings.TrimSpace(c.Query("busca")))
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar fornecedores")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"fornecedores": fornecedores})
+}
+
+// @Summary Atualizar fornecedor
+// @Description Altera nome, logotipo e dados de contato do fornecedor. Campos ausentes são mantidos; campos vazios são apagados (apenas administradores).
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do fornecedor"
+// @Param fornecedor body models.FornecedorAtualizarRequest true "Campos a alterar"
+// @Success 200 {object} models.Fornecedor
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/fornecedores/{id} [put]
+func (h *FornecedoresHandler) Atualizar(c *gin.Context) {
+	fornecedorID, ok := uuidDaRota(c, "id", "ID do fornecedor inválido")
+	if !ok {
+		return
+	}
+
+	var req models.FornecedorAtualizarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	fornecedor, err := h.fornecedoresService.Atualizar(c.Request.Context(), fornecedorID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar fornecedor")
+		return
+	}
+
+	c.JSON(http.StatusOK, fornecedor)
+}
+
+// @Summary Mesclar fornecedores
+// @Description Incorpora fornecedores duplicados ao fornecedor da rota: ofertas e grafias do nome passam para ele e os duplicados são excluídos (apenas administradores)
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do fornecedor mantido"
+// @Param mescla body models.MesclarFornecedoresRequest true "Fornecedores duplicados"
+// @Success 200 {object} models.Fornecedor
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/fornecedores/{id}/mesclar [post]
+func (h *FornecedoresHandler) Mesclar(c *gin.Context) {
+	fornecedorID, ok := uuidDaRota(c, "id", "ID do fornecedor inválido")
+	if !ok {
+		return
+	}
+
+	var req models.MesclarFornecedoresRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	fornecedor, err := h.fornecedoresService.Mesclar(c.Request.Context(), fornecedorID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao mesclar fornecedores")
+		return
+	}
+
+	c.JSON(http.StatusOK, fornecedor)
+}
+
+// @Summary Vincular ofertas pendentes
+// @Description Vincula aos fornecedores as ofertas importadas antes do cadastro de fornecedores, sem aguardar o processamento em segundo plano (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param limite query int false "Quantidade máxima de ofertas processadas" default(500)
+// @Success 200 {object} models.VinculacaoFornecedoresResultado
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/fornecedores/vincular [post]
+func (h *FornecedoresHandler) VincularPendentes(c *gin.Context) {
+	limite := 500
+	if l, err := strconv.Atoi(c.Query("limite")); err == nil && l > 0 && l <= 5000 {
+		limite = l
+	}
+
+	resultado, err := h.fornecedoresService.VincularPendentes(c.Request.Context(), limite)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao vincular ofertas aos fornecedores")
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// responderErro traduz os erros do FornecedoresService para o status HTTP correspondente
+func (h *FornecedoresHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "fornecedor não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Fornecedor não encontrado"})
+	case err.Error() == "nenhum campo para atualizar",
+		err.Error() == "e-mail inválido",
+		err.Error() == "UF inválida",
+		err.Error() == "informe fornecedores diferentes do fornecedor mantido",
+		strings.HasPrefix(err.Error(), "documento inválido"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
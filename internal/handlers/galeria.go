@@ -0,0 +1,268 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type GaleriaHandler struct {
+	galeriaService *services.GaleriaService
+}
+
+func NewGaleriaHandler(galeriaService *services.GaleriaService) *GaleriaHandler {
+	return &GaleriaHandler{
+		galeriaService: galeriaService,
+	}
+}
+
+// @Summary Enviar imagens do produto
+// @Description Envia uma ou mais imagens (JPEG, PNG ou WebP, até 10 MB e 40 megapixels cada) para a galeria do produto. As imagens entram no final da galeria e a primeira imagem de um produto sem capa vira a capa. Limite de 20 imagens por produto.
+// @Tags produtos
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Param imagens formData file true "Arquivos de imagem (campo repetível)"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/imagens [post]
+func (h *GaleriaHandler) AdicionarImagens(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	limitarCorpo(c, models.MaxImagensPorProduto*models.TamanhoMaximoImagemProduto)
+	form, err := c.MultipartForm()
+	if corpoExcedido(err) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"erro": fmt.Sprintf("Envio excede o limite de %d imagens de %d MB", models.MaxImagensPorProduto, models.TamanhoMaximoImagemProduto>>20)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Envie as imagens como multipart/form-data no campo 'imagens'"})
+		return
+	}
+
+	cabecalhos := append(form.File["imagens"], form.File["imagem"]...)
+	if len(cabecalhos) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Nenhuma imagem enviada no campo 'imagens'"})
+		return
+	}
+	if len(cabecalhos) > models.MaxImagensPorProduto {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": fmt.Sprintf("Envie no máximo %d imagens por vez", models.MaxImagensPorProduto)})
+		return
+	}
+
+	arquivos := make([]models.ArquivoImagem, 0, len(cabecalhos))
+	for _, cabecalho := range cabecalhos {
+		arquivo, err := lerArquivoImagem(cabecalho)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+			return
+		}
+		arquivos = append(arquivos, arquivo)
+	}
+
+	imagens, err := h.galeriaService.AdicionarImagens(c.Request.Context(), userID, produtoID, arquivos)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao adicionar imagens")
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"imagens": imagens})
+}
+
+// @Summary Listar imagens do produto
+// @Description Lista as imagens da galeria do produto na ordem de exibição
+// @Tags produtos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/imagens [get]
+func (h *GaleriaHandler) ListarImagens(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	imagens, err := h.galeriaService.ListarImagens(c.Request.Context(), userID, produtoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar imagens")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"imagens": imagens})
+}
+
+// @Summary Reordenar imagens do produto
+// @Description Define a ordem de exibição da galeria. A lista deve conter todas as imagens do produto, uma única vez cada.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Param ordem body models.ReordenarImagensRequest true "IDs das imagens na nova ordem"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/imagens/ordem [put]
+func (h *GaleriaHandler) ReordenarImagens(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReordenarImagensRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	if err := h.galeriaService.ReordenarImagens(c.Request.Context(), userID, produtoID, req.IDs); err != nil {
+		h.responderErro(c, err, "Erro ao reordenar imagens")
+		return
+	}
+
+	h.ListarImagens(c)
+}
+
+// @Summary Definir capa do produto
+// @Description Define a imagem exibida como principal do produto na vitrine
+// @Tags produtos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Param imagemId path string true "ID da imagem"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/imagens/{imagemId}/capa [put]
+func (h *GaleriaHandler) DefinirCapa(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	imagemID, err := uuid.Parse(c.Param("imagemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID da imagem inválido"})
+		return
+	}
+
+	if err := h.galeriaService.DefinirCapa(c.Request.Context(), userID, produtoID, imagemID); err != nil {
+		h.responderErro(c, err, "Erro ao definir capa")
+		return
+	}
+
+	h.ListarImagens(c)
+}
+
+// @Summary Remover imagem do produto
+// @Description Remove a imagem da galeria e seus arquivos. Se era a capa, a próxima imagem da galeria assume a capa.
+// @Tags produtos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Param imagemId path string true "ID da imagem"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/imagens/{imagemId} [delete]
+func (h *GaleriaHandler) RemoverImagem(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	imagemID, err := uuid.Parse(c.Param("imagemId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID da imagem inválido"})
+		return
+	}
+
+	if err := h.galeriaService.RemoverImagem(c.Request.Context(), userID, produtoID, imagemID); err != nil {
+		h.responderErro(c, err, "Erro ao remover imagem")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mensagem": "Imagem removida com sucesso"})
+}
+
+// responderErro traduz os erros do GaleriaService para o status HTTP correspondente
+func (h *GaleriaHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "produto não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
+	case err.Error() == "imagem não encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Imagem não encontrada"})
+	case strings.HasPrefix(err.Error(), "imagem inválida"),
+		strings.HasPrefix(err.Error(), "limite de"),
+		strings.HasPrefix(err.Error(), "a lista deve conter"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
+
+// folgaMultipart cobre os cabeçalhos e delimitadores do multipart além dos arquivos
+const folgaMultipart = 1 << 20
+
+// limitarCorpo limita o corpo da requisição antes do parse do multipart, para que um
+// envio acima do permitido seja recusado sem ser lido nem gravado em disco por inteiro
+func limitarCorpo(c *gin.Context, limite int64) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limite+folgaMultipart)
+}
+
+// corpoExcedido verifica se o erro veio do limite aplicado por limitarCorpo
+func corpoExcedido(err error) bool {
+	var excedido *http.MaxBytesError
+	return errors.As(err, &excedido)
+}
+
+// lerArquivoImagem lê o arquivo enviado, recusando os que excedem o tamanho máximo
+// sem carregá-los inteiros em memória
+func lerArquivoImagem(cabecalho *multipart.FileHeader) (models.ArquivoImagem, error) {
+	if cabecalho.Size > models.TamanhoMaximoImagemProduto {
+		return models.ArquivoImagem{}, fmt.Errorf("imagem inválida: arquivo %q excede %d MB", cabecalho.Filename, models.TamanhoMaximoImagemProduto>>20)
+	}
+
+	arquivo, err := cabecalho.Open()
+	if err != nil {
+		return models.ArquivoImagem{}, fmt.Errorf("não foi possível ler o arquivo %q", cabecalho.Filename)
+	}
+	defer arquivo.Close()
+
+	dados, err := io.ReadAll(io.LimitReader(arquivo, models.TamanhoMaximoImagemProduto+1))
+	if err != nil {
+		return models.ArquivoImagem{}, fmt.Errorf("não foi possível ler o arquivo %q", cabecalho.Filename)
+	}
+
+	return models.ArquivoImagem{Nome: cabecalho.Filename, Dados: dados}, nil
+}
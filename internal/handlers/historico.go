@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HistoricoHandler struct {
+	historicoService *services.HistoricoService
+}
+
+func NewHistoricoHandler(historicoService *services.HistoricoService) *HistoricoHandler {
+	return &HistoricoHandler{
+		historicoService: historicoService,
+	}
+}
+
+// @Summary Histórico do produto
+// @Description Linha do tempo do produto aprovado: alterações de preço de venda e importações da oferta em que o cavalete entrou, saiu ou mudou de metragem ou de quantidade de chapas, em ordem cronológica
+// @Tags produtos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Success 200 {object} models.HistoricoProduto
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/historico [get]
+func (h *HistoricoHandler) HistoricoProduto(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	historico, err := h.historicoService.HistoricoProduto(c.Request.Context(), userID, produtoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar histórico do produto")
+		return
+	}
+
+	c.JSON(http.StatusOK, historico)
+}
+
+// @Summary Listar importações da oferta
+// @Description Lista as importações da oferta, da mais recente para a mais antiga, com os totais do estoque e os cavaletes adicionados, removidos e alterados em relação à importação anterior
+// @Tags ofertas
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da oferta"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /ofertas/{id}/importacoes [get]
+func (h *HistoricoHandler) ListarImportacoes(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	ofertaID, ok := uuidDaRota(c, "id", "ID da oferta inválido")
+	if !ok {
+		return
+	}
+
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
+	}
+
+	importacoes, pagina, err := h.historicoService.ListarImportacoes(c.Request.Context(), userID, ofertaID, params)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar importações da oferta")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"importacoes": importacoes,
+		"paginacao":   pagina,
+	})
+}
+
+// responderErro traduz os erros do HistoricoService para o status HTTP correspondente
+func (h *HistoricoHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch err.Error() {
+	case "produto não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
+	case "oferta não encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Oferta não encontrada"})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImagensHandler struct {
+	imagemService *services.ImagemService
+}
+
+func NewImagensHandler(imagemService *services.ImagemService) *ImagensHandler {
+	return &ImagensHandler{
+		imagemService: imagemService,
+	}
+}
+
+// @Summary Espelhar imagens pendentes
+// @Description Copia para o armazenamento próprio as imagens de cavaletes já importados que ainda apontam para o Mobgran, gerando as miniaturas (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param limite query int false "Quantidade máxima de cavaletes processados" default(100)
+// @Success 200 {object} models.EspelhamentoResultado
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/imagens/espelhar [post]
+func (h *ImagensHandler) EspelharPendentes(c *gin.Context) {
+	limite := 100
+	if l, err := strconv.Atoi(c.Query("limite")); err == nil && l > 0 && l <= 1000 {
+		limite = l
+	}
+
+	resultado, err := h.imagemService.EspelharPendentes(c.Request.Context(), limite)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao espelhar imagens pendentes")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
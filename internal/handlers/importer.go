@@ -3,10 +3,11 @@ package handlers
 import (
 	"fmt"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/middleware"
 	"mobgran-importer-go/internal/models"
 	"mobgran-importer-go/internal/services"
 )
@@ -31,17 +32,25 @@ func NewImporterHandler(importerService *services.MobgranImporter, logger *logru
 // @Tags importacao
 // @Accept json
 // @Produce json
+// @Security BearerAuth
 // @Param request body models.ImportRequest true "Dados da importação"
 // @Success 200 {object} models.ImportResponse
 // @Failure 400 {object} models.ImportResponse
+// @Failure 401 {object} map[string]interface{}
 // @Failure 500 {object} models.ImportResponse
 // @Router /api/importar [post]
 func (h *ImporterHandler) ImportarOferta(c *gin.Context) {
+	userID, _, _, err := middleware.GetSupabaseUserFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
+		return
+	}
+
 	var request models.ImportRequest
 
 	// Validar JSON de entrada
 	if err := c.ShouldBindJSON(&request); err != nil {
-		h.logger.WithError(err).Error("Erro ao validar JSON de entrada")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao validar JSON de entrada")
 		c.JSON(http.StatusBadRequest, models.ImportResponse{
 			Sucesso:  false,
 			Mensagem: fmt.Sprintf("Dados inválidos: %v", err),
@@ -50,15 +59,16 @@ func (h *ImporterHandler) ImportarOferta(c *gin.Context) {
 	}
 
 	// Log da requisição
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"url":                 request.URL,
 		"atualizar_existente": request.AtualizarExistente,
+		"user_id":             userID,
 		"client_ip":           c.ClientIP(),
 	}).Info("Recebida requisição de importação")
 
 	// Validar URL
 	if err := h.importerService.ValidarURL(request.URL); err != nil {
-		h.logger.WithError(err).Error("URL inválida")
+		logs.Do(c.Request.Context()).WithError(err).Error("URL inválida")
 		c.JSON(http.StatusBadRequest, models.ImportResponse{
 			Sucesso:  false,
 			Mensagem: fmt.Sprintf("URL inválida: %v", err),
@@ -68,7 +78,9 @@ func (h *ImporterHandler) ImportarOferta(c *gin.Context) {
 
 	// Executar importação
 	sucesso, mensagem, uuid, err := h.importerService.Importar(
+		c.Request.Context(),
 		request.URL,
+		userID,
 		request.AtualizarExistente,
 	)
 
@@ -93,7 +105,7 @@ func (h *ImporterHandler) ImportarOferta(c *gin.Context) {
 	}
 
 	// Log do resultado
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"sucesso":     sucesso,
 		"uuid":        response.UUIDLink,
 		"status_code": statusCode,
@@ -102,24 +114,6 @@ func (h *ImporterHandler) ImportarOferta(c *gin.Context) {
 	c.JSON(statusCode, response)
 }
 
-// HealthCheck verifica a saúde da aplicação
-// @Summary Health check
-// @Description Verifica se a aplicação está funcionando
-// @Tags health
-// @Produce json
-// @Success 200 {object} map[string]interface{}
-// @Router /health [get]
-func (h *ImporterHandler) HealthCheck(c *gin.Context) {
-	response := map[string]interface{}{
-		"status":    "ok",
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
-		"service":   "mobgran-importer-go",
-		"version":   "1.0.0",
-	}
-
-	c.JSON(http.StatusOK, response)
-}
-
 // ValidarURL valida uma URL do Mobgran
 // @Summary Valida URL do Mobgran
 // @Description Valida se uma URL é um link válido do Mobgran
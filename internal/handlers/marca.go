@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MarcaHandler struct {
+	marcaService *services.MarcaService
+}
+
+func NewMarcaHandler(marcaService *services.MarcaService) *MarcaHandler {
+	return &MarcaHandler{
+		marcaService: marcaService,
+	}
+}
+
+// @Summary Obter dados de marca
+// @Description Retorna os dados da empresa e o logotipo exibidos nos PDFs do trader
+// @Tags marca
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.MarcaTrader
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /marca [get]
+func (h *MarcaHandler) Buscar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	marca, err := h.marcaService.Buscar(c.Request.Context(), userID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar dados de marca")
+		return
+	}
+
+	c.JSON(http.StatusOK, marca)
+}
+
+// @Summary Atualizar dados de marca
+// @Description Altera empresa, documento, telefone, endereço e site exibidos nos PDFs. Campos ausentes são mantidos; campos vazios são apagados.
+// @Tags marca
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param marca body models.AtualizarMarcaRequest true "Dados da empresa"
+// @Success 200 {object} models.MarcaTrader
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /marca [put]
+func (h *MarcaHandler) Atualizar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	var req models.AtualizarMarcaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	marca, err := h.marcaService.Atualizar(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar dados de marca")
+		return
+	}
+
+	c.JSON(http.StatusOK, marca)
+}
+
+// @Summary Enviar logotipo
+// @Description Substitui o logotipo do trader (JPEG ou PNG, até 2 MB) usado no cabeçalho dos PDFs
+// @Tags marca
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param logo formData file true "Arquivo do logotipo"
+// @Success 200 {object} models.MarcaTrader
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 413 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /marca/logo [put]
+func (h *MarcaHandler) AtualizarLogo(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	// O limite do corpo acompanha o de lerArquivoImagem; o do logotipo é conferido no serviço
+	limitarCorpo(c, models.TamanhoMaximoImagemProduto)
+	cabecalho, err := c.FormFile("logo")
+	if corpoExcedido(err) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"erro": fmt.Sprintf("Logotipo excede %d MB", models.TamanhoMaximoLogo>>20)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Envie o logotipo como multipart/form-data no campo 'logo'"})
+		return
+	}
+
+	arquivo, err := lerArquivoImagem(cabecalho)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+		return
+	}
+
+	marca, err := h.marcaService.AtualizarLogo(c.Request.Context(), userID, arquivo)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar logotipo")
+		return
+	}
+
+	c.JSON(http.StatusOK, marca)
+}
+
+// @Summary Remover logotipo
+// @Description Remove o logotipo do trader; os PDFs passam a exibir apenas os dados da empresa
+// @Tags marca
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.MarcaTrader
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /marca/logo [delete]
+func (h *MarcaHandler) RemoverLogo(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	marca, err := h.marcaService.RemoverLogo(c.Request.Context(), userID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao remover logotipo")
+		return
+	}
+
+	c.JSON(http.StatusOK, marca)
+}
+
+// responderErro traduz os erros do MarcaService para o status HTTP correspondente
+func (h *MarcaHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "trader não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Trader não encontrado"})
+	case err.Error() == "logotipo não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Logotipo não encontrado"})
+	case err.Error() == "nenhum campo para atualizar",
+		strings.HasPrefix(err.Error(), "imagem inválida"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MedidasHandler struct {
+	medidasService *services.MedidasService
+}
+
+func NewMedidasHandler(medidasService *services.MedidasService) *MedidasHandler {
+	return &MedidasHandler{
+		medidasService: medidasService,
+	}
+}
+
+// @Summary Normalizar medidas pendentes
+// @Description Calcula espessura em mm, dimensões em m, metragem em m² e peso em kg dos cavaletes importados antes da normalização, sem aguardar o processamento em segundo plano (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param limite query int false "Quantidade máxima de cavaletes processados" default(500)
+// @Success 200 {object} models.NormalizacaoMedidasResultado
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/medidas/normalizar [post]
+func (h *MedidasHandler) NormalizarPendentes(c *gin.Context) {
+	limite := 500
+	if l, err := strconv.Atoi(c.Query("limite")); err == nil && l > 0 && l <= 5000 {
+		limite = l
+	}
+
+	resultado, err := h.medidasService.NormalizarPendentes(c.Request.Context(), limite)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao normalizar medidas pendentes")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
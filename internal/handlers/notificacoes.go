@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificacoesHandler struct {
+	notificacoesService *services.NotificacoesService
+}
+
+func NewNotificacoesHandler(notificacoesService *services.NotificacoesService) *NotificacoesHandler {
+	return &NotificacoesHandler{
+		notificacoesService: notificacoesService,
+	}
+}
+
+// @Summary Listar assinaturas de notificação
+// @Description Lista os destinos (webhook ou e-mail) cadastrados pelo trader e os eventos de cada um
+// @Tags notificacoes
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/assinaturas [get]
+func (h *NotificacoesHandler) ListarAssinaturas(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	assinaturas, err := h.notificacoesService.ListarAssinaturas(c.Request.Context(), userID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar assinaturas de notificação")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"assinaturas": assinaturas})
+}
+
+// @Summary Cadastrar assinatura de notificação
+// @Description Cadastra um webhook (URL http/https) ou e-mail para os eventos importacao.concluida, importacao.falhou, cavalete.removido e reserva.expirando ou, para integrações, produto.aprovado, produto.atualizado, produto.removido e oferta.importada. O segredo do HMAC dos webhooks é retornado apenas nesta resposta.
+// @Tags notificacoes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param assinatura body models.AssinaturaCriarRequest true "Canal, destino e eventos"
+// @Success 201 {object} models.AssinaturaNotificacao
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/assinaturas [post]
+func (h *NotificacoesHandler) CriarAssinatura(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	var req models.AssinaturaCriarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	assinatura, err := h.notificacoesService.CriarAssinatura(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao cadastrar assinatura de notificação")
+		return
+	}
+
+	c.JSON(http.StatusCreated, assinatura)
+}
+
+// @Summary Atualizar assinatura de notificação
+// @Description Altera destino, eventos ou ativação. Com regenerar_segredo, um novo segredo do webhook é gerado e retornado.
+// @Tags notificacoes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da assinatura"
+// @Param assinatura body models.AssinaturaAtualizarRequest true "Campos a alterar"
+// @Success 200 {object} models.AssinaturaNotificacao
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/assinaturas/{id} [put]
+func (h *NotificacoesHandler) AtualizarAssinatura(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	assinaturaID, ok := uuidDaRota(c, "id", "ID da assinatura inválido")
+	if !ok {
+		return
+	}
+
+	var req models.AssinaturaAtualizarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	assinatura, err := h.notificacoesService.AtualizarAssinatura(c.Request.Context(), userID, assinaturaID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar assinatura de notificação")
+		return
+	}
+
+	c.JSON(http.StatusOK, assinatura)
+}
+
+// @Summary Remover assinatura de notificação
+// @Description Exclui a assinatura e o registro das suas notificações
+// @Tags notificacoes
+// @Security BearerAuth
+// @Param id path string true "ID da assinatura"
+// @Success 204
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/assinaturas/{id} [delete]
+func (h *NotificacoesHandler) RemoverAssinatura(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	assinaturaID, ok := uuidDaRota(c, "id", "ID da assinatura inválido")
+	if !ok {
+		return
+	}
+
+	if err := h.notificacoesService.RemoverAssinatura(c.Request.Context(), userID, assinaturaID); err != nil {
+		h.responderErro(c, err, "Erro ao remover assinatura de notificação")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Testar assinatura de notificação
+// @Description Enfileira um evento "teste" apenas para esta assinatura, mesmo inativa, para validar o receptor. Acompanhe a entrega em GET /notificacoes/{id}.
+// @Tags notificacoes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da assinatura"
+// @Success 202 {object} models.Notificacao
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/assinaturas/{id}/testar [post]
+func (h *NotificacoesHandler) TestarAssinatura(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	assinaturaID, ok := uuidDaRota(c, "id", "ID da assinatura inválido")
+	if !ok {
+		return
+	}
+
+	notificacao, err := h.notificacoesService.TestarAssinatura(c.Request.Context(), userID, assinaturaID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao testar assinatura de notificação")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, notificacao)
+}
+
+// @Summary Reenviar notificações da assinatura
+// @Description Devolve à fila as notificações da assinatura já entregues ou que falharam, opcionalmente só as de uma situação ou criadas a partir de uma data. Cada uma é entregue de novo com o mesmo id (Idempotency-Key).
+// @Tags notificacoes
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da assinatura"
+// @Param filtro body models.ReenvioRequest false "Situação (entregue ou falhou) e data inicial"
+// @Success 202 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/assinaturas/{id}/reenviar [post]
+func (h *NotificacoesHandler) ReenviarAssinatura(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	assinaturaID, ok := uuidDaRota(c, "id", "ID da assinatura inválido")
+	if !ok {
+		return
+	}
+
+	var req models.ReenvioRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+			return
+		}
+	}
+
+	reenviadas, err := h.notificacoesService.ReenviarAssinatura(c.Request.Context(), userID, assinaturaID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao reenviar notificações")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"reenviadas": reenviadas})
+}
+
+// @Summary Listar notificações
+// @Description Registro das notificações do trader, da mais recente para a mais antiga, com a situação da entrega (pendente, entregue, falhou), tentativas e último erro
+// @Tags notificacoes
+// @Produce json
+// @Security BearerAuth
+// @Param assinatura_id query string false "Filtrar pela assinatura"
+// @Param evento query string false "Filtrar pelo evento"
+// @Param status query string false "Filtrar pela situação (pendente, entregue, falhou)"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes [get]
+func (h *NotificacoesHandler) ListarNotificacoes(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	filtro := models.FiltroNotificacoes{
+		Evento: strings.TrimSpace(c.Query("evento")),
+		Status: strings.TrimSpace(c.Query("status")),
+	}
+	if filtro.AssinaturaID, ok = uuidDaQuery(c, "assinatura_id"); !ok {
+		return
+	}
+
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
+	}
+	filtro.Paginacao = *params
+
+	notificacoes, pagina, err := h.notificacoesService.ListarNotificacoes(c.Request.Context(), userID, &filtro)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar notificações")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"notificacoes": notificacoes,
+		"paginacao":    pagina,
+	})
+}
+
+// @Summary Buscar notificação
+// @Description Retorna a notificação com o registro de cada tentativa de entrega (status HTTP, erro e duração)
+// @Tags notificacoes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da notificação"
+// @Success 200 {object} models.Notificacao
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/{id} [get]
+func (h *NotificacoesHandler) BuscarNotificacao(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	notificacaoID, ok := uuidDaRota(c, "id", "ID da notificação inválido")
+	if !ok {
+		return
+	}
+
+	notificacao, err := h.notificacoesService.BuscarNotificacao(c.Request.Context(), userID, notificacaoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar notificação")
+		return
+	}
+
+	c.JSON(http.StatusOK, notificacao)
+}
+
+// @Summary Reenviar notificação
+// @Description Devolve à fila uma notificação já entregue ou que falhou. A entrega é refeita com o mesmo id (Idempotency-Key) e nova contagem de tentativas.
+// @Tags notificacoes
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID da notificação"
+// @Success 202 {object} models.Notificacao
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /notificacoes/{id}/reenviar [post]
+func (h *NotificacoesHandler) Reenviar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	notificacaoID, ok := uuidDaRota(c, "id", "ID da notificação inválido")
+	if !ok {
+		return
+	}
+
+	notificacao, err := h.notificacoesService.Reenviar(c.Request.Context(), userID, notificacaoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao reenviar notificação")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, notificacao)
+}
+
+// responderErro traduz os erros do NotificacoesService para o status HTTP correspondente
+func (h *NotificacoesHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "assinatura não encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Assinatura não encontrada"})
+	case err.Error() == "notificação não encontrada":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Notificação não encontrada"})
+	case err.Error() == "notificação já está na fila de entrega",
+		strings.HasPrefix(err.Error(), "assinatura inativa"):
+		c.JSON(http.StatusConflict, gin.H{"erro": err.Error()})
+	case err.Error() == "nenhum campo para atualizar",
+		err.Error() == "URL do webhook inválida",
+		err.Error() == "não foi possível resolver o host do webhook",
+		err.Error() == "destino do webhook em rede interna não é permitido",
+		err.Error() == "e-mail inválido",
+		err.Error() == "informe ao menos um evento",
+		err.Error() == "envio de e-mail não configurado no servidor",
+		err.Error() == "apenas assinaturas de webhook têm segredo",
+		strings.HasPrefix(err.Error(), "canal inválido"),
+		strings.HasPrefix(err.Error(), "status inválido"),
+		strings.HasPrefix(err.Error(), "evento inválido"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
@@ -0,0 +1,462 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type OrcamentosHandler struct {
+	orcamentosService *services.OrcamentosService
+}
+
+func NewOrcamentosHandler(orcamentosService *services.OrcamentosService) *OrcamentosHandler {
+	return &OrcamentosHandler{
+		orcamentosService: orcamentosService,
+	}
+}
+
+// @Summary Criar orçamento
+// @Description Cria um orçamento em rascunho para um cliente. Os produtos são incluídos em seguida por POST /orcamentos/{id}/itens.
+// @Tags orcamentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param orcamento body models.OrcamentoCriarRequest true "Dados do orçamento"
+// @Success 201 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos [post]
+func (h *OrcamentosHandler) Criar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	var req models.OrcamentoCriarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	orcamento, err := h.orcamentosService.Criar(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao criar orçamento")
+		return
+	}
+
+	c.JSON(http.StatusCreated, orcamento)
+}
+
+// @Summary Listar orçamentos
+// @Description Lista os orçamentos do trader com totais, do mais recente para o mais antigo
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Status" Enums(rascunho, enviado, aceito, recusado)
+// @Param cliente query string false "Parte do nome do cliente"
+// @Param cliente_id query string false "ID do cliente cadastrado"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos [get]
+func (h *OrcamentosHandler) Listar(c *gin.Context) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return
+	}
+
+	filtro := models.FiltroOrcamentos{
+		Status:  c.Query("status"),
+		Cliente: strings.TrimSpace(c.Query("cliente")),
+	}
+	if filtro.Status != "" && !models.StatusOrcamentoValido(filtro.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Status inválido (use rascunho, enviado, aceito ou recusado)"})
+		return
+	}
+	if valor := c.Query("cliente_id"); valor != "" {
+		clienteID, err := uuid.Parse(valor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "cliente_id inválido"})
+			return
+		}
+		filtro.ClienteID = &clienteID
+	}
+
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
+	}
+	filtro.Paginacao = *params
+
+	orcamentos, pagina, err := h.orcamentosService.Listar(c.Request.Context(), userID, &filtro)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao listar orçamentos")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"orcamentos": orcamentos,
+		"paginacao":  pagina,
+	})
+}
+
+// @Summary Buscar orçamento
+// @Description Retorna o orçamento com itens, totais e o status atual de cada produto
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id} [get]
+func (h *OrcamentosHandler) Buscar(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	orcamento, err := h.orcamentosService.Buscar(c.Request.Context(), userID, orcamentoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao buscar orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, orcamento)
+}
+
+// @Summary Atualizar orçamento
+// @Description Altera cliente, validade, observações e descontos de um orçamento em rascunho
+// @Tags orcamentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Param orcamento body models.OrcamentoAtualizarRequest true "Dados para atualização"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id} [put]
+func (h *OrcamentosHandler) Atualizar(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	var req models.OrcamentoAtualizarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	orcamento, err := h.orcamentosService.Atualizar(c.Request.Context(), userID, orcamentoID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, orcamento)
+}
+
+// @Summary Remover orçamento
+// @Description Exclui um orçamento em rascunho ou recusado
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id} [delete]
+func (h *OrcamentosHandler) Remover(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	if err := h.orcamentosService.Remover(c.Request.Context(), userID, orcamentoID); err != nil {
+		h.responderErro(c, err, "Erro ao remover orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"mensagem": "Orçamento removido com sucesso"})
+}
+
+// @Summary Adicionar item ao orçamento
+// @Description Inclui um produto aprovado no orçamento em rascunho. Sem quantidade_m2, usa a metragem do cavalete; sem preco_m2, o preço de venda do produto.
+// @Tags orcamentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Param item body models.OrcamentoItemRequest true "Produto, quantidade e preço"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/itens [post]
+func (h *OrcamentosHandler) AdicionarItem(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	var req models.OrcamentoItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	orcamento, err := h.orcamentosService.AdicionarItem(c.Request.Context(), userID, orcamentoID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao adicionar item ao orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, orcamento)
+}
+
+// @Summary Atualizar item do orçamento
+// @Description Altera a quantidade em m² ou o preço negociado por m² de um item
+// @Tags orcamentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Param itemId path string true "ID do item"
+// @Param item body models.OrcamentoItemAtualizarRequest true "Quantidade e preço"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/itens/{itemId} [put]
+func (h *OrcamentosHandler) AtualizarItem(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	itemID, ok := uuidDaRota(c, "itemId", "ID do item inválido")
+	if !ok {
+		return
+	}
+
+	var req models.OrcamentoItemAtualizarRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	orcamento, err := h.orcamentosService.AtualizarItem(c.Request.Context(), userID, orcamentoID, itemID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao atualizar item do orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, orcamento)
+}
+
+// @Summary Remover item do orçamento
+// @Description Retira um item do orçamento em rascunho
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Param itemId path string true "ID do item"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/itens/{itemId} [delete]
+func (h *OrcamentosHandler) RemoverItem(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	itemID, ok := uuidDaRota(c, "itemId", "ID do item inválido")
+	if !ok {
+		return
+	}
+
+	orcamento, err := h.orcamentosService.RemoverItem(c.Request.Context(), userID, orcamentoID, itemID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao remover item do orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, orcamento)
+}
+
+// @Summary Enviar orçamento
+// @Description Marca o rascunho como enviado ao cliente. Orçamentos enviados não podem mais ser editados.
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/enviar [post]
+func (h *OrcamentosHandler) Enviar(c *gin.Context) {
+	h.transicionar(c, h.orcamentosService.Enviar, "Erro ao enviar orçamento")
+}
+
+// @Summary Aceitar orçamento
+// @Description Registra o aceite do cliente para um orçamento enviado e dentro da validade
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/aceitar [post]
+func (h *OrcamentosHandler) Aceitar(c *gin.Context) {
+	h.transicionar(c, h.orcamentosService.Aceitar, "Erro ao aceitar orçamento")
+}
+
+// @Summary Recusar orçamento
+// @Description Registra a recusa do cliente para um orçamento enviado
+// @Tags orcamentos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Success 200 {object} models.Orcamento
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/recusar [post]
+func (h *OrcamentosHandler) Recusar(c *gin.Context) {
+	h.transicionar(c, h.orcamentosService.Recusar, "Erro ao recusar orçamento")
+}
+
+// @Summary Converter orçamento em reservas
+// @Description Reserva para o cliente todos os produtos de um orçamento aceito. Se algum produto não estiver disponível, nenhuma reserva é criada.
+// @Tags orcamentos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Param conversao body models.ConverterOrcamentoRequest false "Expiração das reservas"
+// @Success 200 {object} models.ConversaoOrcamentoResultado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/converter [post]
+func (h *OrcamentosHandler) Converter(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	// O corpo é opcional
+	var req models.ConverterOrcamentoRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+			return
+		}
+	}
+
+	resultado, err := h.orcamentosService.Converter(c.Request.Context(), userID, orcamentoID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao converter orçamento")
+		return
+	}
+
+	c.JSON(http.StatusOK, resultado)
+}
+
+// transicionar executa uma mudança de status sem corpo na requisição
+func (h *OrcamentosHandler) transicionar(c *gin.Context, acao func(context.Context, uuid.UUID, uuid.UUID) (*models.Orcamento, error), mensagem string) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	orcamento, err := acao(c.Request.Context(), userID, orcamentoID)
+	if err != nil {
+		h.responderErro(c, err, mensagem)
+		return
+	}
+
+	c.JSON(http.StatusOK, orcamento)
+}
+
+// responderErro traduz os erros do OrcamentosService para o status HTTP correspondente
+func (h *OrcamentosHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "orçamento não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Orçamento não encontrado"})
+	case err.Error() == "item não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Item não encontrado"})
+	case err.Error() == "produto não encontrado":
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Produto não encontrado"})
+	case err.Error() == "cliente não encontrado":
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Cliente não encontrado"})
+	case err.Error() == "produto já está no orçamento",
+		strings.HasPrefix(err.Error(), "operação não permitida"):
+		c.JSON(http.StatusConflict, gin.H{"erro": err.Error()})
+	case err.Error() == "nenhum campo para atualizar",
+		strings.HasPrefix(err.Error(), "informe quantidade_m2"),
+		strings.HasPrefix(err.Error(), "a expiração da reserva"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
+
+// traderEOrcamentoDaRota obtém o trader autenticado e o orçamento do parâmetro :id
+func traderEOrcamentoDaRota(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userID, ok := traderDoContexto(c)
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	orcamentoID, ok := uuidDaRota(c, "id", "ID do orçamento inválido")
+	if !ok {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return userID, orcamentoID, true
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"mobgran-importer-go/internal/paginacao"
+
+	"github.com/gin-gonic/gin"
+)
+
+// parsePaginacao lê limit, offset, cursor e incluir_total da query string, respondendo 400
+// quando o cursor é inválido. O cursor tem precedência sobre o offset.
+func parsePaginacao(c *gin.Context, limitPadrao, limitMaximo int) (*paginacao.Parametros, bool) {
+	params := &paginacao.Parametros{
+		Limit:  limitPadrao,
+		Offset: 0,
+	}
+
+	if l, err := strconv.Atoi(c.Query("limit")); err == nil && l > 0 && l <= limitMaximo {
+		params.Limit = l
+	}
+
+	if o, err := strconv.Atoi(c.Query("offset")); err == nil && o >= 0 {
+		params.Offset = o
+	}
+
+	if incluirTotal, err := strconv.ParseBool(c.Query("incluir_total")); err == nil {
+		params.IncluirTotal = incluirTotal
+	}
+
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		cursor, err := paginacao.Decodificar(cursorStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Parâmetro 'cursor' inválido"})
+			return nil, false
+		}
+		params.Cursor = cursor
+	}
+
+	return params, true
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PDFHandler struct {
+	pdfService *services.PDFService
+}
+
+func NewPDFHandler(pdfService *services.PDFService) *PDFHandler {
+	return &PDFHandler{
+		pdfService: pdfService,
+	}
+}
+
+// @Summary Ficha técnica do produto em PDF
+// @Description Gera a ficha técnica do produto (material, espessura, dimensões, metragem, imagem e preço) com os dados de marca do trader
+// @Tags produtos
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/ficha [get]
+func (h *PDFHandler) FichaProduto(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	dados, err := h.pdfService.FichaProduto(c.Request.Context(), userID, produtoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao gerar ficha do produto")
+		return
+	}
+
+	responderPDF(c, fmt.Sprintf("ficha-%s.pdf", produtoID), dados)
+}
+
+// @Summary Orçamento em PDF
+// @Description Gera o orçamento com itens, totais, validade e observações, com os dados de marca do trader
+// @Tags orcamentos
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "ID do orçamento"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /orcamentos/{id}/pdf [get]
+func (h *PDFHandler) Orcamento(c *gin.Context) {
+	userID, orcamentoID, ok := traderEOrcamentoDaRota(c)
+	if !ok {
+		return
+	}
+
+	dados, err := h.pdfService.Orcamento(c.Request.Context(), userID, orcamentoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao gerar PDF do orçamento")
+		return
+	}
+
+	responderPDF(c, fmt.Sprintf("orcamento-%s.pdf", orcamentoID), dados)
+}
+
+// responderErro traduz os erros do PDFService para o status HTTP correspondente
+func (h *PDFHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch err.Error() {
+	case "produto não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
+	case "orçamento não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Orçamento não encontrado"})
+	case "trader não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Trader não encontrado"})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
+
+// responderPDF envia o PDF para exibição no navegador, com nome para download
+func responderPDF(c *gin.Context, nomeArquivo string, dados []byte) {
+	c.Header("Content-Disposition", fmt.Sprintf(`inline; filename="%s"`, nomeArquivo))
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "application/pdf", dados)
+}
@@ -3,14 +3,15 @@ package handlers
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
+	"mobgran-importer-go/internal/logs"
 	"mobgran-importer-go/internal/middleware"
 	"mobgran-importer-go/internal/models"
 	"mobgran-importer-go/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/sirupsen/logrus"
 )
 
 type ProdutosHandler struct {
@@ -28,8 +29,11 @@ func NewProdutosHandler(produtosService *services.ProdutosService) *ProdutosHand
 // @Tags produtos
 // @Produce json
 // @Security BearerAuth
+// @Param fornecedor_id query string false "Filtrar por fornecedor"
 // @Param limit query int false "Limite de resultados" default(20)
 // @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
@@ -49,34 +53,26 @@ func (h *ProdutosHandler) ListarCavaletesDisponiveis(c *gin.Context) {
 		return
 	}
 
-	// Parâmetros de paginação
-	limit := 20
-	offset := 0
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	fornecedorID, ok := uuidDaQuery(c, "fornecedor_id")
+	if !ok {
+		return
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
 	}
 
-	cavaletes, err := h.produtosService.ListarCavaletesDisponiveis(userID, limit, offset)
+	cavaletes, pagina, err := h.produtosService.ListarCavaletesDisponiveis(c.Request.Context(), userID, fornecedorID, params)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao listar cavaletes disponíveis")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar cavaletes disponíveis")
 		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"cavaletes": cavaletes,
-		"total":     len(cavaletes),
-		"limit":     limit,
-		"offset":    offset,
+		"paginacao": pagina,
 	})
 }
 
@@ -109,14 +105,14 @@ func (h *ProdutosHandler) AprovarProduto(c *gin.Context) {
 
 	var req models.ProdutoAprovarRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logrus.WithError(err).Error("Erro ao fazer bind do JSON")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
 		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
 		return
 	}
 
-	produto, err := h.produtosService.AprovarProduto(userID, &req)
+	produto, err := h.produtosService.AprovarProduto(c.Request.Context(), userID, &req)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao aprovar produto")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao aprovar produto")
 		if err.Error() == "produto já foi aprovado" {
 			c.JSON(http.StatusConflict, gin.H{"erro": "Produto já foi aprovado"})
 			return
@@ -137,9 +133,13 @@ func (h *ProdutosHandler) AprovarProduto(c *gin.Context) {
 // @Tags produtos
 // @Produce json
 // @Security BearerAuth
+// @Param status query string false "Status de venda" Enums(disponivel, reservado, vendido)
 // @Param limit query int false "Limite de resultados" default(20)
 // @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior)"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /produtos/aprovados [get]
@@ -157,34 +157,27 @@ func (h *ProdutosHandler) ListarProdutosAprovados(c *gin.Context) {
 		return
 	}
 
-	// Parâmetros de paginação
-	limit := 20
-	offset := 0
-
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return
 	}
 
-	if offsetStr := c.Query("offset"); offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	status := c.Query("status")
+	if status != "" && !models.StatusProdutoValido(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Status inválido (use disponivel, reservado ou vendido)"})
+		return
 	}
 
-	produtos, err := h.produtosService.ListarProdutosAprovados(userID, limit, offset)
+	produtos, pagina, err := h.produtosService.ListarProdutosAprovados(c.Request.Context(), userID, status, params)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao listar produtos aprovados")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar produtos aprovados")
 		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"produtos": produtos,
-		"total":    len(produtos),
-		"limit":    limit,
-		"offset":   offset,
+		"produtos":  produtos,
+		"paginacao": pagina,
 	})
 }
 
@@ -225,14 +218,14 @@ func (h *ProdutosHandler) AtualizarProduto(c *gin.Context) {
 
 	var req models.ProdutoAtualizarRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		logrus.WithError(err).Error("Erro ao fazer bind do JSON")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
 		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
 		return
 	}
 
-	produto, err := h.produtosService.AtualizarProduto(userID, produtoID, &req)
+	produto, err := h.produtosService.AtualizarProduto(c.Request.Context(), userID, produtoID, &req)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao atualizar produto")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao atualizar produto")
 		if err.Error() == "produto não encontrado" {
 			c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
 			return
@@ -277,9 +270,9 @@ func (h *ProdutosHandler) BuscarProduto(c *gin.Context) {
 		return
 	}
 
-	produto, err := h.produtosService.BuscarProduto(userID, produtoID)
+	produto, err := h.produtosService.BuscarProduto(c.Request.Context(), userID, produtoID)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao buscar produto")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao buscar produto")
 		if err.Error() == "produto não encontrado" {
 			c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
 			return
@@ -324,9 +317,9 @@ func (h *ProdutosHandler) RemoverProduto(c *gin.Context) {
 		return
 	}
 
-	err = h.produtosService.RemoverProduto(userID, produtoID)
+	err = h.produtosService.RemoverProduto(c.Request.Context(), userID, produtoID)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao remover produto")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao remover produto")
 		if err.Error() == "produto não encontrado" {
 			c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
 			return
@@ -339,59 +332,141 @@ func (h *ProdutosHandler) RemoverProduto(c *gin.Context) {
 }
 
 // @Summary Vitrine pública
-// @Description Lista produtos na vitrine pública (não requer autenticação)
-// @Tags produtos
+// @Description Lista produtos na vitrine pública com filtros, busca e ordenação (não requer autenticação)
+// @Tags vitrine
 // @Produce json
 // @Param limit query int false "Limite de resultados" default(20)
 // @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior); apenas com ordenacao=recentes, outras ordenações respondem 400"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
 // @Param trader_id query string false "Filtrar por trader específico"
+// @Param fornecedor_id query string false "Filtrar por fornecedor"
+// @Param destaque query bool false "Apenas produtos em destaque"
+// @Param material query string false "Filtrar por material (busca parcial)"
+// @Param espessura query string false "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)"
+// @Param classificacao query string false "Filtrar por classificação"
+// @Param acabamento query string false "Filtrar por acabamento"
+// @Param comprimento_min query number false "Comprimento mínimo em metros"
+// @Param comprimento_max query number false "Comprimento máximo em metros"
+// @Param altura_min query number false "Altura mínima em metros"
+// @Param altura_max query number false "Altura máxima em metros"
+// @Param metragem_min query number false "Metragem mínima em m²"
+// @Param metragem_max query number false "Metragem máxima em m²"
+// @Param preco_min query number false "Preço mínimo"
+// @Param preco_max query number false "Preço máximo"
+// @Param busca query string false "Busca no nome e na descrição"
+// @Param ordenacao query string false "Ordenação: destaque, preco_asc, preco_desc, recentes, metragem_desc, nome" default(destaque)
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /vitrine/publica [get]
 func (h *ProdutosHandler) ListarVitrinePublica(c *gin.Context) {
-	// Parse query parameters
-	limitStr := c.DefaultQuery("limit", "20")
-	offsetStr := c.DefaultQuery("offset", "0")
-	destaqueStr := c.DefaultQuery("destaque", "false")
+	filtro, ok := parseFiltroVitrine(c)
+	if !ok {
+		return
+	}
 
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit <= 0 {
-		limit = 20
+	if traderIDStr := c.Query("trader_id"); traderIDStr != "" {
+		traderID, err := uuid.Parse(traderIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "trader_id inválido"})
+			return
+		}
+		filtro.TraderID = &traderID
 	}
 
-	offset, err := strconv.Atoi(offsetStr)
-	if err != nil || offset < 0 {
-		offset = 0
+	produtos, pagina, err := h.produtosService.ListarVitrinePublica(c.Request.Context(), filtro)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar vitrine pública")
+		if err.Error() == "paginação por cursor disponível apenas com ordenacao=recentes" {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Paginação por cursor disponível apenas com ordenacao=recentes"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
 	}
 
-	destaque, err := strconv.ParseBool(destaqueStr)
+	c.JSON(http.StatusOK, gin.H{
+		"produtos":  produtos,
+		"paginacao": pagina,
+	})
+}
+
+// @Summary Vitrine do trader
+// @Description Lista os produtos da vitrine pública de um trader pelo slug, com os mesmos filtros de /vitrine/publica (não requer autenticação)
+// @Tags vitrine
+// @Produce json
+// @Param slug path string true "Slug da vitrine do trader"
+// @Param limit query int false "Limite de resultados" default(20)
+// @Param offset query int false "Offset para paginação" default(0)
+// @Param cursor query string false "Cursor opaco (paginacao.proximo_cursor ou paginacao.cursor_anterior da resposta anterior); apenas com ordenacao=recentes, outras ordenações respondem 400"
+// @Param incluir_total query bool false "Incluir o total de registros (consulta adicional)"
+// @Param destaque query bool false "Apenas produtos em destaque"
+// @Param material query string false "Filtrar por material (busca parcial)"
+// @Param espessura query string false "Filtrar por espessura (2cm, 20 mm e 2 são equivalentes)"
+// @Param classificacao query string false "Filtrar por classificação"
+// @Param acabamento query string false "Filtrar por acabamento"
+// @Param fornecedor_id query string false "Filtrar por fornecedor"
+// @Param preco_min query number false "Preço mínimo"
+// @Param preco_max query number false "Preço máximo"
+// @Param busca query string false "Busca no nome e na descrição"
+// @Param ordenacao query string false "Ordenação: destaque, preco_asc, preco_desc, recentes, metragem_desc, nome" default(destaque)
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /vitrine/{slug} [get]
+func (h *ProdutosHandler) ListarVitrineTrader(c *gin.Context) {
+	trader, err := h.produtosService.BuscarTraderPorSlug(c.Request.Context(), c.Param("slug"))
 	if err != nil {
-		destaque = false
+		if err.Error() == "vitrine não encontrada" {
+			c.JSON(http.StatusNotFound, gin.H{"erro": "Vitrine não encontrada"})
+			return
+		}
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao buscar vitrine do trader")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	filtro, ok := parseFiltroVitrine(c)
+	if !ok {
+		return
 	}
+	filtro.TraderID = &trader.ID
 
-	produtos, err := h.produtosService.ListarVitrinePublica(limit, offset, destaque)
+	produtos, pagina, err := h.produtosService.ListarVitrinePublica(c.Request.Context(), filtro)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao listar vitrine pública")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao listar vitrine do trader")
+		if err.Error() == "paginação por cursor disponível apenas com ordenacao=recentes" {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Paginação por cursor disponível apenas com ordenacao=recentes"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"produtos": produtos,
-		"total":    len(produtos),
+		"trader":    trader,
+		"produtos":  produtos,
+		"paginacao": pagina,
 	})
 }
 
-// @Summary Estatísticas de produtos
-// @Description Obtém estatísticas dos produtos do trader
-// @Tags produtos
+// @Summary Alterar slug da vitrine
+// @Description Define o slug usado na URL da vitrine pública do trader (/vitrine/{slug})
+// @Tags vitrine
+// @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} models.EstatisticasProdutos
+// @Param slug body models.AtualizarSlugRequest true "Novo slug"
+// @Success 200 {object} models.VitrineTrader
+// @Failure 400 {object} map[string]interface{}
 // @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
-// @Router /produtos/estatisticas [get]
-func (h *ProdutosHandler) ObterEstatisticas(c *gin.Context) {
+// @Router /vitrine/slug [put]
+func (h *ProdutosHandler) AtualizarSlugVitrine(c *gin.Context) {
 	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
@@ -405,47 +480,121 @@ func (h *ProdutosHandler) ObterEstatisticas(c *gin.Context) {
 		return
 	}
 
-	estatisticas, err := h.produtosService.ObterEstatisticas(userID)
+	var req models.AtualizarSlugRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	trader, err := h.produtosService.AtualizarSlug(c.Request.Context(), userID, req.Slug)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao obter estatísticas")
-		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao atualizar slug da vitrine")
+		switch err.Error() {
+		case "slug inválido":
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Slug inválido: use letras minúsculas, números e hífens"})
+		case "slug já está em uso":
+			c.JSON(http.StatusConflict, gin.H{"erro": "Slug já está em uso"})
+		case "trader não encontrado":
+			c.JSON(http.StatusNotFound, gin.H{"erro": "Trader não encontrado"})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, estatisticas)
+	c.JSON(http.StatusOK, trader)
+}
+
+// parseFiltroVitrine lê os filtros da vitrine da query string, respondendo 400 em caso de valor inválido
+func parseFiltroVitrine(c *gin.Context) (*models.FiltroVitrine, bool) {
+	filtro := &models.FiltroVitrine{
+		Material:      strings.TrimSpace(c.Query("material")),
+		Espessura:     strings.TrimSpace(c.Query("espessura")),
+		Classificacao: strings.TrimSpace(c.Query("classificacao")),
+		Acabamento:    strings.TrimSpace(c.Query("acabamento")),
+		Busca:         strings.TrimSpace(c.Query("busca")),
+		Ordenacao:     c.Query("ordenacao"),
+	}
+
+	params, ok := parsePaginacao(c, 20, 100)
+	if !ok {
+		return nil, false
+	}
+	filtro.Paginacao = *params
+
+	if filtro.FornecedorID, ok = uuidDaQuery(c, "fornecedor_id"); !ok {
+		return nil, false
+	}
+
+	// Cursores só existem na ordenação por mais recentes, que passa a ser a padrão quando
+	// um cursor é informado
+	if filtro.Ordenacao == "" {
+		filtro.Ordenacao = models.OrdenacaoVitrineDestaque
+		if params.Cursor != nil {
+			filtro.Ordenacao = models.OrdenacaoVitrineRecentes
+		}
+	}
+	if params.Cursor != nil && filtro.Ordenacao != models.OrdenacaoVitrineRecentes {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Paginação por cursor disponível apenas com ordenacao=recentes"})
+		return nil, false
+	}
+
+	if destaque, err := strconv.ParseBool(c.Query("destaque")); err == nil {
+		filtro.Destaque = destaque
+	}
+
+	if !services.OrdenacaoVitrineValida(filtro.Ordenacao) {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Parâmetro 'ordenacao' inválido"})
+		return nil, false
+	}
+
+	ok = lerFaixasQuery(c, []faixaQuery{
+		{"comprimento_min", &filtro.ComprimentoMin},
+		{"comprimento_max", &filtro.ComprimentoMax},
+		{"altura_min", &filtro.AlturaMin},
+		{"altura_max", &filtro.AlturaMax},
+		{"metragem_min", &filtro.MetragemMin},
+		{"metragem_max", &filtro.MetragemMax},
+		{"preco_min", &filtro.PrecoMin},
+		{"preco_max", &filtro.PrecoMax},
+	})
+	if !ok {
+		return nil, false
+	}
+
+	return filtro, true
 }
 
-// @Summary Limpar todos os registros do banco de dados
-// @Description Remove todos os registros de produtos, cavaletes, ofertas e dados relacionados do banco de dados
-// @Tags admin
+// @Summary Estatísticas de produtos
+// @Description Obtém as contagens de produtos do trader e os cavaletes das suas ofertas ativas ainda não aprovados. O painel completo está em GET /estatisticas.
+// @Tags produtos
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} map[string]interface{}
+// @Success 200 {object} models.EstatisticasProdutos
 // @Failure 401 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
-// @Router /admin/limpar-dados [delete]
-func (h *ProdutosHandler) LimparTodosRegistros(c *gin.Context) {
-	userID, _, _, err := middleware.GetSupabaseUserFromContext(c)
+// @Router /produtos/estatisticas [get]
+func (h *ProdutosHandler) ObterEstatisticas(c *gin.Context) {
+	userIDStr, _, _, err := middleware.GetSupabaseUserFromContext(c)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, gin.H{"erro": "Usuário não encontrado no contexto"})
 		return
 	}
 
-	logrus.WithField("user_id", userID).Info("Iniciando limpeza de todos os registros")
-
-	err = h.produtosService.LimparTodosRegistros()
+	// Converte userID string para UUID
+	userID, err := uuid.Parse(userIDStr)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao limpar todos os registros")
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"erro": "Erro interno do servidor ao limpar registros",
-		})
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "ID do usuário inválido"})
 		return
 	}
 
-	logrus.WithField("user_id", userID).Info("Limpeza de todos os registros concluída com sucesso")
+	estatisticas, err := h.produtosService.ObterEstatisticas(c.Request.Context(), userID)
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao obter estatísticas")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"sucesso":  true,
-		"mensagem": "Todos os registros foram removidos com sucesso",
-	})
+	c.JSON(http.StatusOK, estatisticas)
 }
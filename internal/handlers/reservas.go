@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReservasHandler struct {
+	reservasService *services.ReservasService
+}
+
+func NewReservasHandler(reservasService *services.ReservasService) *ReservasHandler {
+	return &ReservasHandler{
+		reservasService: reservasService,
+	}
+}
+
+// @Summary Reservar produto
+// @Description Reserva um produto disponível para um cliente. A reserva expira em expira_em, em validade_horas ou, sem nenhum dos dois, na validade padrão configurada; reservas vencidas voltam a disponível automaticamente.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Param reserva body models.ReservarProdutoRequest true "Dados da reserva"
+// @Success 200 {object} models.ProdutoAprovado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/reservar [post]
+func (h *ReservasHandler) Reservar(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	var req models.ReservarProdutoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+		return
+	}
+
+	produto, err := h.reservasService.Reservar(c.Request.Context(), userID, produtoID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao reservar produto")
+		return
+	}
+
+	c.JSON(http.StatusOK, produto)
+}
+
+// @Summary Liberar reserva
+// @Description Cancela a reserva do produto, que volta a ficar disponível
+// @Tags produtos
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Success 200 {object} models.ProdutoAprovado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/liberar [post]
+func (h *ReservasHandler) Liberar(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	produto, err := h.reservasService.Liberar(c.Request.Context(), userID, produtoID)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao liberar reserva")
+		return
+	}
+
+	c.JSON(http.StatusOK, produto)
+}
+
+// @Summary Marcar produto como vendido
+// @Description Registra a venda de um produto disponível ou reservado. Produtos vendidos deixam de aparecer na vitrine pública.
+// @Tags produtos
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "ID do produto"
+// @Param venda body models.VenderProdutoRequest false "Dados da venda"
+// @Success 200 {object} models.ProdutoAprovado
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 404 {object} map[string]interface{}
+// @Failure 409 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /produtos/{id}/vender [post]
+func (h *ReservasHandler) MarcarVendido(c *gin.Context) {
+	userID, produtoID, ok := traderEProdutoDaRota(c)
+	if !ok {
+		return
+	}
+
+	// O corpo é opcional
+	var req models.VenderProdutoRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"erro": "Dados inválidos", "detalhes": err.Error()})
+			return
+		}
+	}
+
+	produto, err := h.reservasService.MarcarVendido(c.Request.Context(), userID, produtoID, &req)
+	if err != nil {
+		h.responderErro(c, err, "Erro ao marcar produto como vendido")
+		return
+	}
+
+	c.JSON(http.StatusOK, produto)
+}
+
+// @Summary Expirar reservas vencidas
+// @Description Libera imediatamente as reservas vencidas, sem aguardar o próximo ciclo do worker (apenas administradores)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ExpiracaoReservasResultado
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /admin/reservas/expirar [post]
+func (h *ReservasHandler) ExpirarReservas(c *gin.Context) {
+	expiradas, err := h.reservasService.ExpirarReservas(c.Request.Context())
+	if err != nil {
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao expirar reservas")
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.ExpiracaoReservasResultado{Expiradas: expiradas})
+}
+
+// responderErro traduz os erros do ReservasService para o status HTTP correspondente
+func (h *ReservasHandler) responderErro(c *gin.Context, err error, mensagem string) {
+	switch {
+	case err.Error() == "produto não encontrado":
+		c.JSON(http.StatusNotFound, gin.H{"erro": "Produto não encontrado"})
+	case err.Error() == "cliente não encontrado":
+		c.JSON(http.StatusBadRequest, gin.H{"erro": "Cliente não encontrado"})
+	case strings.HasPrefix(err.Error(), "operação não permitida"):
+		c.JSON(http.StatusConflict, gin.H{"erro": err.Error()})
+	case strings.HasPrefix(err.Error(), "a expiração da reserva"):
+		c.JSON(http.StatusBadRequest, gin.H{"erro": err.Error()})
+	default:
+		logs.Do(c.Request.Context()).WithError(err).Error(mensagem)
+		c.JSON(http.StatusInternalServerError, gin.H{"erro": "Erro interno do servidor"})
+	}
+}
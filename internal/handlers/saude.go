@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/services"
+	"mobgran-importer-go/internal/versao"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SaudeHandler struct {
+	saudeService *services.SaudeService
+	iniciadoEm   time.Time
+}
+
+func NewSaudeHandler(saudeService *services.SaudeService) *SaudeHandler {
+	return &SaudeHandler{
+		saudeService: saudeService,
+		iniciadoEm:   time.Now(),
+	}
+}
+
+// @Summary Liveness
+// @Description Indica que o processo está no ar, sem consultar dependências. Usado pelo orquestrador para decidir se a instância deve ser reiniciada. Traz a versão e o commit do build.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /health/live [get]
+func (h *SaudeHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":          "alive",
+		"build":           versao.Atual(),
+		"uptime_segundos": int64(time.Since(h.iniciadoEm).Seconds()),
+	})
+}
+
+// @Summary Readiness
+// @Description Verifica as dependências da API: conexão e migrations do banco (críticas), saturação do pool de conexões, Supabase Auth, circuito da API do Mobgran e fila de notificações. Responde 503 quando uma dependência crítica falha; as demais apenas marcam a API como degraded.
+// @Tags health
+// @Produce json
+// @Success 200 {object} models.Prontidao
+// @Failure 503 {object} models.Prontidao
+// @Router /health/ready [get]
+func (h *SaudeHandler) Ready(c *gin.Context) {
+	prontidao := h.saudeService.Prontidao(c.Request.Context())
+
+	status := http.StatusOK
+	if prontidao.Status == models.SaudeIndisponivel {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, prontidao)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"mobgran-importer-go/internal/logs"
 	"mobgran-importer-go/internal/models"
 	"mobgran-importer-go/internal/services"
 )
@@ -24,7 +25,7 @@ func NewSupabaseAuthHandler(supabaseAuthService *services.SupabaseAuthService, l
 // handleError processa erros de forma padronizada
 func (h *SupabaseAuthHandler) handleError(c *gin.Context, err error) {
 	if apiErr, ok := err.(*models.APIError); ok {
-		h.logger.WithFields(logrus.Fields{
+		logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 			"type":    apiErr.Type,
 			"message": apiErr.Message,
 			"details": apiErr.Details,
@@ -35,7 +36,7 @@ func (h *SupabaseAuthHandler) handleError(c *gin.Context, err error) {
 	}
 
 	// Erro não tipado - trata como erro interno
-	h.logger.WithError(err).Error("Erro interno não tipado")
+	logs.Do(c.Request.Context()).WithError(err).Error("Erro interno não tipado")
 	internalErr := models.NewInternalError("Erro interno do servidor")
 	c.JSON(internalErr.StatusCode, models.ErrorResponse{Error: *internalErr})
 }
@@ -83,7 +84,7 @@ func (h *SupabaseAuthHandler) CriarUsuarioAdmin(c *gin.Context) {
 	// Criar usuário admin
 	resp, err := h.supabaseAuthService.CriarUsuarioAdmin(req.Email, req.Password, req.Data)
 	if err != nil {
-		h.logger.WithError(err).Error("Erro ao criar usuário admin")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao criar usuário admin")
 		h.handleError(c, models.NewInternalError("Erro ao criar usuário admin"))
 		return
 	}
@@ -94,13 +95,13 @@ func (h *SupabaseAuthHandler) CriarUsuarioAdmin(c *gin.Context) {
 func (h *SupabaseAuthHandler) Registrar(c *gin.Context) {
 	var req SupabaseRegistroRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Erro ao fazer bind do JSON")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
 		apiErr := models.NewValidationError("Dados inválidos", err.Error())
 		h.handleError(c, apiErr)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"email": req.Email,
 	}).Info("Tentativa de registro no Supabase")
 
@@ -110,7 +111,7 @@ func (h *SupabaseAuthHandler) Registrar(c *gin.Context) {
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"user_id": resp.User.ID,
 		"email":   resp.User.Email,
 	}).Info("Usuário registrado com sucesso no Supabase")
@@ -132,13 +133,13 @@ func (h *SupabaseAuthHandler) Registrar(c *gin.Context) {
 func (h *SupabaseAuthHandler) Login(c *gin.Context) {
 	var req SupabaseLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Erro ao fazer bind do JSON")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
 		apiErr := models.NewValidationError("Dados inválidos", err.Error())
 		h.handleError(c, apiErr)
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"email": req.Email,
 	}).Info("Tentativa de login no Supabase")
 
@@ -148,7 +149,7 @@ func (h *SupabaseAuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"user_id": resp.User.ID,
 		"email":   resp.User.Email,
 	}).Info("Login realizado com sucesso no Supabase")
@@ -180,7 +181,7 @@ func (h *SupabaseAuthHandler) ObterUsuario(c *gin.Context) {
 		token = token[7:]
 	}
 
-	h.logger.Info("Obtendo usuário do Supabase")
+	logs.Do(c.Request.Context()).Info("Obtendo usuário do Supabase")
 
 	user, err := h.supabaseAuthService.ObterUsuario(token)
 	if err != nil {
@@ -188,7 +189,7 @@ func (h *SupabaseAuthHandler) ObterUsuario(c *gin.Context) {
 		return
 	}
 
-	h.logger.WithFields(logrus.Fields{
+	logs.Do(c.Request.Context()).WithFields(logrus.Fields{
 		"user_id": user.ID,
 		"email":   user.Email,
 	}).Info("Usuário obtido com sucesso do Supabase")
@@ -210,13 +211,13 @@ func (h *SupabaseAuthHandler) ObterUsuario(c *gin.Context) {
 func (h *SupabaseAuthHandler) RenovarToken(c *gin.Context) {
 	var req RefreshTokenRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		h.logger.WithError(err).Error("Erro ao fazer bind do JSON")
+		logs.Do(c.Request.Context()).WithError(err).Error("Erro ao fazer bind do JSON")
 		apiErr := models.NewValidationError("Dados inválidos", err.Error())
 		h.handleError(c, apiErr)
 		return
 	}
 
-	h.logger.Info("Renovando token no Supabase")
+	logs.Do(c.Request.Context()).Info("Renovando token no Supabase")
 
 	session, err := h.supabaseAuthService.RenovarToken(req.RefreshToken)
 	if err != nil {
@@ -224,7 +225,7 @@ func (h *SupabaseAuthHandler) RenovarToken(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Token renovado com sucesso no Supabase")
+	logs.Do(c.Request.Context()).Info("Token renovado com sucesso no Supabase")
 
 	c.JSON(http.StatusOK, session)
 }
@@ -253,7 +254,7 @@ func (h *SupabaseAuthHandler) Logout(c *gin.Context) {
 		token = token[7:]
 	}
 
-	h.logger.Info("Fazendo logout no Supabase")
+	logs.Do(c.Request.Context()).Info("Fazendo logout no Supabase")
 
 	err := h.supabaseAuthService.FazerLogout(token)
 	if err != nil {
@@ -261,7 +262,7 @@ func (h *SupabaseAuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
-	h.logger.Info("Logout realizado com sucesso no Supabase")
+	logs.Do(c.Request.Context()).Info("Logout realizado com sucesso no Supabase")
 
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Logout realizado com sucesso",
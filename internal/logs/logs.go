@@ -0,0 +1,65 @@
+// Package logs fornece o logger carregado no contexto: cada linha registrada com o
+// contexto de uma requisição traz request_id e user_id (e os campos acrescentados ao
+// longo da operação, como oferta_id), e passa pela remoção de credenciais
+package logs
+
+import (
+	"context"
+
+	"mobgran-importer-go/internal/auth"
+	"mobgran-importer-go/internal/requestctx"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey string
+
+const loggerKey contextKey = "logger"
+
+// Do retorna o logger da requisição ou operação em andamento. Fora de uma requisição,
+// retorna o logger global ligado ao contexto (para os IDs do trace).
+func Do(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(loggerKey).(*logrus.Entry); ok {
+		return entry.WithContext(ctx)
+	}
+	return logrus.WithContext(ctx)
+}
+
+// ComCampos retorna um contexto cujo logger inclui os campos informados em todas as
+// linhas seguintes (ex.: oferta_id depois de identificada a oferta)
+func ComCampos(ctx context.Context, campos logrus.Fields) context.Context {
+	return context.WithValue(ctx, loggerKey, Do(ctx).WithFields(campos))
+}
+
+// HookContexto acrescenta request_id e user_id, lidos do contexto da entrada, às
+// linhas que ainda não os trazem
+type HookContexto struct{}
+
+// Levels aplica o hook a todos os níveis
+func (HookContexto) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire copia os identificadores da requisição do contexto da entrada, se houver
+func (HookContexto) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	if _, ok := entry.Data["request_id"]; !ok {
+		if requestID := requestctx.GetRequestID(entry.Context); requestID != "" {
+			entry.Data["request_id"] = requestID
+		}
+	}
+
+	if user, err := auth.GetUserFromContext(entry.Context); err == nil {
+		if _, ok := entry.Data["user_id"]; !ok && user.UserID != "" {
+			entry.Data["user_id"] = user.UserID
+		}
+		if _, ok := entry.Data["api_key_id"]; !ok && user.APIKeyID != "" {
+			entry.Data["api_key_id"] = user.APIKeyID
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+package logs
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"mobgran-importer-go/internal/models"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Redigido substitui os valores sensíveis nos logs
+const Redigido = "[REDACTED]"
+
+// camposSensiveis são os nomes de campo (e de header, com "-" trocado por "_") cujo
+// valor nunca é registrado
+var camposSensiveis = map[string]bool{
+	"senha":                true,
+	"nova_senha":           true,
+	"senha_atual":          true,
+	"password":             true,
+	"token":                true,
+	"access_token":         true,
+	"refresh_token":        true,
+	"id_token":             true,
+	"jwt":                  true,
+	"secret":               true,
+	"segredo":              true,
+	"client_secret":        true,
+	"authorization":        true,
+	"cookie":               true,
+	"set_cookie":           true,
+	"api_key":              true,
+	"x_api_key":            true,
+	"chave_api":            true,
+	"x_mobgran_assinatura": true,
+}
+
+// sufixosSensiveis cobrem variações como smtp_senha e supabase_service_token
+var sufixosSensiveis = []string{"_senha", "_password", "_token", "_secret", "_segredo"}
+
+var (
+	padraoBearer = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9\-._~+/]+=*`)
+	padraoChave  = regexp.MustCompile(regexp.QuoteMeta(models.PrefixoAPIKey) + `[A-Za-z0-9_\-]+`)
+	padraoJWT    = regexp.MustCompile(`\beyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+`)
+	padraoQuery  = regexp.MustCompile(`(?i)([?&](?:token|access_token|refresh_token|senha|password|api_key)=)[^&\s"]+`)
+)
+
+// CampoSensivel indica se o campo (ou header) informado guarda uma credencial
+func CampoSensivel(nome string) bool {
+	nome = strings.ToLower(strings.ReplaceAll(nome, "-", "_"))
+	if camposSensiveis[nome] {
+		return true
+	}
+	for _, sufixo := range sufixosSensiveis {
+		if strings.HasSuffix(nome, sufixo) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedigirTexto remove tokens Bearer/Basic, JWTs, chaves de API e credenciais em
+// parâmetros de URL de um texto livre
+func RedigirTexto(texto string) string {
+	texto = padraoBearer.ReplaceAllString(texto, "$1 "+Redigido)
+	texto = padraoJWT.ReplaceAllString(texto, Redigido)
+	texto = padraoChave.ReplaceAllString(texto, Redigido)
+	return padraoQuery.ReplaceAllString(texto, "${1}"+Redigido)
+}
+
+// RedigirHeaders retorna uma cópia dos headers com os valores sensíveis removidos
+func RedigirHeaders(headers http.Header) http.Header {
+	copia := make(http.Header, len(headers))
+	for nome, valores := range headers {
+		if CampoSensivel(nome) {
+			copia[nome] = []string{Redigido}
+			continue
+		}
+		redigidos := make([]string, len(valores))
+		for i, valor := range valores {
+			redigidos[i] = RedigirTexto(valor)
+		}
+		copia[nome] = redigidos
+	}
+	return copia
+}
+
+// redigir remove as credenciais de um valor registrado no log, sem alterar o original
+func redigir(valor interface{}) interface{} {
+	switch v := valor.(type) {
+	case string:
+		return RedigirTexto(v)
+	case error:
+		if texto := v.Error(); RedigirTexto(texto) != texto {
+			return RedigirTexto(texto)
+		}
+		return v
+	case fmt.Stringer:
+		if texto := v.String(); RedigirTexto(texto) != texto {
+			return RedigirTexto(texto)
+		}
+		return v
+	case http.Header:
+		return RedigirHeaders(v)
+	case map[string]string:
+		copia := make(map[string]string, len(v))
+		for chave, item := range v {
+			if CampoSensivel(chave) {
+				copia[chave] = Redigido
+			} else {
+				copia[chave] = RedigirTexto(item)
+			}
+		}
+		return copia
+	case map[string]interface{}:
+		return redigirMapa(v)
+	case logrus.Fields:
+		return logrus.Fields(redigirMapa(v))
+	}
+	return valor
+}
+
+func redigirMapa(mapa map[string]interface{}) map[string]interface{} {
+	copia := make(map[string]interface{}, len(mapa))
+	for chave, item := range mapa {
+		if CampoSensivel(chave) {
+			copia[chave] = Redigido
+		} else {
+			copia[chave] = redigir(item)
+		}
+	}
+	return copia
+}
+
+// HookRedacao remove senhas, tokens, chaves de API e headers de autenticação da
+// mensagem e dos campos de cada linha de log. Deve ser o último hook registrado.
+type HookRedacao struct{}
+
+// Levels aplica o hook a todos os níveis
+func (HookRedacao) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire redige a mensagem e os campos da entrada
+func (HookRedacao) Fire(entry *logrus.Entry) error {
+	entry.Message = RedigirTexto(entry.Message)
+	for chave, valor := range entry.Data {
+		if CampoSensivel(chave) {
+			entry.Data[chave] = Redigido
+			continue
+		}
+		entry.Data[chave] = redigir(valor)
+	}
+	return nil
+}
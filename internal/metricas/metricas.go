@@ -0,0 +1,118 @@
+// Package metricas reúne as métricas Prometheus da API, expostas em /metrics
+package metricas
+
+import (
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "mobgran"
+
+// Resultados de uma importação
+const (
+	ResultadoSucesso  = "sucesso"
+	ResultadoIgnorada = "ignorada"
+	ResultadoFalha    = "falha"
+)
+
+// RotaNaoEncontrada agrupa as requisições sem rota registrada, para não criar uma
+// série por caminho desconhecido
+const RotaNaoEncontrada = "nao_encontrada"
+
+var (
+	httpRequisicoes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requisicoes_total",
+		Help:      "Requisições HTTP atendidas, por método, rota e status",
+	}, []string{"metodo", "rota", "status"})
+
+	httpDuracao = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_requisicao_duracao_segundos",
+		Help:      "Duração das requisições HTTP, por método, rota e status",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"metodo", "rota", "status"})
+
+	importacoes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "importacoes_total",
+		Help:      "Importações de ofertas, por resultado (sucesso, ignorada, falha)",
+	}, []string{"resultado"})
+
+	importacaoDuracao = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "importacao_duracao_segundos",
+		Help:      "Duração das importações de ofertas, por resultado",
+		Buckets:   []float64{0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, []string{"resultado"})
+
+	apiMobgranDuracao = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "api_mobgran_duracao_segundos",
+		Help:      "Latência das chamadas à API do Mobgran, por status HTTP (erro quando não houve resposta)",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+	}, []string{"status"})
+
+	apiMobgranErros = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_mobgran_erros_total",
+		Help:      "Falhas das chamadas à API do Mobgran, por código (status HTTP, rede ou decodificacao)",
+	}, []string{"codigo"})
+
+	cavaletesInseridos = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cavaletes_inseridos_total",
+		Help:      "Cavaletes gravados por importações confirmadas",
+	})
+
+	itensInseridos = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "itens_inseridos_total",
+		Help:      "Itens (chapas) gravados por importações confirmadas",
+	})
+)
+
+// RegistrarBanco expõe as estatísticas do pool de conexões (sql.DB.Stats) como
+// métricas go_sql_* com o rótulo db_name
+func RegistrarBanco(db *sql.DB, nome string) {
+	prometheus.MustRegister(collectors.NewDBStatsCollector(db, nome))
+}
+
+// ObservarRequisicao registra uma requisição HTTP atendida
+func ObservarRequisicao(metodo, rota string, status int, duracao time.Duration) {
+	codigo := strconv.Itoa(status)
+	httpRequisicoes.WithLabelValues(metodo, rota, codigo).Inc()
+	httpDuracao.WithLabelValues(metodo, rota, codigo).Observe(duracao.Seconds())
+}
+
+// ObservarImportacao registra uma importação encerrada com o resultado informado
+func ObservarImportacao(resultado string, duracao time.Duration) {
+	importacoes.WithLabelValues(resultado).Inc()
+	importacaoDuracao.WithLabelValues(resultado).Observe(duracao.Seconds())
+}
+
+// ObservarAPIMobgran registra uma chamada à API do Mobgran. Status 0 indica que não
+// houve resposta (erro de rede ou timeout).
+func ObservarAPIMobgran(status int, duracao time.Duration) {
+	rotulo := "erro"
+	if status > 0 {
+		rotulo = strconv.Itoa(status)
+	}
+	apiMobgranDuracao.WithLabelValues(rotulo).Observe(duracao.Seconds())
+}
+
+// ContarErroAPIMobgran registra uma falha da chamada à API do Mobgran
+func ContarErroAPIMobgran(codigo string) {
+	apiMobgranErros.WithLabelValues(codigo).Inc()
+}
+
+// ContarInseridos registra os cavaletes e itens gravados por uma importação
+func ContarInseridos(cavaletes, itens int) {
+	cavaletesInseridos.Add(float64(cavaletes))
+	itensInseridos.Add(float64(itens))
+}
@@ -0,0 +1,107 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"mobgran-importer-go/internal/auth"
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RoleAPIKey identifica no contexto requisições autenticadas por chave de API
+const RoleAPIKey = "api_key"
+
+// APIKeyValidator valida chaves de API recebidas nas requisições
+type APIKeyValidator interface {
+	ValidarAPIKey(ctx context.Context, chave string) (*models.APIKey, error)
+}
+
+// APIKeyOuSupabaseAuthMiddleware aceita chaves de API (header X-API-Key ou Bearer mgk_...)
+// além dos tokens JWT do Supabase
+func APIKeyOuSupabaseAuthMiddleware(validator APIKeyValidator) gin.HandlerFunc {
+	supabaseAuth := SupabaseAuthMiddleware()
+
+	return func(c *gin.Context) {
+		chave := extrairChaveAPI(c)
+		if chave == "" {
+			supabaseAuth(c)
+			return
+		}
+
+		apiKey, err := validator.ValidarAPIKey(c.Request.Context(), chave)
+		if err != nil {
+			logs.Do(c.Request.Context()).WithError(err).Warn("Chave de API rejeitada")
+			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
+				Error: models.APIError{
+					Type:    "authentication_error",
+					Message: "Chave de API inválida, expirada ou revogada",
+				},
+			})
+			c.Abort()
+			return
+		}
+
+		// Mesmas chaves de contexto do SupabaseAuthMiddleware, para que os handlers
+		// existentes funcionem sem alteração
+		c.Set("user_id", apiKey.TraderID.String())
+		c.Set("user_email", "")
+		c.Set("user_role", RoleAPIKey)
+		c.Set("api_key_id", apiKey.ID.String())
+		c.Set("api_key_escopos", apiKey.Escopos)
+		c.Request = c.Request.WithContext(auth.WithUserContext(c.Request.Context(), &auth.UserContext{
+			UserID:   apiKey.TraderID.String(),
+			Nome:     apiKey.Nome,
+			Role:     RoleAPIKey,
+			APIKeyID: apiKey.ID.String(),
+		}))
+
+		c.Next()
+	}
+}
+
+// RequireEscopo exige que requisições autenticadas por chave de API possuam o escopo informado.
+// Sessões de usuário do Supabase não são restringidas por escopos.
+func RequireEscopo(escopo string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		valor, exists := c.Get("api_key_escopos")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		escopos, _ := valor.([]string)
+		for _, e := range escopos {
+			if e == escopo {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, models.ErrorResponse{
+			Error: models.APIError{
+				Type:    "authorization_error",
+				Message: "Chave de API sem permissão para esta operação",
+				Details: "escopo necessário: " + escopo,
+			},
+		})
+		c.Abort()
+	}
+}
+
+// extrairChaveAPI obtém a chave de API do header X-API-Key ou do header Authorization
+func extrairChaveAPI(c *gin.Context) string {
+	if chave := c.GetHeader("X-API-Key"); chave != "" {
+		return chave
+	}
+
+	tokenString := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if strings.HasPrefix(tokenString, models.PrefixoAPIKey) {
+		return tokenString
+	}
+
+	return ""
+}
@@ -2,11 +2,15 @@ package middleware
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"os"
+	"runtime/debug"
 	"strings"
 	"time"
 
+	"mobgran-importer-go/internal/auth"
+	"mobgran-importer-go/internal/logs"
 	"mobgran-importer-go/internal/models"
 
 	"github.com/gin-gonic/gin"
@@ -58,7 +62,7 @@ func SupabaseAuthMiddleware() gin.HandlerFunc {
 		})
 
 		if err != nil || !token.Valid {
-			logrus.WithError(err).Error("Token JWT inválido")
+			logs.Do(c.Request.Context()).WithError(err).Error("Token JWT inválido")
 			c.JSON(http.StatusUnauthorized, models.ErrorResponse{
 				Error: models.APIError{
 					Type:    "authentication_error",
@@ -71,10 +75,27 @@ func SupabaseAuthMiddleware() gin.HandlerFunc {
 
 		// Extrai as claims do token
 		if claims, ok := token.Claims.(jwt.MapClaims); ok {
+			role := claims["role"]
+
+			// Papéis da aplicação (ex.: admin) ficam em app_metadata e têm precedência
+			if appMetadata, ok := claims["app_metadata"].(map[string]interface{}); ok {
+				if appRole, ok := appMetadata["role"].(string); ok && appRole != "" {
+					role = appRole
+				}
+			}
+
 			// Adiciona as informações do usuário ao contexto
 			c.Set("user_id", claims["sub"])
 			c.Set("user_email", claims["email"])
-			c.Set("user_role", claims["role"])
+			c.Set("user_role", role)
+
+			// Disponibiliza o usuário também no contexto da requisição, usado pelos services
+			userCtx := &auth.UserContext{}
+			userCtx.UserID, _ = claims["sub"].(string)
+			userCtx.Email, _ = claims["email"].(string)
+			userCtx.Role, _ = role.(string)
+			userCtx.SessionID, _ = claims["session_id"].(string)
+			c.Request = c.Request.WithContext(auth.WithUserContext(c.Request.Context(), userCtx))
 		}
 
 		c.Next()
@@ -140,7 +161,7 @@ func CORSMiddleware() gin.HandlerFunc {
 		}
 
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-API-Key, X-Request-ID")
 		c.Header("Access-Control-Allow-Credentials", "true")
 
 		if c.Request.Method == "OPTIONS" {
@@ -172,27 +193,51 @@ func SecurityHeadersMiddleware() gin.HandlerFunc {
 	}
 }
 
-// LoggerMiddleware adiciona logging customizado
+// LoggerMiddleware registra uma linha JSON por requisição, com o logger do contexto
+// (request_id, user_id e trace), a rota registrada e a query sem credenciais
 func LoggerMiddleware() gin.HandlerFunc {
-	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
-			param.ClientIP,
-			param.TimeStamp.Format(time.RFC1123),
-			param.Method,
-			param.Path,
-			param.Request.Proto,
-			param.StatusCode,
-			param.Latency,
-			param.Request.UserAgent(),
-			param.ErrorMessage,
-		)
-	})
+	return func(c *gin.Context) {
+		inicio := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		entry := logs.Do(c.Request.Context()).WithFields(logrus.Fields{
+			"metodo":      c.Request.Method,
+			"rota":        c.FullPath(),
+			"caminho":     c.Request.URL.Path,
+			"status":      status,
+			"latencia_ms": time.Since(inicio).Milliseconds(),
+			"ip":          c.ClientIP(),
+			"user_agent":  c.Request.UserAgent(),
+			"bytes":       c.Writer.Size(),
+		})
+		if query := c.Request.URL.RawQuery; query != "" {
+			entry = entry.WithField("query", logs.RedigirTexto("?" + query)[1:])
+		}
+		if erros := c.Errors.ByType(gin.ErrorTypePrivate).String(); erros != "" {
+			entry = entry.WithField("erros", erros)
+		}
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("Requisição concluída com erro")
+		case status >= http.StatusBadRequest:
+			entry.Warn("Requisição recusada")
+		default:
+			entry.Info("Requisição concluída")
+		}
+	}
 }
 
-// RecoveryMiddleware adiciona recuperação de panic customizada
+// RecoveryMiddleware recupera panics, registrando o valor e a pilha no log da
+// requisição, e responde 500
 func RecoveryMiddleware() gin.HandlerFunc {
-	return gin.CustomRecovery(func(c *gin.Context, recovered interface{}) {
-		logrus.WithField("panic", recovered).Error("Panic recuperado")
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		logs.Do(c.Request.Context()).WithFields(logrus.Fields{
+			"panic": fmt.Sprint(recovered),
+			"pilha": string(debug.Stack()),
+		}).Error("Panic recuperado")
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
 			Error: models.APIError{
 				Type:    "internal_error",
@@ -200,4 +245,4 @@ func RecoveryMiddleware() gin.HandlerFunc {
 			},
 		})
 	})
-}
\ No newline at end of file
+}
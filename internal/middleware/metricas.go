@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"mobgran-importer-go/internal/metricas"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricasMiddleware registra a contagem e a duração de cada requisição pela rota
+// registrada (ex.: /produtos/:id), não pelo caminho recebido
+func MetricasMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		inicio := time.Now()
+
+		c.Next()
+
+		rota := c.FullPath()
+		if rota == "" {
+			rota = metricas.RotaNaoEncontrada
+		}
+		metricas.ObservarRequisicao(c.Request.Method, rota, c.Writer.Status(), time.Since(inicio))
+	}
+}
+
+// MetricasTokenMiddleware exige o token informado em "Authorization: Bearer" para ler
+// as métricas. Com token vazio, o endpoint fica aberto (proteja-o na rede ou no proxy).
+func MetricasTokenMiddleware(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.Next()
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("Authorization")), []byte("Bearer "+token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"erro": "Token de métricas inválido"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"mobgran-importer-go/internal/rastreamento"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// HeaderTraceID é o header da resposta com o ID do trace da requisição
+const HeaderTraceID = "X-Trace-ID"
+
+// rotasSemTrace não geram spans: são consultadas a todo momento por monitoramento
+var rotasSemTrace = map[string]bool{
+	"/health":       true,
+	"/health/live":  true,
+	"/health/ready": true,
+	"/metrics":      true,
+}
+
+// RastreamentoMiddleware abre um span por requisição, continuando o trace recebido no
+// header traceparent, e devolve o ID do trace em X-Trace-ID
+func RastreamentoMiddleware() gin.HandlersChain {
+	return gin.HandlersChain{
+		otelgin.Middleware(rastreamento.NomeServico, otelgin.WithGinFilter(func(c *gin.Context) bool {
+			return !rotasSemTrace[c.FullPath()]
+		})),
+		func(c *gin.Context) {
+			if span := trace.SpanContextFromContext(c.Request.Context()); span.IsValid() {
+				c.Header(HeaderTraceID, span.TraceID().String())
+			}
+			c.Next()
+		},
+	}
+}
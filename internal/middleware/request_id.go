@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"mobgran-importer-go/internal/requestctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// HeaderRequestID é o header usado para propagar o ID da requisição
+const HeaderRequestID = "X-Request-ID"
+
+// RequestIDMiddleware reaproveita o X-Request-ID recebido ou gera um novo,
+// disponibilizando-o no contexto da requisição e no header da resposta
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(HeaderRequestID)
+		if requestID == "" || len(requestID) > 128 {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("request_id", requestID)
+		c.Header(HeaderRequestID, requestID)
+		c.Request = c.Request.WithContext(requestctx.WithRequestID(c.Request.Context(), requestID))
+
+		c.Next()
+	}
+}
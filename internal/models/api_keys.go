@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrefixoAPIKey identifica chaves de API geradas pelo sistema
+const PrefixoAPIKey = "mgk_"
+
+// Escopos disponíveis para chaves de API
+const (
+	EscopoProdutosLeitura   = "produtos:leitura"
+	EscopoProdutosEscrita   = "produtos:escrita"
+	EscopoImportacaoEscrita = "importacao:escrita"
+	EscopoOrcamentosLeitura = "orcamentos:leitura"
+	EscopoOrcamentosEscrita = "orcamentos:escrita"
+	EscopoClientesLeitura   = "clientes:leitura"
+	EscopoClientesEscrita   = "clientes:escrita"
+)
+
+// EscoposAPIKeyValidos lista os escopos que podem ser concedidos a uma chave
+var EscoposAPIKeyValidos = []string{
+	EscopoProdutosLeitura,
+	EscopoProdutosEscrita,
+	EscopoImportacaoEscrita,
+	EscopoOrcamentosLeitura,
+	EscopoOrcamentosEscrita,
+	EscopoClientesLeitura,
+	EscopoClientesEscrita,
+}
+
+// APIKey representa uma chave de API de um trader
+type APIKey struct {
+	ID          uuid.UUID  `json:"id" db:"id"`
+	TraderID    uuid.UUID  `json:"trader_id" db:"trader_id"`
+	Nome        string     `json:"nome" db:"nome"`
+	Prefixo     string     `json:"prefixo" db:"prefixo"`
+	ChaveHash   string     `json:"-" db:"chave_hash"`
+	Escopos     []string   `json:"escopos" db:"escopos"`
+	ExpiraEm    *time.Time `json:"expira_em,omitempty" db:"expira_em"`
+	UltimoUsoEm *time.Time `json:"ultimo_uso_em,omitempty" db:"ultimo_uso_em"`
+	RevogadaEm  *time.Time `json:"revogada_em,omitempty" db:"revogada_em"`
+	CreatedAt   time.Time  `json:"created_at" db:"created_at"`
+}
+
+// APIKeyCriarRequest representa os dados para criar uma chave de API
+type APIKeyCriarRequest struct {
+	Nome     string     `json:"nome" binding:"required,min=1,max=255"`
+	Escopos  []string   `json:"escopos" binding:"required,min=1"`
+	ExpiraEm *time.Time `json:"expira_em,omitempty"`
+}
+
+// APIKeyCriadaResponse representa a resposta de criação, única vez em que a chave é exibida
+type APIKeyCriadaResponse struct {
+	APIKey
+	Chave string `json:"chave"`
+}
+
+// PossuiEscopo verifica se a chave concede o escopo informado
+func (k *APIKey) PossuiEscopo(escopo string) bool {
+	for _, e := range k.Escopos {
+		if e == escopo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ConfirmacaoPurga é o texto que o administrador deve enviar para confirmar a purga
+const ConfirmacaoPurga = "PURGAR DADOS ARQUIVADOS"
+
+// ArquivoRequest representa o escopo de um arquivamento ou restauração.
+// Sem oferta_id, a operação se aplica a todas as ofertas do trader.
+type ArquivoRequest struct {
+	OfertaID *uuid.UUID `json:"oferta_id,omitempty"`
+}
+
+// ArquivoResultado representa a quantidade de registros afetados por arquivamento, restauração ou purga
+type ArquivoResultado struct {
+	Ofertas   int64 `json:"ofertas"`
+	Cavaletes int64 `json:"cavaletes"`
+	Produtos  int64 `json:"produtos"`
+}
+
+// PurgaRequest representa uma purga definitiva de registros arquivados
+type PurgaRequest struct {
+	Confirmacao       string     `json:"confirmacao" binding:"required"`
+	TraderID          *uuid.UUID `json:"trader_id,omitempty"`
+	ArquivadosAntesDe *time.Time `json:"arquivados_antes_de,omitempty"`
+	Simular           bool       `json:"simular"`
+}
+
+// PurgaResponse representa o resultado de uma purga. ProdutosMantidos conta os produtos
+// arquivados que não foram purgados por estarem vendidos, reservados ou em orçamentos
+// fora de rascunho.
+type PurgaResponse struct {
+	ArquivoResultado
+	ProdutosMantidos int64 `json:"produtos_mantidos"`
+	Simulacao        bool  `json:"simulacao"`
+}
@@ -0,0 +1,89 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// Ações registradas na trilha de auditoria
+const (
+	AcaoOfertaImportada     = "oferta.importada"
+	AcaoOfertaAtualizada    = "oferta.atualizada"
+	AcaoProdutoAprovado     = "produto.aprovado"
+	AcaoProdutoAtualizado   = "produto.atualizado"
+	AcaoProdutoRemovido     = "produto.removido"
+	AcaoProdutoReservado    = "produto.reservado"
+	AcaoReservaLiberada     = "produto.reserva_liberada"
+	AcaoReservaExpirada     = "produto.reserva_expirada"
+	AcaoProdutoVendido      = "produto.vendido"
+	AcaoArquivado           = "arquivo.arquivado"
+	AcaoRestaurado          = "arquivo.restaurado"
+	AcaoDadosPurgados       = "dados.purgados"
+	AcaoImagemAdicionada    = "produto.imagem_adicionada"
+	AcaoImagemRemovida      = "produto.imagem_removida"
+	AcaoGaleriaAtualizada   = "produto.galeria_atualizada"
+	AcaoSlugAtualizado      = "trader.slug_atualizado"
+	AcaoMarcaAtualizada     = "trader.marca_atualizada"
+	AcaoOrcamentoCriado     = "orcamento.criado"
+	AcaoOrcamentoAtualizado = "orcamento.atualizado"
+	AcaoOrcamentoRemovido   = "orcamento.removido"
+	AcaoOrcamentoEnviado    = "orcamento.enviado"
+	AcaoOrcamentoAceito     = "orcamento.aceito"
+	AcaoOrcamentoRecusado   = "orcamento.recusado"
+	AcaoOrcamentoConvertido = "orcamento.convertido"
+	AcaoClienteCriado       = "cliente.criado"
+	AcaoClienteAtualizado   = "cliente.atualizado"
+	AcaoClienteRemovido     = "cliente.removido"
+	AcaoMaterialCriado      = "catalogo.material_criado"
+	AcaoMaterialAtualizado  = "catalogo.material_atualizado"
+	AcaoMaterialRemovido    = "catalogo.material_removido"
+	AcaoMapeamentoDefinido  = "catalogo.mapeamento_definido"
+	AcaoMapeamentoRemovido  = "catalogo.mapeamento_removido"
+	AcaoFornecedorEditado   = "fornecedor.atualizado"
+	AcaoFornecedorMesclado  = "fornecedor.mesclado"
+	AcaoAssinaturaCriada    = "notificacao.assinatura_criada"
+	AcaoAssinaturaEditada   = "notificacao.assinatura_atualizada"
+	AcaoAssinaturaRemovida  = "notificacao.assinatura_removida"
+	AcaoNotificacaoReenvio  = "notificacao.reenviada"
+	AcaoAPIKeyCriada        = "api_key.criada"
+	AcaoAPIKeyRevogada      = "api_key.revogada"
+)
+
+// Tipos de ator da trilha de auditoria
+const (
+	AtorTipoUsuario = "usuario"
+	AtorTipoAPIKey  = "api_key"
+	AtorTipoSistema = "sistema"
+)
+
+// RegistroAuditoria representa uma entrada da trilha de auditoria
+type RegistroAuditoria struct {
+	ID          uuid.UUID       `json:"id" db:"id"`
+	AtorID      *string         `json:"ator_id,omitempty" db:"ator_id"`
+	AtorEmail   *string         `json:"ator_email,omitempty" db:"ator_email"`
+	AtorTipo    string          `json:"ator_tipo" db:"ator_tipo"`
+	APIKeyID    *uuid.UUID      `json:"api_key_id,omitempty" db:"api_key_id"`
+	Acao        string          `json:"acao" db:"acao"`
+	Entidade    string          `json:"entidade" db:"entidade"`
+	EntidadeID  *string         `json:"entidade_id,omitempty" db:"entidade_id"`
+	DadosAntes  json.RawMessage `json:"dados_antes,omitempty" db:"dados_antes" swaggertype:"object"`
+	DadosDepois json.RawMessage `json:"dados_depois,omitempty" db:"dados_depois" swaggertype:"object"`
+	RequestID   *string         `json:"request_id,omitempty" db:"request_id"`
+	CreatedAt   time.Time       `json:"created_at" db:"created_at"`
+}
+
+// FiltroAuditoria representa os filtros da consulta à trilha de auditoria
+type FiltroAuditoria struct {
+	AtorID     string
+	Acao       string
+	Entidade   string
+	EntidadeID string
+	RequestID  string
+	Desde      *time.Time
+	Ate        *time.Time
+	Paginacao  paginacao.Parametros
+}
@@ -58,18 +58,28 @@ type TraderResponse struct {
 }
 
 // ProdutoAprovado representa um produto na vitrine do trader
+
 type ProdutoAprovado struct {
-	ID              uuid.UUID `json:"id" db:"id"`
-	TraderID        uuid.UUID `json:"trader_id" db:"trader_id"`
-	CavaleteID      uuid.UUID `json:"cavalete_id" db:"cavalete_id"`
-	NomeCustomizado string    `json:"nome_customizado" db:"nome_customizado" binding:"required,min=1,max=255"`
-	PrecoVenda      float64   `json:"preco_venda" db:"preco_venda" binding:"required,gt=0"`
-	Descricao       *string   `json:"descricao,omitempty" db:"descricao"`
-	Visivel         bool      `json:"visivel" db:"visivel"`
-	Destaque        bool      `json:"destaque" db:"destaque"`
-	OrdemExibicao   int       `json:"ordem_exibicao" db:"ordem_exibicao"`
-	CreatedAt       time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+	ID                uuid.UUID  `json:"id" db:"id"`
+	TraderID          uuid.UUID  `json:"trader_id" db:"trader_id"`
+	CavaleteID        uuid.UUID  `json:"cavalete_id" db:"cavalete_id"`
+	NomeCustomizado   string     `json:"nome_customizado" db:"nome_customizado" binding:"required,min=1,max=255"`
+	PrecoVenda        float64    `json:"preco_venda" db:"preco_venda" binding:"required,gt=0"`
+	Descricao         *string    `json:"descricao,omitempty" db:"descricao"`
+	Visivel           bool       `json:"visivel" db:"visivel"`
+	Destaque          bool       `json:"destaque" db:"destaque"`
+	OrdemExibicao     int        `json:"ordem_exibicao" db:"ordem_exibicao"`
+	Status            string     `json:"status" db:"status"`
+	Cliente           *string    `json:"cliente,omitempty" db:"cliente"`
+	ClienteID         *uuid.UUID `json:"cliente_id,omitempty" db:"cliente_id"`
+	ReservaObservacao *string    `json:"reserva_observacao,omitempty" db:"reserva_observacao"`
+	ReservadoEm       *time.Time `json:"reservado_em,omitempty" db:"reservado_em"`
+	ReservaExpiraEm   *time.Time `json:"reserva_expira_em,omitempty" db:"reserva_expira_em"`
+	VendidoEm         *time.Time `json:"vendido_em,omitempty" db:"vendido_em"`
+	ValorVenda        *float64   `json:"valor_venda,omitempty" db:"valor_venda"`
+	RemovidoNaOrigem  bool       `json:"removido_na_origem"`
+	CreatedAt         time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at" db:"updated_at"`
 }
 
 // ProdutoAprovarRequest representa os dados para aprovar um produto
@@ -100,6 +110,8 @@ type CavaleteDisponivel struct {
 	Bloco             string      `json:"bloco" db:"bloco"`
 	NomeMaterial      string      `json:"nome_material" db:"nome_material"`
 	NomeEspessura     string      `json:"nome_espessura" db:"nome_espessura"`
+	ProdutoCliente    *string     `json:"produto_cliente,omitempty" db:"produto_cliente"`
+	EspessuraCliente  *string     `json:"espessura_cliente,omitempty" db:"espessura_cliente"`
 	NomeClassificacao string      `json:"nome_classificacao" db:"nome_classificacao"`
 	NomeAcabamento    *string     `json:"nome_acabamento" db:"nome_acabamento"`
 	Comprimento       *float64    `json:"comprimento" db:"comprimento"`
@@ -108,12 +120,14 @@ type CavaleteDisponivel struct {
 	Metragem          *float64    `json:"metragem" db:"metragem"`
 	Peso              *float64    `json:"peso" db:"peso"`
 	TipoMetragem      *string     `json:"tipo_metragem" db:"tipo_metragem"`
-	ImagemPrincipal   interface{} `json:"imagem_principal" db:"imagem_principal"`
-	ImagensAdicionais interface{} `json:"imagens_adicionais" db:"imagens_adicionais"`
+	Medidas           Medidas     `json:"medidas"`
+	ImagemPrincipal   JSONB       `json:"imagem_principal" db:"imagem_principal" swaggertype:"object"`
+	ImagensAdicionais JSONB       `json:"imagens_adicionais" db:"imagens_adicionais" swaggertype:"object"`
 	CreatedAt         time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt         time.Time   `json:"updated_at" db:"updated_at"`
 	TraderID          uuid.UUID   `json:"trader_id" db:"trader_id"`
 	NomeEmpresa       string      `json:"nome_empresa" db:"nome_empresa"`
+	FornecedorID      *uuid.UUID  `json:"fornecedor_id,omitempty" db:"fornecedor_id"`
 	JaAprovado        bool        `json:"ja_aprovado" db:"ja_aprovado"`
 }
 
@@ -130,6 +144,8 @@ type VitrinePublica struct {
 	Bloco           string      `json:"bloco" db:"bloco"`
 	NomeMaterial    string      `json:"nome_material" db:"nome_material"`
 	NomeEspessura   string      `json:"nome_espessura" db:"nome_espessura"`
+	ProdutoCliente  *string     `json:"produto_cliente,omitempty" db:"produto_cliente"`
+	EspessuraCliente *string    `json:"espessura_cliente,omitempty" db:"espessura_cliente"`
 	NomeClassificacao *string   `json:"nome_classificacao,omitempty" db:"nome_classificacao"`
 	NomeAcabamento  *string     `json:"nome_acabamento,omitempty" db:"nome_acabamento"`
 	Comprimento     *float64    `json:"comprimento,omitempty" db:"comprimento"`
@@ -138,10 +154,13 @@ type VitrinePublica struct {
 	Metragem        *float64    `json:"metragem,omitempty" db:"metragem"`
 	Peso            *float64    `json:"peso,omitempty" db:"peso"`
 	TipoMetragem    *string     `json:"tipo_metragem,omitempty" db:"tipo_metragem"`
-	ImagemPrincipal interface{} `json:"imagem_principal,omitempty" db:"imagem_principal"`
-	ImagensAdicionais interface{} `json:"imagens_adicionais,omitempty" db:"imagens_adicionais"`
+	Medidas         Medidas     `json:"medidas"`
+	ImagemPrincipal JSONB       `json:"imagem_principal,omitempty" db:"imagem_principal" swaggertype:"object"`
+	ImagensAdicionais JSONB     `json:"imagens_adicionais,omitempty" db:"imagens_adicionais" swaggertype:"object"`
 	TraderNome      string      `json:"trader_nome" db:"trader_nome"`
 	TraderEmpresa   *string     `json:"trader_empresa,omitempty" db:"trader_empresa"`
+	FornecedorID    *uuid.UUID  `json:"fornecedor_id,omitempty" db:"fornecedor_id"`
+	Status          string      `json:"status" db:"status"`
 	CreatedAt       time.Time   `json:"created_at" db:"created_at"`
 	UpdatedAt       time.Time   `json:"updated_at" db:"updated_at"`
 }
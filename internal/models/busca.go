@@ -0,0 +1,76 @@
+package models
+
+import "github.com/google/uuid"
+
+// FiltroBusca representa o termo de busca textual e os filtros de uma busca de cavaletes ou produtos
+type FiltroBusca struct {
+	Termo             string
+	Material          string
+	Espessura         string
+	Classificacao     string
+	Acabamento        string
+	FornecedorID      *uuid.UUID
+	ComprimentoMin    *float64
+	ComprimentoMax    *float64
+	AlturaMin         *float64
+	AlturaMax         *float64
+	MetragemMin       *float64
+	MetragemMax       *float64
+	ApenasDisponiveis bool
+	Limit             int
+	Offset            int
+}
+
+// Faceta representa a quantidade de resultados para um valor de filtro
+type Faceta struct {
+	Valor      string `json:"valor"`
+	Quantidade int    `json:"quantidade"`
+}
+
+// FacetasBusca agrupa as contagens por material, espessura e classificação.
+// Cada faceta considera todos os filtros exceto o da própria dimensão.
+type FacetasBusca struct {
+	Materiais      []Faceta `json:"materiais"`
+	Espessuras     []Faceta `json:"espessuras"`
+	Classificacoes []Faceta `json:"classificacoes"`
+}
+
+// CavaleteEncontrado representa um cavalete retornado pela busca
+type CavaleteEncontrado struct {
+	CavaleteDisponivel
+	Relevancia float64 `json:"relevancia"`
+}
+
+// ProdutoEncontrado representa um produto aprovado retornado pela busca, com os dados do cavalete
+type ProdutoEncontrado struct {
+	ProdutoAprovado
+	Codigo            string   `json:"codigo"`
+	Bloco             string   `json:"bloco"`
+	NomeMaterial      string   `json:"nome_material"`
+	NomeEspessura     string   `json:"nome_espessura"`
+	NomeClassificacao *string  `json:"nome_classificacao,omitempty"`
+	NomeAcabamento    *string  `json:"nome_acabamento,omitempty"`
+	Comprimento       *float64 `json:"comprimento,omitempty"`
+	Altura            *float64 `json:"altura,omitempty"`
+	Metragem          *float64 `json:"metragem,omitempty"`
+	Medidas           Medidas  `json:"medidas"`
+	Relevancia        float64  `json:"relevancia"`
+}
+
+// ResultadoBuscaCavaletes representa a resposta da busca de cavaletes
+type ResultadoBuscaCavaletes struct {
+	Cavaletes []CavaleteEncontrado `json:"cavaletes"`
+	Total     int                  `json:"total"`
+	Facetas   FacetasBusca         `json:"facetas"`
+	Limit     int                  `json:"limit"`
+	Offset    int                  `json:"offset"`
+}
+
+// ResultadoBuscaProdutos representa a resposta da busca de produtos aprovados
+type ResultadoBuscaProdutos struct {
+	Produtos []ProdutoEncontrado `json:"produtos"`
+	Total    int                 `json:"total"`
+	Facetas  FacetasBusca        `json:"facetas"`
+	Limit    int                 `json:"limit"`
+	Offset   int                 `json:"offset"`
+}
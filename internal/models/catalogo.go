@@ -0,0 +1,99 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de mapeamento dos nomes importados para o catálogo
+const (
+	TipoMapeamentoMaterial  = "material"
+	TipoMapeamentoEspessura = "espessura"
+)
+
+// Origem da correspondência aplicada a um cavalete
+const (
+	OrigemCatalogoAutomatico = "automatico"
+	OrigemCatalogoTrader     = "trader"
+)
+
+// MaterialCatalogo representa um material do catálogo curado
+type MaterialCatalogo struct {
+	ID        uuid.UUID          `json:"id" db:"id"`
+	Nome      string             `json:"nome" db:"nome"`
+	Tipo      *string            `json:"tipo,omitempty" db:"tipo" example:"granito"`
+	Sinonimos []SinonimoMaterial `json:"sinonimos"`
+	CreatedAt time.Time          `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at" db:"updated_at"`
+}
+
+// SinonimoMaterial representa outro nome pelo qual o material é conhecido
+type SinonimoMaterial struct {
+	ID       uuid.UUID `json:"id" db:"id"`
+	Sinonimo string    `json:"sinonimo" db:"sinonimo"`
+}
+
+// EspessuraCatalogo representa uma espessura padrão do catálogo
+type EspessuraCatalogo struct {
+	ID          uuid.UUID `json:"id" db:"id"`
+	Nome        string    `json:"nome" db:"nome"`
+	EspessuraMM float64   `json:"espessura_mm" db:"espessura_mm"`
+}
+
+// MaterialCatalogoRequest representa os dados para incluir um material no catálogo
+type MaterialCatalogoRequest struct {
+	Nome      string   `json:"nome" binding:"required,min=1,max=255" example:"Branco Siena"`
+	Tipo      *string  `json:"tipo,omitempty" binding:"omitempty,max=50"`
+	Sinonimos []string `json:"sinonimos,omitempty" binding:"omitempty,max=50,dive,min=1,max=255"`
+}
+
+// MaterialCatalogoAtualizarRequest representa os dados a alterar em um material
+type MaterialCatalogoAtualizarRequest struct {
+	Nome *string `json:"nome,omitempty" binding:"omitempty,min=1,max=255"`
+	Tipo *string `json:"tipo,omitempty" binding:"omitempty,max=50"`
+}
+
+// SinonimoRequest representa um sinônimo a incluir em um material
+type SinonimoRequest struct {
+	Sinonimo string `json:"sinonimo" binding:"required,min=1,max=255" example:"Siena Branco"`
+}
+
+// MapeamentoCatalogo representa um nome importado pelo trader e o item do catálogo
+// associado a ele. Sem catalogo_id, o nome ainda não tem correspondência.
+type MapeamentoCatalogo struct {
+	Tipo         string     `json:"tipo"`
+	ValorOrigem  string     `json:"valor_origem"`
+	Cavaletes    int        `json:"cavaletes"`
+	CatalogoID   *uuid.UUID `json:"catalogo_id,omitempty"`
+	NomeCatalogo *string    `json:"nome_catalogo,omitempty"`
+	Origem       *string    `json:"origem,omitempty" example:"automatico"`
+}
+
+// DefinirMapeamentoRequest confirma ou corrige o item do catálogo de um nome importado
+type DefinirMapeamentoRequest struct {
+	Tipo        string    `json:"tipo" binding:"required,oneof=material espessura"`
+	ValorOrigem string    `json:"valor_origem" binding:"required,min=1,max=255" example:"BRANCO SIENNA"`
+	CatalogoID  uuid.UUID `json:"catalogo_id" binding:"required"`
+}
+
+// MapeamentoDefinido é o resultado da confirmação ou correção de um mapeamento
+type MapeamentoDefinido struct {
+	Tipo                 string    `json:"tipo"`
+	ValorOrigem          string    `json:"valor_origem"`
+	CatalogoID           uuid.UUID `json:"catalogo_id"`
+	NomeCatalogo         string    `json:"nome_catalogo"`
+	CavaletesAtualizados int       `json:"cavaletes_atualizados"`
+}
+
+// FiltroMapeamentos representa os filtros da listagem de mapeamentos do trader
+type FiltroMapeamentos struct {
+	Tipo string
+	// Pendentes lista apenas os nomes ainda não confirmados pelo trader
+	Pendentes bool
+}
+
+// AplicacaoCatalogoResultado resume a reaplicação do catálogo aos cavaletes
+type AplicacaoCatalogoResultado struct {
+	CavaletesAtualizados int `json:"cavaletes_atualizados"`
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// MaxContatosPorCliente limita as pessoas de contato de um cliente
+const MaxContatosPorCliente = 20
+
+// Cliente representa um comprador cadastrado pelo trader
+type Cliente struct {
+	ID            uuid.UUID        `json:"id" db:"id"`
+	TraderID      uuid.UUID        `json:"trader_id" db:"trader_id"`
+	Nome          string           `json:"nome" db:"nome"`
+	Documento     *string          `json:"documento,omitempty" db:"documento"`
+	TipoDocumento *string          `json:"tipo_documento,omitempty" db:"tipo_documento"`
+	Email         *string          `json:"email,omitempty" db:"email"`
+	Telefone      *string          `json:"telefone,omitempty" db:"telefone"`
+	Endereco      EnderecoCliente  `json:"endereco"`
+	Observacoes   *string          `json:"observacoes,omitempty" db:"observacoes"`
+	Contatos      []ContatoCliente `json:"contatos"`
+	CreatedAt     time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// EnderecoCliente representa o endereço do cliente
+type EnderecoCliente struct {
+	CEP         *string `json:"cep,omitempty" db:"cep" binding:"omitempty,max=9"`
+	Logradouro  *string `json:"logradouro,omitempty" db:"logradouro" binding:"omitempty,max=255"`
+	Numero      *string `json:"numero,omitempty" db:"numero" binding:"omitempty,max=20"`
+	Complemento *string `json:"complemento,omitempty" db:"complemento" binding:"omitempty,max=255"`
+	Bairro      *string `json:"bairro,omitempty" db:"bairro" binding:"omitempty,max=100"`
+	Cidade      *string `json:"cidade,omitempty" db:"cidade" binding:"omitempty,max=100"`
+	UF          *string `json:"uf,omitempty" db:"uf" binding:"omitempty,len=2"`
+}
+
+// ContatoCliente representa uma pessoa de contato do cliente
+type ContatoCliente struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Nome      string    `json:"nome" db:"nome"`
+	Cargo     *string   `json:"cargo,omitempty" db:"cargo"`
+	Email     *string   `json:"email,omitempty" db:"email"`
+	Telefone  *string   `json:"telefone,omitempty" db:"telefone"`
+	Principal bool      `json:"principal" db:"principal"`
+}
+
+// ContatoClienteRequest representa uma pessoa de contato enviada no cadastro
+type ContatoClienteRequest struct {
+	Nome      string  `json:"nome" binding:"required,min=1,max=255"`
+	Cargo     *string `json:"cargo,omitempty" binding:"omitempty,max=100"`
+	Email     *string `json:"email,omitempty" binding:"omitempty,email,max=255"`
+	Telefone  *string `json:"telefone,omitempty" binding:"omitempty,max=20"`
+	Principal bool    `json:"principal"`
+}
+
+// ClienteCriarRequest representa os dados para cadastrar um cliente. O documento
+// aceita CPF ou CNPJ, com ou sem pontuação.
+type ClienteCriarRequest struct {
+	Nome        string                  `json:"nome" binding:"required,min=1,max=255"`
+	Documento   *string                 `json:"documento,omitempty" binding:"omitempty,max=18" example:"11.222.333/0001-81"`
+	Email       *string                 `json:"email,omitempty" binding:"omitempty,email,max=255"`
+	Telefone    *string                 `json:"telefone,omitempty" binding:"omitempty,max=20"`
+	Endereco    *EnderecoCliente        `json:"endereco,omitempty"`
+	Observacoes *string                 `json:"observacoes,omitempty"`
+	Contatos    []ContatoClienteRequest `json:"contatos,omitempty" binding:"omitempty,max=20,dive"`
+}
+
+// ClienteAtualizarRequest representa os dados a alterar no cadastro. Campos ausentes
+// são mantidos; documento vazio apaga o documento; contatos, quando enviados,
+// substituem a lista inteira.
+type ClienteAtualizarRequest struct {
+	Nome        *string                  `json:"nome,omitempty" binding:"omitempty,min=1,max=255"`
+	Documento   *string                  `json:"documento,omitempty" binding:"omitempty,max=18"`
+	Email       *string                  `json:"email,omitempty" binding:"omitempty,max=255"`
+	Telefone    *string                  `json:"telefone,omitempty" binding:"omitempty,max=20"`
+	Endereco    *EnderecoCliente         `json:"endereco,omitempty"`
+	Observacoes *string                  `json:"observacoes,omitempty"`
+	Contatos    *[]ContatoClienteRequest `json:"contatos,omitempty" binding:"omitempty,max=20,dive"`
+}
+
+// FiltroClientes representa os filtros da listagem de clientes
+type FiltroClientes struct {
+	Busca     string
+	Paginacao paginacao.Parametros
+}
+
+// HistoricoCliente reúne os orçamentos, reservas e compras de um cliente
+type HistoricoCliente struct {
+	Cliente    *Cliente          `json:"cliente"`
+	Resumo     ResumoCliente     `json:"resumo"`
+	Orcamentos []Orcamento       `json:"orcamentos"`
+	Produtos   []ProdutoAprovado `json:"produtos"`
+}
+
+// ResumoCliente resume o relacionamento com o cliente
+type ResumoCliente struct {
+	Orcamentos         int     `json:"orcamentos"`
+	OrcamentosAceitos  int     `json:"orcamentos_aceitos"`
+	ProdutosReservados int     `json:"produtos_reservados"`
+	ProdutosComprados  int     `json:"produtos_comprados"`
+	ValorComprado      float64 `json:"valor_comprado"`
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Agrupamentos da série de importações do painel
+const (
+	AgrupamentoDia    = "dia"
+	AgrupamentoSemana = "semana"
+	AgrupamentoMes    = "mes"
+)
+
+// FiltroEstatisticas delimita o período do painel. O estoque importado considera a
+// última importação de cada oferta; os produtos, a data de aprovação; as vendas, a
+// data da venda.
+type FiltroEstatisticas struct {
+	Desde       *time.Time
+	Ate         *time.Time
+	Agrupamento string
+}
+
+// EstatisticasTrader é o painel do trader: estoque importado e aprovado, valores,
+// distribuição por material e fornecedor e a série de importações
+type EstatisticasTrader struct {
+	Desde         *time.Time             `json:"desde,omitempty"`
+	Ate           *time.Time             `json:"ate,omitempty"`
+	Agrupamento   string                 `json:"agrupamento"`
+	Produtos      ResumoProdutosPainel   `json:"produtos"`
+	Estoque       ResumoEstoquePainel    `json:"estoque"`
+	PorMaterial   []EstatisticaGrupo     `json:"por_material"`
+	PorFornecedor []EstatisticaGrupo     `json:"por_fornecedor"`
+	Importacoes   []PontoSerieImportacao `json:"importacoes"`
+}
+
+// ResumoProdutosPainel conta os produtos aprovados pelo status de venda
+type ResumoProdutosPainel struct {
+	Total       int `json:"total"`
+	Visiveis    int `json:"visiveis"`
+	Destaque    int `json:"destaque"`
+	Disponiveis int `json:"disponiveis"`
+	Reservados  int `json:"reservados"`
+	Vendidos    int `json:"vendidos"`
+}
+
+// ResumoEstoquePainel compara o estoque importado com o aprovado. Os valores usam o
+// preço de venda por m²; produtos sem metragem cadastrada não entram nos valores.
+type ResumoEstoquePainel struct {
+	Ofertas              int     `json:"ofertas"`
+	OfertasAtivas        int     `json:"ofertas_ativas"`
+	CavaletesImportados  int     `json:"cavaletes_importados"`
+	CavaletesDisponiveis int     `json:"cavaletes_disponiveis"`
+	MetragemImportadaM2  float64 `json:"metragem_importada_m2"`
+	MetragemAprovadaM2   float64 `json:"metragem_aprovada_m2"`
+	PercentualAprovado   float64 `json:"percentual_aprovado"`
+	ValorEstoque         float64 `json:"valor_estoque"`
+	ValorReservado       float64 `json:"valor_reservado"`
+	ValorVendido         float64 `json:"valor_vendido"`
+	ProdutosSemMetragem  int     `json:"produtos_sem_metragem"`
+}
+
+// EstatisticaGrupo totaliza o estoque de um material ou fornecedor. ID é nulo para
+// materiais fora do catálogo e ofertas sem fornecedor vinculado.
+type EstatisticaGrupo struct {
+	ID                  *uuid.UUID `json:"id,omitempty"`
+	Nome                string     `json:"nome"`
+	Cavaletes           int        `json:"cavaletes"`
+	MetragemImportadaM2 float64    `json:"metragem_importada_m2"`
+	Produtos            int        `json:"produtos"`
+	MetragemAprovadaM2  float64    `json:"metragem_aprovada_m2"`
+	ValorEstoque        float64    `json:"valor_estoque"`
+}
+
+// PontoSerieImportacao totaliza as importações de um dia, semana ou mês
+type PontoSerieImportacao struct {
+	Periodo     time.Time `json:"periodo"`
+	Importacoes int       `json:"importacoes"`
+	Ofertas     int       `json:"ofertas"`
+	Cavaletes   int       `json:"cavaletes"`
+	MetragemM2  float64   `json:"metragem_m2"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Eventos de domínio exclusivos do stream (as demais alterações usam os mesmos nomes
+// dos eventos de notificação, ex.: produto.atualizado)
+const (
+	EventoImportacaoIniciada  = "importacao.iniciada"
+	EventoImportacaoProgresso = "importacao.progresso"
+)
+
+// Etapas informadas em importacao.progresso
+const (
+	EtapaImportacaoDados    = "dados_obtidos"
+	EtapaImportacaoImagens  = "imagens_copiadas"
+	EtapaImportacaoGravacao = "cavaletes_gravados"
+)
+
+// Evento é uma alteração de dados do trader gravada na fila de eventos (outbox) na
+// mesma transação da alteração. ID é crescente e serve como cursor do stream.
+type Evento struct {
+	ID         int64           `json:"id"`
+	Tipo       string          `json:"tipo"`
+	Entidade   string          `json:"entidade"`
+	EntidadeID *string         `json:"entidade_id,omitempty"`
+	Dados      json.RawMessage `json:"dados" swaggertype:"object"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
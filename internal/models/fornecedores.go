@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fornecedor representa a empresa de origem das ofertas importadas do Mobgran. O
+// cadastro é compartilhado entre os traders; as estatísticas consideram apenas as
+// ofertas de quem consulta (todas, para administradores).
+type Fornecedor struct {
+	ID           uuid.UUID              `json:"id" db:"id"`
+	Nome         string                 `json:"nome" db:"nome"`
+	Nomes        []string               `json:"nomes"`
+	URLLogo      *string                `json:"url_logo,omitempty" db:"url_logo"`
+	Documento    *string                `json:"documento,omitempty" db:"documento"`
+	Contato      *string                `json:"contato,omitempty" db:"contato"`
+	Email        *string                `json:"email,omitempty" db:"email"`
+	Telefone     *string                `json:"telefone,omitempty" db:"telefone"`
+	Site         *string                `json:"site,omitempty" db:"site"`
+	Cidade       *string                `json:"cidade,omitempty" db:"cidade"`
+	UF           *string                `json:"uf,omitempty" db:"uf"`
+	Observacoes  *string                `json:"observacoes,omitempty" db:"observacoes"`
+	Estatisticas EstatisticasFornecedor `json:"estatisticas"`
+	CreatedAt    time.Time              `json:"created_at" db:"created_at"`
+	UpdatedAt    time.Time              `json:"updated_at" db:"updated_at"`
+}
+
+// EstatisticasFornecedor resume as importações de um fornecedor
+type EstatisticasFornecedor struct {
+	Ofertas          int        `json:"ofertas"`
+	OfertasAtivas    int        `json:"ofertas_ativas"`
+	Cavaletes        int        `json:"cavaletes"`
+	MetragemM2       float64    `json:"metragem_m2"`
+	UltimaImportacao *time.Time `json:"ultima_importacao,omitempty"`
+}
+
+// FornecedorAtualizarRequest representa os dados de contato a alterar. Campos ausentes
+// são mantidos; campos vazios são apagados.
+type FornecedorAtualizarRequest struct {
+	Nome        *string `json:"nome,omitempty" binding:"omitempty,min=1,max=255"`
+	URLLogo     *string `json:"url_logo,omitempty" binding:"omitempty,max=2048"`
+	Documento   *string `json:"documento,omitempty" binding:"omitempty,max=18" example:"11.222.333/0001-81"`
+	Contato     *string `json:"contato,omitempty" binding:"omitempty,max=255"`
+	Email       *string `json:"email,omitempty" binding:"omitempty,max=255"`
+	Telefone    *string `json:"telefone,omitempty" binding:"omitempty,max=20"`
+	Site        *string `json:"site,omitempty" binding:"omitempty,max=255"`
+	Cidade      *string `json:"cidade,omitempty" binding:"omitempty,max=100"`
+	UF          *string `json:"uf,omitempty" binding:"omitempty,max=2"`
+	Observacoes *string `json:"observacoes,omitempty"`
+}
+
+// MesclarFornecedoresRequest indica os fornecedores duplicados a incorporar
+type MesclarFornecedoresRequest struct {
+	FornecedorIDs []uuid.UUID `json:"fornecedor_ids" binding:"required,min=1,max=50"`
+}
+
+// VinculacaoFornecedoresResultado resume a vinculação das ofertas pendentes
+type VinculacaoFornecedoresResultado struct {
+	Ofertas             int `json:"ofertas"`
+	FornecedoresCriados int `json:"fornecedores_criados"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Tipos de evento da linha do tempo do produto
+const (
+	TipoEventoPreco   = "preco"
+	TipoEventoEstoque = "estoque"
+)
+
+// ImportacaoOferta representa uma importação (ou reimportação) da oferta com os
+// totais do estoque e as alterações em relação à importação anterior
+type ImportacaoOferta struct {
+	ID         uuid.UUID `json:"id"`
+	OfertaID   uuid.UUID `json:"oferta_id"`
+	Situacao   string    `json:"situacao"`
+	Cavaletes  int       `json:"cavaletes"`
+	MetragemM2 float64   `json:"metragem_m2"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Alteracoes é omitido na primeira importação da oferta
+	Alteracoes *AlteracoesImportacao `json:"alteracoes,omitempty"`
+}
+
+// AlteracoesImportacao compara os cavaletes de duas importações consecutivas
+type AlteracoesImportacao struct {
+	SituacaoAnterior *string             `json:"situacao_anterior,omitempty"`
+	MetragemM2       float64             `json:"metragem_m2"`
+	Adicionados      []CavaleteHistorico `json:"adicionados"`
+	Removidos        []CavaleteHistorico `json:"removidos"`
+	Alterados        []AlteracaoCavalete `json:"alterados"`
+}
+
+// CavaleteHistorico é o retrato de um cavalete em uma importação
+type CavaleteHistorico struct {
+	Codigo        string   `json:"codigo"`
+	Bloco         string   `json:"bloco"`
+	NomeMaterial  string   `json:"nome_material"`
+	NomeEspessura string   `json:"nome_espessura"`
+	MetragemM2    *float64 `json:"metragem_m2,omitempty"`
+	Chapas        *int     `json:"chapas,omitempty"`
+}
+
+// AlteracaoCavalete mostra um cavalete antes e depois da reimportação
+type AlteracaoCavalete struct {
+	Codigo string            `json:"codigo"`
+	Antes  CavaleteHistorico `json:"antes"`
+	Depois CavaleteHistorico `json:"depois"`
+}
+
+// HistoricoProduto é a linha do tempo de preço e estoque de um produto aprovado
+type HistoricoProduto struct {
+	ProdutoID uuid.UUID                `json:"produto_id"`
+	OfertaID  uuid.UUID                `json:"oferta_id"`
+	Codigo    string                   `json:"codigo"`
+	Eventos   []EventoHistoricoProduto `json:"eventos"`
+}
+
+// EventoHistoricoProduto é uma alteração de preço ou uma importação que mudou o estoque
+// do cavalete do produto. Disponivel falso indica que o cavalete saiu da oferta.
+type EventoHistoricoProduto struct {
+	Tipo          string     `json:"tipo"`
+	Data          time.Time  `json:"data"`
+	PrecoAnterior *float64   `json:"preco_anterior,omitempty"`
+	PrecoNovo     *float64   `json:"preco_novo,omitempty"`
+	ImportacaoID  *uuid.UUID `json:"importacao_id,omitempty"`
+	Disponivel    *bool      `json:"disponivel,omitempty"`
+	MetragemM2    *float64   `json:"metragem_m2,omitempty"`
+	Chapas        *int       `json:"chapas,omitempty"`
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TamanhoMiniatura define uma miniatura gerada para cada imagem espelhada
+type TamanhoMiniatura struct {
+	Nome string
+	Lado int // lado máximo em pixels
+}
+
+// TamanhosMiniatura lista as miniaturas geradas, da menor para a maior
+var TamanhosMiniatura = []TamanhoMiniatura{
+	{Nome: "pequena", Lado: 200},
+	{Nome: "media", Lado: 480},
+	{Nome: "grande", Lado: 1024},
+}
+
+// MiniaturaListagem é a miniatura usada como urlMin nas respostas
+const MiniaturaListagem = "media"
+
+// ImagemEspelhada representa uma imagem externa copiada para o armazenamento da aplicação
+type ImagemEspelhada struct {
+	ID            uuid.UUID         `json:"id" db:"id"`
+	URLOrigem     string            `json:"url_origem" db:"url_origem"`
+	ChaveOriginal string            `json:"chave_original" db:"chave_original"`
+	ContentType   string            `json:"content_type" db:"content_type"`
+	Largura       *int              `json:"largura,omitempty" db:"largura"`
+	Altura        *int              `json:"altura,omitempty" db:"altura"`
+	TamanhoBytes  int64             `json:"tamanho_bytes" db:"tamanho_bytes"`
+	Miniaturas    map[string]string `json:"miniaturas" db:"miniaturas"` // nome -> chave
+	CreatedAt     time.Time         `json:"created_at" db:"created_at"`
+}
+
+// EspelhamentoResultado resume um processamento de imagens pendentes
+type EspelhamentoResultado struct {
+	Processados int `json:"processados"`
+	Espelhados  int `json:"espelhados"`
+	Falhas      int `json:"falhas"`
+}
+
+// Limites da galeria de imagens dos produtos
+const (
+	MaxImagensPorProduto       = 20
+	TamanhoMaximoImagemProduto = 10 << 20
+)
+
+// ImagemProduto representa uma imagem enviada pelo trader para a galeria de um produto
+type ImagemProduto struct {
+	ID           uuid.UUID         `json:"id" db:"id"`
+	ProdutoID    uuid.UUID         `json:"produto_id" db:"produto_id"`
+	URL          string            `json:"url" db:"url"`
+	URLMin       string            `json:"url_min"`
+	Miniaturas   map[string]string `json:"miniaturas" db:"miniaturas"`
+	NomeArquivo  *string           `json:"nome_arquivo,omitempty" db:"nome_arquivo"`
+	ContentType  string            `json:"content_type" db:"content_type"`
+	Largura      *int              `json:"largura,omitempty" db:"largura"`
+	Altura       *int              `json:"altura,omitempty" db:"altura"`
+	TamanhoBytes int64             `json:"tamanho_bytes" db:"tamanho_bytes"`
+	Ordem        int               `json:"ordem" db:"ordem"`
+	Capa         bool              `json:"capa" db:"capa"`
+	CreatedAt    time.Time         `json:"created_at" db:"created_at"`
+}
+
+// ArquivoImagem representa um arquivo recebido no upload
+type ArquivoImagem struct {
+	Nome  string
+	Dados []byte
+}
+
+// ReordenarImagensRequest define a nova ordem da galeria; deve conter todas as imagens do produto
+type ReordenarImagensRequest struct {
+	IDs []uuid.UUID `json:"ids" binding:"required,min=1"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// JSONB representa uma coluna JSONB lida do banco e devolvida na API como JSON
+// (e não como bytes codificados em base64)
+type JSONB json.RawMessage
+
+// Scan implementa sql.Scanner
+func (j *JSONB) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*j = nil
+	case []byte:
+		*j = append((*j)[:0], v...)
+	case string:
+		*j = JSONB(v)
+	default:
+		return fmt.Errorf("tipo incompatível com JSONB: %T", src)
+	}
+	return nil
+}
+
+// Value implementa driver.Valuer
+func (j JSONB) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+	return []byte(j), nil
+}
+
+// MarshalJSON implementa json.Marshaler
+func (j JSONB) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+	return json.RawMessage(j).MarshalJSON()
+}
+
+// UnmarshalJSON implementa json.Unmarshaler
+func (j *JSONB) UnmarshalJSON(dados []byte) error {
+	*j = append((*j)[:0], dados...)
+	return nil
+}
@@ -0,0 +1,29 @@
+package models
+
+import "github.com/google/uuid"
+
+// TamanhoMaximoLogo limita o arquivo do logotipo do trader
+const TamanhoMaximoLogo = 2 << 20
+
+// MarcaTrader representa os dados da empresa do trader exibidos nos PDFs
+type MarcaTrader struct {
+	ID        uuid.UUID `json:"id" db:"id"`
+	Nome      string    `json:"nome" db:"nome"`
+	Empresa   *string   `json:"empresa,omitempty" db:"empresa"`
+	Documento *string   `json:"documento,omitempty" db:"documento"`
+	Email     string    `json:"email" db:"email"`
+	Telefone  *string   `json:"telefone,omitempty" db:"telefone"`
+	Endereco  *string   `json:"endereco,omitempty" db:"endereco"`
+	Site      *string   `json:"site,omitempty" db:"site"`
+	LogoURL   *string   `json:"logo_url,omitempty" db:"logo_url"`
+}
+
+// AtualizarMarcaRequest representa os dados da empresa a alterar; campos ausentes são
+// mantidos e campos vazios são apagados
+type AtualizarMarcaRequest struct {
+	Empresa   *string `json:"empresa,omitempty" binding:"omitempty,max=255"`
+	Documento *string `json:"documento,omitempty" binding:"omitempty,max=20"`
+	Telefone  *string `json:"telefone,omitempty" binding:"omitempty,max=20"`
+	Endereco  *string `json:"endereco,omitempty" binding:"omitempty,max=500"`
+	Site      *string `json:"site,omitempty" binding:"omitempty,max=255"`
+}
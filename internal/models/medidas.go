@@ -0,0 +1,23 @@
+package models
+
+// Medidas são as medidas normalizadas de um cavalete, calculadas na importação a partir
+// dos valores brutos do Mobgran (que permanecem nos campos comprimento, altura, metragem
+// e peso). Campos ausentes não puderam ser determinados.
+type Medidas struct {
+	EspessuraMM      *float64 `json:"espessura_mm,omitempty" db:"espessura_mm"`
+	ComprimentoM     *float64 `json:"comprimento_m,omitempty" db:"comprimento_m"`
+	AlturaM          *float64 `json:"altura_m,omitempty" db:"altura_m"`
+	MetragemM2       *float64 `json:"metragem_m2,omitempty" db:"metragem_m2"`
+	PesoKg           *float64 `json:"peso_kg,omitempty" db:"peso_kg"`
+	UnidadeDimensoes *string  `json:"unidade_dimensoes,omitempty" db:"unidade_dimensoes" example:"cm"`
+}
+
+// NormalizacaoMedidasResultado resume a normalização das medidas de cavaletes já importados
+type NormalizacaoMedidasResultado struct {
+	Cavaletes    int `json:"cavaletes"`
+	Itens        int `json:"itens"`
+	SemEspessura int `json:"sem_espessura"`
+	SemMetragem  int `json:"sem_metragem"`
+	// Falhas são os cavaletes que não puderam ser normalizados, marcados em medidas_erro
+	Falhas int `json:"falhas"`
+}
@@ -26,6 +26,7 @@ type Cavalete struct {
 	Codigo           string          `json:"codigo"`
 	Bloco            string          `json:"bloco"`
 	Metragem         float64         `json:"metragem"`
+	Peso             float64         `json:"peso,omitempty"`
 	Itens            []Item          `json:"itens"`
 }
 
@@ -38,13 +39,17 @@ type Item struct {
 	Codigo             string  `json:"codigo"`
 	Bloco              string  `json:"bloco"`
 	Metragem           float64 `json:"metragem"`
+	Peso               float64 `json:"peso,omitempty"`
 }
 
-// ImagemPrincipal representa as informações de imagem
+// ImagemPrincipal representa as informações de imagem. Após o espelhamento, URL e URLMin
+// apontam para as cópias armazenadas pela aplicação e URLOrigem guarda o endereço no Mobgran.
 type ImagemPrincipal struct {
-	Nome   string `json:"nome"`
-	URL    string `json:"url"`
-	URLMin string `json:"urlMin"`
+	Nome       string            `json:"nome"`
+	URL        string            `json:"url"`
+	URLMin     string            `json:"urlMin"`
+	Miniaturas map[string]string `json:"miniaturas,omitempty"`
+	URLOrigem  string            `json:"urlOrigem,omitempty"`
 }
 
 // Bloco representa um bloco no sistema
@@ -0,0 +1,125 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// Eventos que podem ser assinados
+const (
+	EventoImportacaoConcluida = "importacao.concluida"
+	EventoImportacaoFalhou    = "importacao.falhou"
+	EventoCavaleteRemovido    = "cavalete.removido"
+	EventoReservaExpirando    = "reserva.expirando"
+	// Eventos de integração (ex.: espelhar os produtos aprovados em um ERP)
+	EventoProdutoAprovado   = "produto.aprovado"
+	EventoProdutoAtualizado = "produto.atualizado"
+	EventoProdutoRemovido   = "produto.removido"
+	EventoOfertaImportada   = "oferta.importada"
+	// EventoTeste é enviado apenas pelo teste manual da assinatura
+	EventoTeste = "teste"
+)
+
+// EventosNotificacao lista os eventos que podem ser assinados, com a descrição usada no
+// assunto dos e-mails
+var EventosNotificacao = map[string]string{
+	EventoImportacaoConcluida: "Importação concluída",
+	EventoImportacaoFalhou:    "Falha na importação",
+	EventoCavaleteRemovido:    "Cavalete aprovado saiu da oferta",
+	EventoReservaExpirando:    "Reserva perto de vencer",
+	EventoProdutoAprovado:     "Produto aprovado",
+	EventoProdutoAtualizado:   "Produto atualizado",
+	EventoProdutoRemovido:     "Produto removido",
+	EventoOfertaImportada:     "Oferta importada",
+}
+
+// Canais de entrega das notificações
+const (
+	CanalWebhook = "webhook"
+	CanalEmail   = "email"
+)
+
+// Situações da notificação na fila de entrega
+const (
+	StatusNotificacaoPendente = "pendente"
+	StatusNotificacaoEntregue = "entregue"
+	StatusNotificacaoFalhou   = "falhou"
+)
+
+// AssinaturaNotificacao é um destino (URL do webhook ou e-mail) que recebe os eventos
+// escolhidos pelo trader. Segredo só é retornado na criação e quando regenerado.
+type AssinaturaNotificacao struct {
+	ID        uuid.UUID `json:"id"`
+	TraderID  uuid.UUID `json:"trader_id"`
+	Canal     string    `json:"canal"`
+	Destino   string    `json:"destino"`
+	Eventos   []string  `json:"eventos"`
+	Ativa     bool      `json:"ativa"`
+	Segredo   *string   `json:"segredo,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AssinaturaCriarRequest representa os dados para cadastrar uma assinatura
+type AssinaturaCriarRequest struct {
+	Canal   string   `json:"canal" binding:"required"`
+	Destino string   `json:"destino" binding:"required"`
+	Eventos []string `json:"eventos" binding:"required,min=1"`
+	Ativa   *bool    `json:"ativa,omitempty"`
+}
+
+// AssinaturaAtualizarRequest representa os dados para alterar uma assinatura; campos
+// ausentes são mantidos
+type AssinaturaAtualizarRequest struct {
+	Destino          *string  `json:"destino,omitempty"`
+	Eventos          []string `json:"eventos,omitempty"`
+	Ativa            *bool    `json:"ativa,omitempty"`
+	RegenerarSegredo bool     `json:"regenerar_segredo,omitempty"`
+}
+
+// Notificacao é um evento enfileirado para uma assinatura, com a situação da entrega
+type Notificacao struct {
+	ID               uuid.UUID            `json:"id"`
+	AssinaturaID     uuid.UUID            `json:"assinatura_id"`
+	Evento           string               `json:"evento"`
+	Canal            string               `json:"canal"`
+	Destino          string               `json:"destino"`
+	Dados            json.RawMessage      `json:"dados" swaggertype:"object"`
+	Status           string               `json:"status"`
+	Tentativas       int                  `json:"tentativas"`
+	ProximaTentativa *time.Time           `json:"proxima_tentativa,omitempty"`
+	UltimoErro       *string              `json:"ultimo_erro,omitempty"`
+	EntregueEm       *time.Time           `json:"entregue_em,omitempty"`
+	ReenviadaEm      *time.Time           `json:"reenviada_em,omitempty"`
+	CreatedAt        time.Time            `json:"created_at"`
+	Entregas         []EntregaNotificacao `json:"entregas,omitempty"`
+}
+
+// EntregaNotificacao registra uma tentativa de entrega
+type EntregaNotificacao struct {
+	Tentativa  int       `json:"tentativa"`
+	Sucesso    bool      `json:"sucesso"`
+	StatusHTTP *int      `json:"status_http,omitempty"`
+	Erro       *string   `json:"erro,omitempty"`
+	DuracaoMs  int       `json:"duracao_ms"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ReenvioRequest seleciona as notificações da assinatura que voltam para a fila de
+// entrega; sem filtros, todas as já entregues ou que falharam
+type ReenvioRequest struct {
+	Desde  *time.Time `json:"desde,omitempty"`
+	Status string     `json:"status,omitempty"`
+}
+
+// FiltroNotificacoes representa os filtros do registro de entregas
+type FiltroNotificacoes struct {
+	AssinaturaID *uuid.UUID
+	Evento       string
+	Status       string
+	Paginacao    paginacao.Parametros
+}
@@ -0,0 +1,148 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// Status de um orçamento. Apenas rascunhos podem ser editados.
+const (
+	StatusOrcamentoRascunho = "rascunho"
+	StatusOrcamentoEnviado  = "enviado"
+	StatusOrcamentoAceito   = "aceito"
+	StatusOrcamentoRecusado = "recusado"
+)
+
+// StatusOrcamentoValido verifica se o status informado existe
+func StatusOrcamentoValido(status string) bool {
+	switch status {
+	case StatusOrcamentoRascunho, StatusOrcamentoEnviado, StatusOrcamentoAceito, StatusOrcamentoRecusado:
+		return true
+	}
+	return false
+}
+
+// Orcamento representa um orçamento de produtos aprovados para um cliente
+type Orcamento struct {
+	ID                 uuid.UUID       `json:"id" db:"id"`
+	TraderID           uuid.UUID       `json:"trader_id" db:"trader_id"`
+	Numero             int             `json:"numero" db:"numero"`
+	Cliente            string          `json:"cliente" db:"cliente"`
+	ClienteID          *uuid.UUID      `json:"cliente_id,omitempty" db:"cliente_id"`
+	ClienteContato     *string         `json:"cliente_contato,omitempty" db:"cliente_contato"`
+	Observacoes        *string         `json:"observacoes,omitempty" db:"observacoes"`
+	Validade           *string         `json:"validade,omitempty" db:"validade" example:"2026-12-31"`
+	Status             string          `json:"status" db:"status"`
+	DescontoPercentual float64         `json:"desconto_percentual" db:"desconto_percentual"`
+	DescontoValor      float64         `json:"desconto_valor" db:"desconto_valor"`
+	Totais             TotaisOrcamento `json:"totais"`
+	Itens              []OrcamentoItem `json:"itens,omitempty"`
+	EnviadoEm          *time.Time      `json:"enviado_em,omitempty" db:"enviado_em"`
+	RespondidoEm       *time.Time      `json:"respondido_em,omitempty" db:"respondido_em"`
+	ConvertidoEm       *time.Time      `json:"convertido_em,omitempty" db:"convertido_em"`
+	CreatedAt          time.Time       `json:"created_at" db:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at" db:"updated_at"`
+}
+
+// OrcamentoItem representa um produto incluído no orçamento
+type OrcamentoItem struct {
+	ID            uuid.UUID `json:"id" db:"id"`
+	ProdutoID     uuid.UUID `json:"produto_id" db:"produto_id"`
+	Descricao     string    `json:"descricao" db:"descricao"`
+	QuantidadeM2  float64   `json:"quantidade_m2" db:"quantidade_m2"`
+	PrecoM2       float64   `json:"preco_m2" db:"preco_m2"`
+	Total         float64   `json:"total"`
+	StatusProduto string    `json:"status_produto"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
+
+// TotaisOrcamento resume os valores do orçamento
+type TotaisOrcamento struct {
+	TotalM2  float64 `json:"total_m2"`
+	Subtotal float64 `json:"subtotal"`
+	Desconto float64 `json:"desconto"`
+	Total    float64 `json:"total"`
+}
+
+// CalcularTotais aplica os descontos do orçamento sobre o subtotal dos itens. O desconto
+// percentual incide primeiro; o total nunca fica negativo.
+func CalcularTotais(totalM2, subtotal, descontoPercentual, descontoValor float64) TotaisOrcamento {
+	desconto := Arredondar(subtotal*descontoPercentual/100) + descontoValor
+	if desconto > subtotal {
+		desconto = subtotal
+	}
+
+	return TotaisOrcamento{
+		TotalM2:  math.Round(totalM2*10000) / 10000,
+		Subtotal: Arredondar(subtotal),
+		Desconto: Arredondar(desconto),
+		Total:    Arredondar(subtotal - desconto),
+	}
+}
+
+// Arredondar arredonda valores monetários para centavos
+func Arredondar(valor float64) float64 {
+	return math.Round(valor*100) / 100
+}
+
+// OrcamentoCriarRequest representa os dados para criar um orçamento. O cliente pode
+// ser informado pelo nome ou pelo cadastro (cliente_id), que preenche o nome.
+type OrcamentoCriarRequest struct {
+	Cliente            string     `json:"cliente" binding:"required_without=ClienteID,max=255"`
+	ClienteID          *uuid.UUID `json:"cliente_id,omitempty"`
+	ClienteContato     *string    `json:"cliente_contato,omitempty" binding:"omitempty,max=255"`
+	Observacoes        *string    `json:"observacoes,omitempty"`
+	Validade           *string    `json:"validade,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2026-12-31"`
+	DescontoPercentual *float64   `json:"desconto_percentual,omitempty" binding:"omitempty,min=0,max=100"`
+	DescontoValor      *float64   `json:"desconto_valor,omitempty" binding:"omitempty,min=0"`
+}
+
+// OrcamentoAtualizarRequest representa os dados para atualizar um orçamento em rascunho
+type OrcamentoAtualizarRequest struct {
+	Cliente            *string    `json:"cliente,omitempty" binding:"omitempty,min=1,max=255"`
+	ClienteID          *uuid.UUID `json:"cliente_id,omitempty"`
+	ClienteContato     *string    `json:"cliente_contato,omitempty" binding:"omitempty,max=255"`
+	Observacoes        *string    `json:"observacoes,omitempty"`
+	Validade           *string    `json:"validade,omitempty" binding:"omitempty,datetime=2006-01-02" example:"2026-12-31"`
+	DescontoPercentual *float64   `json:"desconto_percentual,omitempty" binding:"omitempty,min=0,max=100"`
+	DescontoValor      *float64   `json:"desconto_valor,omitempty" binding:"omitempty,min=0"`
+}
+
+// OrcamentoItemRequest representa um produto a incluir no orçamento. Sem quantidade_m2,
+// vale a metragem do cavalete; sem preco_m2, o preço de venda do produto.
+type OrcamentoItemRequest struct {
+	ProdutoID    uuid.UUID `json:"produto_id" binding:"required"`
+	QuantidadeM2 *float64  `json:"quantidade_m2,omitempty" binding:"omitempty,gt=0"`
+	PrecoM2      *float64  `json:"preco_m2,omitempty" binding:"omitempty,min=0"`
+}
+
+// OrcamentoItemAtualizarRequest representa a alteração de quantidade ou preço de um item
+type OrcamentoItemAtualizarRequest struct {
+	QuantidadeM2 *float64 `json:"quantidade_m2,omitempty" binding:"omitempty,gt=0"`
+	PrecoM2      *float64 `json:"preco_m2,omitempty" binding:"omitempty,min=0"`
+}
+
+// ConverterOrcamentoRequest define a expiração das reservas criadas a partir do orçamento.
+// Sem expira_em nem validade_horas, vale a validade padrão das reservas.
+type ConverterOrcamentoRequest struct {
+	ExpiraEm      *time.Time `json:"expira_em,omitempty"`
+	ValidadeHoras *int       `json:"validade_horas,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+// ConversaoOrcamentoResultado representa o orçamento convertido e as reservas criadas
+type ConversaoOrcamentoResultado struct {
+	Orcamento  *Orcamento        `json:"orcamento"`
+	Reservados []ProdutoAprovado `json:"reservados"`
+}
+
+// FiltroOrcamentos representa os filtros da listagem de orçamentos
+type FiltroOrcamentos struct {
+	Status    string
+	Cliente   string
+	ClienteID *uuid.UUID
+	Paginacao paginacao.Parametros
+}
@@ -0,0 +1,61 @@
+package models
+
+import "testing"
+
+func TestCalcularTotais(t *testing.T) {
+	casos := []struct {
+		nome               string
+		totalM2            float64
+		subtotal           float64
+		descontoPercentual float64
+		descontoValor      float64
+		esperado           TotaisOrcamento
+	}{
+		{"sem desconto", 12.5, 1000, 0, 0, TotaisOrcamento{TotalM2: 12.5, Subtotal: 1000, Desconto: 0, Total: 1000}},
+		{"orçamento vazio", 0, 0, 0, 0, TotaisOrcamento{}},
+		{"só percentual", 0, 1000, 10, 0, TotaisOrcamento{Subtotal: 1000, Desconto: 100, Total: 900}},
+		{"só valor", 0, 1000, 0, 50, TotaisOrcamento{Subtotal: 1000, Desconto: 50, Total: 950}},
+		// O percentual incide sobre o subtotal, antes do valor: 10% de 1000 + 50, e não
+		// 10% de (1000 - 50) + 50
+		{"percentual antes do valor", 0, 1000, 10, 50, TotaisOrcamento{Subtotal: 1000, Desconto: 150, Total: 850}},
+		{"valor maior que o subtotal", 0, 300, 0, 500, TotaisOrcamento{Subtotal: 300, Desconto: 300, Total: 0}},
+		{"percentual e valor somados acima do subtotal", 0, 200, 80, 50, TotaisOrcamento{Subtotal: 200, Desconto: 200, Total: 0}},
+		{"percentual total", 0, 750, 100, 0, TotaisOrcamento{Subtotal: 750, Desconto: 750, Total: 0}},
+		{"desconto em orçamento sem itens", 0, 0, 10, 50, TotaisOrcamento{}},
+		{"percentual arredondado para centavos", 0, 100, 33.333, 0, TotaisOrcamento{Subtotal: 100, Desconto: 33.33, Total: 66.67}},
+		{"percentual fracionado", 0, 1234.56, 12.5, 0, TotaisOrcamento{Subtotal: 1234.56, Desconto: 154.32, Total: 1080.24}},
+		{"subtotal e metragem arredondados", 12.34567, 99.999, 0, 0, TotaisOrcamento{TotalM2: 12.3457, Subtotal: 100, Desconto: 0, Total: 100}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			totais := CalcularTotais(c.totalM2, c.subtotal, c.descontoPercentual, c.descontoValor)
+			if totais != c.esperado {
+				t.Errorf("CalcularTotais(%v, %v, %v, %v) = %+v, esperado %+v",
+					c.totalM2, c.subtotal, c.descontoPercentual, c.descontoValor, totais, c.esperado)
+			}
+			if totais.Total < 0 {
+				t.Errorf("total negativo: %v", totais.Total)
+			}
+		})
+	}
+}
+
+func TestArredondar(t *testing.T) {
+	casos := []struct {
+		valor    float64
+		esperado float64
+	}{
+		{10, 10},
+		{10.125, 10.13},
+		{10.124, 10.12},
+		{0.004, 0},
+		{-3.456, -3.46},
+	}
+
+	for _, c := range casos {
+		if valor := Arredondar(c.valor); valor != c.esperado {
+			t.Errorf("Arredondar(%v) = %v, esperado %v", c.valor, valor, c.esperado)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status do ciclo de venda de um produto aprovado
+const (
+	StatusProdutoDisponivel = "disponivel"
+	StatusProdutoReservado  = "reservado"
+	StatusProdutoVendido    = "vendido"
+)
+
+// StatusProdutoValido verifica se o status informado existe
+func StatusProdutoValido(status string) bool {
+	switch status {
+	case StatusProdutoDisponivel, StatusProdutoReservado, StatusProdutoVendido:
+		return true
+	}
+	return false
+}
+
+// ReservarProdutoRequest representa os dados para reservar um produto para um cliente,
+// informado pelo nome ou pelo cadastro (cliente_id). Sem expira_em nem validade_horas,
+// vale a validade padrão configurada.
+type ReservarProdutoRequest struct {
+	Cliente       string     `json:"cliente" binding:"required_without=ClienteID,max=255"`
+	ClienteID     *uuid.UUID `json:"cliente_id,omitempty"`
+	Observacao    *string    `json:"observacao,omitempty"`
+	ExpiraEm      *time.Time `json:"expira_em,omitempty"`
+	ValidadeHoras *int       `json:"validade_horas,omitempty" binding:"omitempty,min=1,max=720"`
+}
+
+// VenderProdutoRequest representa os dados para marcar um produto como vendido.
+// Sem valor_venda, vale o preço de venda do produto; sem cliente nem cliente_id, o
+// cliente da reserva.
+type VenderProdutoRequest struct {
+	Cliente    *string    `json:"cliente,omitempty" binding:"omitempty,min=1,max=255"`
+	ClienteID  *uuid.UUID `json:"cliente_id,omitempty"`
+	ValorVenda *float64   `json:"valor_venda,omitempty" binding:"omitempty,gt=0"`
+}
+
+// ExpiracaoReservasResultado resume uma execução da expiração de reservas
+type ExpiracaoReservasResultado struct {
+	Expiradas int `json:"expiradas"`
+}
@@ -0,0 +1,65 @@
+package models
+
+import "time"
+
+// Situação geral da API nas verificações de saúde
+const (
+	SaudeSaudavel     = "healthy"
+	SaudeDegradada    = "degraded"
+	SaudeIndisponivel = "unhealthy"
+)
+
+// Situação de cada dependência verificada
+const (
+	VerificacaoOK        = "ok"
+	VerificacaoDegradada = "degradado"
+	VerificacaoFalha     = "falha"
+)
+
+// Estados do circuito de chamadas à API do Mobgran
+const (
+	CircuitoFechado    = "fechado"
+	CircuitoAberto     = "aberto"
+	CircuitoSemiAberto = "semiaberto"
+)
+
+// Verificacao é o resultado da verificação de uma dependência. Uma falha torna a API
+// indisponível; uma degradação é informada sem tirá-la de operação.
+type Verificacao struct {
+	Status     string                 `json:"status"`
+	Critica    bool                   `json:"critica"`
+	LatenciaMs int64                  `json:"latencia_ms"`
+	Erro       string                 `json:"erro,omitempty"`
+	Detalhes   map[string]interface{} `json:"detalhes,omitempty"`
+}
+
+// Prontidao é a resposta de /health/ready: a situação geral e a de cada dependência
+type Prontidao struct {
+	Status       string                 `json:"status"`
+	Versao       string                 `json:"versao"`
+	Commit       string                 `json:"commit,omitempty"`
+	Verificacoes map[string]Verificacao `json:"verificacoes"`
+	VerificadoEm time.Time              `json:"verificado_em"`
+}
+
+// EstadoCircuito descreve o circuito de chamadas à API do Mobgran
+type EstadoCircuito struct {
+	Estado             string     `json:"estado"`
+	FalhasConsecutivas int        `json:"falhas_consecutivas"`
+	AbertoAte          *time.Time `json:"aberto_ate,omitempty"`
+	UltimoErro         string     `json:"ultimo_erro,omitempty"`
+}
+
+// StatusMigrations compara as migrations embutidas no binário com as já aplicadas
+type StatusMigrations struct {
+	Aplicadas int      `json:"aplicadas"`
+	Total     int      `json:"total"`
+	Pendentes []string `json:"pendentes,omitempty"`
+}
+
+// FilaNotificacoes resume as notificações aguardando entrega
+type FilaNotificacoes struct {
+	Pendentes      int   `json:"pendentes"`
+	Vencidas       int   `json:"vencidas"`
+	AtrasoSegundos int64 `json:"atraso_segundos"`
+}
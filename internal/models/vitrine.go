@@ -0,0 +1,53 @@
+package models
+
+import (
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// Ordenações aceitas pela vitrine pública
+const (
+	OrdenacaoVitrineDestaque     = "destaque"
+	OrdenacaoVitrinePrecoAsc     = "preco_asc"
+	OrdenacaoVitrinePrecoDesc    = "preco_desc"
+	OrdenacaoVitrineRecentes     = "recentes"
+	OrdenacaoVitrineMetragemDesc = "metragem_desc"
+	OrdenacaoVitrineNome         = "nome"
+)
+
+// FiltroVitrine representa os filtros, busca e ordenação da vitrine pública
+type FiltroVitrine struct {
+	TraderID       *uuid.UUID
+	TraderSlug     string
+	FornecedorID   *uuid.UUID
+	Destaque       bool
+	Material       string
+	Espessura      string
+	Classificacao  string
+	Acabamento     string
+	ComprimentoMin *float64
+	ComprimentoMax *float64
+	AlturaMin      *float64
+	AlturaMax      *float64
+	MetragemMin    *float64
+	MetragemMax    *float64
+	PrecoMin       *float64
+	PrecoMax       *float64
+	Busca          string
+	Ordenacao      string
+	Paginacao      paginacao.Parametros
+}
+
+// VitrineTrader representa os dados públicos do trader exibidos na sua vitrine
+type VitrineTrader struct {
+	ID      uuid.UUID `json:"id" db:"id"`
+	Slug    string    `json:"slug" db:"slug"`
+	Nome    string    `json:"nome" db:"nome"`
+	Empresa *string   `json:"empresa,omitempty" db:"empresa"`
+}
+
+// AtualizarSlugRequest representa os dados para alterar o slug da vitrine do trader
+type AtualizarSlugRequest struct {
+	Slug string `json:"slug" binding:"required,min=3,max=100"`
+}
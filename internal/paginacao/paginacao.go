@@ -0,0 +1,138 @@
+package paginacao
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Direções de navegação codificadas no cursor
+const (
+	Proximo  = "proximo"
+	Anterior = "anterior"
+)
+
+// ErrCursorInvalido indica um cursor malformado ou adulterado
+var ErrCursorInvalido = errors.New("cursor inválido")
+
+// Cursor identifica a posição de um registro na ordenação (created_at DESC, id DESC)
+type Cursor struct {
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+	Direcao   string    `json:"d"`
+}
+
+// Parametros representa os parâmetros de paginação de uma listagem. Com Cursor, a
+// paginação é por keyset; sem ele, Offset é usado (compatibilidade).
+type Parametros struct {
+	Limit        int
+	Offset       int
+	Cursor       *Cursor
+	IncluirTotal bool
+}
+
+// Pagina descreve a página retornada e como navegar para as páginas vizinhas
+type Pagina struct {
+	Limit          int     `json:"limit"`
+	TemMais        bool    `json:"tem_mais"`
+	ProximoCursor  *string `json:"proximo_cursor"`
+	CursorAnterior *string `json:"cursor_anterior"`
+	Total          *int    `json:"total,omitempty"`
+}
+
+// Codificar gera o cursor opaco enviado aos clientes
+func Codificar(createdAt time.Time, id uuid.UUID, direcao string) string {
+	dados, _ := json.Marshal(Cursor{CreatedAt: createdAt, ID: id, Direcao: direcao})
+	return base64.RawURLEncoding.EncodeToString(dados)
+}
+
+// Decodificar interpreta um cursor recebido do cliente
+func Decodificar(valor string) (*Cursor, error) {
+	dados, err := base64.RawURLEncoding.DecodeString(valor)
+	if err != nil {
+		return nil, ErrCursorInvalido
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(dados, &cursor); err != nil {
+		return nil, ErrCursorInvalido
+	}
+
+	if cursor.ID == uuid.Nil || cursor.CreatedAt.IsZero() ||
+		(cursor.Direcao != Proximo && cursor.Direcao != Anterior) {
+		return nil, ErrCursorInvalido
+	}
+
+	return &cursor, nil
+}
+
+// Keyset retorna a condição e o ORDER BY da paginação por keyset sobre as colunas
+// informadas. A condição usa os placeholders $argIndex e $argIndex+1 e é vazia na
+// primeira página; args traz os valores correspondentes.
+func (p *Parametros) Keyset(colunaCreatedAt, colunaID string, argIndex int) (condicao string, args []interface{}, orderBy string) {
+	orderBy = fmt.Sprintf("%s DESC, %s DESC", colunaCreatedAt, colunaID)
+	if p.Cursor == nil {
+		return "", nil, orderBy
+	}
+
+	args = []interface{}{p.Cursor.CreatedAt, p.Cursor.ID}
+	if p.Cursor.Direcao == Anterior {
+		condicao = fmt.Sprintf("(%s, %s) > ($%d, $%d)", colunaCreatedAt, colunaID, argIndex, argIndex+1)
+		orderBy = fmt.Sprintf("%s ASC, %s ASC", colunaCreatedAt, colunaID)
+		return condicao, args, orderBy
+	}
+
+	condicao = fmt.Sprintf("(%s, %s) < ($%d, $%d)", colunaCreatedAt, colunaID, argIndex, argIndex+1)
+	return condicao, args, orderBy
+}
+
+// LimitOffset retorna o LIMIT (um registro a mais, para saber se há próxima página)
+// e o OFFSET da consulta; o offset é ignorado na paginação por cursor
+func (p *Parametros) LimitOffset() (int, int) {
+	if p.Cursor != nil {
+		return p.Limit + 1, 0
+	}
+	return p.Limit + 1, p.Offset
+}
+
+// Montar recorta os registros buscados com LimitOffset e calcula os cursores vizinhos.
+// chave extrai (created_at, id) de um registro; quando nil, nenhum cursor é gerado
+// (listagens com outra ordenação, paginadas apenas por offset).
+func Montar[T any](itens []T, p *Parametros, chave func(T) (time.Time, uuid.UUID)) ([]T, *Pagina) {
+	temMais := len(itens) > p.Limit
+	if temMais {
+		itens = itens[:p.Limit]
+	}
+
+	anterior := p.Cursor != nil && p.Cursor.Direcao == Anterior
+	if anterior {
+		// A página anterior é buscada em ordem crescente e devolvida na ordem da listagem
+		for i, j := 0, len(itens)-1; i < j; i, j = i+1, j-1 {
+			itens[i], itens[j] = itens[j], itens[i]
+		}
+	}
+
+	pagina := &Pagina{Limit: p.Limit, TemMais: temMais || anterior}
+	if chave == nil || len(itens) == 0 {
+		return itens, pagina
+	}
+
+	// Indo para trás, sempre existe a página de onde o cliente veio; indo para frente,
+	// existe página anterior se a atual não é a primeira
+	if temMais || anterior {
+		createdAt, id := chave(itens[len(itens)-1])
+		cursor := Codificar(createdAt, id, Proximo)
+		pagina.ProximoCursor = &cursor
+	}
+	if (anterior && temMais) || (!anterior && (p.Cursor != nil || p.Offset > 0)) {
+		createdAt, id := chave(itens[0])
+		cursor := Codificar(createdAt, id, Anterior)
+		pagina.CursorAnterior = &cursor
+	}
+
+	return itens, pagina
+}
@@ -0,0 +1,272 @@
+package paginacao
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCodificarDecodificar(t *testing.T) {
+	id := uuid.MustParse("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+	saoPaulo := time.FixedZone("BRT", -3*60*60)
+
+	casos := []struct {
+		nome      string
+		createdAt time.Time
+		direcao   string
+	}{
+		{"próximo", time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC), Proximo},
+		{"anterior", time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC), Anterior},
+		{"microssegundos do Postgres", time.Date(2026, 3, 10, 14, 30, 0, 123456000, time.UTC), Proximo},
+		{"nanossegundos", time.Date(2026, 3, 10, 14, 30, 0, 123456789, time.UTC), Proximo},
+		{"outro fuso", time.Date(2026, 3, 10, 11, 30, 0, 0, saoPaulo), Anterior},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			valor := Codificar(c.createdAt, id, c.direcao)
+
+			if _, err := base64.RawURLEncoding.DecodeString(valor); err != nil {
+				t.Fatalf("cursor %q não é base64 URL sem preenchimento: %v", valor, err)
+			}
+
+			cursor, err := Decodificar(valor)
+			if err != nil {
+				t.Fatalf("Decodificar(%q): erro inesperado %v", valor, err)
+			}
+			if !cursor.CreatedAt.Equal(c.createdAt) {
+				t.Errorf("created_at = %v, esperado %v", cursor.CreatedAt, c.createdAt)
+			}
+			if cursor.ID != id {
+				t.Errorf("id = %v, esperado %v", cursor.ID, id)
+			}
+			if cursor.Direcao != c.direcao {
+				t.Errorf("direção = %q, esperado %q", cursor.Direcao, c.direcao)
+			}
+		})
+	}
+}
+
+func TestDecodificarInvalido(t *testing.T) {
+	json := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	casos := []struct {
+		nome  string
+		valor string
+	}{
+		{"vazio", ""},
+		{"fora do base64", "não é base64!"},
+		{"base64 com preenchimento", base64.URLEncoding.EncodeToString([]byte(`{"c":"2026-03-10T14:30:00Z","i":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","d":"proximo"}`))},
+		{"não é JSON", json("abc")},
+		{"JSON de outro tipo", json(`[1,2,3]`)},
+		{"sem id", json(`{"c":"2026-03-10T14:30:00Z","d":"proximo"}`)},
+		{"id nulo", json(`{"c":"2026-03-10T14:30:00Z","i":"00000000-0000-0000-0000-000000000000","d":"proximo"}`)},
+		{"id malformado", json(`{"c":"2026-03-10T14:30:00Z","i":"123","d":"proximo"}`)},
+		{"sem data", json(`{"i":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","d":"proximo"}`)},
+		{"data malformada", json(`{"c":"ontem","i":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","d":"proximo"}`)},
+		{"sem direção", json(`{"c":"2026-03-10T14:30:00Z","i":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b"}`)},
+		{"direção desconhecida", json(`{"c":"2026-03-10T14:30:00Z","i":"6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b","d":"lateral"}`)},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			cursor, err := Decodificar(c.valor)
+			if !errors.Is(err, ErrCursorInvalido) {
+				t.Fatalf("Decodificar(%q) = %+v, %v; esperado %v", c.valor, cursor, err, ErrCursorInvalido)
+			}
+		})
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	createdAt := time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)
+	id := uuid.New()
+
+	casos := []struct {
+		nome     string
+		cursor   *Cursor
+		condicao string
+		orderBy  string
+		args     int
+	}{
+		{"primeira página", nil, "", "pa.created_at DESC, pa.id DESC", 0},
+		{"próxima página", &Cursor{CreatedAt: createdAt, ID: id, Direcao: Proximo},
+			"(pa.created_at, pa.id) < ($3, $4)", "pa.created_at DESC, pa.id DESC", 2},
+		{"página anterior", &Cursor{CreatedAt: createdAt, ID: id, Direcao: Anterior},
+			"(pa.created_at, pa.id) > ($3, $4)", "pa.created_at ASC, pa.id ASC", 2},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			p := &Parametros{Limit: 20, Cursor: c.cursor}
+			condicao, args, orderBy := p.Keyset("pa.created_at", "pa.id", 3)
+			if condicao != c.condicao {
+				t.Errorf("condição = %q, esperado %q", condicao, c.condicao)
+			}
+			if orderBy != c.orderBy {
+				t.Errorf("order by = %q, esperado %q", orderBy, c.orderBy)
+			}
+			if len(args) != c.args {
+				t.Fatalf("args = %v, esperado %d valores", args, c.args)
+			}
+			if c.args > 0 && (args[0] != createdAt || args[1] != id) {
+				t.Errorf("args = %v, esperado [%v %v]", args, createdAt, id)
+			}
+		})
+	}
+}
+
+func TestLimitOffset(t *testing.T) {
+	casos := []struct {
+		nome   string
+		p      Parametros
+		limit  int
+		offset int
+	}{
+		{"offset", Parametros{Limit: 20, Offset: 40}, 21, 40},
+		{"cursor ignora offset", Parametros{Limit: 20, Offset: 40, Cursor: &Cursor{Direcao: Proximo}}, 21, 0},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			limit, offset := c.p.LimitOffset()
+			if limit != c.limit || offset != c.offset {
+				t.Errorf("LimitOffset() = (%d, %d), esperado (%d, %d)", limit, offset, c.limit, c.offset)
+			}
+		})
+	}
+}
+
+// registro é um item de listagem ordenado por (created_at DESC, id DESC)
+type registro struct {
+	createdAt time.Time
+	id        uuid.UUID
+}
+
+func chaveRegistro(r registro) (time.Time, uuid.UUID) { return r.createdAt, r.id }
+
+func TestMontar(t *testing.T) {
+	base := time.Date(2026, 3, 10, 14, 30, 0, 0, time.UTC)
+	registros := make([]registro, 5)
+	for i := range registros {
+		registros[i] = registro{createdAt: base.Add(-time.Duration(i) * time.Minute), id: uuid.New()}
+	}
+	invertidos := func(rs []registro) []registro {
+		out := make([]registro, len(rs))
+		for i, r := range rs {
+			out[len(rs)-1-i] = r
+		}
+		return out
+	}
+
+	casos := []struct {
+		nome     string
+		buscados []registro
+		p        Parametros
+		itens    []registro
+		temMais  bool
+		proximo  *registro
+		anterior *registro
+	}{
+		{
+			nome:     "primeira página com mais registros",
+			buscados: registros[:3], p: Parametros{Limit: 2},
+			itens: registros[:2], temMais: true, proximo: &registros[1],
+		},
+		{
+			nome:     "página única",
+			buscados: registros[:2], p: Parametros{Limit: 2},
+			itens: registros[:2],
+		},
+		{
+			nome:     "próxima página no meio",
+			buscados: registros[2:5], p: Parametros{Limit: 2, Cursor: &Cursor{Direcao: Proximo}},
+			itens: registros[2:4], temMais: true, proximo: &registros[3], anterior: &registros[2],
+		},
+		{
+			nome:     "última página",
+			buscados: registros[4:5], p: Parametros{Limit: 2, Cursor: &Cursor{Direcao: Proximo}},
+			itens: registros[4:5], anterior: &registros[4],
+		},
+		{
+			// A página anterior chega em ordem crescente e volta na ordem da listagem
+			nome:     "página anterior no meio",
+			buscados: invertidos(registros[0:3]), p: Parametros{Limit: 2, Cursor: &Cursor{Direcao: Anterior}},
+			itens: registros[1:3], temMais: true, proximo: &registros[2], anterior: &registros[1],
+		},
+		{
+			nome:     "página anterior chegando à primeira",
+			buscados: invertidos(registros[0:2]), p: Parametros{Limit: 2, Cursor: &Cursor{Direcao: Anterior}},
+			itens: registros[0:2], temMais: true, proximo: &registros[1],
+		},
+		{
+			nome:     "offset depois da primeira página",
+			buscados: registros[2:4], p: Parametros{Limit: 2, Offset: 2},
+			itens: registros[2:4], anterior: &registros[2],
+		},
+		{
+			nome:     "vazia",
+			buscados: nil, p: Parametros{Limit: 2, Cursor: &Cursor{Direcao: Proximo}},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			buscados := append([]registro(nil), c.buscados...)
+			itens, pagina := Montar(buscados, &c.p, chaveRegistro)
+
+			if len(itens) != len(c.itens) {
+				t.Fatalf("itens = %d, esperado %d", len(itens), len(c.itens))
+			}
+			for i := range itens {
+				if itens[i] != c.itens[i] {
+					t.Errorf("item %d fora de ordem", i)
+				}
+			}
+			if pagina.Limit != c.p.Limit || pagina.TemMais != c.temMais {
+				t.Errorf("página = %+v, esperado limit %d e tem_mais %v", pagina, c.p.Limit, c.temMais)
+			}
+			conferirCursor(t, "próximo", pagina.ProximoCursor, c.proximo, Proximo)
+			conferirCursor(t, "anterior", pagina.CursorAnterior, c.anterior, Anterior)
+		})
+	}
+}
+
+func TestMontarSemChave(t *testing.T) {
+	registros := []registro{{id: uuid.New()}, {id: uuid.New()}, {id: uuid.New()}}
+
+	itens, pagina := Montar(registros, &Parametros{Limit: 2, Offset: 2}, nil)
+	if len(itens) != 2 || !pagina.TemMais {
+		t.Fatalf("itens = %d, tem_mais = %v; esperado 2 e true", len(itens), pagina.TemMais)
+	}
+	if pagina.ProximoCursor != nil || pagina.CursorAnterior != nil {
+		t.Errorf("listagens sem chave não geram cursores: %+v", pagina)
+	}
+}
+
+// conferirCursor verifica se o cursor aponta para o registro esperado (ou não existe)
+func conferirCursor(t *testing.T, nome string, valor *string, esperado *registro, direcao string) {
+	t.Helper()
+
+	if esperado == nil {
+		if valor != nil {
+			t.Errorf("cursor %s = %q, esperado nenhum", nome, *valor)
+		}
+		return
+	}
+	if valor == nil {
+		t.Errorf("cursor %s ausente", nome)
+		return
+	}
+
+	cursor, err := Decodificar(*valor)
+	if err != nil {
+		t.Fatalf("cursor %s inválido: %v", nome, err)
+	}
+	if !cursor.CreatedAt.Equal(esperado.createdAt) || cursor.ID != esperado.id || cursor.Direcao != direcao {
+		t.Errorf("cursor %s = %+v, esperado (%v, %v, %s)", nome, cursor, esperado.createdAt, esperado.id, direcao)
+	}
+}
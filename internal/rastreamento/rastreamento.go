@@ -0,0 +1,104 @@
+// Package rastreamento configura o tracing OpenTelemetry da API: o exportador dos
+// spans (OTLP ou stdout), a propagação W3C Trace Context e a correlação com os logs
+package rastreamento
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// NomeServico identifica a API nos spans (service.name) quando OTEL_SERVICE_NAME não
+// é informado
+const NomeServico = "mobgran-importer"
+
+// Exportadores aceitos em OTEL_TRACES_EXPORTER
+const (
+	ExportadorNenhum = "none"
+	ExportadorOTLP   = "otlp"
+	ExportadorStdout = "stdout"
+)
+
+// Tracer retorna o tracer dos spans criados pela aplicação
+func Tracer() trace.Tracer {
+	return otel.Tracer("mobgran-importer-go")
+}
+
+// Configurar instala o provedor de traces com o exportador informado. O OTLP usa
+// HTTP e lê o destino das variáveis padrão (OTEL_EXPORTER_OTLP_ENDPOINT etc.); o
+// stdout escreve os spans no terminal, sem depender de coletor. Com "none", os spans
+// não são exportados, mas os IDs continuam sendo gerados para o X-Trace-ID e os logs.
+// A função retornada descarrega os spans pendentes e encerra o provedor.
+func Configurar(ctx context.Context, exportador string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch exportador {
+	case "", ExportadorNenhum:
+	case ExportadorOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExportadorStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout), stdouttrace.WithPrettyPrint())
+	default:
+		return nil, fmt.Errorf("exportador de traces inválido: %s (use otlp, stdout ou none)", exportador)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar exportador de traces: %w", err)
+	}
+
+	// OTEL_SERVICE_NAME e OTEL_RESOURCE_ATTRIBUTES, quando informados, prevalecem
+	recurso, err := resource.Merge(
+		resource.NewSchemaless(semconv.ServiceName(NomeServico)),
+		resource.Environment(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao montar recurso dos traces: %w", err)
+	}
+
+	// A amostragem segue OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG (padrão: todos)
+	opcoes := []sdktrace.TracerProviderOption{sdktrace.WithResource(recurso)}
+	if exporter != nil {
+		opcoes = append(opcoes, sdktrace.WithBatcher(exporter))
+	}
+	provider := sdktrace.NewTracerProvider(opcoes...)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// HookLogs acrescenta trace_id e span_id às linhas de log registradas com o contexto
+// de um span (logger.WithContext(ctx))
+type HookLogs struct{}
+
+// Levels aplica o hook a todos os níveis
+func (HookLogs) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire copia os IDs do span do contexto da entrada, se houver
+func (HookLogs) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+	span := trace.SpanContextFromContext(entry.Context)
+	if !span.IsValid() {
+		return nil
+	}
+	entry.Data["trace_id"] = span.TraceID().String()
+	entry.Data["span_id"] = span.SpanID().String()
+	return nil
+}
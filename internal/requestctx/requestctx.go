@@ -0,0 +1,18 @@
+package requestctx
+
+import "context"
+
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID adiciona o ID da requisição ao contexto
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// GetRequestID obtém o ID da requisição do contexto, ou string vazia se ausente
+func GetRequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
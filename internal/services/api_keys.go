@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// APIKeyService gerencia chaves de API para integrações máquina-a-máquina
+type APIKeyService struct {
+	db        *sql.DB
+	auditoria *AuditoriaService
+}
+
+// NewAPIKeyService cria uma nova instância do APIKeyService
+func NewAPIKeyService(db *sql.DB, auditoria *AuditoriaService) *APIKeyService {
+	return &APIKeyService{db: db, auditoria: auditoria}
+}
+
+// CriarAPIKey gera uma nova chave de API para o trader
+func (s *APIKeyService) CriarAPIKey(ctx context.Context, traderID uuid.UUID, request *models.APIKeyCriarRequest) (*models.APIKeyCriadaResponse, error) {
+	for _, escopo := range request.Escopos {
+		if !escopoValido(escopo) {
+			return nil, fmt.Errorf("escopo inválido: %s", escopo)
+		}
+	}
+
+	if request.ExpiraEm != nil && request.ExpiraEm.Before(time.Now()) {
+		return nil, fmt.Errorf("data de expiração deve ser futura")
+	}
+
+	chave, prefixo, err := gerarChaveAPI()
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao gerar chave de API")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	apiKey := models.APIKey{
+		ID:        uuid.New(),
+		TraderID:  traderID,
+		Nome:      request.Nome,
+		Prefixo:   prefixo,
+		ChaveHash: hashChaveAPI(chave),
+		Escopos:   request.Escopos,
+		ExpiraEm:  request.ExpiraEm,
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO api_keys (id, trader_id, nome, prefixo, chave_hash, escopos, expira_em)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`, apiKey.ID, apiKey.TraderID, apiKey.Nome, apiKey.Prefixo, apiKey.ChaveHash,
+		pq.Array(apiKey.Escopos), apiKey.ExpiraEm,
+	).Scan(&apiKey.CreatedAt)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao inserir chave de API")
+		return nil, fmt.Errorf("erro ao criar chave de API")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoAPIKeyCriada, "api_key", apiKey.ID.String(), nil, apiKey); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da chave de API")
+		return nil, fmt.Errorf("erro ao criar chave de API")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":    traderID,
+		"api_key_id": apiKey.ID,
+		"prefixo":    apiKey.Prefixo,
+	}).Info("Chave de API criada com sucesso")
+
+	return &models.APIKeyCriadaResponse{APIKey: apiKey, Chave: chave}, nil
+}
+
+// ListarAPIKeys lista as chaves de API do trader (sem o valor da chave), da mais recente
+// para a mais antiga
+func (s *APIKeyService) ListarAPIKeys(ctx context.Context, traderID uuid.UUID, params *paginacao.Parametros) ([]models.APIKey, *paginacao.Pagina, error) {
+	conditions := []string{"trader_id = $1"}
+	args := []interface{}{traderID}
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM api_keys WHERE trader_id = $1", traderID).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar chaves de API")
+			return nil, nil, fmt.Errorf("erro ao buscar chaves de API")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("created_at", "id", len(args)+1)
+	if condicao != "" {
+		conditions = append(conditions, condicao)
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT id, trader_id, nome, prefixo, escopos, expira_em, ultimo_uso_em, revogada_em, created_at
+		FROM api_keys
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(conditions, " AND "), orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar chaves de API")
+		return nil, nil, fmt.Errorf("erro ao buscar chaves de API")
+	}
+	defer rows.Close()
+
+	apiKeys := []models.APIKey{}
+	for rows.Next() {
+		var k models.APIKey
+		err := rows.Scan(
+			&k.ID, &k.TraderID, &k.Nome, &k.Prefixo, pq.Array(&k.Escopos),
+			&k.ExpiraEm, &k.UltimoUsoEm, &k.RevogadaEm, &k.CreatedAt,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear chave de API")
+			continue
+		}
+		apiKeys = append(apiKeys, k)
+	}
+
+	apiKeys, pagina := paginacao.Montar(apiKeys, params, func(k models.APIKey) (time.Time, uuid.UUID) {
+		return k.CreatedAt, k.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return apiKeys, pagina, nil
+}
+
+// RevogarAPIKey revoga uma chave de API do trader
+func (s *APIKeyService) RevogarAPIKey(ctx context.Context, traderID, apiKeyID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE api_keys
+		SET revogada_em = NOW()
+		WHERE id = $1 AND trader_id = $2 AND revogada_em IS NULL
+	`, apiKeyID, traderID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao revogar chave de API")
+		return fmt.Errorf("erro ao revogar chave de API")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar linhas afetadas")
+		return fmt.Errorf("erro interno do servidor")
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("chave de API não encontrada")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoAPIKeyRevogada, "api_key", apiKeyID.String(), nil, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da revogação da chave de API")
+		return fmt.Errorf("erro ao revogar chave de API")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":    traderID,
+		"api_key_id": apiKeyID,
+	}).Info("Chave de API revogada com sucesso")
+
+	return nil
+}
+
+// ValidarAPIKey valida uma chave recebida em uma requisição e registra seu uso
+func (s *APIKeyService) ValidarAPIKey(ctx context.Context, chave string) (*models.APIKey, error) {
+	if !strings.HasPrefix(chave, models.PrefixoAPIKey) {
+		return nil, fmt.Errorf("chave de API inválida")
+	}
+
+	var k models.APIKey
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, trader_id, nome, prefixo, escopos, expira_em, ultimo_uso_em, revogada_em, created_at
+		FROM api_keys
+		WHERE chave_hash = $1
+	`, hashChaveAPI(chave)).Scan(
+		&k.ID, &k.TraderID, &k.Nome, &k.Prefixo, pq.Array(&k.Escopos),
+		&k.ExpiraEm, &k.UltimoUsoEm, &k.RevogadaEm, &k.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("chave de API inválida")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar chave de API")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	if k.RevogadaEm != nil {
+		return nil, fmt.Errorf("chave de API revogada")
+	}
+
+	if k.ExpiraEm != nil && k.ExpiraEm.Before(time.Now()) {
+		return nil, fmt.Errorf("chave de API expirada")
+	}
+
+	// Atualiza o último uso no máximo uma vez por minuto para evitar escrita a cada requisição
+	_, err = s.db.ExecContext(ctx, `
+		UPDATE api_keys
+		SET ultimo_uso_em = NOW()
+		WHERE id = $1 AND (ultimo_uso_em IS NULL OR ultimo_uso_em < NOW() - INTERVAL '1 minute')
+	`, k.ID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("api_key_id", k.ID).Warn("Erro ao registrar uso da chave de API")
+	}
+
+	return &k, nil
+}
+
+// gerarChaveAPI gera uma chave aleatória no formato mgk_<prefixo>_<segredo>
+func gerarChaveAPI() (string, string, error) {
+	prefixoBytes := make([]byte, 4)
+	if _, err := rand.Read(prefixoBytes); err != nil {
+		return "", "", err
+	}
+
+	segredoBytes := make([]byte, 32)
+	if _, err := rand.Read(segredoBytes); err != nil {
+		return "", "", err
+	}
+
+	prefixo := models.PrefixoAPIKey + hex.EncodeToString(prefixoBytes)
+	chave := prefixo + "_" + hex.EncodeToString(segredoBytes)
+
+	return chave, prefixo, nil
+}
+
+// hashChaveAPI calcula o hash SHA-256 armazenado para a chave
+func hashChaveAPI(chave string) string {
+	hash := sha256.Sum256([]byte(chave))
+	return hex.EncodeToString(hash[:])
+}
+
+// escopoValido verifica se o escopo pode ser concedido a uma chave
+func escopoValido(escopo string) bool {
+	for _, e := range models.EscoposAPIKeyValidos {
+		if e == escopo {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+)
+
+// ArquivoService gerencia arquivamento (soft delete), restauração e purga de ofertas,
+// cavaletes e produtos aprovados
+type ArquivoService struct {
+	db        *sql.DB
+	auditoria *AuditoriaService
+	eventos   *EventosService
+}
+
+// NewArquivoService cria uma nova instância do ArquivoService. Os produtos arquivados e
+// restaurados são gravados como eventos (produto.removido e produto.aprovado), se
+// informado.
+func NewArquivoService(db *sql.DB, auditoria *AuditoriaService, eventos *EventosService) *ArquivoService {
+	return &ArquivoService{db: db, auditoria: auditoria, eventos: eventos}
+}
+
+// Arquivar arquiva as ofertas do trader (ou apenas a oferta informada) junto com seus
+// cavaletes e produtos aprovados. Todos os registros recebem o mesmo deleted_at, o que
+// permite restaurar exatamente o que foi arquivado.
+func (s *ArquivoService) Arquivar(ctx context.Context, traderID uuid.UUID, ofertaID *uuid.UUID) (*models.ArquivoResultado, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação de arquivamento")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarOferta(ctx, tx, traderID, ofertaID, false); err != nil {
+		return nil, err
+	}
+
+	var resultado models.ArquivoResultado
+
+	// NOW() é constante dentro da transação
+	produtoIDs, err := execRetornandoIDs(ctx, tx, `
+		UPDATE produtos_aprovados pa
+		SET deleted_at = NOW()
+		FROM cavaletes c
+		JOIN ofertas o ON c.oferta_id = o.id
+		WHERE pa.cavalete_id = c.id
+			AND o.trader_id = $1 AND ($2::uuid IS NULL OR o.id = $2)
+			AND o.deleted_at IS NULL AND pa.deleted_at IS NULL
+		RETURNING pa.id
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao arquivar produtos")
+		return nil, fmt.Errorf("erro ao arquivar registros")
+	}
+	resultado.Produtos = int64(len(produtoIDs))
+
+	resultado.Cavaletes, err = execContando(ctx, tx, `
+		UPDATE cavaletes c
+		SET deleted_at = NOW()
+		FROM ofertas o
+		WHERE c.oferta_id = o.id
+			AND o.trader_id = $1 AND ($2::uuid IS NULL OR o.id = $2)
+			AND o.deleted_at IS NULL AND c.deleted_at IS NULL
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao arquivar cavaletes")
+		return nil, fmt.Errorf("erro ao arquivar registros")
+	}
+
+	resultado.Ofertas, err = execContando(ctx, tx, `
+		UPDATE ofertas
+		SET deleted_at = NOW()
+		WHERE trader_id = $1 AND ($2::uuid IS NULL OR id = $2)
+			AND deleted_at IS NULL
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao arquivar ofertas")
+		return nil, fmt.Errorf("erro ao arquivar registros")
+	}
+
+	entidade, entidadeID := escopoArquivo(traderID, ofertaID)
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoArquivado, entidade, entidadeID, nil, resultado); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventos.registrarProdutos(ctx, tx, models.EventoProdutoRemovido, models.AcaoArquivado, produtoIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do arquivamento")
+		return nil, fmt.Errorf("erro ao arquivar registros")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":   traderID,
+		"oferta_id": ofertaID,
+		"ofertas":   resultado.Ofertas,
+		"cavaletes": resultado.Cavaletes,
+		"produtos":  resultado.Produtos,
+	}).Info("Registros arquivados com sucesso")
+
+	return &resultado, nil
+}
+
+// Restaurar restaura as ofertas arquivadas do trader (ou apenas a oferta informada).
+// Cavaletes e produtos só são restaurados se foram arquivados junto com a oferta;
+// produtos removidos individualmente continuam removidos.
+func (s *ArquivoService) Restaurar(ctx context.Context, traderID uuid.UUID, ofertaID *uuid.UUID) (*models.ArquivoResultado, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação de restauração")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarOferta(ctx, tx, traderID, ofertaID, true); err != nil {
+		return nil, err
+	}
+
+	var resultado models.ArquivoResultado
+
+	// A ordem importa: produtos e cavaletes são comparados com o deleted_at da oferta,
+	// que só é limpo ao final
+	produtoIDs, err := execRetornandoIDs(ctx, tx, `
+		UPDATE produtos_aprovados pa
+		SET deleted_at = NULL
+		FROM cavaletes c
+		JOIN ofertas o ON c.oferta_id = o.id
+		WHERE pa.cavalete_id = c.id
+			AND o.trader_id = $1 AND ($2::uuid IS NULL OR o.id = $2)
+			AND o.deleted_at IS NOT NULL AND pa.deleted_at = o.deleted_at
+		RETURNING pa.id
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao restaurar produtos")
+		return nil, fmt.Errorf("erro ao restaurar registros")
+	}
+	resultado.Produtos = int64(len(produtoIDs))
+
+	resultado.Cavaletes, err = execContando(ctx, tx, `
+		UPDATE cavaletes c
+		SET deleted_at = NULL
+		FROM ofertas o
+		WHERE c.oferta_id = o.id
+			AND o.trader_id = $1 AND ($2::uuid IS NULL OR o.id = $2)
+			AND o.deleted_at IS NOT NULL AND c.deleted_at = o.deleted_at
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao restaurar cavaletes")
+		return nil, fmt.Errorf("erro ao restaurar registros")
+	}
+
+	resultado.Ofertas, err = execContando(ctx, tx, `
+		UPDATE ofertas
+		SET deleted_at = NULL
+		WHERE trader_id = $1 AND ($2::uuid IS NULL OR id = $2)
+			AND deleted_at IS NOT NULL
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao restaurar ofertas")
+		return nil, fmt.Errorf("erro ao restaurar registros")
+	}
+
+	entidade, entidadeID := escopoArquivo(traderID, ofertaID)
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoRestaurado, entidade, entidadeID, nil, resultado); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventos.registrarProdutos(ctx, tx, models.EventoProdutoAprovado, models.AcaoRestaurado, produtoIDs); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da restauração")
+		return nil, fmt.Errorf("erro ao restaurar registros")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":   traderID,
+		"oferta_id": ofertaID,
+		"ofertas":   resultado.Ofertas,
+		"cavaletes": resultado.Cavaletes,
+		"produtos":  resultado.Produtos,
+	}).Info("Registros restaurados com sucesso")
+
+	return &resultado, nil
+}
+
+// produtoMantido verifica se o produto pa foi vendido, está reservado ou está em algum
+// orçamento fora de rascunho. Esses produtos (e seus cavaletes e ofertas) não são
+// purgados: a exclusão apagaria o registro da venda (valor_venda, cliente_id), os itens
+// dos orçamentos e o histórico de preços do produto.
+const produtoMantido = `(pa.status IN ('vendido', 'reservado') OR EXISTS (
+	SELECT 1 FROM orcamento_itens oi
+	JOIN orcamentos oc ON oc.id = oi.orcamento_id
+	WHERE oi.produto_id = pa.id AND oc.status <> 'rascunho'
+))`
+
+// Purgar remove definitivamente registros arquivados. Exige o texto de confirmação e,
+// em modo de simulação, desfaz a transação retornando apenas as quantidades. Produtos
+// vendidos, reservados ou em orçamentos fora de rascunho são mantidos, com seus
+// cavaletes e ofertas.
+func (s *ArquivoService) Purgar(ctx context.Context, request *models.PurgaRequest) (*models.PurgaResponse, error) {
+	if request.Confirmacao != models.ConfirmacaoPurga {
+		return nil, fmt.Errorf("confirmação inválida")
+	}
+
+	limite := time.Now()
+	if request.ArquivadosAntesDe != nil {
+		limite = *request.ArquivadosAntesDe
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação de purga")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	var resultado models.ArquivoResultado
+	var mantidos int64
+
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM produtos_aprovados pa
+		WHERE pa.deleted_at IS NOT NULL AND pa.deleted_at <= $1
+			AND ($2::uuid IS NULL OR pa.trader_id = $2)
+			AND `+produtoMantido, limite, request.TraderID).Scan(&mantidos)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao contar produtos mantidos na purga")
+		return nil, fmt.Errorf("erro ao purgar registros")
+	}
+
+	resultado.Produtos, err = execContando(ctx, tx, `
+		DELETE FROM produtos_aprovados pa
+		WHERE pa.deleted_at IS NOT NULL AND pa.deleted_at <= $1
+			AND ($2::uuid IS NULL OR pa.trader_id = $2)
+			AND NOT `+produtoMantido, limite, request.TraderID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao purgar produtos")
+		return nil, fmt.Errorf("erro ao purgar registros")
+	}
+
+	// Cavaletes e ofertas apagam em cascata os produtos restantes, então os que ainda
+	// têm produtos mantidos ficam
+	resultado.Cavaletes, err = execContando(ctx, tx, `
+		DELETE FROM cavaletes c
+		USING ofertas o
+		WHERE c.oferta_id = o.id
+			AND c.deleted_at IS NOT NULL AND c.deleted_at <= $1
+			AND ($2::uuid IS NULL OR o.trader_id = $2)
+			AND NOT EXISTS (
+				SELECT 1 FROM produtos_aprovados pa
+				WHERE pa.cavalete_id = c.id AND `+produtoMantido+`
+			)
+	`, limite, request.TraderID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao purgar cavaletes")
+		return nil, fmt.Errorf("erro ao purgar registros")
+	}
+
+	resultado.Ofertas, err = execContando(ctx, tx, `
+		DELETE FROM ofertas o
+		WHERE o.deleted_at IS NOT NULL AND o.deleted_at <= $1
+			AND ($2::uuid IS NULL OR o.trader_id = $2)
+			AND NOT EXISTS (
+				SELECT 1 FROM produtos_aprovados pa
+				WHERE pa.oferta_id = o.id AND `+produtoMantido+`
+			)
+	`, limite, request.TraderID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao purgar ofertas")
+		return nil, fmt.Errorf("erro ao purgar registros")
+	}
+
+	response := &models.PurgaResponse{ArquivoResultado: resultado, ProdutosMantidos: mantidos, Simulacao: request.Simular}
+	if request.Simular {
+		logs.Do(ctx).WithFields(logrus.Fields{
+			"ofertas":           resultado.Ofertas,
+			"cavaletes":         resultado.Cavaletes,
+			"produtos":          resultado.Produtos,
+			"produtos_mantidos": mantidos,
+		}).Info("Simulação de purga concluída")
+		return response, nil
+	}
+
+	entidadeID := ""
+	if request.TraderID != nil {
+		entidadeID = request.TraderID.String()
+	}
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoDadosPurgados, "banco_de_dados", entidadeID, request, response); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da purga")
+		return nil, fmt.Errorf("erro ao purgar registros")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"trader_id":         request.TraderID,
+		"limite":            limite,
+		"ofertas":           resultado.Ofertas,
+		"cavaletes":         resultado.Cavaletes,
+		"produtos":          resultado.Produtos,
+		"produtos_mantidos": mantidos,
+	}).Warn("Registros arquivados purgados definitivamente")
+
+	return response, nil
+}
+
+// verificarOferta garante que a oferta informada pertence ao trader e está no estado esperado
+func (s *ArquivoService) verificarOferta(ctx context.Context, tx *sql.Tx, traderID uuid.UUID, ofertaID *uuid.UUID, arquivada bool) error {
+	if ofertaID == nil {
+		return nil
+	}
+
+	var deletedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, `
+		SELECT deleted_at FROM ofertas WHERE id = $1 AND trader_id = $2
+	`, *ofertaID, traderID).Scan(&deletedAt)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("oferta não encontrada")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar oferta")
+		return fmt.Errorf("erro interno do servidor")
+	}
+
+	if arquivada && !deletedAt.Valid {
+		return fmt.Errorf("oferta não está arquivada")
+	}
+	if !arquivada && deletedAt.Valid {
+		return fmt.Errorf("oferta já está arquivada")
+	}
+
+	return nil
+}
+
+// escopoArquivo retorna a entidade auditada de uma operação de arquivo
+func escopoArquivo(traderID uuid.UUID, ofertaID *uuid.UUID) (string, string) {
+	if ofertaID != nil {
+		return "oferta", ofertaID.String()
+	}
+	return "trader", traderID.String()
+}
+
+// execContando executa um comando e retorna a quantidade de linhas afetadas
+func execContando(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (int64, error) {
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// execRetornandoIDs executa um comando com RETURNING id e retorna os ids afetados
+func execRetornandoIDs(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) ([]string, error) {
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/auth"
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+	"mobgran-importer-go/internal/requestctx"
+)
+
+// executor é satisfeito por *sql.DB e *sql.Tx, permitindo gravar a auditoria
+// na mesma transação da alteração auditada
+type executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// AuditoriaService registra e consulta a trilha de auditoria
+type AuditoriaService struct {
+	db *sql.DB
+}
+
+// NewAuditoriaService cria uma nova instância do AuditoriaService
+func NewAuditoriaService(db *sql.DB) *AuditoriaService {
+	return &AuditoriaService{db: db}
+}
+
+// Registrar grava uma entrada de auditoria. O ator e o request id são obtidos do contexto.
+// Quando exec é nil a gravação é feita fora de transação.
+func (s *AuditoriaService) Registrar(ctx context.Context, exec executor, acao, entidade, entidadeID string, antes, depois interface{}) error {
+	if exec == nil {
+		exec = s.db
+	}
+
+	dadosAntes, err := serializarAuditoria(antes)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar dados anteriores: %w", err)
+	}
+
+	dadosDepois, err := serializarAuditoria(depois)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar dados posteriores: %w", err)
+	}
+
+	atorTipo := models.AtorTipoSistema
+	var atorID, atorEmail, apiKeyID, entID, requestID sql.NullString
+
+	if user, err := auth.GetUserFromContext(ctx); err == nil {
+		atorTipo = models.AtorTipoUsuario
+		atorID = nullString(user.UserID)
+		atorEmail = nullString(user.Email)
+		if user.APIKeyID != "" {
+			atorTipo = models.AtorTipoAPIKey
+			apiKeyID = nullString(user.APIKeyID)
+		}
+	}
+
+	entID = nullString(entidadeID)
+	requestID = nullString(requestctx.GetRequestID(ctx))
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO auditoria (
+			id, ator_id, ator_email, ator_tipo, api_key_id, acao, entidade, entidade_id,
+			dados_antes, dados_depois, request_id
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, uuid.New(), atorID, atorEmail, atorTipo, apiKeyID, acao, entidade, entID,
+		dadosAntes, dadosDepois, requestID,
+	)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithFields(logrus.Fields{
+			"acao":        acao,
+			"entidade":    entidade,
+			"entidade_id": entidadeID,
+		}).Error("Erro ao registrar auditoria")
+		return fmt.Errorf("erro ao registrar auditoria")
+	}
+
+	return nil
+}
+
+// ListarRegistros consulta a trilha de auditoria com filtros e paginação por cursor ou offset
+func (s *AuditoriaService) ListarRegistros(ctx context.Context, filtro *models.FiltroAuditoria) ([]models.RegistroAuditoria, *paginacao.Pagina, error) {
+	// Constrói o WHERE dinamicamente
+	conditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	adicionar := func(condition string, value interface{}) {
+		conditions = append(conditions, fmt.Sprintf(condition, argIndex))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if filtro.AtorID != "" {
+		adicionar("ator_id = $%d", filtro.AtorID)
+	}
+	if filtro.Acao != "" {
+		adicionar("acao = $%d", filtro.Acao)
+	}
+	if filtro.Entidade != "" {
+		adicionar("entidade = $%d", filtro.Entidade)
+	}
+	if filtro.EntidadeID != "" {
+		adicionar("entidade_id = $%d", filtro.EntidadeID)
+	}
+	if filtro.RequestID != "" {
+		adicionar("request_id = $%d", filtro.RequestID)
+	}
+	if filtro.Desde != nil {
+		adicionar("created_at >= $%d", *filtro.Desde)
+	}
+	if filtro.Ate != nil {
+		adicionar("created_at <= $%d", *filtro.Ate)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	params := &filtro.Paginacao
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM auditoria "+where, args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar registros de auditoria")
+			return nil, nil, fmt.Errorf("erro ao buscar registros de auditoria")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("created_at", "id", argIndex)
+	if condicao != "" {
+		if where == "" {
+			where = "WHERE " + condicao
+		} else {
+			where += " AND " + condicao
+		}
+		args = append(args, argsCursor...)
+		argIndex += len(argsCursor)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT id, ator_id, ator_email, ator_tipo, api_key_id, acao, entidade, entidade_id,
+			   dados_antes, dados_depois, request_id, created_at
+		FROM auditoria
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar registros de auditoria")
+		return nil, nil, fmt.Errorf("erro ao buscar registros de auditoria")
+	}
+	defer rows.Close()
+
+	registros := []models.RegistroAuditoria{}
+	for rows.Next() {
+		var r models.RegistroAuditoria
+		var dadosAntes, dadosDepois []byte
+		err := rows.Scan(
+			&r.ID, &r.AtorID, &r.AtorEmail, &r.AtorTipo, &r.APIKeyID, &r.Acao, &r.Entidade,
+			&r.EntidadeID, &dadosAntes, &dadosDepois, &r.RequestID, &r.CreatedAt,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear registro de auditoria")
+			continue
+		}
+		r.DadosAntes = dadosAntes
+		r.DadosDepois = dadosDepois
+		registros = append(registros, r)
+	}
+
+	registros, pagina := paginacao.Montar(registros, params, func(r models.RegistroAuditoria) (time.Time, uuid.UUID) {
+		return r.CreatedAt, r.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return registros, pagina, nil
+}
+
+// serializarAuditoria converte os dados auditados para JSON, mantendo NULL quando ausentes
+func serializarAuditoria(dados interface{}) (interface{}, error) {
+	if dados == nil {
+		return nil, nil
+	}
+
+	dadosJSON, err := json.Marshal(dados)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(dadosJSON), nil
+}
+
+// nullString converte strings vazias em NULL
+func nullString(valor string) sql.NullString {
+	return sql.NullString{String: valor, Valid: valor != ""}
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/medidas"
+)
+
+// configuracaoBusca é a configuração de busca textual criada na migration 008
+// (dicionário português, sem acentos)
+const configuracaoBusca = "portugues_sem_acento"
+
+// limiteFacetas limita a quantidade de valores retornados por faceta
+const limiteFacetas = 50
+
+// Dimensões das facetas, usadas para não aplicar o filtro da própria faceta na contagem
+const (
+	facetaMaterial      = "material"
+	facetaEspessura     = "espessura"
+	facetaClassificacao = "classificacao"
+)
+
+// BuscaService implementa a busca textual e por facetas sobre cavaletes e produtos aprovados
+type BuscaService struct {
+	db *sql.DB
+}
+
+// NewBuscaService cria uma nova instância do BuscaService
+func NewBuscaService(db *sql.DB) *BuscaService {
+	return &BuscaService{db: db}
+}
+
+// consultaBusca descreve a origem dos dados de uma busca
+type consultaBusca struct {
+	from   string
+	escopo []string
+	vetor  string
+	alias  string
+	// disponivel é a condição aplicada com ApenasDisponiveis (cavaletes ainda não aprovados)
+	disponivel string
+}
+
+var consultaCavaletes = consultaBusca{
+	from: `
+		FROM cavaletes c
+		JOIN ofertas o ON c.oferta_id = o.id
+		LEFT JOIN produtos_aprovados pa ON pa.cavalete_id = c.id AND pa.trader_id = $1 AND pa.deleted_at IS NULL`,
+	escopo: []string{
+		"o.situacao = 'ativa'",
+		"o.trader_id = $1",
+		"o.deleted_at IS NULL",
+		"c.deleted_at IS NULL",
+	},
+	vetor:      "c.busca_vetor",
+	alias:      "c",
+	disponivel: "pa.id IS NULL",
+}
+
+var consultaProdutos = consultaBusca{
+	from: `
+		FROM produtos_aprovados pa
+		JOIN cavaletes c ON pa.cavalete_id = c.id
+		JOIN ofertas o ON c.oferta_id = o.id`,
+	escopo: []string{
+		"pa.trader_id = $1",
+		"pa.deleted_at IS NULL",
+		"c.deleted_at IS NULL",
+		"o.deleted_at IS NULL",
+	},
+	vetor: "(pa.busca_vetor || c.busca_vetor)",
+	alias: "pa",
+}
+
+// BuscarCavaletes busca cavaletes do trader por texto e filtros, retornando também as facetas
+func (s *BuscaService) BuscarCavaletes(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroBusca) (*models.ResultadoBuscaCavaletes, error) {
+	where, args := montarFiltroBusca(consultaCavaletes, traderID, filtro, "")
+
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) "+consultaCavaletes.from+where, args...).Scan(&total)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao contar cavaletes da busca")
+		return nil, fmt.Errorf("erro ao buscar cavaletes")
+	}
+
+	relevancia, orderBy := ordenacaoBusca(consultaCavaletes, filtro)
+	query := fmt.Sprintf(`
+		SELECT
+			c.id, c.oferta_id, c.codigo, c.bloco, c.nome_material, c.nome_espessura,
+			c.produto_cliente, c.espessura_cliente,
+			COALESCE(c.nome_classificacao, ''), c.nome_acabamento, c.comprimento, c.altura, c.largura,
+			c.metragem, c.peso, c.tipo_metragem, c.imagem_principal, c.imagens_adicionais,
+			c.created_at, c.updated_at,
+			c.espessura_mm, c.comprimento_m, c.altura_m, c.metragem_m2, c.peso_kg, c.unidade_dimensoes,
+			o.trader_id, o.nome_empresa, o.fornecedor_id,
+			CASE WHEN pa.id IS NOT NULL THEN true ELSE false END as ja_aprovado,
+			%s AS relevancia
+		%s%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, relevancia, consultaCavaletes.from, where, orderBy, len(args)+1, len(args)+2)
+
+	rows, err := s.db.QueryContext(ctx, query, append(args, filtro.Limit, filtro.Offset)...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar cavaletes")
+		return nil, fmt.Errorf("erro ao buscar cavaletes")
+	}
+	defer rows.Close()
+
+	cavaletes := []models.CavaleteEncontrado{}
+	for rows.Next() {
+		var c models.CavaleteEncontrado
+		err := rows.Scan(
+			&c.ID, &c.OfertaID, &c.Codigo, &c.Bloco, &c.NomeMaterial, &c.NomeEspessura,
+			&c.ProdutoCliente, &c.EspessuraCliente,
+			&c.NomeClassificacao, &c.NomeAcabamento, &c.Comprimento, &c.Altura, &c.Largura,
+			&c.Metragem, &c.Peso, &c.TipoMetragem, &c.ImagemPrincipal, &c.ImagensAdicionais,
+			&c.CreatedAt, &c.UpdatedAt,
+			&c.Medidas.EspessuraMM, &c.Medidas.ComprimentoM, &c.Medidas.AlturaM,
+			&c.Medidas.MetragemM2, &c.Medidas.PesoKg, &c.Medidas.UnidadeDimensoes,
+			&c.TraderID, &c.NomeEmpresa, &c.FornecedorID, &c.JaAprovado,
+			&c.Relevancia,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear cavalete da busca")
+			continue
+		}
+		cavaletes = append(cavaletes, c)
+	}
+
+	facetas, err := s.calcularFacetas(ctx, consultaCavaletes, traderID, filtro)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ResultadoBuscaCavaletes{
+		Cavaletes: cavaletes,
+		Total:     total,
+		Facetas:   *facetas,
+		Limit:     filtro.Limit,
+		Offset:    filtro.Offset,
+	}, nil
+}
+
+// BuscarProdutos busca produtos aprovados do trader por texto e filtros, retornando também as facetas
+func (s *BuscaService) BuscarProdutos(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroBusca) (*models.ResultadoBuscaProdutos, error) {
+	where, args := montarFiltroBusca(consultaProdutos, traderID, filtro, "")
+
+	var total int
+	err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) "+consultaProdutos.from+where, args...).Scan(&total)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao contar produtos da busca")
+		return nil, fmt.Errorf("erro ao buscar produtos")
+	}
+
+	relevancia, orderBy := ordenacaoBusca(consultaProdutos, filtro)
+	query := fmt.Sprintf(`
+		SELECT
+			pa.id, pa.trader_id, pa.cavalete_id, pa.nome_customizado, pa.preco_venda, pa.descricao,
+			pa.visivel, pa.destaque, pa.ordem_exibicao, pa.created_at, pa.updated_at,
+			c.codigo, c.bloco, c.nome_material, c.nome_espessura, c.nome_classificacao,
+			c.nome_acabamento, c.comprimento, c.altura, c.metragem,
+			c.espessura_mm, c.comprimento_m, c.altura_m, c.metragem_m2, c.peso_kg, c.unidade_dimensoes,
+			%s AS relevancia
+		%s%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, relevancia, consultaProdutos.from, where, orderBy, len(args)+1, len(args)+2)
+
+	rows, err := s.db.QueryContext(ctx, query, append(args, filtro.Limit, filtro.Offset)...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produtos")
+		return nil, fmt.Errorf("erro ao buscar produtos")
+	}
+	defer rows.Close()
+
+	produtos := []models.ProdutoEncontrado{}
+	for rows.Next() {
+		var p models.ProdutoEncontrado
+		err := rows.Scan(
+			&p.ID, &p.TraderID, &p.CavaleteID, &p.NomeCustomizado, &p.PrecoVenda, &p.Descricao,
+			&p.Visivel, &p.Destaque, &p.OrdemExibicao, &p.CreatedAt, &p.UpdatedAt,
+			&p.Codigo, &p.Bloco, &p.NomeMaterial, &p.NomeEspessura, &p.NomeClassificacao,
+			&p.NomeAcabamento, &p.Comprimento, &p.Altura, &p.Metragem,
+			&p.Medidas.EspessuraMM, &p.Medidas.ComprimentoM, &p.Medidas.AlturaM,
+			&p.Medidas.MetragemM2, &p.Medidas.PesoKg, &p.Medidas.UnidadeDimensoes,
+			&p.Relevancia,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear produto da busca")
+			continue
+		}
+		produtos = append(produtos, p)
+	}
+
+	facetas, err := s.calcularFacetas(ctx, consultaProdutos, traderID, filtro)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ResultadoBuscaProdutos{
+		Produtos: produtos,
+		Total:    total,
+		Facetas:  *facetas,
+		Limit:    filtro.Limit,
+		Offset:   filtro.Offset,
+	}, nil
+}
+
+// calcularFacetas conta os resultados por material, espessura e classificação
+func (s *BuscaService) calcularFacetas(ctx context.Context, consulta consultaBusca, traderID uuid.UUID, filtro *models.FiltroBusca) (*models.FacetasBusca, error) {
+	facetas := &models.FacetasBusca{}
+	dimensoes := []struct {
+		faceta  string
+		coluna  string
+		destino *[]models.Faceta
+	}{
+		{facetaMaterial, "COALESCE(c.produto_cliente, c.nome_material)", &facetas.Materiais},
+		{facetaEspessura, "c.nome_espessura", &facetas.Espessuras},
+		{facetaClassificacao, "c.nome_classificacao", &facetas.Classificacoes},
+	}
+
+	for _, d := range dimensoes {
+		where, args := montarFiltroBusca(consulta, traderID, filtro, d.faceta)
+		query := fmt.Sprintf(`
+			SELECT %[1]s, COUNT(*)
+			%[2]s%[3]s AND %[1]s IS NOT NULL AND %[1]s <> ''
+			GROUP BY %[1]s
+			ORDER BY COUNT(*) DESC, %[1]s ASC
+			LIMIT %[4]d
+		`, d.coluna, consulta.from, where, limiteFacetas)
+
+		rows, err := s.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			logs.Do(ctx).WithError(err).WithField("faceta", d.faceta).Error("Erro ao calcular faceta")
+			return nil, fmt.Errorf("erro ao calcular facetas")
+		}
+
+		valores := []models.Faceta{}
+		for rows.Next() {
+			var f models.Faceta
+			if err := rows.Scan(&f.Valor, &f.Quantidade); err != nil {
+				logs.Do(ctx).WithError(err).Error("Erro ao escanear faceta")
+				continue
+			}
+			valores = append(valores, f)
+		}
+		rows.Close()
+
+		*d.destino = valores
+	}
+
+	return facetas, nil
+}
+
+// montarFiltroBusca monta o WHERE da busca a partir do escopo do trader e dos filtros.
+// O filtro da dimensão informada em omitir não é aplicado (usado no cálculo das facetas).
+func montarFiltroBusca(consulta consultaBusca, traderID uuid.UUID, filtro *models.FiltroBusca, omitir string) (string, []interface{}) {
+	conditions := append([]string{}, consulta.escopo...)
+	args := []interface{}{traderID}
+
+	adicionar := func(condition string, value interface{}) {
+		args = append(args, value)
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", len(args))))
+	}
+
+	// O termo é sempre o segundo argumento quando presente, para ser reutilizado no ranking
+	if filtro.Termo != "" {
+		adicionar(consulta.vetor+" @@ websearch_to_tsquery('"+configuracaoBusca+"', $?)", filtro.Termo)
+	}
+	if filtro.Material != "" && omitir != facetaMaterial {
+		adicionar("(lower(COALESCE(c.produto_cliente, c.nome_material)) = lower($?) OR lower(c.nome_material) = lower($?))", filtro.Material)
+	}
+	if filtro.FornecedorID != nil {
+		adicionar("o.fornecedor_id = $?", *filtro.FornecedorID)
+	}
+	if filtro.Espessura != "" && omitir != facetaEspessura {
+		if mm, ok := medidas.EspessuraMM(filtro.Espessura); ok {
+			adicionar("c.espessura_mm = $?", mm)
+		} else {
+			adicionar("lower(c.nome_espessura) = lower($?)", filtro.Espessura)
+		}
+	}
+	if filtro.Classificacao != "" && omitir != facetaClassificacao {
+		adicionar("lower(c.nome_classificacao) = lower($?)", filtro.Classificacao)
+	}
+	if filtro.Acabamento != "" {
+		adicionar("lower(c.nome_acabamento) = lower($?)", filtro.Acabamento)
+	}
+	if filtro.ComprimentoMin != nil {
+		adicionar("c.comprimento_m >= $?", *filtro.ComprimentoMin)
+	}
+	if filtro.ComprimentoMax != nil {
+		adicionar("c.comprimento_m <= $?", *filtro.ComprimentoMax)
+	}
+	if filtro.AlturaMin != nil {
+		adicionar("c.altura_m >= $?", *filtro.AlturaMin)
+	}
+	if filtro.AlturaMax != nil {
+		adicionar("c.altura_m <= $?", *filtro.AlturaMax)
+	}
+	if filtro.MetragemMin != nil {
+		adicionar("c.metragem_m2 >= $?", *filtro.MetragemMin)
+	}
+	if filtro.MetragemMax != nil {
+		adicionar("c.metragem_m2 <= $?", *filtro.MetragemMax)
+	}
+	if filtro.ApenasDisponiveis && consulta.disponivel != "" {
+		conditions = append(conditions, consulta.disponivel)
+	}
+
+	return "\n\t\tWHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ordenacaoBusca retorna a expressão de relevância e o ORDER BY: por relevância quando há
+// termo de busca, senão pelos mais recentes
+func ordenacaoBusca(consulta consultaBusca, filtro *models.FiltroBusca) (string, string) {
+	recentes := fmt.Sprintf("%[1]s.created_at DESC, %[1]s.id", consulta.alias)
+	if filtro.Termo == "" {
+		return "0::float8", recentes
+	}
+
+	relevancia := fmt.Sprintf("ts_rank(%s, websearch_to_tsquery('%s', $2))::float8", consulta.vetor, configuracaoBusca)
+	return relevancia, "relevancia DESC, " + recentes
+}
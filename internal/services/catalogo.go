@@ -0,0 +1,836 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/catalogo"
+)
+
+// CatalogoService mantém o catálogo curado de materiais e espessuras e associa a ele
+// os nomes importados de cada fornecedor, preenchendo produto_cliente e
+// espessura_cliente dos cavaletes
+type CatalogoService struct {
+	db        *sql.DB
+	auditoria *AuditoriaService
+	// reaplicacaoPendente marca que o catálogo mudou desde a última reaplicação; com
+	// buffer 1, alterações seguidas resultam em uma só reaplicação
+	reaplicacaoPendente chan struct{}
+}
+
+// NewCatalogoService cria uma nova instância do CatalogoService
+func NewCatalogoService(db *sql.DB, auditoria *AuditoriaService) *CatalogoService {
+	return &CatalogoService{db: db, auditoria: auditoria, reaplicacaoPendente: make(chan struct{}, 1)}
+}
+
+// itemCatalogo é o material ou espessura do catálogo associado a um nome importado
+type itemCatalogo struct {
+	id   uuid.UUID
+	nome string
+}
+
+// indiceCatalogo reúne o catálogo em memória para associar os nomes importados
+type indiceCatalogo struct {
+	// materiais indexa nomes e sinônimos pela chave canônica
+	materiais map[string]itemCatalogo
+	// espessuras indexa as espessuras padrão pelo valor em milímetros
+	espessuras map[float64]itemCatalogo
+	// mapeamentos guarda as correspondências definidas pelos traders
+	mapeamentos map[chaveMapeamento]itemCatalogo
+}
+
+type chaveMapeamento struct {
+	traderID uuid.UUID
+	tipo     string
+	chave    string
+}
+
+// ListarMateriais lista o catálogo de materiais com os sinônimos. A busca considera o
+// nome e os sinônimos, ignorando acentos.
+func (s *CatalogoService) ListarMateriais(ctx context.Context, busca string) ([]models.MaterialCatalogo, error) {
+	query := `SELECT m.id, m.nome, m.tipo, m.created_at, m.updated_at FROM catalogo_materiais m`
+	args := []interface{}{}
+	if busca != "" {
+		args = append(args, "%"+busca+"%")
+		query += ` WHERE unaccent(m.nome) ILIKE unaccent($1) OR EXISTS (
+			SELECT 1 FROM catalogo_sinonimos cs WHERE cs.material_id = m.id AND unaccent(cs.sinonimo) ILIKE unaccent($1))`
+	}
+	query += ` ORDER BY m.nome`
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao listar materiais do catálogo")
+		return nil, fmt.Errorf("erro ao listar materiais")
+	}
+	defer rows.Close()
+
+	materiais := []models.MaterialCatalogo{}
+	for rows.Next() {
+		var m models.MaterialCatalogo
+		if err := rows.Scan(&m.ID, &m.Nome, &m.Tipo, &m.CreatedAt, &m.UpdatedAt); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear material do catálogo")
+			return nil, fmt.Errorf("erro ao listar materiais")
+		}
+		materiais = append(materiais, m)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao listar materiais do catálogo")
+		return nil, fmt.Errorf("erro ao listar materiais")
+	}
+
+	if err := s.carregarSinonimos(ctx, s.db, materiais); err != nil {
+		return nil, err
+	}
+	return materiais, nil
+}
+
+// BuscarMaterial retorna um material do catálogo com os sinônimos
+func (s *CatalogoService) BuscarMaterial(ctx context.Context, materialID uuid.UUID) (*models.MaterialCatalogo, error) {
+	return s.buscarMaterial(ctx, s.db, materialID)
+}
+
+// CriarMaterial inclui um material no catálogo e reaplica o catálogo aos cavaletes ainda
+// sem correspondência
+func (s *CatalogoService) CriarMaterial(ctx context.Context, request *models.MaterialCatalogoRequest) (*models.MaterialCatalogo, error) {
+	nome := strings.TrimSpace(request.Nome)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarChave(ctx, tx, nome, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	var materialID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO catalogo_materiais (nome, chave, tipo) VALUES ($1, $2, $3) RETURNING id
+	`, nome, catalogo.Chave(nome), textoOuNulo(request.Tipo)).Scan(&materialID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao incluir material no catálogo")
+		return nil, fmt.Errorf("erro ao incluir material")
+	}
+
+	for _, sinonimo := range request.Sinonimos {
+		if err := s.inserirSinonimo(ctx, tx, materialID, sinonimo); err != nil {
+			return nil, err
+		}
+	}
+
+	material, err := s.buscarMaterial(ctx, tx, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoMaterialCriado, "material", materialID.String(), nil, material); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do material")
+		return nil, fmt.Errorf("erro ao incluir material")
+	}
+
+	s.reaplicarEmSegundoPlano(ctx)
+	return material, nil
+}
+
+// AtualizarMaterial altera o nome ou o tipo de um material do catálogo
+func (s *CatalogoService) AtualizarMaterial(ctx context.Context, materialID uuid.UUID, request *models.MaterialCatalogoAtualizarRequest) (*models.MaterialCatalogo, error) {
+	if request.Nome == nil && request.Tipo == nil {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	return s.alterarMaterial(ctx, materialID, models.AcaoMaterialAtualizado, func(tx *sql.Tx) error {
+		if request.Nome != nil {
+			nome := strings.TrimSpace(*request.Nome)
+			if err := s.verificarChave(ctx, tx, nome, materialID); err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE catalogo_materiais SET nome = $2, chave = $3, updated_at = NOW() WHERE id = $1
+			`, materialID, nome, catalogo.Chave(nome)); err != nil {
+				logs.Do(ctx).WithError(err).Error("Erro ao atualizar material do catálogo")
+				return fmt.Errorf("erro ao atualizar material")
+			}
+		}
+		if request.Tipo != nil {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE catalogo_materiais SET tipo = $2, updated_at = NOW() WHERE id = $1
+			`, materialID, textoOuNulo(request.Tipo)); err != nil {
+				logs.Do(ctx).WithError(err).Error("Erro ao atualizar material do catálogo")
+				return fmt.Errorf("erro ao atualizar material")
+			}
+		}
+		return nil
+	})
+}
+
+// AdicionarSinonimo inclui um sinônimo no material do catálogo
+func (s *CatalogoService) AdicionarSinonimo(ctx context.Context, materialID uuid.UUID, request *models.SinonimoRequest) (*models.MaterialCatalogo, error) {
+	return s.alterarMaterial(ctx, materialID, models.AcaoMaterialAtualizado, func(tx *sql.Tx) error {
+		return s.inserirSinonimo(ctx, tx, materialID, request.Sinonimo)
+	})
+}
+
+// RemoverSinonimo exclui um sinônimo do material do catálogo
+func (s *CatalogoService) RemoverSinonimo(ctx context.Context, materialID, sinonimoID uuid.UUID) (*models.MaterialCatalogo, error) {
+	return s.alterarMaterial(ctx, materialID, models.AcaoMaterialAtualizado, func(tx *sql.Tx) error {
+		afetadas, err := execContando(ctx, tx, `
+			DELETE FROM catalogo_sinonimos WHERE id = $1 AND material_id = $2
+		`, sinonimoID, materialID)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao remover sinônimo")
+			return fmt.Errorf("erro ao remover sinônimo")
+		}
+		if afetadas == 0 {
+			return fmt.Errorf("sinônimo não encontrado")
+		}
+		return nil
+	})
+}
+
+// RemoverMaterial exclui o material do catálogo, desfazendo as correspondências dos
+// cavaletes e os mapeamentos dos traders que apontavam para ele
+func (s *CatalogoService) RemoverMaterial(ctx context.Context, materialID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscarMaterial(ctx, tx, materialID)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE cavaletes SET material_id = NULL, produto_cliente = NULL, origem_material = NULL, updated_at = NOW()
+		WHERE material_id = $1
+	`, materialID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao desfazer correspondências do material")
+		return fmt.Errorf("erro ao remover material")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM catalogo_materiais WHERE id = $1`, materialID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover material do catálogo")
+		return fmt.Errorf("erro ao remover material")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoMaterialRemovido, "material", materialID.String(), antes, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção do material")
+		return fmt.Errorf("erro ao remover material")
+	}
+	return nil
+}
+
+// ListarEspessuras lista as espessuras padrão do catálogo
+func (s *CatalogoService) ListarEspessuras(ctx context.Context) ([]models.EspessuraCatalogo, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, nome, espessura_mm FROM catalogo_espessuras ORDER BY espessura_mm
+	`)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao listar espessuras do catálogo")
+		return nil, fmt.Errorf("erro ao listar espessuras")
+	}
+	defer rows.Close()
+
+	espessuras := []models.EspessuraCatalogo{}
+	for rows.Next() {
+		var e models.EspessuraCatalogo
+		if err := rows.Scan(&e.ID, &e.Nome, &e.EspessuraMM); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear espessura do catálogo")
+			return nil, fmt.Errorf("erro ao listar espessuras")
+		}
+		espessuras = append(espessuras, e)
+	}
+	return espessuras, rows.Err()
+}
+
+// colunasMapeamento são as colunas do cavalete envolvidas em cada tipo de mapeamento
+var colunasMapeamento = map[string]struct {
+	origem, id, nome, tipoOrigem string
+}{
+	models.TipoMapeamentoMaterial:  {"c.nome_material", "c.material_id", "c.produto_cliente", "c.origem_material"},
+	models.TipoMapeamentoEspessura: {"c.nome_espessura", "c.espessura_id", "c.espessura_cliente", "c.origem_espessura"},
+}
+
+// ListarMapeamentos lista os nomes de material e espessura dos cavaletes do trader com o
+// item do catálogo associado a cada um, para conferência
+func (s *CatalogoService) ListarMapeamentos(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroMapeamentos) ([]models.MapeamentoCatalogo, error) {
+	tipos := []string{models.TipoMapeamentoMaterial, models.TipoMapeamentoEspessura}
+	if filtro.Tipo != "" {
+		tipos = []string{filtro.Tipo}
+	}
+
+	mapeamentos := []models.MapeamentoCatalogo{}
+	for _, tipo := range tipos {
+		colunas := colunasMapeamento[tipo]
+		pendentes := ""
+		if filtro.Pendentes {
+			pendentes = fmt.Sprintf("AND %s IS DISTINCT FROM '%s'", colunas.tipoOrigem, models.OrigemCatalogoTrader)
+		}
+
+		rows, err := s.db.QueryContext(ctx, fmt.Sprintf(`
+			SELECT %[1]s, COUNT(*), %[2]s, %[3]s, %[4]s
+			FROM cavaletes c
+			JOIN ofertas o ON o.id = c.oferta_id
+			WHERE o.trader_id = $1 AND c.deleted_at IS NULL AND o.deleted_at IS NULL %[5]s
+			GROUP BY %[1]s, %[2]s, %[3]s, %[4]s
+			ORDER BY %[2]s IS NOT NULL, %[1]s
+		`, colunas.origem, colunas.id, colunas.nome, colunas.tipoOrigem, pendentes), traderID)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao listar mapeamentos do catálogo")
+			return nil, fmt.Errorf("erro ao listar mapeamentos")
+		}
+
+		for rows.Next() {
+			m := models.MapeamentoCatalogo{Tipo: tipo}
+			if err := rows.Scan(&m.ValorOrigem, &m.Cavaletes, &m.CatalogoID, &m.NomeCatalogo, &m.Origem); err != nil {
+				rows.Close()
+				logs.Do(ctx).WithError(err).Error("Erro ao escanear mapeamento do catálogo")
+				return nil, fmt.Errorf("erro ao listar mapeamentos")
+			}
+			mapeamentos = append(mapeamentos, m)
+		}
+		rows.Close()
+	}
+
+	return mapeamentos, nil
+}
+
+// DefinirMapeamento confirma ou corrige o item do catálogo associado a um nome importado
+// pelo trader. O mapeamento vale para todas as grafias com a mesma chave canônica, nos
+// cavaletes existentes e nas próximas importações.
+func (s *CatalogoService) DefinirMapeamento(ctx context.Context, traderID uuid.UUID, request *models.DefinirMapeamentoRequest) (*models.MapeamentoDefinido, error) {
+	valor := strings.TrimSpace(request.ValorOrigem)
+	chave := catalogo.Chave(valor)
+	if chave == "" {
+		return nil, fmt.Errorf("valor_origem inválido")
+	}
+
+	tabela := "catalogo_materiais"
+	var materialID, espessuraID *uuid.UUID
+	if request.Tipo == models.TipoMapeamentoMaterial {
+		materialID = &request.CatalogoID
+	} else {
+		tabela = "catalogo_espessuras"
+		espessuraID = &request.CatalogoID
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	resultado := &models.MapeamentoDefinido{Tipo: request.Tipo, ValorOrigem: valor, CatalogoID: request.CatalogoID}
+	err = tx.QueryRowContext(ctx, `SELECT nome FROM `+tabela+` WHERE id = $1`, request.CatalogoID).Scan(&resultado.NomeCatalogo)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("item do catálogo não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar item do catálogo")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO mapeamentos_catalogo (trader_id, tipo, valor_origem, chave, material_id, espessura_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (trader_id, tipo, chave) DO UPDATE
+		SET valor_origem = EXCLUDED.valor_origem, material_id = EXCLUDED.material_id,
+			espessura_id = EXCLUDED.espessura_id, updated_at = NOW()
+	`, traderID, request.Tipo, valor, chave, materialID, espessuraID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao gravar mapeamento do catálogo")
+		return nil, fmt.Errorf("erro ao gravar mapeamento")
+	}
+
+	if resultado.CavaletesAtualizados, err = s.aplicar(ctx, tx, &traderID, nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoMapeamentoDefinido, "trader", traderID.String(), nil, resultado); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do mapeamento")
+		return nil, fmt.Errorf("erro ao gravar mapeamento")
+	}
+	return resultado, nil
+}
+
+// RemoverMapeamento desfaz o mapeamento do trader; o nome volta a ser associado
+// automaticamente pelo catálogo
+func (s *CatalogoService) RemoverMapeamento(ctx context.Context, traderID uuid.UUID, tipo, valorOrigem string) (*models.AplicacaoCatalogoResultado, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	afetadas, err := execContando(ctx, tx, `
+		DELETE FROM mapeamentos_catalogo WHERE trader_id = $1 AND tipo = $2 AND chave = $3
+	`, traderID, tipo, catalogo.Chave(valorOrigem))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover mapeamento do catálogo")
+		return nil, fmt.Errorf("erro ao remover mapeamento")
+	}
+	if afetadas == 0 {
+		return nil, fmt.Errorf("mapeamento não encontrado")
+	}
+
+	resultado := &models.AplicacaoCatalogoResultado{}
+	if resultado.CavaletesAtualizados, err = s.aplicar(ctx, tx, &traderID, nil); err != nil {
+		return nil, err
+	}
+
+	antes := map[string]string{"tipo": tipo, "valor_origem": valorOrigem}
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoMapeamentoRemovido, "trader", traderID.String(), antes, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção do mapeamento")
+		return nil, fmt.Errorf("erro ao remover mapeamento")
+	}
+	return resultado, nil
+}
+
+// AplicarOferta associa ao catálogo os cavaletes de uma oferta recém-importada
+func (s *CatalogoService) AplicarOferta(ctx context.Context, ofertaID string) (int, error) {
+	id, err := uuid.Parse(ofertaID)
+	if err != nil {
+		return 0, fmt.Errorf("oferta_id inválido")
+	}
+	return s.aplicarTransacao(ctx, nil, &id)
+}
+
+// Reaplicar recalcula as correspondências de todos os cavaletes, por exemplo após
+// mudanças no catálogo. Cada trader é aplicado e confirmado em sua própria transação,
+// para não manter locks sobre todos os cavaletes de uma vez.
+func (s *CatalogoService) Reaplicar(ctx context.Context) (*models.AplicacaoCatalogoResultado, error) {
+	rows, err := s.db.QueryContext(ctx, `SELECT DISTINCT trader_id FROM ofertas`)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar traders para reaplicar o catálogo")
+		return nil, fmt.Errorf("erro ao aplicar catálogo")
+	}
+	var traders []uuid.UUID
+	for rows.Next() {
+		var traderID uuid.UUID
+		if err := rows.Scan(&traderID); err != nil {
+			rows.Close()
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear trader")
+			return nil, fmt.Errorf("erro ao aplicar catálogo")
+		}
+		traders = append(traders, traderID)
+	}
+	rows.Close()
+
+	resultado := &models.AplicacaoCatalogoResultado{}
+	for _, traderID := range traders {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		atualizados, err := s.aplicarTransacao(ctx, &traderID, nil)
+		if err != nil {
+			return nil, err
+		}
+		resultado.CavaletesAtualizados += atualizados
+	}
+	return resultado, nil
+}
+
+// reaplicarEmSegundoPlano agenda a reaplicação do catálogo sem atrasar a resposta da
+// alteração. Se já houver uma agendada, a alteração é coberta por ela.
+func (s *CatalogoService) reaplicarEmSegundoPlano(ctx context.Context) {
+	select {
+	case s.reaplicacaoPendente <- struct{}{}:
+	default:
+		logs.Do(ctx).Debug("Reaplicação do catálogo já agendada")
+	}
+}
+
+// ExecutarReaplicacao é o worker que reaplica o catálogo aos cavaletes após as
+// alterações, uma reaplicação por vez, até o contexto ser cancelado
+func (s *CatalogoService) ExecutarReaplicacao(ctx context.Context) {
+	logs.Do(ctx).Info("Worker de reaplicação do catálogo iniciado")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logs.Do(ctx).Info("Worker de reaplicação do catálogo finalizado")
+			return
+		case <-s.reaplicacaoPendente:
+		}
+
+		resultado, err := s.Reaplicar(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				logs.Do(ctx).WithError(err).Warn("Não foi possível reaplicar o catálogo aos cavaletes")
+			}
+			continue
+		}
+		logs.Do(ctx).WithField("cavaletes_atualizados", resultado.CavaletesAtualizados).Info("Catálogo reaplicado aos cavaletes")
+	}
+}
+
+func (s *CatalogoService) aplicarTransacao(ctx context.Context, traderID, ofertaID *uuid.UUID) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return 0, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	atualizados, err := s.aplicar(ctx, tx, traderID, ofertaID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da aplicação do catálogo")
+		return 0, fmt.Errorf("erro ao aplicar catálogo")
+	}
+	return atualizados, nil
+}
+
+// aplicar associa ao catálogo os cavaletes do trader e/ou da oferta informados (todos,
+// quando ambos forem nil). O mapeamento do trader prevalece; sem ele, o material é
+// associado pela chave do nome ou dos sinônimos e a espessura pelo valor em milímetros.
+// Retorna quantos cavaletes tiveram a correspondência alterada.
+func (s *CatalogoService) aplicar(ctx context.Context, tx *sql.Tx, traderID, ofertaID *uuid.UUID) (int, error) {
+	indice, err := s.carregarIndice(ctx, tx, traderID)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT o.trader_id, c.nome_material, c.nome_espessura, c.espessura_mm
+		FROM cavaletes c
+		JOIN ofertas o ON o.id = c.oferta_id
+		WHERE ($1::uuid IS NULL OR o.trader_id = $1) AND ($2::uuid IS NULL OR c.oferta_id = $2)
+	`, traderID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar nomes dos cavaletes")
+		return 0, fmt.Errorf("erro ao aplicar catálogo")
+	}
+
+	type grupo struct {
+		traderID    uuid.UUID
+		material    string
+		espessura   string
+		espessuraMM *float64
+	}
+	var grupos []grupo
+	for rows.Next() {
+		var g grupo
+		if err := rows.Scan(&g.traderID, &g.material, &g.espessura, &g.espessuraMM); err != nil {
+			rows.Close()
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear nomes dos cavaletes")
+			return 0, fmt.Errorf("erro ao aplicar catálogo")
+		}
+		grupos = append(grupos, g)
+	}
+	rows.Close()
+
+	total := 0
+	for _, g := range grupos {
+		material, origemMaterial := indice.material(g.traderID, g.material)
+		espessura, origemEspessura := indice.espessura(g.traderID, g.espessura, g.espessuraMM)
+
+		afetadas, err := execContando(ctx, tx, `
+			UPDATE cavaletes c
+			SET material_id = $5, produto_cliente = $6, origem_material = $7,
+				espessura_id = $8, espessura_cliente = $9, origem_espessura = $10, updated_at = NOW()
+			FROM ofertas o
+			WHERE o.id = c.oferta_id AND o.trader_id = $1 AND ($2::uuid IS NULL OR c.oferta_id = $2)
+				AND c.nome_material = $3 AND c.nome_espessura = $4
+				AND c.espessura_mm IS NOT DISTINCT FROM $11
+				AND (c.material_id IS DISTINCT FROM $5 OR c.produto_cliente IS DISTINCT FROM $6
+					OR c.origem_material IS DISTINCT FROM $7 OR c.espessura_id IS DISTINCT FROM $8
+					OR c.espessura_cliente IS DISTINCT FROM $9 OR c.origem_espessura IS DISTINCT FROM $10)
+		`, g.traderID, ofertaID, g.material, g.espessura,
+			material.idOuNulo(), material.nomeOuNulo(), origemMaterial,
+			espessura.idOuNulo(), espessura.nomeOuNulo(), origemEspessura, g.espessuraMM)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao aplicar catálogo aos cavaletes")
+			return 0, fmt.Errorf("erro ao aplicar catálogo")
+		}
+		total += int(afetadas)
+	}
+
+	return total, nil
+}
+
+// carregarIndice carrega o catálogo e os mapeamentos do trader (de todos, com traderID nil)
+func (s *CatalogoService) carregarIndice(ctx context.Context, q consultor, traderID *uuid.UUID) (*indiceCatalogo, error) {
+	indice := &indiceCatalogo{
+		materiais:   map[string]itemCatalogo{},
+		espessuras:  map[float64]itemCatalogo{},
+		mapeamentos: map[chaveMapeamento]itemCatalogo{},
+	}
+
+	consultas := []struct {
+		query string
+		args  []interface{}
+		ler   func(*sql.Rows) error
+	}{
+		{`
+			SELECT m.id, m.nome, m.chave FROM catalogo_materiais m
+			UNION ALL
+			SELECT m.id, m.nome, cs.chave FROM catalogo_sinonimos cs JOIN catalogo_materiais m ON m.id = cs.material_id
+		`, nil, func(rows *sql.Rows) error {
+			var item itemCatalogo
+			var chave string
+			if err := rows.Scan(&item.id, &item.nome, &chave); err != nil {
+				return err
+			}
+			indice.materiais[chave] = item
+			return nil
+		}},
+		{`SELECT id, nome, espessura_mm FROM catalogo_espessuras`, nil, func(rows *sql.Rows) error {
+			var item itemCatalogo
+			var mm float64
+			if err := rows.Scan(&item.id, &item.nome, &mm); err != nil {
+				return err
+			}
+			indice.espessuras[mm] = item
+			return nil
+		}},
+		{`
+			SELECT mc.trader_id, mc.tipo, mc.chave, COALESCE(m.id, e.id), COALESCE(m.nome, e.nome)
+			FROM mapeamentos_catalogo mc
+			LEFT JOIN catalogo_materiais m ON m.id = mc.material_id
+			LEFT JOIN catalogo_espessuras e ON e.id = mc.espessura_id
+			WHERE $1::uuid IS NULL OR mc.trader_id = $1
+		`, []interface{}{traderID}, func(rows *sql.Rows) error {
+			var chave chaveMapeamento
+			var item itemCatalogo
+			if err := rows.Scan(&chave.traderID, &chave.tipo, &chave.chave, &item.id, &item.nome); err != nil {
+				return err
+			}
+			indice.mapeamentos[chave] = item
+			return nil
+		}},
+	}
+
+	for _, consulta := range consultas {
+		rows, err := q.QueryContext(ctx, consulta.query, consulta.args...)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao carregar catálogo")
+			return nil, fmt.Errorf("erro ao aplicar catálogo")
+		}
+		for rows.Next() {
+			if err := consulta.ler(rows); err != nil {
+				rows.Close()
+				logs.Do(ctx).WithError(err).Error("Erro ao escanear catálogo")
+				return nil, fmt.Errorf("erro ao aplicar catálogo")
+			}
+		}
+		rows.Close()
+	}
+
+	return indice, nil
+}
+
+// material resolve o material do catálogo para o nome importado pelo trader
+func (i *indiceCatalogo) material(traderID uuid.UUID, nome string) (*itemCatalogo, *string) {
+	chave := catalogo.Chave(nome)
+	if item, ok := i.mapeamentos[chaveMapeamento{traderID, models.TipoMapeamentoMaterial, chave}]; ok {
+		return &item, ptrTexto(models.OrigemCatalogoTrader)
+	}
+	if item, ok := i.materiais[chave]; ok && chave != "" {
+		return &item, ptrTexto(models.OrigemCatalogoAutomatico)
+	}
+	return nil, nil
+}
+
+// espessura resolve a espessura do catálogo para o nome importado pelo trader
+func (i *indiceCatalogo) espessura(traderID uuid.UUID, nome string, espessuraMM *float64) (*itemCatalogo, *string) {
+	if item, ok := i.mapeamentos[chaveMapeamento{traderID, models.TipoMapeamentoEspessura, catalogo.Chave(nome)}]; ok {
+		return &item, ptrTexto(models.OrigemCatalogoTrader)
+	}
+	if espessuraMM != nil {
+		if item, ok := i.espessuras[*espessuraMM]; ok {
+			return &item, ptrTexto(models.OrigemCatalogoAutomatico)
+		}
+	}
+	return nil, nil
+}
+
+func (i *itemCatalogo) idOuNulo() *uuid.UUID {
+	if i == nil {
+		return nil
+	}
+	return &i.id
+}
+
+func (i *itemCatalogo) nomeOuNulo() *string {
+	if i == nil {
+		return nil
+	}
+	return &i.nome
+}
+
+func ptrTexto(texto string) *string {
+	return &texto
+}
+
+// alterarMaterial executa a alteração do material em uma transação com auditoria e
+// reaplica o catálogo aos cavaletes
+func (s *CatalogoService) alterarMaterial(ctx context.Context, materialID uuid.UUID, acao string, alterar func(*sql.Tx) error) (*models.MaterialCatalogo, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscarMaterial(ctx, tx, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := alterar(tx); err != nil {
+		return nil, err
+	}
+
+	depois, err := s.buscarMaterial(ctx, tx, materialID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, acao, "material", materialID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do material")
+		return nil, fmt.Errorf("erro ao atualizar material")
+	}
+
+	s.reaplicarEmSegundoPlano(ctx)
+	return depois, nil
+}
+
+func (s *CatalogoService) buscarMaterial(ctx context.Context, q consultor, materialID uuid.UUID) (*models.MaterialCatalogo, error) {
+	var m models.MaterialCatalogo
+	err := q.QueryRowContext(ctx, `
+		SELECT id, nome, tipo, created_at, updated_at FROM catalogo_materiais WHERE id = $1
+	`, materialID).Scan(&m.ID, &m.Nome, &m.Tipo, &m.CreatedAt, &m.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("material não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar material do catálogo")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	materiais := []models.MaterialCatalogo{m}
+	if err := s.carregarSinonimos(ctx, q, materiais); err != nil {
+		return nil, err
+	}
+	return &materiais[0], nil
+}
+
+// carregarSinonimos preenche os sinônimos dos materiais com uma única consulta
+func (s *CatalogoService) carregarSinonimos(ctx context.Context, q consultor, materiais []models.MaterialCatalogo) error {
+	if len(materiais) == 0 {
+		return nil
+	}
+
+	indices := make(map[uuid.UUID]int, len(materiais))
+	ids := make([]string, len(materiais))
+	for i := range materiais {
+		materiais[i].Sinonimos = []models.SinonimoMaterial{}
+		indices[materiais[i].ID] = i
+		ids[i] = materiais[i].ID.String()
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT material_id, id, sinonimo
+		FROM catalogo_sinonimos
+		WHERE material_id = ANY($1::uuid[])
+		ORDER BY sinonimo
+	`, pq.Array(ids))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar sinônimos dos materiais")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var materialID uuid.UUID
+		var sinonimo models.SinonimoMaterial
+		if err := rows.Scan(&materialID, &sinonimo.ID, &sinonimo.Sinonimo); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear sinônimo do material")
+			return fmt.Errorf("erro interno do servidor")
+		}
+		if i, ok := indices[materialID]; ok {
+			materiais[i].Sinonimos = append(materiais[i].Sinonimos, sinonimo)
+		}
+	}
+
+	return rows.Err()
+}
+
+// inserirSinonimo inclui o sinônimo no material, garantindo que a chave não pertença a
+// outro material
+func (s *CatalogoService) inserirSinonimo(ctx context.Context, tx *sql.Tx, materialID uuid.UUID, sinonimo string) error {
+	sinonimo = strings.TrimSpace(sinonimo)
+	if err := s.verificarChave(ctx, tx, sinonimo, materialID); err != nil {
+		return err
+	}
+
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO catalogo_sinonimos (material_id, sinonimo, chave) VALUES ($1, $2, $3)
+		ON CONFLICT (chave) DO NOTHING
+	`, materialID, sinonimo, catalogo.Chave(sinonimo))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao incluir sinônimo")
+		return fmt.Errorf("erro ao incluir sinônimo")
+	}
+	return nil
+}
+
+// verificarChave impede que um nome ou sinônimo coincida, pela chave canônica, com o
+// nome ou um sinônimo de outro material
+func (s *CatalogoService) verificarChave(ctx context.Context, tx *sql.Tx, nome string, materialID uuid.UUID) error {
+	chave := catalogo.Chave(nome)
+	if chave == "" {
+		return fmt.Errorf("nome inválido: informe letras ou números")
+	}
+
+	var existente string
+	err := tx.QueryRowContext(ctx, `
+		SELECT m.nome FROM catalogo_materiais m
+		WHERE m.id <> $2 AND (m.chave = $1 OR EXISTS (
+			SELECT 1 FROM catalogo_sinonimos cs WHERE cs.material_id = m.id AND cs.chave = $1))
+		LIMIT 1
+	`, chave, materialID).Scan(&existente)
+	if err == sql.ErrNoRows {
+		return nil
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar nome do material")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	return fmt.Errorf("nome já cadastrado no catálogo como %s", existente)
+}
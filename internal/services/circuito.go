@@ -0,0 +1,95 @@
+package services
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"mobgran-importer-go/internal/models"
+)
+
+const (
+	// falhasParaAbrirCircuito é a quantidade de falhas seguidas da API do Mobgran que
+	// interrompe as chamadas
+	falhasParaAbrirCircuito = 5
+	// pausaCircuito é por quanto tempo o circuito fica aberto antes de liberar uma
+	// chamada de teste
+	pausaCircuito = 30 * time.Second
+)
+
+// circuito interrompe as chamadas a uma dependência depois de falhas seguidas, para
+// não acumular importações esperando o timeout de uma API fora do ar. Passada a pausa,
+// uma única chamada de teste é liberada: se der certo o circuito fecha, senão reabre.
+type circuito struct {
+	mu         sync.Mutex
+	falhas     int
+	abertoAte  time.Time
+	emTeste    bool
+	ultimoErro string
+}
+
+// permitir indica se a chamada pode ser feita; com o circuito aberto, retorna o erro
+// a ser devolvido no lugar da chamada
+func (c *circuito) permitir() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.falhas < falhasParaAbrirCircuito {
+		return nil
+	}
+	if time.Now().Before(c.abertoAte) || c.emTeste {
+		return fmt.Errorf("API do Mobgran indisponível após %d falhas seguidas; nova tentativa a partir de %s",
+			c.falhas, c.abertoAte.Format(time.RFC3339))
+	}
+	c.emTeste = true
+	return nil
+}
+
+// registrar atualiza o circuito com o resultado de uma chamada liberada
+func (c *circuito) registrar(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.emTeste = false
+	if err == nil {
+		c.falhas = 0
+		c.ultimoErro = ""
+		return
+	}
+
+	c.falhas++
+	c.ultimoErro = err.Error()
+	if c.falhas >= falhasParaAbrirCircuito {
+		c.abertoAte = time.Now().Add(pausaCircuito)
+	}
+}
+
+// liberar encerra uma chamada liberada sem resultado sobre a dependência (ex.: a
+// requisição de origem foi cancelada), sem alterar a contagem de falhas
+func (c *circuito) liberar() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.emTeste = false
+}
+
+// estado descreve o circuito para as verificações de saúde
+func (c *circuito) estado() models.EstadoCircuito {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	estado := models.EstadoCircuito{
+		Estado:             models.CircuitoFechado,
+		FalhasConsecutivas: c.falhas,
+		UltimoErro:         c.ultimoErro,
+	}
+	if c.falhas >= falhasParaAbrirCircuito {
+		abertoAte := c.abertoAte
+		estado.AbertoAte = &abertoAte
+		estado.Estado = models.CircuitoAberto
+		if !time.Now().Before(c.abertoAte) {
+			estado.Estado = models.CircuitoSemiAberto
+		}
+	}
+	return estado
+}
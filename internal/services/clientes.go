@@ -0,0 +1,603 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+	"mobgran-importer-go/pkg/documento"
+)
+
+// limiteHistoricoCliente limita os orçamentos e produtos exibidos no histórico do cliente
+const limiteHistoricoCliente = 50
+
+// colunasCliente são as colunas lidas por escanearCliente, na mesma ordem
+const colunasCliente = `c.id, c.trader_id, c.nome, c.documento, c.tipo_documento, c.email, c.telefone,
+			   c.cep, c.logradouro, c.numero, c.complemento, c.bairro, c.cidade, c.uf,
+			   c.observacoes, c.created_at, c.updated_at`
+
+// escanearCliente lê uma linha com as colunasCliente
+func escanearCliente(row interface{ Scan(...interface{}) error }, c *models.Cliente) error {
+	e := &c.Endereco
+	return row.Scan(
+		&c.ID, &c.TraderID, &c.Nome, &c.Documento, &c.TipoDocumento, &c.Email, &c.Telefone,
+		&e.CEP, &e.Logradouro, &e.Numero, &e.Complemento, &e.Bairro, &e.Cidade, &e.UF,
+		&c.Observacoes, &c.CreatedAt, &c.UpdatedAt,
+	)
+}
+
+// ClientesService gerencia o cadastro de clientes (compradores) dos traders
+type ClientesService struct {
+	db         *sql.DB
+	auditoria  *AuditoriaService
+	orcamentos *OrcamentosService
+}
+
+// NewClientesService cria uma nova instância do ClientesService
+func NewClientesService(db *sql.DB, auditoria *AuditoriaService, orcamentos *OrcamentosService) *ClientesService {
+	return &ClientesService{db: db, auditoria: auditoria, orcamentos: orcamentos}
+}
+
+// Criar cadastra um cliente com seus contatos
+func (s *ClientesService) Criar(ctx context.Context, traderID uuid.UUID, request *models.ClienteCriarRequest) (*models.Cliente, error) {
+	tipo, doc, err := normalizarDocumento(request.Documento)
+	if err != nil {
+		return nil, err
+	}
+	if err := validarEmail(request.Email); err != nil {
+		return nil, err
+	}
+	endereco := models.EnderecoCliente{}
+	if request.Endereco != nil {
+		endereco = *request.Endereco
+	}
+	if err := normalizarEndereco(&endereco); err != nil {
+		return nil, err
+	}
+	contatos, err := normalizarContatos(request.Contatos)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarDocumento(ctx, tx, traderID, doc, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	var cliente models.Cliente
+	err = escanearCliente(tx.QueryRowContext(ctx, `
+		INSERT INTO clientes AS c (
+			trader_id, nome, documento, tipo_documento, email, telefone,
+			cep, logradouro, numero, complemento, bairro, cidade, uf, observacoes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		RETURNING `+colunasCliente,
+		traderID, strings.TrimSpace(request.Nome), doc, tipo, textoOuNulo(request.Email), textoOuNulo(request.Telefone),
+		endereco.CEP, endereco.Logradouro, endereco.Numero, endereco.Complemento,
+		endereco.Bairro, endereco.Cidade, endereco.UF, textoOuNulo(request.Observacoes)), &cliente)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao cadastrar cliente")
+		return nil, fmt.Errorf("erro ao cadastrar cliente")
+	}
+
+	if cliente.Contatos, err = s.gravarContatos(ctx, tx, cliente.ID, contatos); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoClienteCriado, "cliente", cliente.ID.String(), nil, cliente); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do cliente")
+		return nil, fmt.Errorf("erro ao cadastrar cliente")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":    traderID,
+		"cliente_id": cliente.ID,
+	}).Info("Cliente cadastrado com sucesso")
+
+	return &cliente, nil
+}
+
+// Listar lista os clientes do trader. A busca procura no nome (sem acentos), no
+// documento e no e-mail.
+func (s *ClientesService) Listar(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroClientes) ([]models.Cliente, *paginacao.Pagina, error) {
+	conditions := []string{"c.trader_id = $1"}
+	args := []interface{}{traderID}
+
+	if busca := strings.TrimSpace(filtro.Busca); busca != "" {
+		args = append(args, "%"+busca+"%")
+		condicao := fmt.Sprintf("(unaccent(c.nome) ILIKE unaccent($%d) OR c.email ILIKE $%d", len(args), len(args))
+		if digitos := documento.ApenasDigitos(busca); len(digitos) >= 3 {
+			args = append(args, digitos+"%")
+			condicao += fmt.Sprintf(" OR c.documento LIKE $%d", len(args))
+		}
+		conditions = append(conditions, condicao+")")
+	}
+
+	params := &filtro.Paginacao
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM clientes c WHERE "+strings.Join(conditions, " AND "), args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar clientes")
+			return nil, nil, fmt.Errorf("erro ao buscar clientes")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("c.created_at", "c.id", len(args)+1)
+	if condicao != "" {
+		conditions = append(conditions, condicao)
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM clientes c
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, colunasCliente, strings.Join(conditions, " AND "), orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar clientes")
+		return nil, nil, fmt.Errorf("erro ao buscar clientes")
+	}
+	defer rows.Close()
+
+	clientes := []models.Cliente{}
+	for rows.Next() {
+		var c models.Cliente
+		if err := escanearCliente(rows, &c); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear cliente")
+			continue
+		}
+		clientes = append(clientes, c)
+	}
+
+	clientes, pagina := paginacao.Montar(clientes, params, func(c models.Cliente) (time.Time, uuid.UUID) {
+		return c.CreatedAt, c.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	if err := s.carregarContatos(ctx, s.db, clientes); err != nil {
+		return nil, nil, err
+	}
+
+	return clientes, pagina, nil
+}
+
+// Buscar retorna o cliente com seus contatos
+func (s *ClientesService) Buscar(ctx context.Context, traderID, clienteID uuid.UUID) (*models.Cliente, error) {
+	return s.buscar(ctx, s.db, traderID, clienteID, false)
+}
+
+// Atualizar altera o cadastro do cliente
+func (s *ClientesService) Atualizar(ctx context.Context, traderID, clienteID uuid.UUID, request *models.ClienteAtualizarRequest) (*models.Cliente, error) {
+	setParts := []string{}
+	args := []interface{}{}
+
+	adicionar := func(coluna string, valor interface{}) {
+		args = append(args, valor)
+		setParts = append(setParts, fmt.Sprintf("%s = $%d", coluna, len(args)))
+	}
+
+	if request.Nome != nil {
+		adicionar("nome", strings.TrimSpace(*request.Nome))
+	}
+	var doc *string
+	if request.Documento != nil {
+		tipo, normalizado, err := normalizarDocumento(request.Documento)
+		if err != nil {
+			return nil, err
+		}
+		doc = normalizado
+		adicionar("documento", normalizado)
+		adicionar("tipo_documento", tipo)
+	}
+	if request.Email != nil {
+		if err := validarEmail(request.Email); err != nil {
+			return nil, err
+		}
+		adicionar("email", textoOuNulo(request.Email))
+	}
+	if request.Telefone != nil {
+		adicionar("telefone", textoOuNulo(request.Telefone))
+	}
+	if request.Endereco != nil {
+		endereco := *request.Endereco
+		if err := normalizarEndereco(&endereco); err != nil {
+			return nil, err
+		}
+		adicionar("cep", endereco.CEP)
+		adicionar("logradouro", endereco.Logradouro)
+		adicionar("numero", endereco.Numero)
+		adicionar("complemento", endereco.Complemento)
+		adicionar("bairro", endereco.Bairro)
+		adicionar("cidade", endereco.Cidade)
+		adicionar("uf", endereco.UF)
+	}
+	if request.Observacoes != nil {
+		adicionar("observacoes", textoOuNulo(request.Observacoes))
+	}
+
+	var contatos []models.ContatoClienteRequest
+	if request.Contatos != nil {
+		var err error
+		if contatos, err = normalizarContatos(*request.Contatos); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(setParts) == 0 && request.Contatos == nil {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, traderID, clienteID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.verificarDocumento(ctx, tx, traderID, doc, clienteID); err != nil {
+		return nil, err
+	}
+
+	args = append(args, clienteID)
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE clientes SET %s WHERE id = $%d
+	`, strings.Join(append(setParts, "updated_at = NOW()"), ", "), len(args)), args...); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar cliente")
+		return nil, fmt.Errorf("erro ao atualizar cliente")
+	}
+
+	if request.Contatos != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM cliente_contatos WHERE cliente_id = $1`, clienteID); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao substituir contatos do cliente")
+			return nil, fmt.Errorf("erro ao atualizar cliente")
+		}
+		if _, err := s.gravarContatos(ctx, tx, clienteID, contatos); err != nil {
+			return nil, err
+		}
+	}
+
+	depois, err := s.buscar(ctx, tx, traderID, clienteID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoClienteAtualizado, "cliente", clienteID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do cliente")
+		return nil, fmt.Errorf("erro ao atualizar cliente")
+	}
+
+	return depois, nil
+}
+
+// Remover exclui o cadastro do cliente. Reservas, vendas e orçamentos vinculados
+// perdem o vínculo, mas mantêm o nome do cliente.
+func (s *ClientesService) Remover(ctx context.Context, traderID, clienteID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, traderID, clienteID, true)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM clientes WHERE id = $1`, clienteID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover cliente")
+		return fmt.Errorf("erro ao remover cliente")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoClienteRemovido, "cliente", clienteID.String(), antes, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção do cliente")
+		return fmt.Errorf("erro ao remover cliente")
+	}
+
+	return nil
+}
+
+// Historico retorna o resumo do relacionamento com o cliente e seus orçamentos,
+// reservas e compras mais recentes
+func (s *ClientesService) Historico(ctx context.Context, traderID, clienteID uuid.UUID) (*models.HistoricoCliente, error) {
+	cliente, err := s.Buscar(ctx, traderID, clienteID)
+	if err != nil {
+		return nil, err
+	}
+
+	historico := &models.HistoricoCliente{Cliente: cliente}
+	resumo := &historico.Resumo
+	err = s.db.QueryRowContext(ctx, `
+		SELECT
+			(SELECT COUNT(*) FROM orcamentos WHERE cliente_id = $1 AND trader_id = $2),
+			(SELECT COUNT(*) FROM orcamentos WHERE cliente_id = $1 AND trader_id = $2 AND status = 'aceito'),
+			COUNT(*) FILTER (WHERE pa.status = 'reservado'),
+			COUNT(*) FILTER (WHERE pa.status = 'vendido'),
+			COALESCE(SUM(pa.valor_venda) FILTER (WHERE pa.status = 'vendido'), 0)
+		FROM produtos_aprovados pa
+		WHERE pa.cliente_id = $1 AND pa.trader_id = $2 AND pa.deleted_at IS NULL
+	`, clienteID, traderID).Scan(
+		&resumo.Orcamentos, &resumo.OrcamentosAceitos,
+		&resumo.ProdutosReservados, &resumo.ProdutosComprados, &resumo.ValorComprado,
+	)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao resumir histórico do cliente")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	historico.Orcamentos, _, err = s.orcamentos.Listar(ctx, traderID, &models.FiltroOrcamentos{
+		ClienteID: &clienteID,
+		Paginacao: paginacao.Parametros{Limit: limiteHistoricoCliente},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+colunasProduto+`
+		FROM produtos_aprovados
+		WHERE cliente_id = $1 AND trader_id = $2 AND deleted_at IS NULL
+		ORDER BY COALESCE(vendido_em, reservado_em, updated_at) DESC, id DESC
+		LIMIT $3
+	`, clienteID, traderID, limiteHistoricoCliente)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produtos do cliente")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer rows.Close()
+
+	historico.Produtos = []models.ProdutoAprovado{}
+	for rows.Next() {
+		var p models.ProdutoAprovado
+		if err := escanearProduto(rows, &p); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear produto do cliente")
+			continue
+		}
+		historico.Produtos = append(historico.Produtos, p)
+	}
+
+	return historico, nil
+}
+
+// buscar carrega o cliente do trader com seus contatos. Com bloquear, a linha fica
+// travada até o fim da transação.
+func (s *ClientesService) buscar(ctx context.Context, q consultor, traderID, clienteID uuid.UUID, bloquear bool) (*models.Cliente, error) {
+	query := `SELECT ` + colunasCliente + ` FROM clientes c WHERE c.id = $1 AND c.trader_id = $2`
+	if bloquear {
+		query += " FOR UPDATE"
+	}
+
+	var cliente models.Cliente
+	err := escanearCliente(q.QueryRowContext(ctx, query, clienteID, traderID), &cliente)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("cliente não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar cliente")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	clientes := []models.Cliente{cliente}
+	if err := s.carregarContatos(ctx, q, clientes); err != nil {
+		return nil, err
+	}
+
+	return &clientes[0], nil
+}
+
+// carregarContatos preenche os contatos dos clientes com uma única consulta
+func (s *ClientesService) carregarContatos(ctx context.Context, q consultor, clientes []models.Cliente) error {
+	if len(clientes) == 0 {
+		return nil
+	}
+
+	indices := make(map[uuid.UUID]int, len(clientes))
+	ids := make([]string, len(clientes))
+	for i := range clientes {
+		clientes[i].Contatos = []models.ContatoCliente{}
+		indices[clientes[i].ID] = i
+		ids[i] = clientes[i].ID.String()
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT cliente_id, id, nome, cargo, email, telefone, principal
+		FROM cliente_contatos
+		WHERE cliente_id = ANY($1::uuid[])
+		ORDER BY principal DESC, created_at, id
+	`, pq.Array(ids))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar contatos dos clientes")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clienteID uuid.UUID
+		var contato models.ContatoCliente
+		if err := rows.Scan(&clienteID, &contato.ID, &contato.Nome, &contato.Cargo, &contato.Email, &contato.Telefone, &contato.Principal); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear contato do cliente")
+			return fmt.Errorf("erro interno do servidor")
+		}
+		if i, ok := indices[clienteID]; ok {
+			clientes[i].Contatos = append(clientes[i].Contatos, contato)
+		}
+	}
+
+	return rows.Err()
+}
+
+// gravarContatos insere os contatos do cliente na transação
+func (s *ClientesService) gravarContatos(ctx context.Context, tx *sql.Tx, clienteID uuid.UUID, contatos []models.ContatoClienteRequest) ([]models.ContatoCliente, error) {
+	gravados := make([]models.ContatoCliente, 0, len(contatos))
+	for _, c := range contatos {
+		var contato models.ContatoCliente
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO cliente_contatos (cliente_id, nome, cargo, email, telefone, principal)
+			VALUES ($1, $2, NULLIF($3, ''), NULLIF($4, ''), NULLIF($5, ''), $6)
+			RETURNING id, nome, cargo, email, telefone, principal
+		`, clienteID, c.Nome, c.Cargo, c.Email, c.Telefone, c.Principal).Scan(
+			&contato.ID, &contato.Nome, &contato.Cargo, &contato.Email, &contato.Telefone, &contato.Principal,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao gravar contato do cliente")
+			return nil, fmt.Errorf("erro ao gravar contatos do cliente")
+		}
+		gravados = append(gravados, contato)
+	}
+	return gravados, nil
+}
+
+// verificarDocumento garante que o documento não pertence a outro cliente do trader
+func (s *ClientesService) verificarDocumento(ctx context.Context, tx *sql.Tx, traderID uuid.UUID, doc *string, clienteID uuid.UUID) error {
+	if doc == nil {
+		return nil
+	}
+
+	var emUso bool
+	err := tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM clientes WHERE trader_id = $1 AND documento = $2 AND id <> $3)
+	`, traderID, *doc, clienteID).Scan(&emUso)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar documento do cliente")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	if emUso {
+		return fmt.Errorf("já existe um cliente com este documento")
+	}
+	return nil
+}
+
+// nomeDoCliente retorna o nome de um cliente do trader, usado para preencher o nome
+// gravado em reservas, vendas e orçamentos vinculados ao cadastro
+func nomeDoCliente(ctx context.Context, q consultor, traderID, clienteID uuid.UUID) (string, error) {
+	var nome string
+	err := q.QueryRowContext(ctx, `
+		SELECT nome FROM clientes WHERE id = $1 AND trader_id = $2
+	`, clienteID, traderID).Scan(&nome)
+	if err == sql.ErrNoRows {
+		return "", fmt.Errorf("cliente não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar cliente")
+		return "", fmt.Errorf("erro interno do servidor")
+	}
+	return nome, nil
+}
+
+// normalizarDocumento valida o CPF/CNPJ informado. Documento ausente ou vazio resulta
+// em nulo.
+func normalizarDocumento(doc *string) (*string, *string, error) {
+	if doc == nil || strings.TrimSpace(*doc) == "" {
+		return nil, nil, nil
+	}
+
+	tipo, digitos, err := documento.Normalizar(*doc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("documento inválido: %s", err.Error())
+	}
+	return &tipo, &digitos, nil
+}
+
+// normalizarEndereco padroniza CEP (apenas dígitos) e UF (maiúsculas) e converte
+// campos vazios em nulos
+func normalizarEndereco(e *models.EnderecoCliente) error {
+	for _, campo := range []**string{&e.CEP, &e.Logradouro, &e.Numero, &e.Complemento, &e.Bairro, &e.Cidade, &e.UF} {
+		*campo = textoOuNulo(*campo)
+	}
+
+	if e.CEP != nil {
+		cep := documento.ApenasDigitos(*e.CEP)
+		if len(cep) != 8 {
+			return fmt.Errorf("endereço inválido: CEP deve ter 8 dígitos")
+		}
+		e.CEP = &cep
+	}
+	if e.UF != nil {
+		uf := strings.ToUpper(*e.UF)
+		e.UF = &uf
+	}
+	return nil
+}
+
+// normalizarContatos garante no máximo um contato principal; sem nenhum marcado, o
+// primeiro assume
+func normalizarContatos(contatos []models.ContatoClienteRequest) ([]models.ContatoClienteRequest, error) {
+	principais := 0
+	for _, c := range contatos {
+		if c.Principal {
+			principais++
+		}
+	}
+	if principais > 1 {
+		return nil, fmt.Errorf("contatos inválidos: apenas um contato pode ser principal")
+	}
+
+	normalizados := append([]models.ContatoClienteRequest(nil), contatos...)
+	if principais == 0 && len(normalizados) > 0 {
+		normalizados[0].Principal = true
+	}
+	return normalizados, nil
+}
+
+// validarEmail verifica o formato do e-mail, quando informado
+func validarEmail(email *string) error {
+	if email == nil || strings.TrimSpace(*email) == "" {
+		return nil
+	}
+	if _, err := mail.ParseAddress(strings.TrimSpace(*email)); err != nil {
+		return fmt.Errorf("e-mail inválido")
+	}
+	return nil
+}
+
+// textoOuNulo remove espaços das pontas e converte texto vazio em nulo
+func textoOuNulo(texto *string) *string {
+	if texto == nil {
+		return nil
+	}
+	limpo := strings.TrimSpace(*texto)
+	if limpo == "" {
+		return nil
+	}
+	return &limpo
+}
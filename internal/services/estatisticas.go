@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+)
+
+// unidadesAgrupamento traduz o agrupamento do painel para a unidade do date_trunc
+var unidadesAgrupamento = map[string]string{
+	models.AgrupamentoDia:    "day",
+	models.AgrupamentoSemana: "week",
+	models.AgrupamentoMes:    "month",
+}
+
+// EstatisticasService monta o painel do trader, sempre restrito às ofertas e aos
+// produtos do próprio trader
+type EstatisticasService struct {
+	db *sql.DB
+}
+
+// NewEstatisticasService cria uma nova instância do EstatisticasService
+func NewEstatisticasService(db *sql.DB) *EstatisticasService {
+	return &EstatisticasService{db: db}
+}
+
+// noPeriodo restringe a coluna ao período do filtro ($2 e $3, ignorados quando nulos)
+func noPeriodo(coluna string) string {
+	return fmt.Sprintf("($2::timestamptz IS NULL OR %[1]s >= $2) AND ($3::timestamptz IS NULL OR %[1]s <= $3)", coluna)
+}
+
+// Obter calcula o painel do trader no período do filtro
+func (s *EstatisticasService) Obter(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroEstatisticas) (*models.EstatisticasTrader, error) {
+	if filtro.Agrupamento == "" {
+		filtro.Agrupamento = models.AgrupamentoMes
+	}
+	unidade, ok := unidadesAgrupamento[filtro.Agrupamento]
+	if !ok {
+		return nil, fmt.Errorf("agrupamento inválido: use dia, semana ou mes")
+	}
+	if filtro.Desde != nil && filtro.Ate != nil && filtro.Desde.After(*filtro.Ate) {
+		return nil, fmt.Errorf("período inválido: desde posterior a ate")
+	}
+
+	stats := &models.EstatisticasTrader{
+		Desde:       filtro.Desde,
+		Ate:         filtro.Ate,
+		Agrupamento: filtro.Agrupamento,
+	}
+	args := []interface{}{traderID, filtro.Desde, filtro.Ate}
+
+	if err := s.resumoProdutos(ctx, args, stats); err != nil {
+		return nil, err
+	}
+	if err := s.resumoEstoque(ctx, args, stats); err != nil {
+		return nil, err
+	}
+
+	var err error
+	stats.PorMaterial, err = s.grupos(ctx, args,
+		"c.material_id", "COALESCE(cm.nome, c.produto_cliente, c.nome_material)",
+		"LEFT JOIN catalogo_materiais cm ON cm.id = c.material_id")
+	if err != nil {
+		return nil, err
+	}
+	stats.PorFornecedor, err = s.grupos(ctx, args,
+		"o.fornecedor_id", "COALESCE(f.nome, 'Sem fornecedor vinculado')",
+		"LEFT JOIN fornecedores f ON f.id = o.fornecedor_id")
+	if err != nil {
+		return nil, err
+	}
+
+	stats.Importacoes, err = s.serieImportacoes(ctx, append(args, unidade))
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// resumoProdutos conta os produtos aprovados no período e soma a metragem e os valores
+// pelo preço de venda por m²; as vendas consideram a data da venda
+func (s *EstatisticasService) resumoProdutos(ctx context.Context, args []interface{}, stats *models.EstatisticasTrader) error {
+	query := fmt.Sprintf(`
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE pa.visivel),
+			COUNT(*) FILTER (WHERE pa.destaque),
+			COUNT(*) FILTER (WHERE pa.status = 'disponivel'),
+			COUNT(*) FILTER (WHERE pa.status = 'reservado'),
+			COUNT(*) FILTER (WHERE pa.status = 'vendido'),
+			COALESCE(ROUND(SUM(COALESCE(c.metragem_m2, c.metragem)), 3), 0),
+			COALESCE(ROUND(SUM(pa.preco_venda * COALESCE(c.metragem_m2, c.metragem)) FILTER (WHERE pa.status = 'disponivel'), 2), 0),
+			COALESCE(ROUND(SUM(pa.preco_venda * COALESCE(c.metragem_m2, c.metragem)) FILTER (WHERE pa.status = 'reservado'), 2), 0),
+			COUNT(*) FILTER (WHERE COALESCE(c.metragem_m2, c.metragem) IS NULL AND pa.status <> 'vendido')
+		FROM produtos_aprovados pa
+		JOIN cavaletes c ON c.id = pa.cavalete_id
+		WHERE pa.trader_id = $1 AND pa.deleted_at IS NULL AND %s
+	`, noPeriodo("pa.created_at"))
+
+	p := &stats.Produtos
+	e := &stats.Estoque
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(
+		&p.Total, &p.Visiveis, &p.Destaque, &p.Disponiveis, &p.Reservados, &p.Vendidos,
+		&e.MetragemAprovadaM2, &e.ValorEstoque, &e.ValorReservado, &e.ProdutosSemMetragem,
+	)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao calcular estatísticas dos produtos")
+		return fmt.Errorf("erro ao buscar estatísticas")
+	}
+
+	query = fmt.Sprintf(`
+		SELECT COALESCE(SUM(pa.valor_venda), 0)
+		FROM produtos_aprovados pa
+		WHERE pa.trader_id = $1 AND pa.deleted_at IS NULL AND pa.status = 'vendido' AND %s
+	`, noPeriodo("pa.vendido_em"))
+	if err := s.db.QueryRowContext(ctx, query, args...).Scan(&e.ValorVendido); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao calcular valor vendido")
+		return fmt.Errorf("erro ao buscar estatísticas")
+	}
+
+	return nil
+}
+
+// resumoEstoque totaliza os cavaletes das ofertas do trader cuja última importação
+// caiu no período. Disponíveis são os de ofertas ativas ainda não aprovados pelo trader.
+func (s *EstatisticasService) resumoEstoque(ctx context.Context, args []interface{}, stats *models.EstatisticasTrader) error {
+	query := fmt.Sprintf(`
+		SELECT COUNT(DISTINCT o.id),
+			COUNT(DISTINCT o.id) FILTER (WHERE o.situacao = 'ativa'),
+			COUNT(c.id),
+			COUNT(c.id) FILTER (WHERE o.situacao = 'ativa' AND NOT EXISTS (
+				SELECT 1 FROM produtos_aprovados pa
+				WHERE pa.cavalete_id = c.id AND pa.trader_id = $1 AND pa.deleted_at IS NULL
+			)),
+			COALESCE(ROUND(SUM(COALESCE(c.metragem_m2, c.metragem)), 3), 0)
+		FROM ofertas o
+		JOIN cavaletes c ON c.oferta_id = o.id AND c.deleted_at IS NULL
+		WHERE o.trader_id = $1 AND o.deleted_at IS NULL AND %s
+	`, noPeriodo("c.created_at"))
+
+	e := &stats.Estoque
+	err := s.db.QueryRowContext(ctx, query, args...).Scan(
+		&e.Ofertas, &e.OfertasAtivas, &e.CavaletesImportados, &e.CavaletesDisponiveis, &e.MetragemImportadaM2,
+	)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao calcular estatísticas do estoque")
+		return fmt.Errorf("erro ao buscar estatísticas")
+	}
+
+	if e.MetragemImportadaM2 > 0 {
+		e.PercentualAprovado = math.Round(e.MetragemAprovadaM2/e.MetragemImportadaM2*10000) / 100
+	}
+
+	return nil
+}
+
+// grupos distribui o estoque importado e o aprovado no período pela chave informada
+// (material ou fornecedor), do grupo com mais metragem importada para o com menos
+func (s *EstatisticasService) grupos(ctx context.Context, args []interface{}, colunaID, colunaNome, juncao string) ([]models.EstatisticaGrupo, error) {
+	query := fmt.Sprintf(`
+		SELECT g.grupo_id, g.nome,
+			COUNT(*) FILTER (WHERE g.importado),
+			COALESCE(ROUND(SUM(g.metragem) FILTER (WHERE g.importado), 3), 0),
+			COUNT(*) FILTER (WHERE NOT g.importado),
+			COALESCE(ROUND(SUM(g.metragem) FILTER (WHERE NOT g.importado), 3), 0),
+			COALESCE(ROUND(SUM(g.preco * g.metragem) FILTER (WHERE NOT g.importado AND g.status = 'disponivel'), 2), 0)
+		FROM (
+			SELECT %[1]s AS grupo_id, %[2]s AS nome, true AS importado,
+				COALESCE(c.metragem_m2, c.metragem) AS metragem, NULL::numeric AS preco, NULL::text AS status
+			FROM cavaletes c
+			JOIN ofertas o ON o.id = c.oferta_id
+			%[3]s
+			WHERE o.trader_id = $1 AND o.deleted_at IS NULL AND c.deleted_at IS NULL AND %[4]s
+			UNION ALL
+			SELECT %[1]s, %[2]s, false,
+				COALESCE(c.metragem_m2, c.metragem), pa.preco_venda, pa.status::text
+			FROM produtos_aprovados pa
+			JOIN cavaletes c ON c.id = pa.cavalete_id
+			JOIN ofertas o ON o.id = c.oferta_id
+			%[3]s
+			WHERE pa.trader_id = $1 AND pa.deleted_at IS NULL AND %[5]s
+		) g
+		GROUP BY g.grupo_id, g.nome
+		ORDER BY 4 DESC, 6 DESC, g.nome
+	`, colunaID, colunaNome, juncao, noPeriodo("c.created_at"), noPeriodo("pa.created_at"))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao agrupar estatísticas")
+		return nil, fmt.Errorf("erro ao buscar estatísticas")
+	}
+	defer rows.Close()
+
+	grupos := []models.EstatisticaGrupo{}
+	for rows.Next() {
+		var g models.EstatisticaGrupo
+		if err := rows.Scan(&g.ID, &g.Nome, &g.Cavaletes, &g.MetragemImportadaM2,
+			&g.Produtos, &g.MetragemAprovadaM2, &g.ValorEstoque); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear grupo das estatísticas")
+			continue
+		}
+		grupos = append(grupos, g)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iterar grupos das estatísticas")
+		return nil, fmt.Errorf("erro ao buscar estatísticas")
+	}
+
+	return grupos, nil
+}
+
+// serieImportacoes conta as importações das ofertas do trader por dia, semana ou mês
+// ($4), em ordem cronológica
+func (s *EstatisticasService) serieImportacoes(ctx context.Context, args []interface{}) ([]models.PontoSerieImportacao, error) {
+	query := fmt.Sprintf(`
+		SELECT date_trunc($4, io.created_at) AS periodo,
+			COUNT(*), COUNT(DISTINCT io.oferta_id),
+			COALESCE(SUM(io.cavaletes), 0), COALESCE(ROUND(SUM(io.metragem_m2), 3), 0)
+		FROM importacoes_oferta io
+		JOIN ofertas o ON o.id = io.oferta_id
+		WHERE o.trader_id = $1 AND o.deleted_at IS NULL AND %s
+		GROUP BY periodo
+		ORDER BY periodo
+	`, noPeriodo("io.created_at"))
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar série de importações")
+		return nil, fmt.Errorf("erro ao buscar estatísticas")
+	}
+	defer rows.Close()
+
+	serie := []models.PontoSerieImportacao{}
+	for rows.Next() {
+		var p models.PontoSerieImportacao
+		if err := rows.Scan(&p.Periodo, &p.Importacoes, &p.Ofertas, &p.Cavaletes, &p.MetragemM2); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear série de importações")
+			continue
+		}
+		serie = append(serie, p)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iterar série de importações")
+		return nil, fmt.Errorf("erro ao buscar estatísticas")
+	}
+
+	return serie, nil
+}
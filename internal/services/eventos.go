@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+)
+
+// canalEventos é o canal do LISTEN/NOTIFY avisado a cada evento gravado (migration 022)
+const canalEventos = "eventos"
+
+// EventosService grava os eventos de domínio na fila de eventos (outbox), na mesma
+// transação das alterações, repassa os eventos assináveis às notificações e avisa os
+// streams SSE abertos nesta instância quando há eventos novos do trader
+type EventosService struct {
+	db           *sql.DB
+	notificacoes *NotificacoesService
+
+	mu       sync.Mutex
+	ouvintes map[uuid.UUID]map[chan struct{}]struct{}
+
+	encerrar   sync.Once
+	encerrando chan struct{}
+}
+
+// NewEventosService cria uma nova instância do EventosService. Com notificacoes, os
+// eventos listados em models.EventosNotificacao também são enfileirados para as
+// assinaturas do trader.
+func NewEventosService(db *sql.DB, notificacoes *NotificacoesService) *EventosService {
+	return &EventosService{
+		db:           db,
+		notificacoes: notificacoes,
+		ouvintes:     map[uuid.UUID]map[chan struct{}]struct{}{},
+		encerrando:   make(chan struct{}),
+	}
+}
+
+// Registrar grava o evento do trader. Com exec sendo uma transação, o evento (e as
+// notificações geradas por ele) só existe se a alteração for confirmada. Sem o
+// serviço de eventos, não faz nada.
+func (s *EventosService) Registrar(ctx context.Context, exec executor, traderID uuid.UUID, tipo, entidade, entidadeID string, dados interface{}) error {
+	if s == nil {
+		return nil
+	}
+	if exec == nil {
+		exec = s.db
+	}
+
+	corpo, err := json.Marshal(dados)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("tipo", tipo).Error("Erro ao serializar evento")
+		return fmt.Errorf("erro ao registrar evento")
+	}
+
+	_, err = exec.ExecContext(ctx, `
+		INSERT INTO eventos (trader_id, tipo, entidade, entidade_id, dados)
+		VALUES ($1, $2, $3, $4, $5)
+	`, traderID, tipo, entidade, nullString(entidadeID), string(corpo))
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("tipo", tipo).Error("Erro ao gravar evento")
+		return fmt.Errorf("erro ao registrar evento")
+	}
+
+	if _, assinavel := models.EventosNotificacao[tipo]; assinavel && s.notificacoes != nil {
+		return s.notificacoes.Publicar(ctx, exec, traderID, tipo, "", dados)
+	}
+	return nil
+}
+
+// registrarProduto grava um evento produto.* com o estado do produto e a ação que o
+// originou (a mesma registrada na auditoria)
+func (s *EventosService) registrarProduto(ctx context.Context, exec executor, tipo, acao string, produto *models.ProdutoAprovado) error {
+	dados := map[string]interface{}{
+		"produto_id": produto.ID,
+		"acao":       acao,
+		"produto":    produto,
+	}
+	return s.Registrar(ctx, exec, produto.TraderID, tipo, "produto", produto.ID.String(), dados)
+}
+
+// registrarProdutos grava o evento para cada produto informado, lendo o estado atual
+// dos produtos (inclusive arquivados) na transação da alteração em lote
+func (s *EventosService) registrarProdutos(ctx context.Context, tx *sql.Tx, tipo, acao string, produtoIDs []string) error {
+	if s == nil || len(produtoIDs) == 0 {
+		return nil
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT `+colunasProduto+`
+		FROM produtos_aprovados
+		WHERE id = ANY($1::uuid[])
+	`, pq.Array(produtoIDs))
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("tipo", tipo).Error("Erro ao buscar produtos do evento")
+		return fmt.Errorf("erro ao registrar evento")
+	}
+
+	produtos := []models.ProdutoAprovado{}
+	for rows.Next() {
+		var p models.ProdutoAprovado
+		if err := escanearProduto(rows, &p); err != nil {
+			rows.Close()
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear produto do evento")
+			return fmt.Errorf("erro ao registrar evento")
+		}
+		produtos = append(produtos, p)
+	}
+	rows.Close()
+
+	for i := range produtos {
+		if err := s.registrarProduto(ctx, tx, tipo, acao, &produtos[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// eventosConfirmados restringe a leitura aos eventos de transações já encerradas: um
+// evento de id menor cuja transação confirme depois de outra não é pulado pelo cursor
+const eventosConfirmados = `transacao < pg_snapshot_xmin(pg_current_snapshot())`
+
+// Listar retorna os eventos do trader posteriores ao id informado, em ordem
+func (s *EventosService) Listar(ctx context.Context, traderID uuid.UUID, aposID int64, limite int) ([]models.Evento, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, tipo, entidade, entidade_id, dados, created_at
+		FROM eventos
+		WHERE trader_id = $1 AND id > $2 AND `+eventosConfirmados+`
+		ORDER BY id
+		LIMIT $3
+	`, traderID, aposID, limite)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar eventos")
+		return nil, fmt.Errorf("erro ao buscar eventos")
+	}
+	defer rows.Close()
+
+	eventos := []models.Evento{}
+	for rows.Next() {
+		var e models.Evento
+		if err := rows.Scan(&e.ID, &e.Tipo, &e.Entidade, &e.EntidadeID, &e.Dados, &e.CreatedAt); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear evento")
+			return nil, fmt.Errorf("erro ao buscar eventos")
+		}
+		eventos = append(eventos, e)
+	}
+
+	return eventos, rows.Err()
+}
+
+// UltimoID retorna o id do último evento confirmado do trader, ponto de partida de um
+// stream sem cursor
+func (s *EventosService) UltimoID(ctx context.Context, traderID uuid.UUID) (int64, error) {
+	var id int64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COALESCE(MAX(id), 0) FROM eventos WHERE trader_id = $1 AND `+eventosConfirmados,
+		traderID).Scan(&id)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar último evento")
+		return 0, fmt.Errorf("erro ao buscar eventos")
+	}
+	return id, nil
+}
+
+// Ouvir registra um stream do trader. O canal recebe um sinal quando há eventos novos;
+// a função retornada encerra o registro.
+func (s *EventosService) Ouvir(traderID uuid.UUID) (<-chan struct{}, func()) {
+	sinal := make(chan struct{}, 1)
+
+	s.mu.Lock()
+	if s.ouvintes[traderID] == nil {
+		s.ouvintes[traderID] = map[chan struct{}]struct{}{}
+	}
+	s.ouvintes[traderID][sinal] = struct{}{}
+	s.mu.Unlock()
+
+	return sinal, func() {
+		s.mu.Lock()
+		delete(s.ouvintes[traderID], sinal)
+		if len(s.ouvintes[traderID]) == 0 {
+			delete(s.ouvintes, traderID)
+		}
+		s.mu.Unlock()
+	}
+}
+
+// avisar sinaliza os streams do trader ou, com traderID nil, todos os streams
+func (s *EventosService) avisar(traderID *uuid.UUID) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for trader, sinais := range s.ouvintes {
+		if traderID != nil && trader != *traderID {
+			continue
+		}
+		for sinal := range sinais {
+			select {
+			case sinal <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// Encerrar avisa os streams abertos que a API está sendo encerrada, para que fechem a
+// conexão (o cliente reconecta em outra instância a partir do último id recebido)
+func (s *EventosService) Encerrar() {
+	s.encerrar.Do(func() { close(s.encerrando) })
+}
+
+// Encerrando é fechado quando a API começa a ser encerrada
+func (s *EventosService) Encerrando() <-chan struct{} {
+	return s.encerrando
+}
+
+// ExecutarEscuta acompanha o NOTIFY dos eventos gravados (por qualquer instância da
+// API) e avisa os streams do trader, até o contexto ser cancelado. Após uma reconexão,
+// todos os streams são avisados para buscarem o que possam ter perdido.
+func (s *EventosService) ExecutarEscuta(ctx context.Context, connString string) {
+	listener := pq.NewListener(connString, time.Second, time.Minute, func(ev pq.ListenerEventType, err error) {
+		if err != nil {
+			logs.Do(ctx).WithError(err).Warn("Conexão de escuta dos eventos interrompida")
+		}
+	})
+	defer listener.Close()
+
+	if err := listener.Listen(canalEventos); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao escutar eventos; streams dependerão da consulta periódica")
+		return
+	}
+	logs.Do(ctx).Info("Escuta de eventos iniciada")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logs.Do(ctx).Info("Escuta de eventos finalizada")
+			return
+		case n := <-listener.Notify:
+			if n == nil {
+				s.avisar(nil)
+				continue
+			}
+			if traderID, err := uuid.Parse(n.Extra); err == nil {
+				s.avisar(&traderID)
+			}
+		case <-time.After(90 * time.Second):
+			go listener.Ping()
+		}
+	}
+}
+
+// Limpar remove os eventos mais antigos que a retenção
+func (s *EventosService) Limpar(ctx context.Context, retencao time.Duration) (int64, error) {
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM eventos WHERE created_at < NOW() - $1 * INTERVAL '1 second'
+	`, int64(retencao.Seconds()))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover eventos antigos")
+		return 0, fmt.Errorf("erro ao remover eventos antigos")
+	}
+	return result.RowsAffected()
+}
+
+// ExecutarLimpeza remove os eventos antigos a cada hora até o contexto ser cancelado
+func (s *EventosService) ExecutarLimpeza(ctx context.Context, retencao time.Duration) {
+	logs.Do(ctx).WithField("retencao", retencao.String()).Info("Worker de limpeza de eventos iniciado")
+
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+
+	for {
+		if removidos, err := s.Limpar(ctx, retencao); err != nil && ctx.Err() == nil {
+			logs.Do(ctx).WithError(err).Warn("Falha ao remover eventos antigos; nova tentativa no próximo ciclo")
+		} else if removidos > 0 {
+			logs.Do(ctx).WithField("removidos", removidos).Info("Eventos antigos removidos")
+		}
+
+		select {
+		case <-ctx.Done():
+			logs.Do(ctx).Info("Worker de limpeza de eventos finalizado")
+			return
+		case <-ticker.C:
+		}
+	}
+}
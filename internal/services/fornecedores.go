@@ -0,0 +1,472 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/catalogo"
+)
+
+// loteVinculacao é a quantidade de ofertas vinculadas por ciclo do worker
+const loteVinculacao = 500
+
+// FornecedoresService mantém o cadastro de fornecedores, identificados pelo nome da
+// empresa que o Mobgran envia em cada oferta
+type FornecedoresService struct {
+	db        *sql.DB
+	auditoria *AuditoriaService
+}
+
+// NewFornecedoresService cria uma nova instância do FornecedoresService
+func NewFornecedoresService(db *sql.DB, auditoria *AuditoriaService) *FornecedoresService {
+	return &FornecedoresService{db: db, auditoria: auditoria}
+}
+
+// consultaFornecedores lê o fornecedor com as estatísticas das ofertas do trader ($1;
+// de todos os traders quando nulo). A última importação é a criação mais recente dos
+// cavaletes, que são regravados a cada reimportação da oferta.
+const consultaFornecedores = `
+	SELECT f.id, f.nome, f.url_logo, f.documento, f.contato, f.email, f.telefone, f.site,
+		f.cidade, f.uf, f.observacoes, f.created_at, f.updated_at,
+		COUNT(DISTINCT o.id), COUNT(DISTINCT o.id) FILTER (WHERE o.situacao = 'ativa'),
+		COUNT(c.id), COALESCE(SUM(COALESCE(c.metragem_m2, c.metragem)), 0), MAX(c.created_at)
+	FROM fornecedores f
+	LEFT JOIN ofertas o ON o.fornecedor_id = f.id AND o.deleted_at IS NULL
+		AND ($1::uuid IS NULL OR o.trader_id = $1)
+	LEFT JOIN cavaletes c ON c.oferta_id = o.id AND c.deleted_at IS NULL
+	WHERE %s
+	GROUP BY f.id
+	HAVING $1::uuid IS NULL OR COUNT(o.id) > 0
+	ORDER BY f.nome`
+
+// escanearFornecedor lê uma linha da consultaFornecedores
+func escanearFornecedor(row interface{ Scan(...interface{}) error }, f *models.Fornecedor) error {
+	return row.Scan(
+		&f.ID, &f.Nome, &f.URLLogo, &f.Documento, &f.Contato, &f.Email, &f.Telefone, &f.Site,
+		&f.Cidade, &f.UF, &f.Observacoes, &f.CreatedAt, &f.UpdatedAt,
+		&f.Estatisticas.Ofertas, &f.Estatisticas.OfertasAtivas, &f.Estatisticas.Cavaletes,
+		&f.Estatisticas.MetragemM2, &f.Estatisticas.UltimaImportacao,
+	)
+}
+
+// Listar lista os fornecedores das ofertas do trader com as estatísticas. Com traderID
+// nil, lista todos os fornecedores com as estatísticas de todos os traders. A busca
+// considera o nome e as grafias conhecidas, ignorando acentos.
+func (s *FornecedoresService) Listar(ctx context.Context, traderID *uuid.UUID, busca string) ([]models.Fornecedor, error) {
+	where := "TRUE"
+	args := []interface{}{traderID}
+	if busca != "" {
+		args = append(args, "%"+busca+"%")
+		where = `(unaccent(f.nome) ILIKE unaccent($2) OR EXISTS (
+			SELECT 1 FROM fornecedores_nomes fn WHERE fn.fornecedor_id = f.id AND unaccent(fn.nome) ILIKE unaccent($2)))`
+	}
+
+	rows, err := s.db.QueryContext(ctx, fmt.Sprintf(consultaFornecedores, where), args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao listar fornecedores")
+		return nil, fmt.Errorf("erro ao listar fornecedores")
+	}
+	defer rows.Close()
+
+	fornecedores := []models.Fornecedor{}
+	for rows.Next() {
+		var f models.Fornecedor
+		if err := escanearFornecedor(rows, &f); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear fornecedor")
+			return nil, fmt.Errorf("erro ao listar fornecedores")
+		}
+		fornecedores = append(fornecedores, f)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao listar fornecedores")
+		return nil, fmt.Errorf("erro ao listar fornecedores")
+	}
+
+	if err := s.carregarNomes(ctx, s.db, fornecedores); err != nil {
+		return nil, err
+	}
+	return fornecedores, nil
+}
+
+// Buscar retorna o fornecedor com as estatísticas das ofertas do trader. Para o
+// trader, fornecedores sem ofertas suas não são encontrados.
+func (s *FornecedoresService) Buscar(ctx context.Context, traderID *uuid.UUID, fornecedorID uuid.UUID) (*models.Fornecedor, error) {
+	return s.buscar(ctx, s.db, traderID, fornecedorID)
+}
+
+// Atualizar altera os dados de contato do fornecedor
+func (s *FornecedoresService) Atualizar(ctx context.Context, fornecedorID uuid.UUID, request *models.FornecedorAtualizarRequest) (*models.Fornecedor, error) {
+	setParts := []string{}
+	args := []interface{}{}
+
+	adicionar := func(coluna string, valor interface{}) {
+		args = append(args, valor)
+		setParts = append(setParts, fmt.Sprintf("%s = $%d", coluna, len(args)))
+	}
+
+	if request.Nome != nil {
+		adicionar("nome", strings.TrimSpace(*request.Nome))
+	}
+	if request.Documento != nil {
+		_, normalizado, err := normalizarDocumento(request.Documento)
+		if err != nil {
+			return nil, err
+		}
+		adicionar("documento", normalizado)
+	}
+	if request.Email != nil {
+		if err := validarEmail(request.Email); err != nil {
+			return nil, err
+		}
+		adicionar("email", textoOuNulo(request.Email))
+	}
+	if request.UF != nil {
+		uf := textoOuNulo(request.UF)
+		if uf != nil {
+			if len(*uf) != 2 {
+				return nil, fmt.Errorf("UF inválida")
+			}
+			*uf = strings.ToUpper(*uf)
+		}
+		adicionar("uf", uf)
+	}
+	for _, campo := range []struct {
+		coluna string
+		valor  *string
+	}{
+		{"url_logo", request.URLLogo},
+		{"contato", request.Contato},
+		{"telefone", request.Telefone},
+		{"site", request.Site},
+		{"cidade", request.Cidade},
+		{"observacoes", request.Observacoes},
+	} {
+		if campo.valor != nil {
+			adicionar(campo.coluna, textoOuNulo(campo.valor))
+		}
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, nil, fornecedorID)
+	if err != nil {
+		return nil, err
+	}
+
+	args = append(args, fornecedorID)
+	if _, err := tx.ExecContext(ctx, fmt.Sprintf(`
+		UPDATE fornecedores SET %s WHERE id = $%d
+	`, strings.Join(append(setParts, "updated_at = NOW()"), ", "), len(args)), args...); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar fornecedor")
+		return nil, fmt.Errorf("erro ao atualizar fornecedor")
+	}
+
+	depois, err := s.buscar(ctx, tx, nil, fornecedorID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoFornecedorEditado, "fornecedor", fornecedorID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do fornecedor")
+		return nil, fmt.Errorf("erro ao atualizar fornecedor")
+	}
+	return depois, nil
+}
+
+// Mesclar incorpora fornecedores duplicados ao fornecedor informado: as ofertas e as
+// grafias do nome passam para ele e os duplicados são excluídos. As próximas
+// importações com qualquer das grafias são vinculadas ao fornecedor mantido.
+func (s *FornecedoresService) Mesclar(ctx context.Context, fornecedorID uuid.UUID, request *models.MesclarFornecedoresRequest) (*models.Fornecedor, error) {
+	duplicados := []string{}
+	vistos := map[uuid.UUID]bool{fornecedorID: true}
+	for _, id := range request.FornecedorIDs {
+		if !vistos[id] {
+			vistos[id] = true
+			duplicados = append(duplicados, id.String())
+		}
+	}
+	if len(duplicados) == 0 {
+		return nil, fmt.Errorf("informe fornecedores diferentes do fornecedor mantido")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if _, err := s.buscar(ctx, tx, nil, fornecedorID); err != nil {
+		return nil, err
+	}
+
+	var encontrados int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM fornecedores WHERE id = ANY($1::uuid[])
+	`, pq.Array(duplicados)).Scan(&encontrados)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar fornecedores duplicados")
+		return nil, fmt.Errorf("erro ao mesclar fornecedores")
+	}
+	if encontrados != len(duplicados) {
+		return nil, fmt.Errorf("fornecedor não encontrado")
+	}
+
+	for _, query := range []string{
+		`UPDATE fornecedores_nomes SET fornecedor_id = $1 WHERE fornecedor_id = ANY($2::uuid[])`,
+		`UPDATE ofertas SET fornecedor_id = $1 WHERE fornecedor_id = ANY($2::uuid[])`,
+		`DELETE FROM fornecedores WHERE id = ANY($2::uuid[]) AND id <> $1`,
+	} {
+		if _, err := tx.ExecContext(ctx, query, fornecedorID, pq.Array(duplicados)); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao mesclar fornecedores")
+			return nil, fmt.Errorf("erro ao mesclar fornecedores")
+		}
+	}
+
+	depois, err := s.buscar(ctx, tx, nil, fornecedorID)
+	if err != nil {
+		return nil, err
+	}
+
+	antes := map[string]interface{}{"fornecedores_incorporados": duplicados}
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoFornecedorMesclado, "fornecedor", fornecedorID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da mescla de fornecedores")
+		return nil, fmt.Errorf("erro ao mesclar fornecedores")
+	}
+	return depois, nil
+}
+
+// VincularOferta associa a oferta ao fornecedor com o mesmo nome de empresa,
+// cadastrando o fornecedor na primeira importação
+func (s *FornecedoresService) VincularOferta(ctx context.Context, ofertaID string) error {
+	_, err := s.vincular(ctx, ofertaID)
+	return err
+}
+
+// VincularPendentes vincula até limite ofertas importadas antes do cadastro de
+// fornecedores
+func (s *FornecedoresService) VincularPendentes(ctx context.Context, limite int) (*models.VinculacaoFornecedoresResultado, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id FROM ofertas
+		WHERE fornecedor_id IS NULL AND btrim(nome_empresa) <> ''
+		ORDER BY created_at
+		LIMIT $1
+	`, limite)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar ofertas sem fornecedor")
+		return nil, fmt.Errorf("erro ao buscar ofertas sem fornecedor")
+	}
+
+	var pendentes []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear oferta sem fornecedor")
+			continue
+		}
+		pendentes = append(pendentes, id)
+	}
+	rows.Close()
+
+	resultado := &models.VinculacaoFornecedoresResultado{}
+	for _, id := range pendentes {
+		criado, err := s.vincular(ctx, id)
+		if err != nil {
+			logs.Do(ctx).WithError(err).WithField("oferta_id", id).Error("Erro ao vincular oferta ao fornecedor")
+			return resultado, fmt.Errorf("erro ao vincular fornecedores")
+		}
+		resultado.Ofertas++
+		if criado {
+			resultado.FornecedoresCriados++
+		}
+	}
+
+	return resultado, nil
+}
+
+// ExecutarVinculacao vincula em lotes todas as ofertas pendentes e termina quando não
+// houver mais pendências ou o contexto for cancelado
+func (s *FornecedoresService) ExecutarVinculacao(ctx context.Context) {
+	total := 0
+	for ctx.Err() == nil {
+		resultado, err := s.VincularPendentes(ctx, loteVinculacao)
+		if err != nil {
+			if ctx.Err() == nil {
+				logs.Do(ctx).WithError(err).Warn("Vinculação de fornecedores interrompida")
+			}
+			return
+		}
+		total += resultado.Ofertas
+		if resultado.Ofertas < loteVinculacao {
+			break
+		}
+	}
+
+	if total > 0 {
+		logs.Do(ctx).WithField("ofertas", total).Info("Ofertas importadas vinculadas aos fornecedores")
+	}
+}
+
+// vincular associa a oferta ao fornecedor pela chave do nome da empresa, retornando se
+// o fornecedor foi cadastrado agora. Duas importações simultâneas do mesmo fornecedor
+// novo disputam a grafia; a perdedora repete a busca e encontra o fornecedor criado.
+func (s *FornecedoresService) vincular(ctx context.Context, ofertaID string) (bool, error) {
+	var nomeEmpresa string
+	var urlLogo sql.NullString
+	err := s.db.QueryRowContext(ctx, `
+		SELECT nome_empresa, url_logo FROM ofertas WHERE id = $1
+	`, ofertaID).Scan(&nomeEmpresa, &urlLogo)
+	if err == sql.ErrNoRows {
+		return false, fmt.Errorf("oferta não encontrada")
+	} else if err != nil {
+		return false, err
+	}
+
+	nomeEmpresa = strings.TrimSpace(nomeEmpresa)
+	chave := catalogo.ChaveEmpresa(nomeEmpresa)
+	if chave == "" {
+		return false, nil
+	}
+	logo := textoOuNulo(&urlLogo.String)
+
+	for tentativa := 0; ; tentativa++ {
+		criado, err := s.vincularTx(ctx, ofertaID, nomeEmpresa, chave, logo)
+		if err != errGrafiaDisputada || tentativa > 0 {
+			return criado, err
+		}
+	}
+}
+
+var errGrafiaDisputada = fmt.Errorf("grafia do fornecedor cadastrada em paralelo")
+
+func (s *FornecedoresService) vincularTx(ctx context.Context, ofertaID, nome, chave string, logo *string) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	criado := false
+	var fornecedorID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT fornecedor_id FROM fornecedores_nomes WHERE chave = $1
+	`, chave).Scan(&fornecedorID)
+	switch {
+	case err == sql.ErrNoRows:
+		err = tx.QueryRowContext(ctx, `
+			INSERT INTO fornecedores (nome, url_logo) VALUES ($1, $2) RETURNING id
+		`, nome, logo).Scan(&fornecedorID)
+		if err != nil {
+			return false, err
+		}
+		afetadas, err := execContando(ctx, tx, `
+			INSERT INTO fornecedores_nomes (chave, fornecedor_id, nome) VALUES ($1, $2, $3)
+			ON CONFLICT (chave) DO NOTHING
+		`, chave, fornecedorID, nome)
+		if err != nil {
+			return false, err
+		}
+		if afetadas == 0 {
+			return false, errGrafiaDisputada
+		}
+		criado = true
+	case err != nil:
+		return false, err
+	default:
+		// O logotipo da oferta só preenche o fornecedor que ainda não tem um
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE fornecedores SET url_logo = $2, updated_at = NOW() WHERE id = $1 AND url_logo IS NULL AND $2::text IS NOT NULL
+		`, fornecedorID, logo); err != nil {
+			return false, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE ofertas SET fornecedor_id = $2 WHERE id = $1 AND fornecedor_id IS DISTINCT FROM $2
+	`, ofertaID, fornecedorID); err != nil {
+		return false, err
+	}
+
+	return criado, tx.Commit()
+}
+
+func (s *FornecedoresService) buscar(ctx context.Context, q consultor, traderID *uuid.UUID, fornecedorID uuid.UUID) (*models.Fornecedor, error) {
+	var f models.Fornecedor
+	err := escanearFornecedor(q.QueryRowContext(ctx, fmt.Sprintf(consultaFornecedores, "f.id = $2"), traderID, fornecedorID), &f)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("fornecedor não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar fornecedor")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	fornecedores := []models.Fornecedor{f}
+	if err := s.carregarNomes(ctx, q, fornecedores); err != nil {
+		return nil, err
+	}
+	return &fornecedores[0], nil
+}
+
+// carregarNomes preenche as grafias conhecidas dos fornecedores com uma única consulta
+func (s *FornecedoresService) carregarNomes(ctx context.Context, q consultor, fornecedores []models.Fornecedor) error {
+	if len(fornecedores) == 0 {
+		return nil
+	}
+
+	indices := make(map[uuid.UUID]int, len(fornecedores))
+	ids := make([]string, len(fornecedores))
+	for i := range fornecedores {
+		fornecedores[i].Nomes = []string{}
+		indices[fornecedores[i].ID] = i
+		ids[i] = fornecedores[i].ID.String()
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT fornecedor_id, nome FROM fornecedores_nomes
+		WHERE fornecedor_id = ANY($1::uuid[])
+		ORDER BY nome
+	`, pq.Array(ids))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar nomes dos fornecedores")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var fornecedorID uuid.UUID
+		var nome string
+		if err := rows.Scan(&fornecedorID, &nome); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear nome do fornecedor")
+			return fmt.Errorf("erro interno do servidor")
+		}
+		if i, ok := indices[fornecedorID]; ok {
+			fornecedores[i].Nomes = append(fornecedores[i].Nomes, nome)
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,501 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/imagens"
+	"mobgran-importer-go/pkg/storage"
+)
+
+// tiposImagemProduto são os formatos aceitos no upload da galeria
+var tiposImagemProduto = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/webp": true,
+}
+
+// imagemArmazenada guarda as chaves gravadas para uma imagem ainda não registrada
+type imagemArmazenada struct {
+	imagem           models.ImagemProduto
+	chaveOriginal    string
+	chavesMiniaturas map[string]string
+}
+
+// chaves retorna todas as chaves gravadas no armazenamento para a imagem
+func (a *imagemArmazenada) chaves() []string {
+	chaves := []string{a.chaveOriginal}
+	for _, chave := range a.chavesMiniaturas {
+		chaves = append(chaves, chave)
+	}
+	return chaves
+}
+
+// GaleriaService gerencia as imagens enviadas pelos traders para seus produtos
+type GaleriaService struct {
+	db        *sql.DB
+	store     storage.BlobStore
+	auditoria *AuditoriaService
+}
+
+// NewGaleriaService cria uma nova instância do GaleriaService
+func NewGaleriaService(db *sql.DB, store storage.BlobStore, auditoria *AuditoriaService) *GaleriaService {
+	return &GaleriaService{db: db, store: store, auditoria: auditoria}
+}
+
+// ListarImagens lista as imagens da galeria do produto na ordem de exibição
+func (s *GaleriaService) ListarImagens(ctx context.Context, traderID, produtoID uuid.UUID) ([]models.ImagemProduto, error) {
+	if err := s.verificarProduto(ctx, s.db, traderID, produtoID); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, produto_id, url, miniaturas, nome_arquivo, content_type, largura, altura,
+			   tamanho_bytes, ordem, capa, created_at
+		FROM imagens_produtos
+		WHERE produto_id = $1
+		ORDER BY ordem, created_at
+	`, produtoID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar imagens do produto")
+		return nil, fmt.Errorf("erro ao buscar imagens do produto")
+	}
+	defer rows.Close()
+
+	imagens := []models.ImagemProduto{}
+	for rows.Next() {
+		var img models.ImagemProduto
+		var miniaturas []byte
+		err := rows.Scan(
+			&img.ID, &img.ProdutoID, &img.URL, &miniaturas, &img.NomeArquivo, &img.ContentType,
+			&img.Largura, &img.Altura, &img.TamanhoBytes, &img.Ordem, &img.Capa, &img.CreatedAt,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear imagem do produto")
+			continue
+		}
+		if err := json.Unmarshal(miniaturas, &img.Miniaturas); err != nil {
+			logs.Do(ctx).WithError(err).WithField("imagem_id", img.ID).Warn("Miniaturas da imagem inválidas")
+		}
+		img.URLMin = urlListagem(img.URL, img.Miniaturas)
+		imagens = append(imagens, img)
+	}
+
+	return imagens, nil
+}
+
+// AdicionarImagens valida e armazena as imagens enviadas, adicionando-as ao final da
+// galeria. A primeira imagem de um produto sem capa se torna a capa.
+func (s *GaleriaService) AdicionarImagens(ctx context.Context, traderID, produtoID uuid.UUID, arquivos []models.ArquivoImagem) ([]models.ImagemProduto, error) {
+	if err := s.verificarProduto(ctx, s.db, traderID, produtoID); err != nil {
+		return nil, err
+	}
+
+	// Valida todos os arquivos antes de armazenar qualquer um
+	contentTypes := make([]string, len(arquivos))
+	for i, arquivo := range arquivos {
+		contentType, err := validarImagemProduto(arquivo)
+		if err != nil {
+			return nil, err
+		}
+		contentTypes[i] = contentType
+	}
+
+	// Verificação antecipada para não armazenar arquivos que seriam recusados; o limite é
+	// conferido de novo na transação
+	if _, _, err := s.contarImagens(ctx, s.db, produtoID, len(arquivos)); err != nil {
+		return nil, err
+	}
+
+	// Armazena os arquivos; em caso de falha, o que já foi gravado é removido
+	armazenadas := make([]imagemArmazenada, 0, len(arquivos))
+	chaves := []string{}
+	for i, arquivo := range arquivos {
+		armazenada, err := s.armazenarImagem(ctx, produtoID, arquivo, contentTypes[i])
+		if armazenada != nil {
+			chaves = append(chaves, armazenada.chaves()...)
+		}
+		if err != nil {
+			s.removerArquivos(ctx, chaves)
+			return nil, err
+		}
+		armazenadas = append(armazenadas, *armazenada)
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		s.removerArquivos(ctx, chaves)
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	// A linha do produto fica travada até o commit, o que serializa envios simultâneos e
+	// impede que juntos passem do limite de imagens
+	var bloqueado uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT id FROM produtos_aprovados
+		WHERE id = $1 AND trader_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, produtoID, traderID).Scan(&bloqueado)
+	if err == sql.ErrNoRows {
+		s.removerArquivos(ctx, chaves)
+		return nil, fmt.Errorf("produto não encontrado")
+	} else if err != nil {
+		s.removerArquivos(ctx, chaves)
+		logs.Do(ctx).WithError(err).Error("Erro ao bloquear produto")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	quantidade, temCapa, err := s.contarImagens(ctx, tx, produtoID, len(armazenadas))
+	if err != nil {
+		s.removerArquivos(ctx, chaves)
+		return nil, err
+	}
+
+	imagens := make([]models.ImagemProduto, 0, len(armazenadas))
+	for i := range armazenadas {
+		img := &armazenadas[i].imagem
+		img.Ordem = quantidade + i
+		img.Capa = !temCapa && i == 0
+
+		miniaturas, _ := json.Marshal(img.Miniaturas)
+		chavesMiniaturas, _ := json.Marshal(armazenadas[i].chavesMiniaturas)
+
+		err := tx.QueryRowContext(ctx, `
+			INSERT INTO imagens_produtos (
+				id, produto_id, trader_id, chave_original, chaves_miniaturas, url, miniaturas,
+				nome_arquivo, content_type, largura, altura, tamanho_bytes, ordem, capa
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+			RETURNING created_at
+		`, img.ID, produtoID, traderID, armazenadas[i].chaveOriginal,
+			string(chavesMiniaturas), img.URL, string(miniaturas), img.NomeArquivo, img.ContentType,
+			img.Largura, img.Altura, img.TamanhoBytes, img.Ordem, img.Capa).Scan(&img.CreatedAt)
+		if err != nil {
+			s.removerArquivos(ctx, chaves)
+			logs.Do(ctx).WithError(err).Error("Erro ao registrar imagem do produto")
+			return nil, fmt.Errorf("erro ao salvar imagens")
+		}
+
+		if err := s.auditoria.Registrar(ctx, tx, models.AcaoImagemAdicionada, "produto", produtoID.String(), nil, img); err != nil {
+			s.removerArquivos(ctx, chaves)
+			return nil, err
+		}
+		imagens = append(imagens, *img)
+	}
+
+	if err := tx.Commit(); err != nil {
+		s.removerArquivos(ctx, chaves)
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit das imagens")
+		return nil, fmt.Errorf("erro ao salvar imagens")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":    traderID,
+		"produto_id": produtoID,
+		"imagens":    len(imagens),
+	}).Info("Imagens adicionadas ao produto")
+
+	return imagens, nil
+}
+
+// DefinirCapa define a imagem exibida como principal do produto
+func (s *GaleriaService) DefinirCapa(ctx context.Context, traderID, produtoID, imagemID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarProduto(ctx, tx, traderID, produtoID); err != nil {
+		return err
+	}
+
+	// Remove a capa atual antes de definir a nova, respeitando o índice único
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE imagens_produtos SET capa = false WHERE produto_id = $1 AND capa AND id <> $2
+	`, produtoID, imagemID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover capa atual")
+		return fmt.Errorf("erro ao definir capa")
+	}
+
+	afetadas, err := execContando(ctx, tx, `
+		UPDATE imagens_produtos SET capa = true WHERE id = $1 AND produto_id = $2
+	`, imagemID, produtoID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao definir capa")
+		return fmt.Errorf("erro ao definir capa")
+	}
+	if afetadas == 0 {
+		return fmt.Errorf("imagem não encontrada")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoGaleriaAtualizada, "produto", produtoID.String(),
+		nil, map[string]interface{}{"capa": imagemID}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da capa")
+		return fmt.Errorf("erro ao definir capa")
+	}
+
+	return nil
+}
+
+// ReordenarImagens aplica a ordem informada; a lista deve conter todas as imagens do produto
+func (s *GaleriaService) ReordenarImagens(ctx context.Context, traderID, produtoID uuid.UUID, ids []uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarProduto(ctx, tx, traderID, produtoID); err != nil {
+		return err
+	}
+
+	var quantidade int
+	if err := tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM imagens_produtos WHERE produto_id = $1`, produtoID).Scan(&quantidade); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao contar imagens do produto")
+		return fmt.Errorf("erro interno do servidor")
+	}
+
+	vistos := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		vistos[id] = true
+	}
+	if len(vistos) != len(ids) || len(ids) != quantidade {
+		return fmt.Errorf("a lista deve conter todas as imagens do produto, sem repetições")
+	}
+
+	for ordem, id := range ids {
+		afetadas, err := execContando(ctx, tx, `
+			UPDATE imagens_produtos SET ordem = $1 WHERE id = $2 AND produto_id = $3
+		`, ordem, id, produtoID)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao reordenar imagens")
+			return fmt.Errorf("erro ao reordenar imagens")
+		}
+		if afetadas == 0 {
+			return fmt.Errorf("a lista deve conter todas as imagens do produto, sem repetições")
+		}
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoGaleriaAtualizada, "produto", produtoID.String(),
+		nil, map[string]interface{}{"ordem": ids}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da reordenação")
+		return fmt.Errorf("erro ao reordenar imagens")
+	}
+
+	return nil
+}
+
+// RemoverImagem remove a imagem da galeria e seus arquivos. Se era a capa, a próxima
+// imagem na ordem passa a ser a capa.
+func (s *GaleriaService) RemoverImagem(ctx context.Context, traderID, produtoID, imagemID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	if err := s.verificarProduto(ctx, tx, traderID, produtoID); err != nil {
+		return err
+	}
+
+	var chaveOriginal, url string
+	var chavesMiniaturas []byte
+	var capa bool
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM imagens_produtos
+		WHERE id = $1 AND produto_id = $2
+		RETURNING chave_original, chaves_miniaturas, url, capa
+	`, imagemID, produtoID).Scan(&chaveOriginal, &chavesMiniaturas, &url, &capa)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("imagem não encontrada")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover imagem do produto")
+		return fmt.Errorf("erro ao remover imagem")
+	}
+
+	if capa {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE imagens_produtos SET capa = true
+			WHERE id = (
+				SELECT id FROM imagens_produtos WHERE produto_id = $1 ORDER BY ordem, created_at LIMIT 1
+			)
+		`, produtoID)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao promover nova capa")
+			return fmt.Errorf("erro ao remover imagem")
+		}
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoImagemRemovida, "produto", produtoID.String(),
+		map[string]interface{}{"imagem_id": imagemID, "url": url, "capa": capa}, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção da imagem")
+		return fmt.Errorf("erro ao remover imagem")
+	}
+
+	// Os arquivos só são apagados depois que o registro deixou de existir
+	chaves := []string{chaveOriginal}
+	var miniaturas map[string]string
+	if err := json.Unmarshal(chavesMiniaturas, &miniaturas); err == nil {
+		for _, chave := range miniaturas {
+			chaves = append(chaves, chave)
+		}
+	}
+	s.removerArquivos(ctx, chaves)
+
+	return nil
+}
+
+// armazenarImagem grava o original e as miniaturas. A imagem retornada ainda não tem
+// ordem nem capa; em caso de erro, as chaves já gravadas vêm no retorno para limpeza.
+func (s *GaleriaService) armazenarImagem(ctx context.Context, produtoID uuid.UUID, arquivo models.ArquivoImagem, contentType string) (*imagemArmazenada, error) {
+	id := uuid.New()
+	base := fmt.Sprintf("produtos/%s/%s", produtoID, id)
+
+	armazenada := &imagemArmazenada{
+		imagem: models.ImagemProduto{
+			ID:           id,
+			ProdutoID:    produtoID,
+			ContentType:  contentType,
+			TamanhoBytes: int64(len(arquivo.Dados)),
+		},
+		chaveOriginal: fmt.Sprintf("%s/original.%s", base, extensoesImagem[contentType]),
+	}
+	if arquivo.Nome != "" {
+		nome := arquivo.Nome
+		armazenada.imagem.NomeArquivo = &nome
+	}
+
+	if err := s.store.Salvar(ctx, armazenada.chaveOriginal, arquivo.Dados, contentType); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao armazenar imagem do produto")
+		return nil, fmt.Errorf("erro ao armazenar imagem")
+	}
+
+	largura, altura, chavesMiniaturas, err := salvarMiniaturas(ctx, s.store, base, arquivo.Dados)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao gerar miniaturas da imagem do produto")
+		return armazenada, fmt.Errorf("erro ao armazenar imagem")
+	}
+	armazenada.chavesMiniaturas = chavesMiniaturas
+
+	img := &armazenada.imagem
+	img.Largura = largura
+	img.Altura = altura
+	img.URL = s.store.URL(armazenada.chaveOriginal)
+	img.Miniaturas = make(map[string]string, len(chavesMiniaturas))
+	for nome, chave := range chavesMiniaturas {
+		img.Miniaturas[nome] = s.store.URL(chave)
+	}
+	img.URLMin = urlListagem(img.URL, img.Miniaturas)
+
+	return armazenada, nil
+}
+
+// verificarProduto garante que o produto existe, não foi removido e pertence ao trader
+func (s *GaleriaService) verificarProduto(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}, traderID, produtoID uuid.UUID) error {
+	var existe bool
+	err := q.QueryRowContext(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM produtos_aprovados WHERE id = $1 AND trader_id = $2 AND deleted_at IS NULL
+		)
+	`, produtoID, traderID).Scan(&existe)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar produto")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	if !existe {
+		return fmt.Errorf("produto não encontrado")
+	}
+	return nil
+}
+
+// contarImagens retorna quantas imagens o produto tem e se alguma é capa, recusando o
+// envio de novas imagens que passariam do limite por produto
+func (s *GaleriaService) contarImagens(ctx context.Context, q interface {
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}, produtoID uuid.UUID, novas int) (int, bool, error) {
+	var quantidade int
+	var temCapa bool
+	err := q.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(bool_or(capa), false) FROM imagens_produtos WHERE produto_id = $1
+	`, produtoID).Scan(&quantidade, &temCapa)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao contar imagens do produto")
+		return 0, false, fmt.Errorf("erro interno do servidor")
+	}
+	if quantidade+novas > models.MaxImagensPorProduto {
+		return 0, false, fmt.Errorf("limite de %d imagens por produto excedido", models.MaxImagensPorProduto)
+	}
+	return quantidade, temCapa, nil
+}
+
+// removerArquivos apaga arquivos do armazenamento, registrando falhas sem interromper
+func (s *GaleriaService) removerArquivos(ctx context.Context, chaves []string) {
+	for _, chave := range chaves {
+		if err := s.store.Remover(context.Background(), chave); err != nil {
+			logs.Do(ctx).WithError(err).WithField("chave", chave).Warn("Não foi possível remover arquivo do armazenamento")
+		}
+	}
+}
+
+// validarImagemProduto verifica tamanho, formato e dimensões do arquivo enviado
+func validarImagemProduto(arquivo models.ArquivoImagem) (string, error) {
+	if len(arquivo.Dados) == 0 {
+		return "", fmt.Errorf("imagem inválida: arquivo %q vazio", arquivo.Nome)
+	}
+	if len(arquivo.Dados) > models.TamanhoMaximoImagemProduto {
+		return "", fmt.Errorf("imagem inválida: arquivo %q excede %d MB", arquivo.Nome, models.TamanhoMaximoImagemProduto>>20)
+	}
+
+	contentType := http.DetectContentType(arquivo.Dados)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if !tiposImagemProduto[contentType] {
+		return "", fmt.Errorf("imagem inválida: arquivo %q não é JPEG, PNG ou WebP", arquivo.Nome)
+	}
+
+	// As dimensões vêm do cabeçalho: a imagem é recusada antes de ser armazenada ou
+	// decodificada para as miniaturas. WebP, sem decodificador, não é verificado.
+	if _, _, err := imagens.Dimensoes(arquivo.Dados); errors.Is(err, imagens.ErrImagemGrande) {
+		return "", fmt.Errorf("imagem inválida: arquivo %q excede %d megapixels", arquivo.Nome, imagens.LimitePixels/1_000_000)
+	}
+
+	return contentType, nil
+}
+
+// urlListagem retorna a miniatura usada em listagens, ou o original quando não há miniaturas
+func urlListagem(url string, miniaturas map[string]string) string {
+	if miniatura, ok := miniaturas[models.MiniaturaListagem]; ok {
+		return miniatura
+	}
+	return url
+}
@@ -0,0 +1,432 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// toleranciaMetragem é a diferença de metragem (m²) abaixo da qual o cavalete é
+// considerado inalterado entre duas importações
+const toleranciaMetragem = 0.001
+
+// HistoricoService registra o retrato do estoque a cada importação das ofertas e monta
+// as linhas do tempo de estoque e preço
+type HistoricoService struct {
+	db *sql.DB
+}
+
+// NewHistoricoService cria uma nova instância do HistoricoService
+func NewHistoricoService(db *sql.DB) *HistoricoService {
+	return &HistoricoService{db: db}
+}
+
+// RegistrarImportacao grava o retrato atual dos cavaletes da oferta. Deve ser chamado
+// depois que os cavaletes da importação foram gravados.
+func (s *HistoricoService) RegistrarImportacao(ctx context.Context, ofertaID string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro ao registrar histórico da importação")
+	}
+	defer tx.Rollback()
+
+	var importacaoID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO importacoes_oferta (oferta_id, situacao, cavaletes, metragem_m2)
+		SELECT o.id, o.situacao, COUNT(c.id), COALESCE(SUM(COALESCE(c.metragem_m2, c.metragem)), 0)
+		FROM ofertas o
+		LEFT JOIN cavaletes c ON c.oferta_id = o.id AND c.deleted_at IS NULL
+		WHERE o.id = $1
+		GROUP BY o.id
+		RETURNING id
+	`, ofertaID).Scan(&importacaoID)
+	if err == sql.ErrNoRows {
+		return fmt.Errorf("oferta não encontrada")
+	}
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Error("Erro ao registrar importação da oferta")
+		return fmt.Errorf("erro ao registrar histórico da importação")
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO historico_cavaletes (importacao_id, oferta_id, codigo, bloco, nome_material, nome_espessura, metragem_m2, quantidade_itens)
+		SELECT $1, c.oferta_id, c.codigo, c.bloco, c.nome_material, c.nome_espessura,
+			COALESCE(c.metragem_m2, c.metragem), c.quantidade_itens
+		FROM cavaletes c
+		WHERE c.oferta_id = $2 AND c.deleted_at IS NULL
+	`, importacaoID, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Error("Erro ao registrar retrato dos cavaletes")
+		return fmt.Errorf("erro ao registrar histórico da importação")
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do histórico da importação")
+		return fmt.Errorf("erro ao registrar histórico da importação")
+	}
+
+	return nil
+}
+
+// ListarImportacoes lista as importações da oferta do trader, da mais recente para a
+// mais antiga, com as alterações de cada uma em relação à importação anterior
+func (s *HistoricoService) ListarImportacoes(ctx context.Context, traderID, ofertaID uuid.UUID, params *paginacao.Parametros) ([]models.ImportacaoOferta, *paginacao.Pagina, error) {
+	var existe bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM ofertas WHERE id = $1 AND trader_id = $2 AND deleted_at IS NULL)
+	`, ofertaID, traderID).Scan(&existe)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar oferta")
+		return nil, nil, fmt.Errorf("erro ao buscar importações")
+	}
+	if !existe {
+		return nil, nil, fmt.Errorf("oferta não encontrada")
+	}
+
+	conditions := []string{"i.oferta_id = $1"}
+	args := []interface{}{ofertaID}
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM importacoes_oferta i WHERE i.oferta_id = $1", ofertaID).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar importações")
+			return nil, nil, fmt.Errorf("erro ao buscar importações")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("i.created_at", "i.id", len(args)+1)
+	if condicao != "" {
+		conditions = append(conditions, condicao)
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	// A importação anterior é calculada sobre todas as importações da oferta, antes do
+	// recorte da página
+	query := fmt.Sprintf(`
+		SELECT i.id, i.oferta_id, i.situacao, i.cavaletes, i.metragem_m2, i.created_at,
+			i.anterior_id, i.situacao_anterior, i.metragem_anterior
+		FROM (
+			SELECT io.*,
+				LAG(io.id) OVER janela AS anterior_id,
+				LAG(io.situacao) OVER janela AS situacao_anterior,
+				LAG(io.metragem_m2) OVER janela AS metragem_anterior
+			FROM importacoes_oferta io
+			WHERE io.oferta_id = $1
+			WINDOW janela AS (ORDER BY io.created_at, io.id)
+		) i
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, strings.Join(conditions, " AND "), orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar importações")
+		return nil, nil, fmt.Errorf("erro ao buscar importações")
+	}
+	defer rows.Close()
+
+	type importacaoComAnterior struct {
+		models.ImportacaoOferta
+		anteriorID       *uuid.UUID
+		situacaoAnterior *string
+		metragemAnterior *float64
+	}
+
+	lidas := []importacaoComAnterior{}
+	for rows.Next() {
+		var i importacaoComAnterior
+		if err := rows.Scan(&i.ID, &i.OfertaID, &i.Situacao, &i.Cavaletes, &i.MetragemM2, &i.CreatedAt,
+			&i.anteriorID, &i.situacaoAnterior, &i.metragemAnterior); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear importação")
+			continue
+		}
+		lidas = append(lidas, i)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iterar importações")
+		return nil, nil, fmt.Errorf("erro ao buscar importações")
+	}
+
+	lidas, pagina := paginacao.Montar(lidas, params, func(i importacaoComAnterior) (time.Time, uuid.UUID) {
+		return i.CreatedAt, i.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	ids := []string{}
+	for _, i := range lidas {
+		ids = append(ids, i.ID.String())
+		if i.anteriorID != nil {
+			ids = append(ids, i.anteriorID.String())
+		}
+	}
+	retratos, err := s.carregarRetratos(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	importacoes := make([]models.ImportacaoOferta, 0, len(lidas))
+	for _, i := range lidas {
+		importacao := i.ImportacaoOferta
+		if i.anteriorID != nil {
+			alteracoes := compararRetratos(retratos[*i.anteriorID], retratos[i.ID])
+			if i.situacaoAnterior != nil && *i.situacaoAnterior != i.Situacao {
+				alteracoes.SituacaoAnterior = i.situacaoAnterior
+			}
+			if i.metragemAnterior != nil {
+				alteracoes.MetragemM2 = math.Round((i.MetragemM2-*i.metragemAnterior)*1000) / 1000
+			}
+			importacao.Alteracoes = alteracoes
+		}
+		importacoes = append(importacoes, importacao)
+	}
+
+	return importacoes, pagina, nil
+}
+
+// carregarRetratos lê os cavaletes registrados nas importações informadas, indexados
+// pela importação e pelo código do cavalete
+func (s *HistoricoService) carregarRetratos(ctx context.Context, importacaoIDs []string) (map[uuid.UUID]map[string]models.CavaleteHistorico, error) {
+	retratos := map[uuid.UUID]map[string]models.CavaleteHistorico{}
+	if len(importacaoIDs) == 0 {
+		return retratos, nil
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT importacao_id, codigo, bloco, nome_material, nome_espessura, metragem_m2, quantidade_itens
+		FROM historico_cavaletes
+		WHERE importacao_id = ANY($1::uuid[])
+	`, pq.Array(importacaoIDs))
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar retrato dos cavaletes")
+		return nil, fmt.Errorf("erro ao buscar importações")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var importacaoID uuid.UUID
+		var c models.CavaleteHistorico
+		if err := rows.Scan(&importacaoID, &c.Codigo, &c.Bloco, &c.NomeMaterial, &c.NomeEspessura, &c.MetragemM2, &c.Chapas); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear retrato do cavalete")
+			continue
+		}
+		if retratos[importacaoID] == nil {
+			retratos[importacaoID] = map[string]models.CavaleteHistorico{}
+		}
+		retratos[importacaoID][c.Codigo] = c
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iterar retrato dos cavaletes")
+		return nil, fmt.Errorf("erro ao buscar importações")
+	}
+
+	return retratos, nil
+}
+
+// compararRetratos lista os cavaletes adicionados, removidos e alterados entre duas
+// importações, em ordem de código
+func compararRetratos(anterior, atual map[string]models.CavaleteHistorico) *models.AlteracoesImportacao {
+	alteracoes := &models.AlteracoesImportacao{
+		Adicionados: []models.CavaleteHistorico{},
+		Removidos:   []models.CavaleteHistorico{},
+		Alterados:   []models.AlteracaoCavalete{},
+	}
+
+	for codigo, depois := range atual {
+		antes, existia := anterior[codigo]
+		switch {
+		case !existia:
+			alteracoes.Adicionados = append(alteracoes.Adicionados, depois)
+		case cavaleteAlterado(antes, depois):
+			alteracoes.Alterados = append(alteracoes.Alterados, models.AlteracaoCavalete{Codigo: codigo, Antes: antes, Depois: depois})
+		}
+	}
+	for codigo, antes := range anterior {
+		if _, existe := atual[codigo]; !existe {
+			alteracoes.Removidos = append(alteracoes.Removidos, antes)
+		}
+	}
+
+	sort.Slice(alteracoes.Adicionados, func(i, j int) bool {
+		return alteracoes.Adicionados[i].Codigo < alteracoes.Adicionados[j].Codigo
+	})
+	sort.Slice(alteracoes.Removidos, func(i, j int) bool {
+		return alteracoes.Removidos[i].Codigo < alteracoes.Removidos[j].Codigo
+	})
+	sort.Slice(alteracoes.Alterados, func(i, j int) bool {
+		return alteracoes.Alterados[i].Codigo < alteracoes.Alterados[j].Codigo
+	})
+
+	return alteracoes
+}
+
+// cavaleteAlterado indica se o material, a espessura, a metragem ou a quantidade de
+// chapas do cavalete mudou entre duas importações
+func cavaleteAlterado(antes, depois models.CavaleteHistorico) bool {
+	return antes.NomeMaterial != depois.NomeMaterial ||
+		antes.NomeEspessura != depois.NomeEspessura ||
+		metragemDiferente(antes.MetragemM2, depois.MetragemM2) ||
+		inteiroDiferente(antes.Chapas, depois.Chapas)
+}
+
+func metragemDiferente(a, b *float64) bool {
+	if a == nil || b == nil {
+		return (a == nil) != (b == nil)
+	}
+	return math.Abs(*a-*b) > toleranciaMetragem
+}
+
+func inteiroDiferente(a, b *int) bool {
+	if a == nil || b == nil {
+		return (a == nil) != (b == nil)
+	}
+	return *a != *b
+}
+
+// HistoricoProduto monta a linha do tempo do produto do trader: as alterações de preço
+// e as importações em que o estoque do cavalete mudou, em ordem cronológica
+func (s *HistoricoService) HistoricoProduto(ctx context.Context, traderID, produtoID uuid.UUID) (*models.HistoricoProduto, error) {
+	historico := &models.HistoricoProduto{
+		ProdutoID: produtoID,
+		Eventos:   []models.EventoHistoricoProduto{},
+	}
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT c.oferta_id, c.codigo
+		FROM produtos_aprovados pa
+		JOIN cavaletes c ON c.id = pa.cavalete_id
+		WHERE pa.id = $1 AND pa.trader_id = $2 AND pa.deleted_at IS NULL
+	`, produtoID, traderID).Scan(&historico.OfertaID, &historico.Codigo)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("produto não encontrado")
+	}
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produto")
+		return nil, fmt.Errorf("erro ao buscar histórico do produto")
+	}
+
+	precos, err := s.eventosPreco(ctx, produtoID)
+	if err != nil {
+		return nil, err
+	}
+	estoque, err := s.eventosEstoque(ctx, historico.OfertaID, historico.Codigo)
+	if err != nil {
+		return nil, err
+	}
+
+	historico.Eventos = append(append(historico.Eventos, precos...), estoque...)
+	sort.SliceStable(historico.Eventos, func(i, j int) bool {
+		return historico.Eventos[i].Data.Before(historico.Eventos[j].Data)
+	})
+
+	return historico, nil
+}
+
+// eventosPreco lê as alterações de preço do produto
+func (s *HistoricoService) eventosPreco(ctx context.Context, produtoID uuid.UUID) ([]models.EventoHistoricoProduto, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT preco_anterior, preco_novo, created_at
+		FROM historico_precos
+		WHERE produto_id = $1
+		ORDER BY created_at
+	`, produtoID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar histórico de preços")
+		return nil, fmt.Errorf("erro ao buscar histórico do produto")
+	}
+	defer rows.Close()
+
+	eventos := []models.EventoHistoricoProduto{}
+	for rows.Next() {
+		evento := models.EventoHistoricoProduto{Tipo: models.TipoEventoPreco}
+		var novo float64
+		if err := rows.Scan(&evento.PrecoAnterior, &novo, &evento.Data); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear histórico de preços")
+			continue
+		}
+		evento.PrecoNovo = &novo
+		eventos = append(eventos, evento)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iterar histórico de preços")
+		return nil, fmt.Errorf("erro ao buscar histórico do produto")
+	}
+
+	return eventos, nil
+}
+
+// eventosEstoque percorre as importações da oferta e gera um evento na primeira e em
+// cada importação em que o cavalete entrou, saiu ou mudou de metragem ou de chapas
+func (s *HistoricoService) eventosEstoque(ctx context.Context, ofertaID uuid.UUID, codigo string) ([]models.EventoHistoricoProduto, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT io.id, io.created_at, hc.id IS NOT NULL, hc.metragem_m2, hc.quantidade_itens
+		FROM importacoes_oferta io
+		LEFT JOIN historico_cavaletes hc ON hc.importacao_id = io.id AND hc.codigo = $2
+		WHERE io.oferta_id = $1
+		ORDER BY io.created_at, io.id
+	`, ofertaID, codigo)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar histórico de estoque")
+		return nil, fmt.Errorf("erro ao buscar histórico do produto")
+	}
+	defer rows.Close()
+
+	eventos := []models.EventoHistoricoProduto{}
+	var ultimo *models.EventoHistoricoProduto
+	for rows.Next() {
+		var importacaoID uuid.UUID
+		var disponivel bool
+		evento := models.EventoHistoricoProduto{Tipo: models.TipoEventoEstoque}
+		if err := rows.Scan(&importacaoID, &evento.Data, &disponivel, &evento.MetragemM2, &evento.Chapas); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear histórico de estoque")
+			continue
+		}
+		evento.ImportacaoID = &importacaoID
+		evento.Disponivel = &disponivel
+
+		if ultimo != nil && *ultimo.Disponivel == disponivel &&
+			!metragemDiferente(ultimo.MetragemM2, evento.MetragemM2) &&
+			!inteiroDiferente(ultimo.Chapas, evento.Chapas) {
+			continue
+		}
+		eventos = append(eventos, evento)
+		ultimo = &evento
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iterar histórico de estoque")
+		return nil, fmt.Errorf("erro ao buscar histórico do produto")
+	}
+
+	return eventos, nil
+}
+
+// registrarPreco grava uma alteração de preço do produto na transação informada;
+// anterior nulo indica o preço definido na aprovação
+func registrarPreco(ctx context.Context, tx *sql.Tx, produtoID uuid.UUID, anterior *float64, novo float64) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO historico_precos (produto_id, preco_anterior, preco_novo)
+		VALUES ($1, $2, $3)
+	`, produtoID, anterior, novo)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("produto_id", produtoID).Error("Erro ao registrar histórico de preço")
+		return fmt.Errorf("erro ao registrar histórico de preço")
+	}
+	return nil
+}
@@ -0,0 +1,426 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/imagens"
+	"mobgran-importer-go/pkg/notificacao"
+	"mobgran-importer-go/pkg/storage"
+)
+
+// tamanhoMaximoImagem limita o download de cada imagem
+const tamanhoMaximoImagem = 20 << 20
+
+// maxRedirecionamentosImagem limita os saltos seguidos no download de uma imagem
+const maxRedirecionamentosImagem = 3
+
+// concorrenciaEspelhamento limita os downloads simultâneos durante a importação
+const concorrenciaEspelhamento = 4
+
+// extensoesImagem mapeia content types aceitos para a extensão do arquivo original
+var extensoesImagem = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/gif":  "gif",
+	"image/webp": "webp",
+}
+
+// ImagemService copia as imagens dos cavaletes hospedadas no Mobgran para o
+// armazenamento da aplicação e gera miniaturas
+type ImagemService struct {
+	db         *sql.DB
+	store      storage.BlobStore
+	httpClient *http.Client
+	logger     *logrus.Logger
+}
+
+// NewImagemService cria uma nova instância do ImagemService
+func NewImagemService(db *sql.DB, store storage.BlobStore, logger *logrus.Logger) *ImagemService {
+	return &ImagemService{
+		db:         db,
+		store:      store,
+		httpClient: novoClienteImagens(30 * time.Second),
+		logger:     logger,
+	}
+}
+
+// novoClienteImagens cria o cliente dos downloads. As URLs vêm do payload do Mobgran,
+// então conexões à rede interna são recusadas pelo dialer (também nos redirecionamentos)
+// e não há proxy, para o dialer ver o endereço real do destino.
+func novoClienteImagens(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout:   timeout,
+		KeepAlive: 30 * time.Second,
+		Control:   notificacao.ControlarDestino,
+	}
+
+	transporte := http.DefaultTransport.(*http.Transport).Clone()
+	transporte.Proxy = nil
+	transporte.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transporte,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirecionamentosImagem {
+				return fmt.Errorf("excesso de redirecionamentos")
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return fmt.Errorf("redirecionamento para esquema não suportado: %s", req.URL.Scheme)
+			}
+			return nil
+		},
+	}
+}
+
+// Espelhar garante uma cópia da imagem no armazenamento, baixando-a apenas se ainda
+// não foi espelhada
+func (s *ImagemService) Espelhar(ctx context.Context, urlOrigem string) (*models.ImagemEspelhada, error) {
+	existente, err := s.buscarEspelhada(ctx, urlOrigem)
+	if err != nil {
+		return nil, err
+	}
+	if existente != nil {
+		return existente, nil
+	}
+
+	dados, contentType, err := s.baixar(ctx, urlOrigem)
+	if err != nil {
+		return nil, err
+	}
+	// Recusar pelo cabeçalho imagens grandes demais para decodificar, antes de armazená-las
+	if _, _, err := imagens.Dimensoes(dados); errors.Is(err, imagens.ErrImagemGrande) {
+		return nil, err
+	}
+
+	// Chaves derivadas do endereço de origem: reimportações sobrescrevem os mesmos objetos
+	soma := sha256.Sum256([]byte(urlOrigem))
+	hash := hex.EncodeToString(soma[:])
+	base := fmt.Sprintf("imagens/%s/%s", hash[:2], hash)
+
+	imagem := &models.ImagemEspelhada{
+		URLOrigem:     urlOrigem,
+		ChaveOriginal: fmt.Sprintf("%s/original.%s", base, extensoesImagem[contentType]),
+		ContentType:   contentType,
+		TamanhoBytes:  int64(len(dados)),
+	}
+
+	if err := s.store.Salvar(ctx, imagem.ChaveOriginal, dados, contentType); err != nil {
+		return nil, fmt.Errorf("erro ao armazenar imagem: %w", err)
+	}
+
+	imagem.Largura, imagem.Altura, imagem.Miniaturas, err = salvarMiniaturas(ctx, s.store, base, dados)
+	if err != nil {
+		return nil, err
+	}
+	if len(imagem.Miniaturas) == 0 {
+		logs.Do(ctx).WithField("url_origem", urlOrigem).Warn("Imagem armazenada sem miniaturas")
+	}
+
+	if err := s.registrarEspelhada(ctx, imagem); err != nil {
+		return nil, err
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"url_origem": urlOrigem,
+		"chave":      imagem.ChaveOriginal,
+		"bytes":      imagem.TamanhoBytes,
+		"miniaturas": len(imagem.Miniaturas),
+	}).Info("Imagem espelhada com sucesso")
+
+	return imagem, nil
+}
+
+// EspelharImagemPrincipal retorna a imagem do cavalete apontando para as cópias
+// armazenadas. Imagens já espelhadas são retornadas sem alteração.
+func (s *ImagemService) EspelharImagemPrincipal(ctx context.Context, imagem *models.ImagemPrincipal) (*models.ImagemPrincipal, error) {
+	if imagem == nil || imagem.URLOrigem != "" {
+		return imagem, nil
+	}
+
+	origem := imagem.URL
+	if origem == "" {
+		origem = imagem.URLMin
+	}
+	if origem == "" {
+		return imagem, nil
+	}
+
+	espelhada, err := s.Espelhar(ctx, origem)
+	if err != nil {
+		return nil, err
+	}
+
+	resultado := &models.ImagemPrincipal{
+		Nome:      imagem.Nome,
+		URL:       s.store.URL(espelhada.ChaveOriginal),
+		URLMin:    s.store.URL(espelhada.ChaveOriginal),
+		URLOrigem: origem,
+	}
+
+	if len(espelhada.Miniaturas) > 0 {
+		resultado.Miniaturas = make(map[string]string, len(espelhada.Miniaturas))
+		for nome, chave := range espelhada.Miniaturas {
+			resultado.Miniaturas[nome] = s.store.URL(chave)
+		}
+		if url, ok := resultado.Miniaturas[models.MiniaturaListagem]; ok {
+			resultado.URLMin = url
+		}
+	}
+
+	return resultado, nil
+}
+
+// EspelharCavaletes espelha as imagens dos cavaletes recebidos do Mobgran, substituindo
+// as URLs em cada cavalete. Falhas mantêm a URL original e são apenas registradas, para
+// não interromper a importação. Retorna a quantidade de falhas.
+func (s *ImagemService) EspelharCavaletes(ctx context.Context, cavaletes []models.Cavalete) int {
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		falhas int
+	)
+	semaforo := make(chan struct{}, concorrenciaEspelhamento)
+
+	for i := range cavaletes {
+		if cavaletes[i].ImagemPrincipal == nil {
+			continue
+		}
+
+		wg.Add(1)
+		semaforo <- struct{}{}
+		go func(cavalete *models.Cavalete) {
+			defer wg.Done()
+			defer func() { <-semaforo }()
+
+			imagem, err := s.EspelharImagemPrincipal(ctx, cavalete.ImagemPrincipal)
+			if err != nil {
+				logs.Do(ctx).WithError(err).WithField("cavalete_codigo", cavalete.Codigo).Warn("Não foi possível espelhar imagem do cavalete")
+				mu.Lock()
+				falhas++
+				mu.Unlock()
+				return
+			}
+			cavalete.ImagemPrincipal = imagem
+		}(&cavaletes[i])
+	}
+
+	wg.Wait()
+	return falhas
+}
+
+// EspelharPendentes espelha as imagens de cavaletes já importados que ainda apontam
+// para o Mobgran
+func (s *ImagemService) EspelharPendentes(ctx context.Context, limite int) (*models.EspelhamentoResultado, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, imagem_principal
+		FROM cavaletes
+		WHERE imagem_principal IS NOT NULL AND (imagem_principal->>'urlOrigem') IS NULL
+			AND deleted_at IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`, limite)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar imagens pendentes")
+		return nil, fmt.Errorf("erro ao buscar imagens pendentes")
+	}
+
+	type pendente struct {
+		id     string
+		imagem models.ImagemPrincipal
+	}
+	var pendentes []pendente
+	for rows.Next() {
+		var p pendente
+		var dados []byte
+		if err := rows.Scan(&p.id, &dados); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear imagem pendente")
+			continue
+		}
+		if err := json.Unmarshal(dados, &p.imagem); err != nil {
+			logs.Do(ctx).WithError(err).WithField("cavalete_id", p.id).Warn("Imagem do cavalete com formato inválido")
+			continue
+		}
+		pendentes = append(pendentes, p)
+	}
+	rows.Close()
+
+	resultado := &models.EspelhamentoResultado{}
+	for _, p := range pendentes {
+		resultado.Processados++
+
+		imagem, err := s.EspelharImagemPrincipal(ctx, &p.imagem)
+		if err != nil {
+			logs.Do(ctx).WithError(err).WithField("cavalete_id", p.id).Warn("Não foi possível espelhar imagem do cavalete")
+			resultado.Falhas++
+			continue
+		}
+
+		dados, err := json.Marshal(imagem)
+		if err != nil {
+			resultado.Falhas++
+			continue
+		}
+
+		_, err = s.db.ExecContext(ctx, `
+			UPDATE cavaletes SET imagem_principal = $2, updated_at = NOW() WHERE id = $1
+		`, p.id, string(dados))
+		if err != nil {
+			logs.Do(ctx).WithError(err).WithField("cavalete_id", p.id).Error("Erro ao atualizar imagem do cavalete")
+			resultado.Falhas++
+			continue
+		}
+		resultado.Espelhados++
+	}
+
+	return resultado, nil
+}
+
+// salvarMiniaturas gera e armazena as miniaturas da imagem em base/<tamanho>.jpg,
+// retornando as dimensões do original e as chaves por tamanho. Formatos sem
+// decodificador (ex.: WebP) não geram miniaturas nem dimensões; imagens acima do limite
+// de pixels são recusadas.
+func salvarMiniaturas(ctx context.Context, store storage.BlobStore, base string, dados []byte) (*int, *int, map[string]string, error) {
+	miniaturas := map[string]string{}
+
+	img, _, err := imagens.Decodificar(dados)
+	if errors.Is(err, imagens.ErrImagemGrande) {
+		return nil, nil, nil, err
+	} else if err != nil {
+		return nil, nil, miniaturas, nil
+	}
+	largura, altura := img.Bounds().Dx(), img.Bounds().Dy()
+
+	for _, tamanho := range models.TamanhosMiniatura {
+		miniatura, err := imagens.CodificarJPEG(imagens.Redimensionar(img, tamanho.Lado))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		chave := fmt.Sprintf("%s/%s.jpg", base, tamanho.Nome)
+		if err := store.Salvar(ctx, chave, miniatura, "image/jpeg"); err != nil {
+			return nil, nil, nil, fmt.Errorf("erro ao armazenar miniatura: %w", err)
+		}
+		miniaturas[tamanho.Nome] = chave
+	}
+
+	return &largura, &altura, miniaturas, nil
+}
+
+// baixar faz o download da imagem, validando tamanho e tipo de conteúdo
+func (s *ImagemService) baixar(ctx context.Context, url string) ([]byte, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("URL de imagem inválida: %w", err)
+	}
+
+	// As imagens do Mobgran podem exigir o mesmo Referer do site
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; MobgranImporter/1.0)")
+	req.Header.Set("Accept", "image/*")
+	req.Header.Set("Referer", "https://www.mobgran.com/")
+
+	resp, err := s.httpClient.Do(req)
+	if errors.Is(err, notificacao.ErrDestinoBloqueado) {
+		return nil, "", fmt.Errorf("URL de imagem aponta para a rede interna: %s", url)
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao baixar imagem: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("download da imagem retornou status %d", resp.StatusCode)
+	}
+
+	dados, err := io.ReadAll(io.LimitReader(resp.Body, tamanhoMaximoImagem+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("erro ao ler imagem: %w", err)
+	}
+	if len(dados) > tamanhoMaximoImagem {
+		return nil, "", fmt.Errorf("imagem excede o tamanho máximo de %d bytes", tamanhoMaximoImagem)
+	}
+
+	// O tipo é detectado pelo conteúdo; o cabeçalho do servidor nem sempre é confiável
+	contentType := http.DetectContentType(dados)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if _, ok := extensoesImagem[contentType]; !ok {
+		return nil, "", fmt.Errorf("tipo de imagem não suportado: %s", contentType)
+	}
+
+	return dados, contentType, nil
+}
+
+// buscarEspelhada retorna a imagem já espelhada para o endereço, ou nil
+func (s *ImagemService) buscarEspelhada(ctx context.Context, urlOrigem string) (*models.ImagemEspelhada, error) {
+	var imagem models.ImagemEspelhada
+	var miniaturas []byte
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, url_origem, chave_original, content_type, largura, altura, tamanho_bytes, miniaturas, created_at
+		FROM imagens_espelhadas
+		WHERE url_origem = $1
+	`, urlOrigem).Scan(
+		&imagem.ID, &imagem.URLOrigem, &imagem.ChaveOriginal, &imagem.ContentType,
+		&imagem.Largura, &imagem.Altura, &imagem.TamanhoBytes, &miniaturas, &imagem.CreatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar imagem espelhada")
+		return nil, fmt.Errorf("erro ao buscar imagem espelhada")
+	}
+
+	if err := json.Unmarshal(miniaturas, &imagem.Miniaturas); err != nil {
+		return nil, fmt.Errorf("miniaturas da imagem espelhada inválidas: %w", err)
+	}
+
+	return &imagem, nil
+}
+
+// registrarEspelhada grava (ou atualiza) o registro da imagem espelhada
+func (s *ImagemService) registrarEspelhada(ctx context.Context, imagem *models.ImagemEspelhada) error {
+	miniaturas, err := json.Marshal(imagem.Miniaturas)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar miniaturas: %w", err)
+	}
+
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO imagens_espelhadas (
+			url_origem, chave_original, content_type, largura, altura, tamanho_bytes, miniaturas
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (url_origem) DO UPDATE SET
+			chave_original = EXCLUDED.chave_original,
+			content_type = EXCLUDED.content_type,
+			largura = EXCLUDED.largura,
+			altura = EXCLUDED.altura,
+			tamanho_bytes = EXCLUDED.tamanho_bytes,
+			miniaturas = EXCLUDED.miniaturas,
+			updated_at = NOW()
+		RETURNING id, created_at
+	`, imagem.URLOrigem, imagem.ChaveOriginal, imagem.ContentType, imagem.Largura, imagem.Altura,
+		imagem.TamanhoBytes, string(miniaturas)).Scan(&imagem.ID, &imagem.CreatedAt)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao registrar imagem espelhada")
+		return fmt.Errorf("erro ao registrar imagem espelhada")
+	}
+
+	return nil
+}
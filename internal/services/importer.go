@@ -1,40 +1,74 @@
 package services
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/metricas"
 	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/rastreamento"
 	"mobgran-importer-go/pkg/database"
 )
 
 // MobgranImporter representa o serviço de importação do Mobgran
 type MobgranImporter struct {
-	dbClient   *database.Client
-	httpClient *http.Client
-	logger     *logrus.Logger
-	apiBaseURL string
+	dbClient     *database.Client
+	auditoria    *AuditoriaService
+	imagens      *ImagemService
+	catalogo     *CatalogoService
+	fornecedores *FornecedoresService
+	historico    *HistoricoService
+	eventos      *EventosService
+	httpClient   *http.Client
+	circuito     *circuito
+	logger       *logrus.Logger
+	apiBaseURL   string
+
+	// emAndamento acompanha as importações em execução, aguardadas no encerramento
+	emAndamento sync.WaitGroup
+	ativas      atomic.Int64
 }
 
-// NewMobgranImporter cria uma nova instância do importador
-func NewMobgranImporter(dbClient *database.Client, logger *logrus.Logger) *MobgranImporter {
+// NewMobgranImporter cria uma nova instância do importador. Com imagens nil, as imagens
+// dos cavaletes continuam apontando para o Mobgran; com catalogo nil, os nomes importados
+// não são associados ao catálogo de materiais; com fornecedores nil, as ofertas ficam
+// pendentes de vínculo com o fornecedor; com historico nil, as importações não entram no
+// histórico de estoque; com eventos nil, nenhum evento de importação é gravado.
+func NewMobgranImporter(dbClient *database.Client, auditoria *AuditoriaService, imagens *ImagemService, catalogo *CatalogoService, fornecedores *FornecedoresService, historico *HistoricoService, eventos *EventosService, logger *logrus.Logger) *MobgranImporter {
 	// Cliente HTTP simples e padrão
 	client := &http.Client{
 		Timeout: 60 * time.Second,
 	}
 
 	return &MobgranImporter{
-		dbClient:   dbClient,
-		httpClient: client,
-		logger:     logger,
-		apiBaseURL: "https://www.mobgran.com/app/api/link-produto",
+		dbClient:     dbClient,
+		auditoria:    auditoria,
+		imagens:      imagens,
+		catalogo:     catalogo,
+		fornecedores: fornecedores,
+		historico:    historico,
+		eventos:      eventos,
+		httpClient:   client,
+		circuito:     &circuito{},
+		logger:       logger,
+		apiBaseURL:   "https://www.mobgran.com/app/api/link-produto",
 	}
 }
 
@@ -58,13 +92,44 @@ func (m *MobgranImporter) ExtrairUUIDLink(url string) (*string, error) {
 }
 
 // BuscarDadosAPI busca os dados da API do Mobgran
-func (m *MobgranImporter) BuscarDadosAPI(uuid string) (*models.MobgranResponse, error) {
-	m.logger.WithField("uuid", uuid).Info("Buscando dados da API Mobgran")
+func (m *MobgranImporter) BuscarDadosAPI(ctx context.Context, uuid string) (dados *models.MobgranResponse, err error) {
+	ctx, span := rastreamento.Tracer().Start(ctx, "MobgranImporter.BuscarDadosAPI", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else {
+			span.SetAttributes(attribute.Int("mobgran.total_cavaletes", len(dados.Cavaletes)))
+		}
+		span.End()
+	}()
 
 	url := fmt.Sprintf("%s/%s", m.apiBaseURL, uuid)
-	m.logger.WithField("url_completa", url).Info("URL da API construída")
 
-	req, err := http.NewRequest("GET", url, nil)
+	span.SetAttributes(
+		attribute.String("http.request.method", http.MethodGet),
+		attribute.String("url.full", url),
+		attribute.String("mobgran.uuid_link", uuid),
+	)
+
+	if err := m.circuito.permitir(); err != nil {
+		metricas.ContarErroAPIMobgran("circuito_aberto")
+		logs.Do(ctx).WithError(err).Warn("Chamada à API do Mobgran interrompida pelo circuito")
+		return nil, err
+	}
+
+	// Falhas de rede, 5xx, 429 e respostas ilegíveis contam para o circuito; os demais
+	// status (ex.: 404 de link inexistente) mostram que a API está respondendo
+	var falhaAPI error
+	defer func() {
+		if falhaAPI != nil && ctx.Err() != nil {
+			m.circuito.liberar()
+			return
+		}
+		m.circuito.registrar(falhaAPI)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar requisição: %w", err)
 	}
@@ -75,144 +140,529 @@ func (m *MobgranImporter) BuscarDadosAPI(uuid string) (*models.MobgranResponse,
 	req.Header.Set("Referer", "https://www.mobgran.com/")
 	req.Header.Set("Origin", "https://www.mobgran.com")
 
-	m.logger.WithFields(logrus.Fields{
-		"method":     req.Method,
-		"url":        req.URL.String(),
-		"headers":    req.Header,
-	}).Info("Fazendo requisição HTTP")
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"uuid": uuid,
+		"url":  req.URL.String(),
+	}).Info("Buscando dados da API Mobgran")
 
+	inicio := time.Now()
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		m.logger.WithError(err).Error("Erro ao fazer requisição para API")
-		return nil, fmt.Errorf("erro ao fazer requisição para API: %w", err)
+		metricas.ObservarAPIMobgran(0, time.Since(inicio))
+		metricas.ContarErroAPIMobgran("rede")
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer requisição para API")
+		falhaAPI = fmt.Errorf("erro ao fazer requisição para API: %w", err)
+		return nil, falhaAPI
 	}
 	defer resp.Body.Close()
+	metricas.ObservarAPIMobgran(resp.StatusCode, time.Since(inicio))
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
 
-	m.logger.WithFields(logrus.Fields{
-		"status_code": resp.StatusCode,
-		"headers":     resp.Header,
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"status_code":  resp.StatusCode,
+		"content_type": resp.Header.Get("Content-Type"),
+		"latencia_ms":  time.Since(inicio).Milliseconds(),
 	}).Info("Resposta recebida da API")
 
 	if resp.StatusCode != http.StatusOK {
-		// Ler o corpo da resposta para debug
-		body, _ := io.ReadAll(resp.Body)
-		m.logger.WithFields(logrus.Fields{
+		metricas.ContarErroAPIMobgran(strconv.Itoa(resp.StatusCode))
+		// Ler o início do corpo da resposta para debug
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		logs.Do(ctx).WithFields(logrus.Fields{
 			"status_code": resp.StatusCode,
 			"body":        string(body),
 		}).Error("API retornou erro")
-		return nil, fmt.Errorf("API retornou status %d: %s", resp.StatusCode, string(body))
+		err := fmt.Errorf("API retornou status %d: %s", resp.StatusCode, string(body))
+		if resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests {
+			falhaAPI = err
+		}
+		return nil, err
 	}
 
-	var dados models.MobgranResponse
-	if err := json.NewDecoder(resp.Body).Decode(&dados); err != nil {
-		m.logger.WithError(err).Error("Erro ao decodificar resposta da API")
-		return nil, fmt.Errorf("erro ao decodificar resposta da API: %w", err)
+	dados = &models.MobgranResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(dados); err != nil {
+		metricas.ContarErroAPIMobgran("decodificacao")
+		logs.Do(ctx).WithError(err).Error("Erro ao decodificar resposta da API")
+		falhaAPI = fmt.Errorf("erro ao decodificar resposta da API: %w", err)
+		return nil, falhaAPI
 	}
 
-	m.logger.WithFields(logrus.Fields{
+	logs.Do(ctx).WithFields(logrus.Fields{
 		"situacao":      dados.Situacao,
 		"nome_empresa":  dados.NomeEmpresa,
 		"num_cavaletes": len(dados.Cavaletes),
 	}).Info("Dados da API obtidos com sucesso")
 
-	return &dados, nil
+	return dados, nil
+}
+
+// EstadoCircuito retorna o estado do circuito das chamadas à API do Mobgran
+func (m *MobgranImporter) EstadoCircuito() models.EstadoCircuito {
+	return m.circuito.estado()
+}
+
+// ImportacoesEmAndamento retorna quantas importações estão em execução
+func (m *MobgranImporter) ImportacoesEmAndamento() int64 {
+	return m.ativas.Load()
+}
+
+// AguardarImportacoes espera as importações em execução terminarem (concluídas ou
+// desfeitas), até o contexto expirar
+func (m *MobgranImporter) AguardarImportacoes(ctx context.Context) error {
+	concluidas := make(chan struct{})
+	go func() {
+		m.emAndamento.Wait()
+		close(concluidas)
+	}()
+
+	select {
+	case <-concluidas:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("%d importação(ões) ainda em andamento: %w", m.ativas.Load(), ctx.Err())
+	}
+}
+
+// Importar executa o processo completo de importação para o trader informado. O
+// andamento e o resultado são gravados como eventos (importacao.*); a conclusão e a
+// falha chegam também às assinaturas de notificação do trader.
+func (m *MobgranImporter) Importar(ctx context.Context, url, traderID string, atualizarExistente bool) (bool, string, *string, error) {
+	inicio := time.Now()
+	m.emAndamento.Add(1)
+	m.ativas.Add(1)
+	defer func() {
+		m.ativas.Add(-1)
+		m.emAndamento.Done()
+	}()
+
+	ctx, span := rastreamento.Tracer().Start(ctx, "MobgranImporter.Importar", trace.WithAttributes(
+		attribute.String("mobgran.url", url),
+		attribute.String("mobgran.trader_id", traderID),
+		attribute.Bool("mobgran.atualizar_existente", atualizarExistente),
+	))
+	defer span.End()
+
+	m.registrarEvento(ctx, url, traderID, models.EventoImportacaoIniciada, nil)
+	sucesso, mensagem, ofertaID, err := m.importar(ctx, url, traderID, atualizarExistente)
+	m.registrarResultado(ctx, url, traderID, ofertaID, sucesso, mensagem, err)
+
+	resultado := metricas.ResultadoFalha
+	if sucesso {
+		resultado = metricas.ResultadoSucesso
+	} else if err == nil {
+		// Oferta já existente sem pedido de atualização
+		resultado = metricas.ResultadoIgnorada
+	}
+	metricas.ObservarImportacao(resultado, time.Since(inicio))
+
+	span.SetAttributes(attribute.String("mobgran.resultado", resultado))
+	if ofertaID != nil {
+		span.SetAttributes(attribute.String("mobgran.oferta_id", *ofertaID))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, mensagem)
+	}
+
+	return sucesso, mensagem, ofertaID, err
+}
+
+// registrarEvento grava um evento de andamento da importação fora de transação; falhas
+// na gravação não interrompem a importação
+func (m *MobgranImporter) registrarEvento(ctx context.Context, url, traderID, tipo string, dados map[string]interface{}) {
+	if m.eventos == nil {
+		return
+	}
+	trader, err := uuid.Parse(traderID)
+	if err != nil {
+		return
+	}
+
+	if dados == nil {
+		dados = map[string]interface{}{}
+	}
+	dados["url"] = url
+	ofertaID, _ := dados["oferta_id"].(string)
+
+	if err := m.eventos.Registrar(context.WithoutCancel(ctx), nil, trader, tipo, "oferta", ofertaID, dados); err != nil {
+		logs.Do(ctx).WithError(err).WithField("tipo", tipo).Warn("Andamento da importação sem registro de evento")
+	}
+}
+
+// registrarResultado grava importacao.concluida ou importacao.falhou; falhas na
+// gravação não alteram o resultado da importação
+func (m *MobgranImporter) registrarResultado(ctx context.Context, url, traderID string, ofertaID *string, sucesso bool, mensagem string, errImportacao error) {
+	tipo := models.EventoImportacaoConcluida
+	dados := map[string]interface{}{
+		"mensagem": mensagem,
+	}
+	if ofertaID != nil {
+		dados["oferta_id"] = *ofertaID
+	}
+
+	if !sucesso {
+		tipo = models.EventoImportacaoFalhou
+		dados["erro"] = mensagem
+		if errImportacao != nil {
+			dados["erro"] = errImportacao.Error()
+		}
+	} else if ofertaID != nil {
+		if resumo, err := m.dbClient.ComContexto(ctx).BuscarResumoOferta(*ofertaID); err == nil {
+			for chave, valor := range resumo {
+				dados[chave] = valor
+			}
+		}
+	}
+
+	m.registrarEvento(ctx, url, traderID, tipo, dados)
 }
 
-// Importar executa o processo completo de importação
-func (m *MobgranImporter) Importar(url string, atualizarExistente bool) (bool, string, *string, error) {
-	m.logger.WithField("url", url).Info("Iniciando importação")
+// importar executa as etapas da importação. A oferta, os cavaletes, a auditoria e os
+// eventos são gravados em uma única transação; as etapas que dependem de rede (API e
+// imagens) vêm antes e os complementos (fornecedor, catálogo, histórico) depois dela.
+func (m *MobgranImporter) importar(ctx context.Context, url, traderID string, atualizarExistente bool) (bool, string, *string, error) {
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"url":     url,
+		"user_id": traderID,
+	}).Info("Iniciando importação")
 
 	// Validar URL
 	if err := m.ValidarURL(url); err != nil {
 		return false, "URL inválida", nil, err
 	}
 
+	trader, err := uuid.Parse(traderID)
+	if err != nil {
+		return false, "ID do trader inválido", nil, err
+	}
+
 	// Extrair UUID do link
 	uuid, err := m.ExtrairUUIDLink(url)
 	if err != nil {
 		return false, "Erro ao extrair UUID do link", nil, err
 	}
 
-	// Verificar se a oferta já existe
-	ofertaExistente, err := m.dbClient.VerificarOfertaExistente(*uuid)
+	// Verificar se o trader já importou a oferta
+	cliente := m.dbClient.ComContexto(ctx)
+	ofertaExistente, err := cliente.VerificarOfertaExistente(*uuid, traderID)
 	if err != nil {
 		return false, "Erro ao verificar oferta existente", nil, err
 	}
 
+	if ofertaExistente != nil && !atualizarExistente {
+		return false, "Oferta já existe e atualização não foi solicitada", ofertaExistente, nil
+	}
+
 	// Buscar dados da API
-	dados, err := m.BuscarDadosAPI(*uuid)
+	dados, err := m.BuscarDadosAPI(ctx, *uuid)
 	if err != nil {
 		return false, "Erro ao buscar dados da API", nil, err
 	}
 
+	m.registrarEvento(ctx, url, traderID, models.EventoImportacaoProgresso, map[string]interface{}{
+		"etapa":           models.EtapaImportacaoDados,
+		"nome_empresa":    dados.NomeEmpresa,
+		"total_cavaletes": len(dados.Cavaletes),
+	})
+
+	var resumoAnterior map[string]interface{}
+	acao := models.AcaoOfertaImportada
+
+	if ofertaExistente != nil {
+		// Guarda o estado anterior para a auditoria
+		acao = models.AcaoOfertaAtualizada
+		resumoAnterior, err = cliente.BuscarResumoOferta(*ofertaExistente)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Warn("Não foi possível obter o estado anterior da oferta")
+		}
+	}
+
+	// Copiar as imagens para o armazenamento próprio antes de gravar os cavaletes
+	if m.imagens != nil {
+		if falhas := m.imagens.EspelharCavaletes(ctx, dados.Cavaletes); falhas > 0 {
+			logs.Do(ctx).WithFields(logrus.Fields{
+				"url":    url,
+				"falhas": falhas,
+			}).Warn("Algumas imagens não foram espelhadas e continuam apontando para o Mobgran")
+		}
+		m.registrarEvento(ctx, url, traderID, models.EventoImportacaoProgresso, map[string]interface{}{
+			"etapa": models.EtapaImportacaoImagens,
+		})
+	}
+
+	// Gravar tudo em uma transação: uma falha no meio da reimportação não deixa a oferta
+	// sem cavaletes, e os eventos só existem se a importação for confirmada
+	tx, err := cliente.GetDB().BeginTx(ctx, nil)
+	if err != nil {
+		return false, "Erro ao iniciar a gravação da oferta", ofertaExistente, err
+	}
+	defer tx.Rollback()
+	db := cliente.ComTransacao(tx)
+
 	var ofertaID string
+	var aprovadosAntes []aprovadoNaOferta
+	var existentes map[string]string
 
 	if ofertaExistente != nil {
-		// Oferta já existe
-		if !atualizarExistente {
-			return false, "Oferta já existe e atualização não foi solicitada", ofertaExistente, nil
+		// Guardar os produtos aprovados sobre os cavaletes atuais, para avisar a saída
+		// dos que estiverem em cavaletes removidos na origem
+		aprovadosAntes, err = aprovadosDaOferta(ctx, tx, *ofertaExistente)
+		if err != nil {
+			return false, "Erro ao buscar produtos aprovados da oferta", ofertaExistente, err
 		}
 
 		// Atualizar oferta existente
-		if err := m.dbClient.AtualizarOferta(*ofertaExistente, dados); err != nil {
+		if err := db.AtualizarOferta(*ofertaExistente, dados); err != nil {
 			return false, "Erro ao atualizar oferta", ofertaExistente, err
 		}
 
-		// Remover cavaletes e itens antigos
-		if err := m.dbClient.RemoverCavaletesEItens(*ofertaExistente); err != nil {
-			return false, "Erro ao remover cavaletes e itens antigos", ofertaExistente, err
+		// Os cavaletes são identificados entre importações pelo código e atualizados no
+		// lugar, mantendo os produtos aprovados, orçamentos e histórico de preços
+		existentes, err = db.CavaletesDaOferta(*ofertaExistente)
+		if err != nil {
+			return false, "Erro ao buscar cavaletes da oferta", ofertaExistente, err
 		}
 
 		ofertaID = *ofertaExistente
-		m.logger.WithField("oferta_id", ofertaID).Info("Oferta atualizada com sucesso")
 	} else {
 		// Criar nova oferta
-		novoOfertaID, err := m.dbClient.SalvarOferta(*uuid, dados)
+		novoOfertaID, err := db.SalvarOferta(*uuid, traderID, dados)
 		if err != nil {
 			return false, "Erro ao salvar nova oferta", nil, err
 		}
 		ofertaID = *novoOfertaID
-		m.logger.WithField("oferta_id", ofertaID).Info("Nova oferta criada com sucesso")
 	}
 
+	// Identificar a oferta nas linhas de log seguintes, inclusive as do cliente do banco
+	ctx = logs.ComCampos(ctx, logrus.Fields{"oferta_id": ofertaID})
+	db = db.ComContexto(ctx)
+
 	// Salvar cavaletes e itens
-	if err := m.salvarCavaletesEItens(ofertaID, dados.Cavaletes); err != nil {
-		return false, "Erro ao salvar cavaletes e itens", &ofertaID, err
+	ausentes, err := m.salvarCavaletesEItens(ctx, db, ofertaID, dados.Cavaletes, existentes)
+	if err != nil {
+		return false, "Erro ao salvar cavaletes e itens", ofertaExistente, err
+	}
+
+	// Arquivar os cavaletes que não vieram na reimportação
+	removidos, err := db.MarcarCavaletesRemovidosNaOrigem(ausentes)
+	if err != nil {
+		return false, "Erro ao arquivar cavaletes removidos na origem", ofertaExistente, err
+	}
+
+	// Avisar a saída dos produtos aprovados sobre os cavaletes removidos na origem
+	if err := m.registrarRemovidos(ctx, tx, ofertaID, aprovadosAntes, removidos); err != nil {
+		return false, "Erro ao registrar produtos removidos", ofertaExistente, err
+	}
+
+	resumoAtual := map[string]interface{}{
+		"uuid_link":       *uuid,
+		"situacao":        dados.Situacao,
+		"nome_empresa":    dados.NomeEmpresa,
+		"total_cavaletes": len(dados.Cavaletes),
+	}
+	var antes interface{}
+	if resumoAnterior != nil {
+		antes = resumoAnterior
+	}
+	if err := m.auditoria.Registrar(ctx, tx, acao, "oferta", ofertaID, antes, resumoAtual); err != nil {
+		return false, "Erro ao registrar a importação", ofertaExistente, err
+	}
+
+	dadosEvento := map[string]interface{}{
+		"oferta_id":    ofertaID,
+		"url":          url,
+		"reimportacao": ofertaExistente != nil,
+	}
+	for chave, valor := range resumoAtual {
+		dadosEvento[chave] = valor
+	}
+	if err := m.eventos.Registrar(ctx, tx, trader, models.EventoOfertaImportada, "oferta", ofertaID, dadosEvento); err != nil {
+		return false, "Erro ao registrar a importação", ofertaExistente, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Error("Erro ao fazer commit da importação")
+		return false, "Erro ao gravar a oferta", ofertaExistente, err
+	}
+
+	totalItens := 0
+	for _, cavalete := range dados.Cavaletes {
+		totalItens += len(cavalete.Itens)
+	}
+	metricas.ContarInseridos(len(dados.Cavaletes), totalItens)
+
+	if ofertaExistente != nil {
+		logs.Do(ctx).WithField("oferta_id", ofertaID).Info("Oferta atualizada com sucesso")
+	} else {
+		logs.Do(ctx).WithField("oferta_id", ofertaID).Info("Nova oferta criada com sucesso")
+	}
+
+	m.registrarEvento(ctx, url, traderID, models.EventoImportacaoProgresso, map[string]interface{}{
+		"etapa":     models.EtapaImportacaoGravacao,
+		"oferta_id": ofertaID,
+	})
+
+	// Vincular a oferta ao fornecedor pelo nome da empresa; pendências são tratadas
+	// pelo worker de vinculação
+	if m.fornecedores != nil {
+		if err := m.fornecedores.VincularOferta(ctx, ofertaID); err != nil {
+			logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Warn("Oferta importada sem vínculo com o fornecedor")
+		}
+	}
+
+	// Associar materiais e espessuras ao catálogo; falhas não invalidam a importação
+	if m.catalogo != nil {
+		if _, err := m.catalogo.AplicarOferta(ctx, ofertaID); err != nil {
+			logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Warn("Cavaletes importados sem associação ao catálogo")
+		}
+	}
+
+	// Registrar o retrato do estoque para o histórico de alterações entre importações
+	if m.historico != nil {
+		if err := m.historico.RegistrarImportacao(ctx, ofertaID); err != nil {
+			logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Warn("Importação concluída sem registro no histórico de estoque")
+		}
 	}
 
 	return true, "Importação realizada com sucesso", &ofertaID, nil
 }
 
-// salvarCavaletesEItens salva os cavaletes e seus itens
-func (m *MobgranImporter) salvarCavaletesEItens(ofertaID string, cavaletes []models.Cavalete) error {
-	m.logger.WithField("oferta_id", ofertaID).WithField("total_cavaletes", len(cavaletes)).Info("Salvando cavaletes e itens")
-
-	for i, cavalete := range cavaletes {
-		m.logger.WithField("cavalete_index", i).WithField("codigo", cavalete.Codigo).Info("Processando cavalete")
+// aprovadoNaOferta é um produto aprovado sobre um cavalete da oferta, identificado
+// entre importações pelo código do cavalete
+type aprovadoNaOferta struct {
+	Produto models.ProdutoAprovado
+	Codigo  string
+}
 
-		// Salvar cavalete
-		cavaleteID, err := m.dbClient.SalvarCavalete(ofertaID, &cavalete)
-		if err != nil {
-			m.logger.WithError(err).WithField("cavalete_codigo", cavalete.Codigo).Error("Erro ao salvar cavalete")
-			return fmt.Errorf("erro ao salvar cavalete %s: %w", cavalete.Codigo, err)
+// aprovadosDaOferta lista os produtos aprovados sobre os cavaletes da oferta; deve ser
+// chamado antes de a reimportação arquivar os cavaletes removidos na origem (e os produtos
+// sobre eles)
+func aprovadosDaOferta(ctx context.Context, q consultor, ofertaID string) ([]aprovadoNaOferta, error) {
+	rows, err := q.QueryContext(ctx, `
+		SELECT `+colunasProduto+`, codigo
+		FROM (
+			SELECT pa.*, c.codigo
+			FROM produtos_aprovados pa
+			JOIN cavaletes c ON c.id = pa.cavalete_id
+			WHERE c.oferta_id = $1 AND pa.deleted_at IS NULL
+		) aprovados
+	`, ofertaID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("oferta_id", ofertaID).Error("Erro ao buscar produtos aprovados da oferta")
+		return nil, fmt.Errorf("erro ao buscar produtos aprovados da oferta")
+	}
+	defer rows.Close()
+
+	aprovados := []aprovadoNaOferta{}
+	for rows.Next() {
+		var a aprovadoNaOferta
+		if err := escanearProduto(rows, &a.Produto, &a.Codigo); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear produto aprovado da oferta")
+			return nil, fmt.Errorf("erro ao buscar produtos aprovados da oferta")
 		}
+		aprovados = append(aprovados, a)
+	}
 
-		// Salvar itens do cavalete
-		for j, item := range cavalete.Itens {
-			m.logger.WithField("item_index", j).WithField("codigo", item.Codigo).Info("Processando item")
+	return aprovados, rows.Err()
+}
+
+// registrarRemovidos grava, na transação da reimportação, produto.removido para os
+// produtos disponíveis arquivados com os cavaletes removidos na origem e cavalete.removido
+// para os não vendidos. Os reservados continuam ativos, marcados como removidos na origem,
+// e o aviso indica que a reserva precisa ser revista.
+func (m *MobgranImporter) registrarRemovidos(ctx context.Context, tx *sql.Tx, ofertaID string, aprovados []aprovadoNaOferta, removidos []string) error {
+	if len(aprovados) == 0 || len(removidos) == 0 || m.eventos == nil {
+		return nil
+	}
+
+	cavaletesRemovidos := make(map[string]bool, len(removidos))
+	for _, id := range removidos {
+		cavaletesRemovidos[id] = true
+	}
+
+	for i := range aprovados {
+		a := &aprovados[i]
+		if !cavaletesRemovidos[a.Produto.CavaleteID.String()] {
+			continue
+		}
 
-			if err := m.dbClient.SalvarItem(*cavaleteID, &item); err != nil {
-				m.logger.WithError(err).WithField("item_codigo", item.Codigo).Error("Erro ao salvar item")
-				return fmt.Errorf("erro ao salvar item %s do cavalete %s: %w", item.Codigo, cavalete.Codigo, err)
+		if a.Produto.Status == models.StatusProdutoDisponivel {
+			if err := m.eventos.registrarProduto(ctx, tx, models.EventoProdutoRemovido, models.AcaoOfertaAtualizada, &a.Produto); err != nil {
+				return err
 			}
 		}
 
-		m.logger.WithField("cavalete_id", *cavaleteID).WithField("total_itens", len(cavalete.Itens)).Info("Cavalete e itens salvos com sucesso")
+		if a.Produto.Status == models.StatusProdutoVendido {
+			continue
+		}
+		dados := map[string]interface{}{
+			"oferta_id":        ofertaID,
+			"produto_id":       a.Produto.ID,
+			"nome_customizado": a.Produto.NomeCustomizado,
+			"codigo":           a.Codigo,
+			"status":           a.Produto.Status,
+		}
+		if err := m.eventos.Registrar(ctx, tx, a.Produto.TraderID, models.EventoCavaleteRemovido, "produto", a.Produto.ID.String(), dados); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// salvarCavaletesEItens grava os cavaletes e seus itens pelo cliente informado. Os que já
+// existem na oferta (pelo código) são atualizados no lugar e os novos, inseridos; retorna
+// os cavaletes existentes que não vieram na importação.
+func (m *MobgranImporter) salvarCavaletesEItens(ctx context.Context, db *database.Client, ofertaID string, cavaletes []models.Cavalete, existentes map[string]string) ([]string, error) {
+	logs.Do(ctx).WithField("oferta_id", ofertaID).WithField("total_cavaletes", len(cavaletes)).Info("Salvando cavaletes e itens")
+
+	presentes := map[string]bool{}
+	for _, cavalete := range cavaletes {
+		var cavaleteID string
+		if id, ok := existentes[cavalete.Codigo]; ok && !presentes[id] {
+			if err := db.AtualizarCavalete(id, &cavalete); err != nil {
+				logs.Do(ctx).WithError(err).WithField("cavalete_codigo", cavalete.Codigo).Error("Erro ao atualizar cavalete")
+				return nil, fmt.Errorf("erro ao atualizar cavalete %s: %w", cavalete.Codigo, err)
+			}
+			// Os itens não têm vínculos próprios e são regravados por inteiro
+			if err := db.RemoverItens(id); err != nil {
+				return nil, fmt.Errorf("erro ao remover itens do cavalete %s: %w", cavalete.Codigo, err)
+			}
+			cavaleteID = id
+		} else {
+			id, err := db.SalvarCavalete(ofertaID, &cavalete)
+			if err != nil {
+				logs.Do(ctx).WithError(err).WithField("cavalete_codigo", cavalete.Codigo).Error("Erro ao salvar cavalete")
+				return nil, fmt.Errorf("erro ao salvar cavalete %s: %w", cavalete.Codigo, err)
+			}
+			cavaleteID = *id
+		}
+		presentes[cavaleteID] = true
+
+		// Salvar itens do cavalete
+		for _, item := range cavalete.Itens {
+			if err := db.SalvarItem(cavaleteID, &item); err != nil {
+				logs.Do(ctx).WithError(err).WithField("item_codigo", item.Codigo).Error("Erro ao salvar item")
+				return nil, fmt.Errorf("erro ao salvar item %s do cavalete %s: %w", item.Codigo, cavalete.Codigo, err)
+			}
+		}
+
+		logs.Do(ctx).WithFields(logrus.Fields{
+			"cavalete_id":     cavaleteID,
+			"cavalete_codigo": cavalete.Codigo,
+			"total_itens":     len(cavalete.Itens),
+		}).Debug("Cavalete e itens salvos")
+	}
+
+	ausentes := []string{}
+	for _, id := range existentes {
+		if !presentes[id] {
+			ausentes = append(ausentes, id)
+		}
+	}
+	return ausentes, nil
+}
+
 // ValidarURL valida se a URL é um link válido do Mobgran
 func (m *MobgranImporter) ValidarURL(url string) error {
 	if url == "" {
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/imagens"
+	"mobgran-importer-go/pkg/storage"
+)
+
+// tiposLogo são os formatos aceitos para o logotipo; precisam ser decodificáveis para
+// entrar nos PDFs
+var tiposLogo = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+// colunasMarca são as colunas lidas por escanearMarca
+const colunasMarca = `id, nome, empresa, documento, email, telefone, endereco, site, logo_url`
+
+// escanearMarca lê uma linha com as colunas de colunasMarca
+func escanearMarca(row interface{ Scan(...interface{}) error }, m *models.MarcaTrader) error {
+	return row.Scan(&m.ID, &m.Nome, &m.Empresa, &m.Documento, &m.Email, &m.Telefone, &m.Endereco, &m.Site, &m.LogoURL)
+}
+
+// MarcaService gerencia os dados da empresa e o logotipo que identificam o trader nos PDFs
+type MarcaService struct {
+	db        *sql.DB
+	store     storage.BlobStore
+	auditoria *AuditoriaService
+}
+
+// NewMarcaService cria uma nova instância do MarcaService
+func NewMarcaService(db *sql.DB, store storage.BlobStore, auditoria *AuditoriaService) *MarcaService {
+	return &MarcaService{db: db, store: store, auditoria: auditoria}
+}
+
+// Buscar retorna os dados de marca do trader
+func (s *MarcaService) Buscar(ctx context.Context, traderID uuid.UUID) (*models.MarcaTrader, error) {
+	var marca models.MarcaTrader
+	err := escanearMarca(s.db.QueryRowContext(ctx, `
+		SELECT `+colunasMarca+` FROM traders WHERE id = $1
+	`, traderID), &marca)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trader não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar dados de marca do trader")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	return &marca, nil
+}
+
+// Atualizar altera os dados da empresa do trader. Campos enviados vazios são apagados.
+func (s *MarcaService) Atualizar(ctx context.Context, traderID uuid.UUID, request *models.AtualizarMarcaRequest) (*models.MarcaTrader, error) {
+	setParts := []string{}
+	args := []interface{}{}
+
+	adicionar := func(coluna string, valor *string) {
+		if valor == nil {
+			return
+		}
+		args = append(args, strings.TrimSpace(*valor))
+		setParts = append(setParts, fmt.Sprintf("%s = NULLIF($%d, '')", coluna, len(args)))
+	}
+	adicionar("empresa", request.Empresa)
+	adicionar("documento", request.Documento)
+	adicionar("telefone", request.Telefone)
+	adicionar("endereco", request.Endereco)
+	adicionar("site", request.Site)
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	return s.alterar(ctx, traderID, strings.Join(setParts, ", "), args...)
+}
+
+// AtualizarLogo substitui o logotipo do trader. O arquivo anterior é removido do
+// armazenamento depois que o novo é registrado.
+func (s *MarcaService) AtualizarLogo(ctx context.Context, traderID uuid.UUID, arquivo models.ArquivoImagem) (*models.MarcaTrader, error) {
+	if len(arquivo.Dados) == 0 {
+		return nil, fmt.Errorf("imagem inválida: arquivo %q vazio", arquivo.Nome)
+	}
+	if len(arquivo.Dados) > models.TamanhoMaximoLogo {
+		return nil, fmt.Errorf("imagem inválida: arquivo %q excede %d MB", arquivo.Nome, models.TamanhoMaximoLogo>>20)
+	}
+
+	contentType := http.DetectContentType(arquivo.Dados)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	if !tiposLogo[contentType] {
+		return nil, fmt.Errorf("imagem inválida: o logotipo deve ser JPEG ou PNG")
+	}
+	if _, _, err := imagens.Dimensoes(arquivo.Dados); errors.Is(err, imagens.ErrImagemGrande) {
+		return nil, fmt.Errorf("imagem inválida: arquivo %q excede %d megapixels", arquivo.Nome, imagens.LimitePixels/1_000_000)
+	}
+
+	anterior, err := s.chaveLogo(ctx, traderID)
+	if err != nil {
+		return nil, err
+	}
+
+	chave := fmt.Sprintf("traders/%s/logo-%s.%s", traderID, uuid.New(), extensoesImagem[contentType])
+	if err := s.store.Salvar(ctx, chave, arquivo.Dados, contentType); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao armazenar logotipo")
+		return nil, fmt.Errorf("erro ao armazenar imagem")
+	}
+
+	marca, err := s.alterar(ctx, traderID, "logo_chave = $1, logo_url = $2", chave, s.store.URL(chave))
+	if err != nil {
+		s.removerArquivo(ctx, chave)
+		return nil, err
+	}
+
+	if anterior != nil {
+		s.removerArquivo(ctx, *anterior)
+	}
+
+	return marca, nil
+}
+
+// RemoverLogo apaga o logotipo do trader
+func (s *MarcaService) RemoverLogo(ctx context.Context, traderID uuid.UUID) (*models.MarcaTrader, error) {
+	anterior, err := s.chaveLogo(ctx, traderID)
+	if err != nil {
+		return nil, err
+	}
+	if anterior == nil {
+		return nil, fmt.Errorf("logotipo não encontrado")
+	}
+
+	marca, err := s.alterar(ctx, traderID, "logo_chave = NULL, logo_url = NULL")
+	if err != nil {
+		return nil, err
+	}
+	s.removerArquivo(ctx, *anterior)
+
+	return marca, nil
+}
+
+// alterar aplica as alterações nos dados de marca do trader, auditando o estado
+// anterior e o novo. Os argumentos começam em $1; o ID do trader vem por último.
+func (s *MarcaService) alterar(ctx context.Context, traderID uuid.UUID, set string, args ...interface{}) (*models.MarcaTrader, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	var antes models.MarcaTrader
+	err = escanearMarca(tx.QueryRowContext(ctx, `
+		SELECT `+colunasMarca+` FROM traders WHERE id = $1 FOR UPDATE
+	`, traderID), &antes)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trader não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar dados de marca do trader")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	args = append(args, traderID)
+	var depois models.MarcaTrader
+	err = escanearMarca(tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE traders SET %s, updated_at = NOW()
+		WHERE id = $%d
+		RETURNING `+colunasMarca, set, len(args)), args...), &depois)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar dados de marca do trader")
+		return nil, fmt.Errorf("erro ao atualizar dados de marca")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoMarcaAtualizada, "trader", traderID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit dos dados de marca")
+		return nil, fmt.Errorf("erro ao atualizar dados de marca")
+	}
+
+	logs.Do(ctx).WithField("trader_id", traderID).Info("Dados de marca do trader atualizados")
+
+	return &depois, nil
+}
+
+// chaveLogo retorna a chave do logotipo atual do trader, ou nil quando não há
+func (s *MarcaService) chaveLogo(ctx context.Context, traderID uuid.UUID) (*string, error) {
+	var chave *string
+	err := s.db.QueryRowContext(ctx, `SELECT logo_chave FROM traders WHERE id = $1`, traderID).Scan(&chave)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trader não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar logotipo do trader")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	return chave, nil
+}
+
+// removerArquivo apaga um arquivo do armazenamento, registrando a falha sem interromper
+func (s *MarcaService) removerArquivo(ctx context.Context, chave string) {
+	if err := s.store.Remover(context.Background(), chave); err != nil {
+		logs.Do(ctx).WithError(err).WithField("chave", chave).Warn("Não foi possível remover arquivo do armazenamento")
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/medidas"
+)
+
+// loteNormalizacao é a quantidade de cavaletes normalizados por ciclo do worker
+const loteNormalizacao = 500
+
+// MedidasService normaliza as medidas dos cavaletes importados antes da normalização
+// passar a ser feita na própria importação
+type MedidasService struct {
+	db *sql.DB
+}
+
+func NewMedidasService(db *sql.DB) *MedidasService {
+	return &MedidasService{
+		db: db,
+	}
+}
+
+// NormalizarPendentes calcula as medidas normalizadas de até limite cavaletes ainda
+// pendentes e dos seus itens. Um cavalete que falha é marcado com o erro em medidas_erro
+// e não interrompe o lote. Para recalcular cavaletes já normalizados (por exemplo, após
+// ajustar as regras de pkg/medidas), basta zerar medidas_normalizadas_em.
+func (s *MedidasService) NormalizarPendentes(ctx context.Context, limite int) (*models.NormalizacaoMedidasResultado, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT id, nome_espessura, comprimento, altura, metragem, peso, COALESCE(quantidade_itens, 0)
+		FROM cavaletes
+		WHERE medidas_normalizadas_em IS NULL
+		ORDER BY created_at
+		LIMIT $1
+	`, limite)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar cavaletes com medidas pendentes")
+		return nil, fmt.Errorf("erro ao buscar medidas pendentes")
+	}
+
+	type pendente struct {
+		id      string
+		entrada medidas.Entrada
+	}
+	var pendentes []pendente
+	for rows.Next() {
+		var p pendente
+		var espessura sql.NullString
+		var comprimento, altura, metragem, peso sql.NullFloat64
+		if err := rows.Scan(&p.id, &espessura, &comprimento, &altura, &metragem, &peso, &p.entrada.Pecas); err != nil {
+			rows.Close()
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear cavalete com medidas pendentes")
+			return nil, fmt.Errorf("erro ao buscar medidas pendentes")
+		}
+		p.entrada.Espessura = espessura.String
+		p.entrada.Comprimento, p.entrada.Altura = comprimento.Float64, altura.Float64
+		p.entrada.Metragem, p.entrada.Peso = metragem.Float64, peso.Float64
+		pendentes = append(pendentes, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao ler cavaletes com medidas pendentes")
+		return nil, fmt.Errorf("erro ao buscar medidas pendentes")
+	}
+
+	resultado := &models.NormalizacaoMedidasResultado{}
+	for _, p := range pendentes {
+		if ctx.Err() != nil {
+			return resultado, ctx.Err()
+		}
+
+		itens, err := s.normalizarCavalete(ctx, p.id, p.entrada, resultado)
+		if err != nil {
+			logs.Do(ctx).WithError(err).WithField("cavalete_id", p.id).Warn("Erro ao normalizar medidas do cavalete")
+			if err := s.marcarFalha(ctx, p.id, err); err != nil {
+				logs.Do(ctx).WithError(err).WithField("cavalete_id", p.id).Error("Erro ao marcar falha na normalização de medidas")
+				return resultado, fmt.Errorf("erro ao normalizar medidas")
+			}
+			resultado.Falhas++
+			continue
+		}
+		resultado.Cavaletes++
+		resultado.Itens += itens
+	}
+
+	return resultado, nil
+}
+
+// marcarFalha registra o erro da normalização do cavalete, tirando-o das pendências
+func (s *MedidasService) marcarFalha(ctx context.Context, cavaleteID string, falha error) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE cavaletes SET medidas_normalizadas_em = NOW(), medidas_erro = $2 WHERE id = $1
+	`, cavaleteID, falha.Error())
+	return err
+}
+
+// ExecutarNormalizacao normaliza em lotes todos os cavaletes pendentes e termina quando
+// não houver mais pendências ou o contexto for cancelado
+func (s *MedidasService) ExecutarNormalizacao(ctx context.Context) {
+	total, falhas := 0, 0
+	for ctx.Err() == nil {
+		resultado, err := s.NormalizarPendentes(ctx, loteNormalizacao)
+		if err != nil {
+			if ctx.Err() == nil {
+				logs.Do(ctx).WithError(err).Warn("Normalização de medidas interrompida")
+			}
+			return
+		}
+		total += resultado.Cavaletes
+		falhas += resultado.Falhas
+		if resultado.Cavaletes+resultado.Falhas < loteNormalizacao {
+			break
+		}
+	}
+
+	if total > 0 {
+		logs.Do(ctx).WithField("cavaletes", total).Info("Medidas dos cavaletes importados normalizadas")
+	}
+	if falhas > 0 {
+		logs.Do(ctx).WithField("cavaletes", falhas).Warn("Cavaletes com falha na normalização de medidas (veja cavaletes.medidas_erro)")
+	}
+}
+
+// normalizarCavalete grava as medidas do cavalete e dos seus itens em uma transação,
+// retornando quantos itens foram normalizados
+func (s *MedidasService) normalizarCavalete(ctx context.Context, cavaleteID string, entrada medidas.Entrada, resultado *models.NormalizacaoMedidasResultado) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	r := medidas.Normalizar(entrada)
+	if r.EspessuraMM == nil {
+		resultado.SemEspessura++
+	}
+	if r.MetragemM2 == nil {
+		resultado.SemMetragem++
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE cavaletes
+		SET tipo_metragem = $2, espessura_mm = $3, comprimento_m = $4, altura_m = $5,
+			metragem_m2 = $6, peso_kg = $7, unidade_dimensoes = $8, medidas_normalizadas_em = NOW(),
+			medidas_erro = NULL
+		WHERE id = $1
+	`, cavaleteID, r.TipoMetragem, r.EspessuraMM, r.ComprimentoM, r.AlturaM, r.MetragemM2, r.PesoKg, r.UnidadeDimensoes)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, nome_espessura, comprimento, altura, metragem, peso
+		FROM itens
+		WHERE cavalete_id = $1
+	`, cavaleteID)
+	if err != nil {
+		return 0, err
+	}
+
+	type item struct {
+		id string
+		r  medidas.Resultado
+	}
+	var itens []item
+	for rows.Next() {
+		var i item
+		var e medidas.Entrada
+		var espessura sql.NullString
+		var comprimento, altura, metragem, peso sql.NullFloat64
+		if err := rows.Scan(&i.id, &espessura, &comprimento, &altura, &metragem, &peso); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		e.Espessura = espessura.String
+		e.Comprimento, e.Altura, e.Metragem, e.Peso = comprimento.Float64, altura.Float64, metragem.Float64, peso.Float64
+		i.r = medidas.Normalizar(e)
+		itens = append(itens, i)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, i := range itens {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE itens
+			SET tipo_metragem = $2, espessura_mm = $3, comprimento_m = $4, altura_m = $5,
+				metragem_m2 = $6, peso_kg = $7, unidade_dimensoes = $8
+			WHERE id = $1
+		`, i.id, i.r.TipoMetragem, i.r.EspessuraMM, i.r.ComprimentoM, i.r.AlturaM, i.r.MetragemM2, i.r.PesoKg, i.r.UnidadeDimensoes)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(itens), tx.Commit()
+}
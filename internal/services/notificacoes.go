@@ -0,0 +1,929 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+	"mobgran-importer-go/pkg/notificacao"
+)
+
+const (
+	// loteEntregas é a quantidade de notificações entregues por ciclo do worker
+	loteEntregas = 50
+	// maxTentativasNotificacao é o total de tentativas antes de a notificação falhar
+	maxTentativasNotificacao = 6
+	// reservaEntrega é por quanto tempo uma notificação em entrega fica reservada para a
+	// instância que a pegou; se ela cair, outra instância tenta de novo depois disso
+	reservaEntrega = 5 * time.Minute
+)
+
+// NotificacoesService mantém as assinaturas de notificação dos traders, enfileira os
+// eventos e os entrega por webhook ou e-mail com novas tentativas espaçadas
+type NotificacoesService struct {
+	db           *sql.DB
+	auditoria    *AuditoriaService
+	webhook      *notificacao.Webhook
+	smtp         *notificacao.SMTP
+	avisoReserva time.Duration
+	acordar      chan struct{}
+}
+
+// NewNotificacoesService cria uma nova instância do NotificacoesService. Com smtp não
+// configurado, assinaturas por e-mail são recusadas; avisoReserva é a antecedência do
+// aviso de reserva perto de vencer.
+func NewNotificacoesService(db *sql.DB, auditoria *AuditoriaService, webhook *notificacao.Webhook, smtp *notificacao.SMTP, avisoReserva time.Duration) *NotificacoesService {
+	return &NotificacoesService{
+		db:           db,
+		auditoria:    auditoria,
+		webhook:      webhook,
+		smtp:         smtp,
+		avisoReserva: avisoReserva,
+		acordar:      make(chan struct{}, 1),
+	}
+}
+
+const colunasAssinatura = `a.id, a.trader_id, a.canal, a.destino, a.eventos, a.ativa, a.created_at, a.updated_at`
+
+func escanearAssinatura(row interface{ Scan(...interface{}) error }, a *models.AssinaturaNotificacao) error {
+	return row.Scan(&a.ID, &a.TraderID, &a.Canal, &a.Destino, pq.Array(&a.Eventos), &a.Ativa, &a.CreatedAt, &a.UpdatedAt)
+}
+
+// ListarAssinaturas lista as assinaturas do trader
+func (s *NotificacoesService) ListarAssinaturas(ctx context.Context, traderID uuid.UUID) ([]models.AssinaturaNotificacao, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+colunasAssinatura+`
+		FROM assinaturas_notificacao a
+		WHERE a.trader_id = $1
+		ORDER BY a.created_at, a.id
+	`, traderID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar assinaturas de notificação")
+		return nil, fmt.Errorf("erro ao buscar assinaturas")
+	}
+	defer rows.Close()
+
+	assinaturas := []models.AssinaturaNotificacao{}
+	for rows.Next() {
+		var a models.AssinaturaNotificacao
+		if err := escanearAssinatura(rows, &a); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear assinatura de notificação")
+			continue
+		}
+		assinaturas = append(assinaturas, a)
+	}
+
+	return assinaturas, nil
+}
+
+// buscarAssinatura lê a assinatura do trader
+func (s *NotificacoesService) buscarAssinatura(ctx context.Context, q consultor, traderID, assinaturaID uuid.UUID) (*models.AssinaturaNotificacao, error) {
+	var a models.AssinaturaNotificacao
+	err := escanearAssinatura(q.QueryRowContext(ctx, `
+		SELECT `+colunasAssinatura+`
+		FROM assinaturas_notificacao a
+		WHERE a.id = $1 AND a.trader_id = $2
+	`, assinaturaID, traderID), &a)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("assinatura não encontrada")
+	}
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar assinatura de notificação")
+		return nil, fmt.Errorf("erro ao buscar assinatura")
+	}
+	return &a, nil
+}
+
+// CriarAssinatura cadastra o destino do trader para os eventos informados. Webhooks
+// recebem um segredo de assinatura, retornado apenas nesta resposta.
+func (s *NotificacoesService) CriarAssinatura(ctx context.Context, traderID uuid.UUID, request *models.AssinaturaCriarRequest) (*models.AssinaturaNotificacao, error) {
+	canal := strings.ToLower(strings.TrimSpace(request.Canal))
+	destino, err := s.validarDestino(ctx, canal, request.Destino)
+	if err != nil {
+		return nil, err
+	}
+	eventos, err := validarEventos(request.Eventos)
+	if err != nil {
+		return nil, err
+	}
+
+	var segredo *string
+	if canal == models.CanalWebhook {
+		novo, err := gerarSegredoWebhook(ctx)
+		if err != nil {
+			return nil, err
+		}
+		segredo = &novo
+	}
+
+	ativa := true
+	if request.Ativa != nil {
+		ativa = *request.Ativa
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	var a models.AssinaturaNotificacao
+	err = escanearAssinatura(tx.QueryRowContext(ctx, `
+		INSERT INTO assinaturas_notificacao AS a (trader_id, canal, destino, segredo, eventos, ativa)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING `+colunasAssinatura+`
+	`, traderID, canal, destino, segredo, pq.Array(eventos), ativa), &a)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao cadastrar assinatura de notificação")
+		return nil, fmt.Errorf("erro ao cadastrar assinatura")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoAssinaturaCriada, "assinatura_notificacao", a.ID.String(), nil, a); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da assinatura de notificação")
+		return nil, fmt.Errorf("erro ao cadastrar assinatura")
+	}
+
+	a.Segredo = segredo
+	return &a, nil
+}
+
+// AtualizarAssinatura altera destino, eventos e ativação da assinatura; com
+// RegenerarSegredo, o novo segredo do webhook é retornado na resposta
+func (s *NotificacoesService) AtualizarAssinatura(ctx context.Context, traderID, assinaturaID uuid.UUID, request *models.AssinaturaAtualizarRequest) (*models.AssinaturaNotificacao, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscarAssinatura(ctx, tx, traderID, assinaturaID)
+	if err != nil {
+		return nil, err
+	}
+
+	setParts := []string{}
+	args := []interface{}{assinaturaID}
+
+	if request.Destino != nil {
+		destino, err := s.validarDestino(ctx, antes.Canal, *request.Destino)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, destino)
+		setParts = append(setParts, fmt.Sprintf("destino = $%d", len(args)))
+	}
+	if request.Eventos != nil {
+		eventos, err := validarEventos(request.Eventos)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, pq.Array(eventos))
+		setParts = append(setParts, fmt.Sprintf("eventos = $%d", len(args)))
+	}
+	if request.Ativa != nil {
+		args = append(args, *request.Ativa)
+		setParts = append(setParts, fmt.Sprintf("ativa = $%d", len(args)))
+	}
+
+	var segredo *string
+	if request.RegenerarSegredo {
+		if antes.Canal != models.CanalWebhook {
+			return nil, fmt.Errorf("apenas assinaturas de webhook têm segredo")
+		}
+		novo, err := gerarSegredoWebhook(ctx)
+		if err != nil {
+			return nil, err
+		}
+		segredo = &novo
+		args = append(args, novo)
+		setParts = append(setParts, fmt.Sprintf("segredo = $%d", len(args)))
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	var depois models.AssinaturaNotificacao
+	err = escanearAssinatura(tx.QueryRowContext(ctx, fmt.Sprintf(`
+		UPDATE assinaturas_notificacao a SET %s
+		WHERE a.id = $1
+		RETURNING `+colunasAssinatura, strings.Join(setParts, ", ")), args...), &depois)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar assinatura de notificação")
+		return nil, fmt.Errorf("erro ao atualizar assinatura")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoAssinaturaEditada, "assinatura_notificacao", assinaturaID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da assinatura de notificação")
+		return nil, fmt.Errorf("erro ao atualizar assinatura")
+	}
+
+	depois.Segredo = segredo
+	return &depois, nil
+}
+
+// RemoverAssinatura exclui a assinatura e o registro das suas notificações
+func (s *NotificacoesService) RemoverAssinatura(ctx context.Context, traderID, assinaturaID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscarAssinatura(ctx, tx, traderID, assinaturaID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM assinaturas_notificacao WHERE id = $1`, assinaturaID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover assinatura de notificação")
+		return fmt.Errorf("erro ao remover assinatura")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoAssinaturaRemovida, "assinatura_notificacao", assinaturaID.String(), antes, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção da assinatura")
+		return fmt.Errorf("erro ao remover assinatura")
+	}
+
+	return nil
+}
+
+// TestarAssinatura enfileira um evento de teste apenas para a assinatura informada,
+// mesmo inativa, para validar o receptor
+func (s *NotificacoesService) TestarAssinatura(ctx context.Context, traderID, assinaturaID uuid.UUID) (*models.Notificacao, error) {
+	a, err := s.buscarAssinatura(ctx, s.db, traderID, assinaturaID)
+	if err != nil {
+		return nil, err
+	}
+
+	dados, _ := json.Marshal(map[string]interface{}{
+		"mensagem": "Notificação de teste",
+		"canal":    a.Canal,
+	})
+
+	var notificacaoID uuid.UUID
+	err = s.db.QueryRowContext(ctx, `
+		INSERT INTO notificacoes (assinatura_id, trader_id, evento, chave, dados)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id
+	`, a.ID, traderID, models.EventoTeste, models.EventoTeste+":"+uuid.NewString(), string(dados)).Scan(&notificacaoID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao enfileirar notificação de teste")
+		return nil, fmt.Errorf("erro ao enfileirar notificação de teste")
+	}
+
+	s.despertar()
+	return s.BuscarNotificacao(ctx, traderID, notificacaoID)
+}
+
+// validarDestino confere a URL do webhook (http ou https, fora da rede interna) ou o
+// e-mail e retorna o destino normalizado
+func (s *NotificacoesService) validarDestino(ctx context.Context, canal, destino string) (string, error) {
+	destino = strings.TrimSpace(destino)
+
+	switch canal {
+	case models.CanalWebhook:
+		return s.webhook.ValidarDestino(ctx, destino)
+	case models.CanalEmail:
+		if !s.smtp.Configurado() {
+			return "", fmt.Errorf("envio de e-mail não configurado no servidor")
+		}
+		endereco, err := mail.ParseAddress(destino)
+		if err != nil {
+			return "", fmt.Errorf("e-mail inválido")
+		}
+		return endereco.Address, nil
+	default:
+		return "", fmt.Errorf("canal inválido: use webhook ou email")
+	}
+}
+
+// validarEventos confere e remove repetições da lista de eventos assinados
+func validarEventos(eventos []string) ([]string, error) {
+	vistos := map[string]bool{}
+	validos := []string{}
+	for _, evento := range eventos {
+		evento = strings.TrimSpace(evento)
+		if _, ok := models.EventosNotificacao[evento]; !ok {
+			return nil, fmt.Errorf("evento inválido: %s", evento)
+		}
+		if !vistos[evento] {
+			vistos[evento] = true
+			validos = append(validos, evento)
+		}
+	}
+	if len(validos) == 0 {
+		return nil, fmt.Errorf("informe ao menos um evento")
+	}
+	return validos, nil
+}
+
+// gerarSegredoWebhook gera o segredo aleatório do HMAC dos webhooks
+func gerarSegredoWebhook(ctx context.Context) (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao gerar segredo do webhook")
+		return "", fmt.Errorf("erro interno do servidor")
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// Publicar enfileira o evento para as assinaturas ativas do trader que o escolheram.
+// chave identifica o acontecimento: publicar de novo a mesma chave não gera outra
+// notificação; vazia, cada publicação é uma notificação nova. Com exec sendo uma
+// transação, a notificação só existe se a alteração que a originou for confirmada.
+func (s *NotificacoesService) Publicar(ctx context.Context, exec executor, traderID uuid.UUID, evento, chave string, dados interface{}) error {
+	if exec == nil {
+		exec = s.db
+	}
+
+	corpo, err := json.Marshal(dados)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("evento", evento).Error("Erro ao serializar dados da notificação")
+		return fmt.Errorf("erro ao publicar notificação")
+	}
+	if chave == "" {
+		chave = evento + ":" + uuid.NewString()
+	}
+
+	result, err := exec.ExecContext(ctx, `
+		INSERT INTO notificacoes (assinatura_id, trader_id, evento, chave, dados)
+		SELECT a.id, a.trader_id, $2::text, $3::text, $4::jsonb
+		FROM assinaturas_notificacao a
+		WHERE a.trader_id = $1 AND a.ativa AND $2::text = ANY(a.eventos)
+		ON CONFLICT (assinatura_id, chave) DO NOTHING
+	`, traderID, evento, chave, string(corpo))
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("evento", evento).Error("Erro ao enfileirar notificação")
+		return fmt.Errorf("erro ao publicar notificação")
+	}
+
+	if afetadas, _ := result.RowsAffected(); afetadas > 0 {
+		s.despertar()
+	}
+	return nil
+}
+
+// despertar antecipa o próximo ciclo do worker de entregas
+func (s *NotificacoesService) despertar() {
+	select {
+	case s.acordar <- struct{}{}:
+	default:
+	}
+}
+
+// AvisarReservasExpirando publica reserva.expirando para as reservas que vencem dentro
+// da antecedência configurada; cada reserva (produto e vencimento) é avisada uma vez
+func (s *NotificacoesService) AvisarReservasExpirando(ctx context.Context) (int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT pa.id, pa.trader_id, pa.nome_customizado, pa.cliente, pa.reserva_expira_em
+		FROM produtos_aprovados pa
+		WHERE pa.status = 'reservado' AND pa.deleted_at IS NULL
+			AND pa.reserva_expira_em > NOW() AND pa.reserva_expira_em <= NOW() + $1 * INTERVAL '1 second'
+			AND EXISTS (
+				SELECT 1 FROM assinaturas_notificacao a
+				WHERE a.trader_id = pa.trader_id AND a.ativa AND $2 = ANY(a.eventos)
+			)
+	`, int64(s.avisoReserva.Seconds()), models.EventoReservaExpirando)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar reservas perto de vencer")
+		return 0, fmt.Errorf("erro ao avisar reservas")
+	}
+
+	type reserva struct {
+		produtoID uuid.UUID
+		traderID  uuid.UUID
+		nome      string
+		cliente   *string
+		expiraEm  time.Time
+	}
+
+	reservas := []reserva{}
+	for rows.Next() {
+		var r reserva
+		if err := rows.Scan(&r.produtoID, &r.traderID, &r.nome, &r.cliente, &r.expiraEm); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear reserva perto de vencer")
+			continue
+		}
+		reservas = append(reservas, r)
+	}
+	rows.Close()
+
+	for _, r := range reservas {
+		dados := map[string]interface{}{
+			"produto_id":        r.produtoID,
+			"nome_customizado":  r.nome,
+			"cliente":           r.cliente,
+			"reserva_expira_em": r.expiraEm,
+		}
+		chave := fmt.Sprintf("%s:%s:%d", models.EventoReservaExpirando, r.produtoID, r.expiraEm.Unix())
+		if err := s.Publicar(ctx, nil, r.traderID, models.EventoReservaExpirando, chave, dados); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(reservas), nil
+}
+
+// notificacaoEmEntrega é uma notificação reservada pelo worker para entrega
+type notificacaoEmEntrega struct {
+	id             uuid.UUID
+	evento         string
+	dados          json.RawMessage
+	tentativa      int
+	tentativasBase int // tentativas anteriores ao último reenvio manual
+	createdAt      time.Time
+	canal          string
+	destino        string
+	segredo        *string
+}
+
+// EntregarPendentes entrega as notificações pendentes cujo horário de tentativa chegou.
+// Cada notificação é reservada antes do envio, então várias instâncias da API podem
+// executá-lo ao mesmo tempo sem entregas duplicadas.
+func (s *NotificacoesService) EntregarPendentes(ctx context.Context, limite int) (int, error) {
+	rows, err := s.db.QueryContext(ctx, `
+		UPDATE notificacoes n
+		SET tentativas = n.tentativas + 1,
+			proxima_tentativa = NOW() + $2 * INTERVAL '1 second',
+			updated_at = NOW()
+		FROM (
+			SELECT pn.id
+			FROM notificacoes pn
+			JOIN assinaturas_notificacao pa ON pa.id = pn.assinatura_id
+			WHERE pn.status = 'pendente' AND pn.proxima_tentativa <= NOW()
+				AND (pa.ativa OR pn.evento = $3)
+			ORDER BY pn.proxima_tentativa
+			LIMIT $1
+			FOR UPDATE OF pn SKIP LOCKED
+		) pendente, assinaturas_notificacao a
+		WHERE n.id = pendente.id AND a.id = n.assinatura_id
+		RETURNING n.id, n.evento, n.dados, n.tentativas, n.tentativas_base, n.created_at, a.canal, a.destino, a.segredo
+	`, limite, int64(reservaEntrega.Seconds()), models.EventoTeste)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao reservar notificações pendentes")
+		return 0, fmt.Errorf("erro ao entregar notificações")
+	}
+
+	lote := []notificacaoEmEntrega{}
+	for rows.Next() {
+		var n notificacaoEmEntrega
+		if err := rows.Scan(&n.id, &n.evento, &n.dados, &n.tentativa, &n.tentativasBase, &n.createdAt, &n.canal, &n.destino, &n.segredo); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear notificação pendente")
+			continue
+		}
+		lote = append(lote, n)
+	}
+	rows.Close()
+
+	entregues := 0
+	for _, n := range lote {
+		inicio := time.Now()
+		statusHTTP, errEnvio := s.enviar(ctx, n)
+		if ctx.Err() != nil {
+			// Encerramento: a reserva expira e a notificação volta para a fila
+			return entregues, nil
+		}
+		if err := s.registrarEntrega(ctx, n, statusHTTP, errEnvio, time.Since(inicio)); err != nil {
+			return entregues, err
+		}
+		if errEnvio == nil {
+			entregues++
+		}
+	}
+
+	return entregues, nil
+}
+
+// enviar entrega a notificação pelo canal da assinatura
+func (s *NotificacoesService) enviar(ctx context.Context, n notificacaoEmEntrega) (int, error) {
+	switch n.canal {
+	case models.CanalWebhook:
+		corpo, err := json.Marshal(map[string]interface{}{
+			"id":        n.id,
+			"evento":    n.evento,
+			"criado_em": n.createdAt,
+			"dados":     n.dados,
+		})
+		if err != nil {
+			return 0, err
+		}
+		segredo := ""
+		if n.segredo != nil {
+			segredo = *n.segredo
+		}
+		return s.webhook.Enviar(ctx, n.destino, segredo, n.evento, n.id.String(), corpo)
+	case models.CanalEmail:
+		assunto, corpo := montarEmail(n)
+		return 0, s.smtp.Enviar(ctx, n.destino, assunto, corpo)
+	default:
+		return 0, fmt.Errorf("canal desconhecido: %s", n.canal)
+	}
+}
+
+// montarEmail gera o assunto e o texto do e-mail da notificação
+func montarEmail(n notificacaoEmEntrega) (string, string) {
+	titulo, ok := models.EventosNotificacao[n.evento]
+	if !ok {
+		titulo = "Notificação de teste"
+	}
+
+	var dados map[string]interface{}
+	json.Unmarshal(n.dados, &dados)
+	detalhes, _ := json.MarshalIndent(dados, "", "  ")
+
+	corpo := fmt.Sprintf("%s\n\nEvento: %s\nData: %s\n\n%s\n",
+		titulo, n.evento, n.createdAt.Format("02/01/2006 15:04:05 -07:00"), detalhes)
+	return "[Mobgran] " + titulo, corpo
+}
+
+// registrarEntrega grava a tentativa e agenda a próxima (30s, 1min, 2min, 4min, 8min)
+// ou encerra a notificação como entregue ou falha. Depois de um reenvio manual, a
+// contagem recomeça.
+func (s *NotificacoesService) registrarEntrega(ctx context.Context, n notificacaoEmEntrega, statusHTTP int, errEnvio error, duracao time.Duration) error {
+	var status *int
+	if statusHTTP > 0 {
+		status = &statusHTTP
+	}
+	var mensagemErro *string
+	if errEnvio != nil {
+		m := errEnvio.Error()
+		mensagemErro = &m
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro ao registrar entrega")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO entregas_notificacao (notificacao_id, tentativa, sucesso, status_http, erro, duracao_ms)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`, n.id, n.tentativa, errEnvio == nil, status, mensagemErro, duracao.Milliseconds())
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao registrar tentativa de entrega")
+		return fmt.Errorf("erro ao registrar entrega")
+	}
+
+	switch {
+	case errEnvio == nil:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE notificacoes SET status = 'entregue', entregue_em = NOW(), proxima_tentativa = NULL,
+				ultimo_erro = NULL, updated_at = NOW()
+			WHERE id = $1
+		`, n.id)
+	case n.tentativa-n.tentativasBase >= maxTentativasNotificacao:
+		_, err = tx.ExecContext(ctx, `
+			UPDATE notificacoes SET status = 'falhou', proxima_tentativa = NULL, ultimo_erro = $2, updated_at = NOW()
+			WHERE id = $1
+		`, n.id, mensagemErro)
+	default:
+		espera := 30 * time.Second << (n.tentativa - n.tentativasBase - 1)
+		_, err = tx.ExecContext(ctx, `
+			UPDATE notificacoes SET proxima_tentativa = NOW() + $2 * INTERVAL '1 second', ultimo_erro = $3, updated_at = NOW()
+			WHERE id = $1
+		`, n.id, int64(espera.Seconds()), mensagemErro)
+	}
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar situação da notificação")
+		return fmt.Errorf("erro ao registrar entrega")
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da entrega")
+		return fmt.Errorf("erro ao registrar entrega")
+	}
+
+	if errEnvio != nil {
+		logs.Do(ctx).WithFields(logrus.Fields{
+			"notificacao_id": n.id,
+			"evento":         n.evento,
+			"tentativa":      n.tentativa,
+		}).WithError(errEnvio).Warn("Falha na entrega da notificação")
+	}
+
+	return nil
+}
+
+// ExecutarEntregas avisa as reservas perto de vencer e entrega as notificações pendentes
+// periodicamente, ou assim que um evento é publicado, até o contexto ser cancelado
+func (s *NotificacoesService) ExecutarEntregas(ctx context.Context, intervalo time.Duration) {
+	logs.Do(ctx).WithField("intervalo", intervalo.String()).Info("Worker de notificações iniciado")
+
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.AvisarReservasExpirando(ctx); err != nil && ctx.Err() == nil {
+			logs.Do(ctx).WithError(err).Warn("Falha ao avisar reservas perto de vencer; nova tentativa no próximo ciclo")
+		}
+
+		for ctx.Err() == nil {
+			entregues, err := s.EntregarPendentes(ctx, loteEntregas)
+			if err != nil {
+				if ctx.Err() == nil {
+					logs.Do(ctx).WithError(err).Warn("Falha ao entregar notificações; nova tentativa no próximo ciclo")
+				}
+				break
+			}
+			if entregues < loteEntregas {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			logs.Do(ctx).Info("Worker de notificações finalizado")
+			return
+		case <-ticker.C:
+		case <-s.acordar:
+		}
+	}
+}
+
+// Fila resume as notificações aguardando entrega: o total pendente, as que já deviam
+// ter sido tentadas e o atraso da mais antiga delas
+func (s *NotificacoesService) Fila(ctx context.Context) (*models.FilaNotificacoes, error) {
+	var fila models.FilaNotificacoes
+	var atraso sql.NullFloat64
+	err := s.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+			COUNT(*) FILTER (WHERE proxima_tentativa <= NOW()),
+			EXTRACT(EPOCH FROM NOW() - MIN(proxima_tentativa) FILTER (WHERE proxima_tentativa <= NOW()))
+		FROM notificacoes
+		WHERE status = 'pendente'
+	`).Scan(&fila.Pendentes, &fila.Vencidas, &atraso)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar fila de notificações: %w", err)
+	}
+	if atraso.Valid {
+		fila.AtrasoSegundos = int64(atraso.Float64)
+	}
+	return &fila, nil
+}
+
+// ListarNotificacoes lista as notificações do trader, da mais recente para a mais antiga
+func (s *NotificacoesService) ListarNotificacoes(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroNotificacoes) ([]models.Notificacao, *paginacao.Pagina, error) {
+	conditions := []string{"n.trader_id = $1"}
+	args := []interface{}{traderID}
+
+	if filtro.AssinaturaID != nil {
+		args = append(args, *filtro.AssinaturaID)
+		conditions = append(conditions, fmt.Sprintf("n.assinatura_id = $%d", len(args)))
+	}
+	if filtro.Evento != "" {
+		args = append(args, filtro.Evento)
+		conditions = append(conditions, fmt.Sprintf("n.evento = $%d", len(args)))
+	}
+	if filtro.Status != "" {
+		args = append(args, filtro.Status)
+		conditions = append(conditions, fmt.Sprintf("n.status = $%d", len(args)))
+	}
+
+	params := &filtro.Paginacao
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM notificacoes n WHERE "+strings.Join(conditions, " AND "), args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar notificações")
+			return nil, nil, fmt.Errorf("erro ao buscar notificações")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("n.created_at", "n.id", len(args)+1)
+	if condicao != "" {
+		conditions = append(conditions, condicao)
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM notificacoes n
+		JOIN assinaturas_notificacao a ON a.id = n.assinatura_id
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, colunasNotificacao, strings.Join(conditions, " AND "), orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar notificações")
+		return nil, nil, fmt.Errorf("erro ao buscar notificações")
+	}
+	defer rows.Close()
+
+	notificacoes := []models.Notificacao{}
+	for rows.Next() {
+		var n models.Notificacao
+		if err := escanearNotificacao(rows, &n); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear notificação")
+			continue
+		}
+		notificacoes = append(notificacoes, n)
+	}
+
+	notificacoes, pagina := paginacao.Montar(notificacoes, params, func(n models.Notificacao) (time.Time, uuid.UUID) {
+		return n.CreatedAt, n.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return notificacoes, pagina, nil
+}
+
+const colunasNotificacao = `n.id, n.assinatura_id, n.evento, a.canal, a.destino, n.dados, n.status, n.tentativas,
+	n.proxima_tentativa, n.ultimo_erro, n.entregue_em, n.reenviada_em, n.created_at`
+
+func escanearNotificacao(row interface{ Scan(...interface{}) error }, n *models.Notificacao) error {
+	return row.Scan(&n.ID, &n.AssinaturaID, &n.Evento, &n.Canal, &n.Destino, &n.Dados, &n.Status, &n.Tentativas,
+		&n.ProximaTentativa, &n.UltimoErro, &n.EntregueEm, &n.ReenviadaEm, &n.CreatedAt)
+}
+
+// BuscarNotificacao retorna a notificação do trader com todas as tentativas de entrega
+func (s *NotificacoesService) BuscarNotificacao(ctx context.Context, traderID, notificacaoID uuid.UUID) (*models.Notificacao, error) {
+	var n models.Notificacao
+	err := escanearNotificacao(s.db.QueryRowContext(ctx, `
+		SELECT `+colunasNotificacao+`
+		FROM notificacoes n
+		JOIN assinaturas_notificacao a ON a.id = n.assinatura_id
+		WHERE n.id = $1 AND n.trader_id = $2
+	`, notificacaoID, traderID), &n)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("notificação não encontrada")
+	}
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar notificação")
+		return nil, fmt.Errorf("erro ao buscar notificação")
+	}
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT tentativa, sucesso, status_http, erro, duracao_ms, created_at
+		FROM entregas_notificacao
+		WHERE notificacao_id = $1
+		ORDER BY tentativa, created_at
+	`, notificacaoID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar tentativas de entrega")
+		return nil, fmt.Errorf("erro ao buscar notificação")
+	}
+	defer rows.Close()
+
+	n.Entregas = []models.EntregaNotificacao{}
+	for rows.Next() {
+		var e models.EntregaNotificacao
+		if err := rows.Scan(&e.Tentativa, &e.Sucesso, &e.StatusHTTP, &e.Erro, &e.DuracaoMs, &e.CreatedAt); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear tentativa de entrega")
+			continue
+		}
+		n.Entregas = append(n.Entregas, e)
+	}
+
+	return &n, nil
+}
+
+// setReenvio devolve a notificação à fila de entrega, recomeçando a contagem de
+// tentativas; o id (chave de idempotência do receptor) é mantido
+const setReenvio = `
+	status = 'pendente', tentativas_base = n.tentativas, proxima_tentativa = NOW(),
+	reenviada_em = NOW(), ultimo_erro = NULL, entregue_em = NULL, updated_at = NOW()
+`
+
+// Reenviar devolve à fila uma notificação já entregue ou que falhou, para ser entregue
+// de novo com o mesmo id
+func (s *NotificacoesService) Reenviar(ctx context.Context, traderID, notificacaoID uuid.UUID) (*models.Notificacao, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro ao reenviar notificação")
+	}
+	defer tx.Rollback()
+
+	var status string
+	var ativa bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT n.status, a.ativa OR n.evento = $3
+		FROM notificacoes n
+		JOIN assinaturas_notificacao a ON a.id = n.assinatura_id
+		WHERE n.id = $1 AND n.trader_id = $2
+		FOR UPDATE OF n
+	`, notificacaoID, traderID, models.EventoTeste).Scan(&status, &ativa)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("notificação não encontrada")
+	}
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar notificação")
+		return nil, fmt.Errorf("erro ao reenviar notificação")
+	}
+	if status == models.StatusNotificacaoPendente {
+		return nil, fmt.Errorf("notificação já está na fila de entrega")
+	}
+	if !ativa {
+		return nil, fmt.Errorf("assinatura inativa: reative-a para reenviar")
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE notificacoes n SET `+setReenvio+` WHERE n.id = $1`, notificacaoID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao reenviar notificação")
+		return nil, fmt.Errorf("erro ao reenviar notificação")
+	}
+
+	antes := map[string]interface{}{"status": status}
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoNotificacaoReenvio, "notificacao", notificacaoID.String(), antes, nil); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do reenvio")
+		return nil, fmt.Errorf("erro ao reenviar notificação")
+	}
+
+	s.despertar()
+	return s.BuscarNotificacao(ctx, traderID, notificacaoID)
+}
+
+// ReenviarAssinatura devolve à fila as notificações da assinatura já entregues ou que
+// falharam, opcionalmente apenas as de uma situação ou criadas a partir de uma data
+// (ex.: para a integração reconstruir o espelho depois de uma perda de dados)
+func (s *NotificacoesService) ReenviarAssinatura(ctx context.Context, traderID, assinaturaID uuid.UUID, request *models.ReenvioRequest) (int64, error) {
+	if request.Status != "" && request.Status != models.StatusNotificacaoEntregue && request.Status != models.StatusNotificacaoFalhou {
+		return 0, fmt.Errorf("status inválido: use entregue ou falhou")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return 0, fmt.Errorf("erro ao reenviar notificações")
+	}
+	defer tx.Rollback()
+
+	a, err := s.buscarAssinatura(ctx, tx, traderID, assinaturaID)
+	if err != nil {
+		return 0, err
+	}
+	if !a.Ativa {
+		return 0, fmt.Errorf("assinatura inativa: reative-a para reenviar")
+	}
+
+	reenviadas, err := execContando(ctx, tx, `
+		UPDATE notificacoes n SET `+setReenvio+`
+		WHERE n.assinatura_id = $1 AND n.status <> 'pendente'
+			AND ($2::text = '' OR n.status = $2)
+			AND ($3::timestamptz IS NULL OR n.created_at >= $3)
+	`, assinaturaID, request.Status, request.Desde)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao reenviar notificações da assinatura")
+		return 0, fmt.Errorf("erro ao reenviar notificações")
+	}
+
+	depois := map[string]interface{}{
+		"status":     request.Status,
+		"desde":      request.Desde,
+		"reenviadas": reenviadas,
+	}
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoNotificacaoReenvio, "assinatura_notificacao", assinaturaID.String(), nil, depois); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do reenvio")
+		return 0, fmt.Errorf("erro ao reenviar notificações")
+	}
+
+	if reenviadas > 0 {
+		s.despertar()
+	}
+	return reenviadas, nil
+}
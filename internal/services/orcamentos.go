@@ -0,0 +1,650 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+)
+
+// consultor é satisfeito por *sql.DB e *sql.Tx para leituras dentro ou fora de transação
+type consultor interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// colunasOrcamento são as colunas lidas por escanearOrcamento, na mesma ordem
+const colunasOrcamento = `o.id, o.trader_id, o.numero, o.cliente, o.cliente_id, o.cliente_contato, o.observacoes,
+			   to_char(o.validade, 'YYYY-MM-DD'), o.status, o.desconto_percentual, o.desconto_valor,
+			   o.enviado_em, o.respondido_em, o.convertido_em, o.created_at, o.updated_at`
+
+// escanearOrcamento lê uma linha com as colunasOrcamento seguidas das colunas extras
+func escanearOrcamento(row interface{ Scan(...interface{}) error }, o *models.Orcamento, extras ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&o.ID, &o.TraderID, &o.Numero, &o.Cliente, &o.ClienteID, &o.ClienteContato, &o.Observacoes,
+		&o.Validade, &o.Status, &o.DescontoPercentual, &o.DescontoValor,
+		&o.EnviadoEm, &o.RespondidoEm, &o.ConvertidoEm, &o.CreatedAt, &o.UpdatedAt,
+	}, extras...)...)
+}
+
+// OrcamentosService gerencia os orçamentos montados pelos traders para seus clientes
+type OrcamentosService struct {
+	db        *sql.DB
+	auditoria *AuditoriaService
+	reservas  *ReservasService
+}
+
+// NewOrcamentosService cria uma nova instância do OrcamentosService
+func NewOrcamentosService(db *sql.DB, auditoria *AuditoriaService, reservas *ReservasService) *OrcamentosService {
+	return &OrcamentosService{db: db, auditoria: auditoria, reservas: reservas}
+}
+
+// Criar cria um orçamento em rascunho, numerado sequencialmente por trader
+func (s *OrcamentosService) Criar(ctx context.Context, traderID uuid.UUID, request *models.OrcamentoCriarRequest) (*models.Orcamento, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	// Trava o trader para que dois orçamentos simultâneos não recebam o mesmo número
+	var existe int
+	err = tx.QueryRowContext(ctx, `SELECT 1 FROM traders WHERE id = $1 FOR UPDATE`, traderID).Scan(&existe)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trader não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar trader")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	cliente := request.Cliente
+	if request.ClienteID != nil {
+		nome, err := nomeDoCliente(ctx, tx, traderID, *request.ClienteID)
+		if err != nil {
+			return nil, err
+		}
+		if cliente == "" {
+			cliente = nome
+		}
+	}
+
+	descontoPercentual, descontoValor := 0.0, 0.0
+	if request.DescontoPercentual != nil {
+		descontoPercentual = *request.DescontoPercentual
+	}
+	if request.DescontoValor != nil {
+		descontoValor = *request.DescontoValor
+	}
+
+	var orcamento models.Orcamento
+	err = escanearOrcamento(tx.QueryRowContext(ctx, `
+		INSERT INTO orcamentos AS o (
+			trader_id, numero, cliente, cliente_contato, observacoes, validade,
+			desconto_percentual, desconto_valor, cliente_id
+		) VALUES (
+			$1, (SELECT COALESCE(MAX(numero), 0) + 1 FROM orcamentos WHERE trader_id = $1),
+			$2, $3, $4, $5, $6, $7, $8
+		)
+		RETURNING `+colunasOrcamento,
+		traderID, cliente, request.ClienteContato, request.Observacoes, request.Validade,
+		descontoPercentual, descontoValor, request.ClienteID), &orcamento)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao criar orçamento")
+		return nil, fmt.Errorf("erro ao criar orçamento")
+	}
+	orcamento.Itens = []models.OrcamentoItem{}
+	orcamento.Totais = models.CalcularTotais(0, 0, orcamento.DescontoPercentual, orcamento.DescontoValor)
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoOrcamentoCriado, "orcamento", orcamento.ID.String(), nil, orcamento); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do orçamento")
+		return nil, fmt.Errorf("erro ao criar orçamento")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":      traderID,
+		"orcamento_id": orcamento.ID,
+		"numero":       orcamento.Numero,
+	}).Info("Orçamento criado com sucesso")
+
+	return &orcamento, nil
+}
+
+// Listar lista os orçamentos do trader com totais, sem os itens
+func (s *OrcamentosService) Listar(ctx context.Context, traderID uuid.UUID, filtro *models.FiltroOrcamentos) ([]models.Orcamento, *paginacao.Pagina, error) {
+	conditions := []string{"o.trader_id = $1"}
+	args := []interface{}{traderID}
+
+	if filtro.Status != "" {
+		args = append(args, filtro.Status)
+		conditions = append(conditions, fmt.Sprintf("o.status = $%d", len(args)))
+	}
+	if filtro.Cliente != "" {
+		args = append(args, "%"+filtro.Cliente+"%")
+		conditions = append(conditions, fmt.Sprintf("o.cliente ILIKE $%d", len(args)))
+	}
+	if filtro.ClienteID != nil {
+		args = append(args, *filtro.ClienteID)
+		conditions = append(conditions, fmt.Sprintf("o.cliente_id = $%d", len(args)))
+	}
+
+	params := &filtro.Paginacao
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM orcamentos o WHERE "+strings.Join(conditions, " AND "), args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar orçamentos")
+			return nil, nil, fmt.Errorf("erro ao buscar orçamentos")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("o.created_at", "o.id", len(args)+1)
+	if condicao != "" {
+		conditions = append(conditions, condicao)
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT %s, COALESCE(t.total_m2, 0), COALESCE(t.subtotal, 0)
+		FROM orcamentos o
+		LEFT JOIN (
+			SELECT orcamento_id, SUM(quantidade_m2) AS total_m2, SUM(ROUND(quantidade_m2 * preco_m2, 2)) AS subtotal
+			FROM orcamento_itens
+			GROUP BY orcamento_id
+		) t ON t.orcamento_id = o.id
+		WHERE %s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, colunasOrcamento, strings.Join(conditions, " AND "), orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar orçamentos")
+		return nil, nil, fmt.Errorf("erro ao buscar orçamentos")
+	}
+	defer rows.Close()
+
+	orcamentos := []models.Orcamento{}
+	for rows.Next() {
+		var o models.Orcamento
+		var totalM2, subtotal float64
+		if err := escanearOrcamento(rows, &o, &totalM2, &subtotal); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear orçamento")
+			continue
+		}
+		o.Totais = models.CalcularTotais(totalM2, subtotal, o.DescontoPercentual, o.DescontoValor)
+		orcamentos = append(orcamentos, o)
+	}
+
+	orcamentos, pagina := paginacao.Montar(orcamentos, params, func(o models.Orcamento) (time.Time, uuid.UUID) {
+		return o.CreatedAt, o.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return orcamentos, pagina, nil
+}
+
+// Buscar retorna o orçamento com itens e totais
+func (s *OrcamentosService) Buscar(ctx context.Context, traderID, orcamentoID uuid.UUID) (*models.Orcamento, error) {
+	return s.buscar(ctx, s.db, traderID, orcamentoID, false)
+}
+
+// Atualizar altera os dados de um orçamento em rascunho
+func (s *OrcamentosService) Atualizar(ctx context.Context, traderID, orcamentoID uuid.UUID, request *models.OrcamentoAtualizarRequest) (*models.Orcamento, error) {
+	setParts := []string{}
+	args := []interface{}{}
+
+	adicionar := func(coluna string, valor interface{}) {
+		args = append(args, valor)
+		setParts = append(setParts, fmt.Sprintf("%s = $%d", coluna, len(args)))
+	}
+
+	if request.Cliente != nil {
+		adicionar("cliente", *request.Cliente)
+	}
+	if request.ClienteID != nil {
+		nome, err := nomeDoCliente(ctx, s.db, traderID, *request.ClienteID)
+		if err != nil {
+			return nil, err
+		}
+		adicionar("cliente_id", *request.ClienteID)
+		if request.Cliente == nil {
+			adicionar("cliente", nome)
+		}
+	}
+	if request.ClienteContato != nil {
+		adicionar("cliente_contato", *request.ClienteContato)
+	}
+	if request.Observacoes != nil {
+		adicionar("observacoes", *request.Observacoes)
+	}
+	if request.Validade != nil {
+		adicionar("validade", *request.Validade)
+	}
+	if request.DescontoPercentual != nil {
+		adicionar("desconto_percentual", *request.DescontoPercentual)
+	}
+	if request.DescontoValor != nil {
+		adicionar("desconto_valor", *request.DescontoValor)
+	}
+
+	if len(setParts) == 0 {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	return s.alterarRascunho(ctx, traderID, orcamentoID, func(tx *sql.Tx, _ *models.Orcamento) error {
+		args = append(args, orcamentoID)
+		_, err := tx.ExecContext(ctx, fmt.Sprintf(`
+			UPDATE orcamentos SET %s WHERE id = $%d
+		`, strings.Join(setParts, ", "), len(args)), args...)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao atualizar orçamento")
+			return fmt.Errorf("erro ao atualizar orçamento")
+		}
+		return nil
+	})
+}
+
+// Remover exclui um orçamento em rascunho ou recusado
+func (s *OrcamentosService) Remover(ctx context.Context, traderID, orcamentoID uuid.UUID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, traderID, orcamentoID, true)
+	if err != nil {
+		return err
+	}
+	if antes.Status != models.StatusOrcamentoRascunho && antes.Status != models.StatusOrcamentoRecusado {
+		return fmt.Errorf("operação não permitida: orçamento está %s", antes.Status)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM orcamentos WHERE id = $1`, orcamentoID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao remover orçamento")
+		return fmt.Errorf("erro ao remover orçamento")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoOrcamentoRemovido, "orcamento", orcamentoID.String(), antes, nil); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção do orçamento")
+		return fmt.Errorf("erro ao remover orçamento")
+	}
+
+	return nil
+}
+
+// AdicionarItem inclui um produto aprovado no orçamento em rascunho
+func (s *OrcamentosService) AdicionarItem(ctx context.Context, traderID, orcamentoID uuid.UUID, request *models.OrcamentoItemRequest) (*models.Orcamento, error) {
+	return s.alterarRascunho(ctx, traderID, orcamentoID, func(tx *sql.Tx, _ *models.Orcamento) error {
+		var descricao, status string
+		var precoVenda float64
+		var metragem *float64
+		var removidoNaOrigem bool
+		err := tx.QueryRowContext(ctx, `
+			SELECT pa.nome_customizado, pa.preco_venda, pa.status, COALESCE(c.metragem_m2, c.metragem),
+				c.removido_na_origem_em IS NOT NULL
+			FROM produtos_aprovados pa
+			JOIN cavaletes c ON c.id = pa.cavalete_id
+			WHERE pa.id = $1 AND pa.trader_id = $2 AND pa.deleted_at IS NULL
+		`, request.ProdutoID, traderID).Scan(&descricao, &precoVenda, &status, &metragem, &removidoNaOrigem)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("produto não encontrado")
+		} else if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao buscar produto do orçamento")
+			return fmt.Errorf("erro interno do servidor")
+		}
+
+		if status == models.StatusProdutoVendido {
+			return fmt.Errorf("operação não permitida: produto está vendido")
+		}
+		if removidoNaOrigem {
+			return fmt.Errorf("operação não permitida: o cavalete do produto foi removido na origem")
+		}
+
+		quantidade := metragem
+		if request.QuantidadeM2 != nil {
+			quantidade = request.QuantidadeM2
+		}
+		if quantidade == nil || *quantidade <= 0 {
+			return fmt.Errorf("informe quantidade_m2: o cavalete não tem metragem cadastrada")
+		}
+
+		preco := precoVenda
+		if request.PrecoM2 != nil {
+			preco = *request.PrecoM2
+		}
+
+		afetadas, err := execContando(ctx, tx, `
+			INSERT INTO orcamento_itens (orcamento_id, produto_id, descricao, quantidade_m2, preco_m2)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (orcamento_id, produto_id) DO NOTHING
+		`, orcamentoID, request.ProdutoID, descricao, *quantidade, preco)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao adicionar item ao orçamento")
+			return fmt.Errorf("erro ao adicionar item")
+		}
+		if afetadas == 0 {
+			return fmt.Errorf("produto já está no orçamento")
+		}
+		return nil
+	})
+}
+
+// AtualizarItem altera a quantidade ou o preço negociado de um item
+func (s *OrcamentosService) AtualizarItem(ctx context.Context, traderID, orcamentoID, itemID uuid.UUID, request *models.OrcamentoItemAtualizarRequest) (*models.Orcamento, error) {
+	if request.QuantidadeM2 == nil && request.PrecoM2 == nil {
+		return nil, fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	return s.alterarRascunho(ctx, traderID, orcamentoID, func(tx *sql.Tx, _ *models.Orcamento) error {
+		afetadas, err := execContando(ctx, tx, `
+			UPDATE orcamento_itens
+			SET quantidade_m2 = COALESCE($1, quantidade_m2), preco_m2 = COALESCE($2, preco_m2), updated_at = NOW()
+			WHERE id = $3 AND orcamento_id = $4
+		`, request.QuantidadeM2, request.PrecoM2, itemID, orcamentoID)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao atualizar item do orçamento")
+			return fmt.Errorf("erro ao atualizar item")
+		}
+		if afetadas == 0 {
+			return fmt.Errorf("item não encontrado")
+		}
+		return nil
+	})
+}
+
+// RemoverItem retira um item do orçamento em rascunho
+func (s *OrcamentosService) RemoverItem(ctx context.Context, traderID, orcamentoID, itemID uuid.UUID) (*models.Orcamento, error) {
+	return s.alterarRascunho(ctx, traderID, orcamentoID, func(tx *sql.Tx, _ *models.Orcamento) error {
+		afetadas, err := execContando(ctx, tx, `
+			DELETE FROM orcamento_itens WHERE id = $1 AND orcamento_id = $2
+		`, itemID, orcamentoID)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao remover item do orçamento")
+			return fmt.Errorf("erro ao remover item")
+		}
+		if afetadas == 0 {
+			return fmt.Errorf("item não encontrado")
+		}
+		return nil
+	})
+}
+
+// Enviar marca o rascunho como enviado ao cliente; a partir daí ele não pode mais ser editado
+func (s *OrcamentosService) Enviar(ctx context.Context, traderID, orcamentoID uuid.UUID) (*models.Orcamento, error) {
+	return s.transicionar(ctx, traderID, orcamentoID, models.AcaoOrcamentoEnviado,
+		models.StatusOrcamentoRascunho, models.StatusOrcamentoEnviado, "enviado_em = NOW()",
+		func(o *models.Orcamento) error {
+			if len(o.Itens) == 0 {
+				return fmt.Errorf("operação não permitida: orçamento sem itens")
+			}
+			return nil
+		})
+}
+
+// Aceitar registra o aceite do cliente, desde que o orçamento esteja dentro da validade
+func (s *OrcamentosService) Aceitar(ctx context.Context, traderID, orcamentoID uuid.UUID) (*models.Orcamento, error) {
+	return s.transicionar(ctx, traderID, orcamentoID, models.AcaoOrcamentoAceito,
+		models.StatusOrcamentoEnviado, models.StatusOrcamentoAceito, "respondido_em = NOW()",
+		func(o *models.Orcamento) error {
+			if o.Validade != nil && *o.Validade < time.Now().Format("2006-01-02") {
+				return fmt.Errorf("operação não permitida: orçamento venceu em %s", *o.Validade)
+			}
+			return nil
+		})
+}
+
+// Recusar registra a recusa do cliente
+func (s *OrcamentosService) Recusar(ctx context.Context, traderID, orcamentoID uuid.UUID) (*models.Orcamento, error) {
+	return s.transicionar(ctx, traderID, orcamentoID, models.AcaoOrcamentoRecusado,
+		models.StatusOrcamentoEnviado, models.StatusOrcamentoRecusado, "respondido_em = NOW()", nil)
+}
+
+// Converter reserva para o cliente todos os produtos de um orçamento aceito. A conversão
+// é atômica: se algum produto não estiver disponível, nenhuma reserva é criada.
+func (s *OrcamentosService) Converter(ctx context.Context, traderID, orcamentoID uuid.UUID, request *models.ConverterOrcamentoRequest) (*models.ConversaoOrcamentoResultado, error) {
+	expiraEm, err := s.reservas.calcularExpiracao(request.ExpiraEm, request.ValidadeHoras)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, traderID, orcamentoID, true)
+	if err != nil {
+		return nil, err
+	}
+	if antes.Status != models.StatusOrcamentoAceito {
+		return nil, fmt.Errorf("operação não permitida: orçamento está %s", antes.Status)
+	}
+	if antes.ConvertidoEm != nil {
+		return nil, fmt.Errorf("operação não permitida: orçamento já foi convertido em reservas")
+	}
+
+	observacao := fmt.Sprintf("Orçamento nº %d", antes.Numero)
+	reservados := make([]models.ProdutoAprovado, 0, len(antes.Itens))
+	for _, item := range antes.Itens {
+		produto, err := s.reservas.reservarTx(ctx, tx, traderID, item.ProdutoID, antes.Cliente, antes.ClienteID, &observacao, expiraEm)
+		if err != nil {
+			if strings.HasSuffix(err.Error(), "removido na origem") {
+				return nil, fmt.Errorf("operação não permitida: o cavalete do produto %q foi removido na origem", item.Descricao)
+			}
+			if err.Error() == "produto não encontrado" || strings.HasPrefix(err.Error(), "operação não permitida") {
+				return nil, fmt.Errorf("operação não permitida: o produto %q não está disponível", item.Descricao)
+			}
+			return nil, err
+		}
+		reservados = append(reservados, *produto)
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE orcamentos SET convertido_em = NOW(), updated_at = NOW() WHERE id = $1
+	`, orcamentoID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao converter orçamento")
+		return nil, fmt.Errorf("erro ao converter orçamento")
+	}
+
+	depois, err := s.buscar(ctx, tx, traderID, orcamentoID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoOrcamentoConvertido, "orcamento", orcamentoID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da conversão do orçamento")
+		return nil, fmt.Errorf("erro ao converter orçamento")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":      traderID,
+		"orcamento_id": orcamentoID,
+		"reservas":     len(reservados),
+	}).Info("Orçamento convertido em reservas")
+
+	return &models.ConversaoOrcamentoResultado{Orcamento: depois, Reservados: reservados}, nil
+}
+
+// alterarRascunho aplica uma alteração a um orçamento em rascunho, registrando o estado
+// anterior e o novo na auditoria
+func (s *OrcamentosService) alterarRascunho(ctx context.Context, traderID, orcamentoID uuid.UUID, alterar func(*sql.Tx, *models.Orcamento) error) (*models.Orcamento, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, traderID, orcamentoID, true)
+	if err != nil {
+		return nil, err
+	}
+	if antes.Status != models.StatusOrcamentoRascunho {
+		return nil, fmt.Errorf("operação não permitida: orçamento está %s", antes.Status)
+	}
+
+	if err := alterar(tx, antes); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE orcamentos SET updated_at = NOW() WHERE id = $1`, orcamentoID); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar orçamento")
+		return nil, fmt.Errorf("erro ao atualizar orçamento")
+	}
+
+	depois, err := s.buscar(ctx, tx, traderID, orcamentoID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoOrcamentoAtualizado, "orcamento", orcamentoID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do orçamento")
+		return nil, fmt.Errorf("erro ao atualizar orçamento")
+	}
+
+	return depois, nil
+}
+
+// transicionar muda o status do orçamento de "de" para "para", aplicando o set extra e a
+// validação opcional sobre o estado atual
+func (s *OrcamentosService) transicionar(ctx context.Context, traderID, orcamentoID uuid.UUID, acao, de, para, set string, validar func(*models.Orcamento) error) (*models.Orcamento, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	antes, err := s.buscar(ctx, tx, traderID, orcamentoID, true)
+	if err != nil {
+		return nil, err
+	}
+	if antes.Status != de {
+		return nil, fmt.Errorf("operação não permitida: orçamento está %s", antes.Status)
+	}
+	if validar != nil {
+		if err := validar(antes); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE orcamentos SET status = $1, `+set+`, updated_at = NOW() WHERE id = $2
+	`, para, orcamentoID); err != nil {
+		logs.Do(ctx).WithError(err).WithField("acao", acao).Error("Erro ao atualizar status do orçamento")
+		return nil, fmt.Errorf("erro ao atualizar status do orçamento")
+	}
+
+	depois, err := s.buscar(ctx, tx, traderID, orcamentoID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, acao, "orcamento", orcamentoID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit do status do orçamento")
+		return nil, fmt.Errorf("erro ao atualizar status do orçamento")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":      traderID,
+		"orcamento_id": orcamentoID,
+		"status":       para,
+	}).Info("Status do orçamento atualizado")
+
+	return depois, nil
+}
+
+// buscar carrega o orçamento do trader com itens e totais. Com bloquear, a linha do
+// orçamento fica travada até o fim da transação.
+func (s *OrcamentosService) buscar(ctx context.Context, q consultor, traderID, orcamentoID uuid.UUID, bloquear bool) (*models.Orcamento, error) {
+	query := `SELECT ` + colunasOrcamento + ` FROM orcamentos o WHERE o.id = $1 AND o.trader_id = $2`
+	if bloquear {
+		query += " FOR UPDATE"
+	}
+
+	var orcamento models.Orcamento
+	err := escanearOrcamento(q.QueryRowContext(ctx, query, orcamentoID, traderID), &orcamento)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("orçamento não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar orçamento")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	rows, err := q.QueryContext(ctx, `
+		SELECT i.id, i.produto_id, i.descricao, i.quantidade_m2, i.preco_m2,
+			   ROUND(i.quantidade_m2 * i.preco_m2, 2), pa.status, i.created_at
+		FROM orcamento_itens i
+		JOIN produtos_aprovados pa ON pa.id = i.produto_id
+		WHERE i.orcamento_id = $1
+		ORDER BY i.created_at, i.id
+	`, orcamentoID)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar itens do orçamento")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer rows.Close()
+
+	orcamento.Itens = []models.OrcamentoItem{}
+	var totalM2, subtotal float64
+	for rows.Next() {
+		var item models.OrcamentoItem
+		err := rows.Scan(
+			&item.ID, &item.ProdutoID, &item.Descricao, &item.QuantidadeM2, &item.PrecoM2,
+			&item.Total, &item.StatusProduto, &item.CreatedAt,
+		)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear item do orçamento")
+			return nil, fmt.Errorf("erro interno do servidor")
+		}
+		totalM2 += item.QuantidadeM2
+		subtotal += item.Total
+		orcamento.Itens = append(orcamento.Itens, item)
+	}
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao ler itens do orçamento")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	orcamento.Totais = models.CalcularTotais(totalM2, subtotal, orcamento.DescontoPercentual, orcamento.DescontoValor)
+
+	return &orcamento, nil
+}
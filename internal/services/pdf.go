@@ -0,0 +1,466 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/imagens"
+	"mobgran-importer-go/pkg/pdf"
+	"mobgran-importer-go/pkg/storage"
+)
+
+// Layout dos PDFs, em pontos
+const (
+	margemPDF        = 40.0
+	rodapePDF        = 50.0
+	ladoMaximoImgPDF = 1024
+)
+
+// Cores dos PDFs
+var (
+	corTextoSecundario = pdf.Cor{R: 90, G: 90, B: 90}
+	corDivisoria       = pdf.Cor{R: 200, G: 200, B: 200}
+	corFundoTabela     = pdf.Cor{R: 236, G: 236, B: 236}
+)
+
+// fichaProduto reúne os dados exibidos na ficha técnica de um produto
+type fichaProduto struct {
+	nome          string
+	descricao     *string
+	precoVenda    float64
+	status        string
+	codigo        string
+	bloco         string
+	material      string
+	espessura     string
+	classificacao *string
+	acabamento    *string
+	comprimento   *float64
+	altura        *float64
+	largura       *float64
+	comprimentoM  *float64
+	alturaM       *float64
+	metragem      *float64
+	peso          *float64
+	quantidade    *int
+	chaveImagem   *string
+}
+
+// PDFService gera fichas técnicas de produtos e orçamentos em PDF com a marca do
+// trader. Tudo é renderizado na própria aplicação, a partir do banco e do armazenamento.
+type PDFService struct {
+	db         *sql.DB
+	store      storage.BlobStore
+	marca      *MarcaService
+	orcamentos *OrcamentosService
+}
+
+// NewPDFService cria uma nova instância do PDFService
+func NewPDFService(db *sql.DB, store storage.BlobStore, marca *MarcaService, orcamentos *OrcamentosService) *PDFService {
+	return &PDFService{db: db, store: store, marca: marca, orcamentos: orcamentos}
+}
+
+// FichaProduto gera a ficha técnica do produto: material, espessura, dimensões,
+// metragem, imagem e preço
+func (s *PDFService) FichaProduto(ctx context.Context, traderID, produtoID uuid.UUID) ([]byte, error) {
+	var f fichaProduto
+	err := s.db.QueryRowContext(ctx, `
+		SELECT pa.nome_customizado, pa.descricao, pa.preco_venda, pa.status,
+			c.codigo, c.bloco, c.nome_material, c.nome_espessura, c.nome_classificacao, c.nome_acabamento,
+			c.comprimento, c.altura, c.largura, c.comprimento_m, c.altura_m,
+			COALESCE(c.metragem_m2, c.metragem), COALESCE(c.peso_kg, c.peso), c.quantidade_itens,
+			COALESCE(
+				(SELECT COALESCE(ip.chaves_miniaturas->>'grande', ip.chave_original)
+				 FROM imagens_produtos ip
+				 WHERE ip.produto_id = pa.id
+				 ORDER BY ip.capa DESC, ip.ordem
+				 LIMIT 1),
+				(SELECT COALESCE(ie.miniaturas->>'grande', ie.chave_original)
+				 FROM imagens_espelhadas ie
+				 WHERE ie.url_origem = COALESCE(NULLIF(c.imagem_principal->>'urlOrigem', ''), c.imagem_principal->>'url'))
+			)
+		FROM produtos_aprovados pa
+		JOIN cavaletes c ON c.id = pa.cavalete_id
+		WHERE pa.id = $1 AND pa.trader_id = $2 AND pa.deleted_at IS NULL
+	`, produtoID, traderID).Scan(
+		&f.nome, &f.descricao, &f.precoVenda, &f.status,
+		&f.codigo, &f.bloco, &f.material, &f.espessura, &f.classificacao, &f.acabamento,
+		&f.comprimento, &f.altura, &f.largura, &f.comprimentoM, &f.alturaM,
+		&f.metragem, &f.peso, &f.quantidade,
+		&f.chaveImagem,
+	)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("produto não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar dados da ficha do produto")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	marca, logo, err := s.carregarMarca(ctx, traderID)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := pdf.NovoDocumento(f.nome)
+	y := cabecalhoPDF(doc, marca, logo)
+	larguraUtil := doc.Largura() - 2*margemPDF
+
+	y += 10
+	doc.Texto(margemPDF, y, f.nome, pdf.HelveticaNegrito, 18, pdf.Preto)
+	y += 18
+	doc.Texto(margemPDF, y, fmt.Sprintf("%s · %s", f.material, f.espessura), pdf.Helvetica, 11, corTextoSecundario)
+	y += 16
+
+	if f.chaveImagem != nil {
+		if img := s.carregarImagem(ctx, *f.chaveImagem); img != nil {
+			if _, altura, err := doc.Imagem(img, margemPDF, y, larguraUtil, 300); err == nil {
+				y += altura + 16
+			}
+		}
+	}
+
+	especificacoes := [][2]string{
+		{"Material", f.material},
+		{"Espessura", f.espessura},
+	}
+	if f.classificacao != nil && *f.classificacao != "" {
+		especificacoes = append(especificacoes, [2]string{"Classificação", *f.classificacao})
+	}
+	if f.acabamento != nil && *f.acabamento != "" {
+		especificacoes = append(especificacoes, [2]string{"Acabamento", *f.acabamento})
+	}
+	// Dimensões normalizadas saem em metros; sem normalização, os valores brutos vão sem unidade
+	if f.comprimentoM != nil && f.alturaM != nil {
+		especificacoes = append(especificacoes, [2]string{"Dimensões (C × A)", formatarDimensoes(f.comprimentoM, f.alturaM) + " m"})
+	} else if dimensoes := formatarDimensoes(f.comprimento, f.altura, f.largura); dimensoes != "" {
+		especificacoes = append(especificacoes, [2]string{"Dimensões (C × A × L)", dimensoes})
+	}
+	if f.metragem != nil {
+		especificacoes = append(especificacoes, [2]string{"Metragem", formatarDecimal(*f.metragem, 2, 3) + " m²"})
+	}
+	if f.peso != nil {
+		especificacoes = append(especificacoes, [2]string{"Peso", formatarDecimal(*f.peso, 0, 3) + " kg"})
+	}
+	if f.quantidade != nil {
+		especificacoes = append(especificacoes, [2]string{"Chapas", strconv.Itoa(*f.quantidade)})
+	}
+	especificacoes = append(especificacoes,
+		[2]string{"Código", f.codigo},
+		[2]string{"Bloco", f.bloco},
+	)
+
+	doc.Texto(margemPDF, y, "Especificações", pdf.HelveticaNegrito, 12, pdf.Preto)
+	y += 8
+	for i, linha := range especificacoes {
+		if i%2 == 0 {
+			doc.Retangulo(margemPDF, y, larguraUtil, 18, corFundoTabela)
+		}
+		doc.Texto(margemPDF+6, y+12.5, linha[0], pdf.HelveticaNegrito, 10, pdf.Preto)
+		doc.Texto(margemPDF+170, y+12.5, linha[1], pdf.Helvetica, 10, pdf.Preto)
+		y += 18
+	}
+
+	y += 22
+	doc.Texto(margemPDF, y, "Preço: "+formatarMoeda(f.precoVenda)+" / m²", pdf.HelveticaNegrito, 14, pdf.Preto)
+	if f.metragem != nil && *f.metragem > 0 {
+		doc.TextoDireita(doc.Largura()-margemPDF, y,
+			"Total estimado: "+formatarMoeda(models.Arredondar(f.precoVenda**f.metragem)), pdf.Helvetica, 11, pdf.Preto)
+	}
+	if f.status != models.StatusProdutoDisponivel {
+		y += 16
+		doc.Texto(margemPDF, y, "Situação: "+f.status, pdf.Helvetica, 10, corTextoSecundario)
+	}
+
+	if f.descricao != nil && strings.TrimSpace(*f.descricao) != "" {
+		y += 26
+		doc.Texto(margemPDF, y, "Descrição", pdf.HelveticaNegrito, 12, pdf.Preto)
+		y += 4
+		for _, linha := range pdf.QuebrarTexto(*f.descricao, pdf.Helvetica, 10, larguraUtil) {
+			if y+14 > doc.Altura()-rodapePDF {
+				doc.NovaPagina()
+				y = cabecalhoPDF(doc, marca, logo)
+			}
+			y += 14
+			doc.Texto(margemPDF, y, linha, pdf.Helvetica, 10, pdf.Preto)
+		}
+	}
+
+	rodapesPDF(doc, "Ficha técnica")
+	return gerarPDF(ctx, doc)
+}
+
+// Orcamento gera o orçamento com seus itens, totais, validade e observações
+func (s *PDFService) Orcamento(ctx context.Context, traderID, orcamentoID uuid.UUID) ([]byte, error) {
+	orcamento, err := s.orcamentos.Buscar(ctx, traderID, orcamentoID)
+	if err != nil {
+		return nil, err
+	}
+
+	marca, logo, err := s.carregarMarca(ctx, traderID)
+	if err != nil {
+		return nil, err
+	}
+
+	titulo := fmt.Sprintf("Orçamento nº %d", orcamento.Numero)
+	doc := pdf.NovoDocumento(titulo)
+	y := cabecalhoPDF(doc, marca, logo)
+	direita := doc.Largura() - margemPDF
+
+	y += 10
+	doc.Texto(margemPDF, y, titulo, pdf.HelveticaNegrito, 18, pdf.Preto)
+	doc.TextoDireita(direita, y, "Emitido em "+orcamento.CreatedAt.Format("02/01/2006"), pdf.Helvetica, 10, corTextoSecundario)
+	y += 22
+
+	doc.Texto(margemPDF, y, "Cliente: ", pdf.HelveticaNegrito, 10, pdf.Preto)
+	doc.Texto(margemPDF+45, y, orcamento.Cliente, pdf.Helvetica, 10, pdf.Preto)
+	if orcamento.Validade != nil {
+		if validade, err := time.Parse("2006-01-02", *orcamento.Validade); err == nil {
+			doc.TextoDireita(direita, y, "Válido até "+validade.Format("02/01/2006"), pdf.HelveticaNegrito, 10, pdf.Preto)
+		}
+	}
+	if orcamento.ClienteContato != nil && *orcamento.ClienteContato != "" {
+		y += 14
+		doc.Texto(margemPDF, y, "Contato: ", pdf.HelveticaNegrito, 10, pdf.Preto)
+		doc.Texto(margemPDF+45, y, *orcamento.ClienteContato, pdf.Helvetica, 10, pdf.Preto)
+	}
+	y += 20
+
+	// Colunas: descrição à esquerda; quantidade, preço e total alinhados à direita
+	colM2, colPreco, colTotal := direita-190, direita-95, direita
+	larguraDescricao := colM2 - 60 - margemPDF - 6
+
+	cabecalhoTabela := func() {
+		doc.Retangulo(margemPDF, y, direita-margemPDF, 20, corFundoTabela)
+		doc.Texto(margemPDF+6, y+13.5, "Descrição", pdf.HelveticaNegrito, 10, pdf.Preto)
+		doc.TextoDireita(colM2, y+13.5, "m²", pdf.HelveticaNegrito, 10, pdf.Preto)
+		doc.TextoDireita(colPreco, y+13.5, "Preço/m²", pdf.HelveticaNegrito, 10, pdf.Preto)
+		doc.TextoDireita(colTotal-6, y+13.5, "Total", pdf.HelveticaNegrito, 10, pdf.Preto)
+		y += 20
+	}
+	cabecalhoTabela()
+
+	for _, item := range orcamento.Itens {
+		linhas := pdf.QuebrarTexto(item.Descricao, pdf.Helvetica, 10, larguraDescricao)
+		altura := float64(len(linhas))*13 + 8
+		if y+altura > doc.Altura()-rodapePDF {
+			doc.NovaPagina()
+			y = cabecalhoPDF(doc, marca, logo) + 10
+			cabecalhoTabela()
+		}
+
+		for i, linha := range linhas {
+			doc.Texto(margemPDF+6, y+14+float64(i)*13, linha, pdf.Helvetica, 10, pdf.Preto)
+		}
+		doc.TextoDireita(colM2, y+14, formatarDecimal(item.QuantidadeM2, 2, 4), pdf.Helvetica, 10, pdf.Preto)
+		doc.TextoDireita(colPreco, y+14, formatarMoeda(item.PrecoM2), pdf.Helvetica, 10, pdf.Preto)
+		doc.TextoDireita(colTotal-6, y+14, formatarMoeda(item.Total), pdf.Helvetica, 10, pdf.Preto)
+		y += altura
+		doc.Linha(margemPDF, y, direita, y, 0.5, corDivisoria)
+	}
+
+	totais := [][2]string{
+		{"Total m²", formatarDecimal(orcamento.Totais.TotalM2, 2, 4)},
+		{"Subtotal", formatarMoeda(orcamento.Totais.Subtotal)},
+	}
+	if orcamento.Totais.Desconto > 0 {
+		totais = append(totais, [2]string{"Desconto", "- " + formatarMoeda(orcamento.Totais.Desconto)})
+	}
+	if y+float64(len(totais)+1)*16+20 > doc.Altura()-rodapePDF {
+		doc.NovaPagina()
+		y = cabecalhoPDF(doc, marca, logo)
+	}
+
+	y += 8
+	for _, total := range totais {
+		y += 16
+		doc.TextoDireita(colPreco, y, total[0], pdf.Helvetica, 10, corTextoSecundario)
+		doc.TextoDireita(colTotal-6, y, total[1], pdf.Helvetica, 10, pdf.Preto)
+	}
+	y += 20
+	doc.TextoDireita(colPreco, y, "Total", pdf.HelveticaNegrito, 12, pdf.Preto)
+	doc.TextoDireita(colTotal-6, y, formatarMoeda(orcamento.Totais.Total), pdf.HelveticaNegrito, 12, pdf.Preto)
+
+	if orcamento.Observacoes != nil && strings.TrimSpace(*orcamento.Observacoes) != "" {
+		y += 30
+		if y+20 > doc.Altura()-rodapePDF {
+			doc.NovaPagina()
+			y = cabecalhoPDF(doc, marca, logo) + 10
+		}
+		doc.Texto(margemPDF, y, "Observações", pdf.HelveticaNegrito, 12, pdf.Preto)
+		y += 4
+		for _, linha := range pdf.QuebrarTexto(*orcamento.Observacoes, pdf.Helvetica, 10, direita-margemPDF) {
+			if y+14 > doc.Altura()-rodapePDF {
+				doc.NovaPagina()
+				y = cabecalhoPDF(doc, marca, logo)
+			}
+			y += 14
+			doc.Texto(margemPDF, y, linha, pdf.Helvetica, 10, pdf.Preto)
+		}
+	}
+
+	rodapesPDF(doc, titulo)
+	return gerarPDF(ctx, doc)
+}
+
+// carregarMarca busca os dados de marca do trader e decodifica o logotipo. Um logotipo
+// ilegível não impede a geração do PDF.
+func (s *PDFService) carregarMarca(ctx context.Context, traderID uuid.UUID) (*models.MarcaTrader, image.Image, error) {
+	marca, err := s.marca.Buscar(ctx, traderID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chave, err := s.marca.chaveLogo(ctx, traderID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if chave == nil {
+		return marca, nil, nil
+	}
+
+	return marca, s.carregarImagem(ctx, *chave), nil
+}
+
+// carregarImagem lê e decodifica uma imagem do armazenamento, reduzindo-a para não
+// inflar o PDF. Retorna nil quando a imagem não pode ser usada.
+func (s *PDFService) carregarImagem(ctx context.Context, chave string) image.Image {
+	dados, err := s.store.Ler(ctx, chave)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("chave", chave).Warn("Imagem indisponível para o PDF")
+		return nil
+	}
+
+	img, _, err := imagens.Decodificar(dados)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("chave", chave).Warn("Imagem não suportada no PDF")
+		return nil
+	}
+
+	return imagens.Redimensionar(img, ladoMaximoImgPDF)
+}
+
+// cabecalhoPDF desenha o logotipo e os dados da empresa no topo da página atual,
+// retornando a posição vertical logo abaixo dele
+func cabecalhoPDF(doc *pdf.Documento, marca *models.MarcaTrader, logo image.Image) float64 {
+	x := margemPDF
+	y := margemPDF
+	direita := doc.Largura() - margemPDF
+
+	altura := 0.0
+	if logo != nil {
+		if largura, h, err := doc.Imagem(logo, margemPDF, margemPDF, 120, 50); err == nil {
+			x += largura + 14
+			altura = h
+		}
+	}
+
+	empresa := marca.Nome
+	if marca.Empresa != nil && *marca.Empresa != "" {
+		empresa = *marca.Empresa
+	}
+	doc.Texto(x, y+14, empresa, pdf.HelveticaNegrito, 14, pdf.Preto)
+	linhaY := y + 14
+
+	contatos := []string{}
+	if marca.Documento != nil && *marca.Documento != "" {
+		contatos = append(contatos, *marca.Documento)
+	}
+	if marca.Endereco != nil && *marca.Endereco != "" {
+		contatos = append(contatos, *marca.Endereco)
+	}
+	contato := []string{}
+	if marca.Telefone != nil && *marca.Telefone != "" {
+		contato = append(contato, *marca.Telefone)
+	}
+	contato = append(contato, marca.Email)
+	if marca.Site != nil && *marca.Site != "" {
+		contato = append(contato, *marca.Site)
+	}
+	contatos = append(contatos, strings.Join(contato, " · "))
+
+	for _, texto := range contatos {
+		for _, linha := range pdf.QuebrarTexto(texto, pdf.Helvetica, 9, direita-x) {
+			linhaY += 12
+			doc.Texto(x, linhaY, linha, pdf.Helvetica, 9, corTextoSecundario)
+		}
+	}
+
+	fim := math.Max(y+altura, linhaY+4) + 8
+	doc.Linha(margemPDF, fim, direita, fim, 1, corDivisoria)
+	return fim + 12
+}
+
+// rodapesPDF escreve o rodapé de todas as páginas, com a numeração "página x de n"
+func rodapesPDF(doc *pdf.Documento, titulo string) {
+	total := doc.TotalPaginas()
+	gerado := "Gerado em " + time.Now().Format("02/01/2006 15:04")
+	for i := 1; i <= total; i++ {
+		doc.SelecionarPagina(i)
+		y := doc.Altura() - 30
+		doc.Linha(margemPDF, y-12, doc.Largura()-margemPDF, y-12, 0.5, corDivisoria)
+		doc.Texto(margemPDF, y, titulo+" · "+gerado, pdf.Helvetica, 8, corTextoSecundario)
+		doc.TextoDireita(doc.Largura()-margemPDF, y, fmt.Sprintf("Página %d de %d", i, total), pdf.Helvetica, 8, corTextoSecundario)
+	}
+}
+
+// gerarPDF serializa o documento
+func gerarPDF(ctx context.Context, doc *pdf.Documento) ([]byte, error) {
+	dados, err := doc.Bytes()
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao gerar PDF")
+		return nil, fmt.Errorf("erro ao gerar PDF")
+	}
+	return dados, nil
+}
+
+// formatarDimensoes formata comprimento × altura × largura, omitindo medidas ausentes
+func formatarDimensoes(medidas ...*float64) string {
+	partes := []string{}
+	for _, medida := range medidas {
+		if medida != nil {
+			partes = append(partes, formatarDecimal(*medida, 2, 3))
+		}
+	}
+	return strings.Join(partes, " × ")
+}
+
+// formatarMoeda formata o valor em reais no padrão brasileiro (R$ 1.234,56)
+func formatarMoeda(valor float64) string {
+	return "R$ " + formatarDecimal(valor, 2, 2)
+}
+
+// formatarDecimal formata o número no padrão brasileiro com no mínimo e no máximo
+// as casas decimais informadas, removendo zeros excedentes
+func formatarDecimal(valor float64, minimo, maximo int) string {
+	texto := strconv.FormatFloat(math.Abs(valor), 'f', maximo, 64)
+	inteiro, decimal, _ := strings.Cut(texto, ".")
+	for len(decimal) > minimo && strings.HasSuffix(decimal, "0") {
+		decimal = decimal[:len(decimal)-1]
+	}
+
+	var sb strings.Builder
+	if valor < 0 && strings.Trim(texto, "0.") != "" {
+		sb.WriteByte('-')
+	}
+	for i, r := range inteiro {
+		if i > 0 && (len(inteiro)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(r)
+	}
+	if decimal != "" {
+		sb.WriteByte(',')
+		sb.WriteString(decimal)
+	}
+	return sb.String()
+}
@@ -1,103 +1,172 @@
 package services
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 
+	"mobgran-importer-go/internal/logs"
 	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/paginacao"
+	"mobgran-importer-go/pkg/medidas"
 )
 
 // ProdutosService gerencia operações relacionadas a produtos
 type ProdutosService struct {
-	db *sql.DB
+	db        *sql.DB
+	auditoria *AuditoriaService
+	eventos   *EventosService
 }
 
-// NewProdutosService cria uma nova instância do ProdutosService
-func NewProdutosService(db *sql.DB) *ProdutosService {
-	return &ProdutosService{db: db}
+// NewProdutosService cria uma nova instância do ProdutosService. As aprovações,
+// alterações e remoções são gravadas como eventos (produto.*), se informado.
+func NewProdutosService(db *sql.DB, auditoria *AuditoriaService, eventos *EventosService) *ProdutosService {
+	return &ProdutosService{db: db, auditoria: auditoria, eventos: eventos}
 }
 
-// ListarCavaletesDisponiveis lista cavaletes disponíveis para aprovação
-func (s *ProdutosService) ListarCavaletesDisponiveis(traderID uuid.UUID, limit, offset int) ([]models.CavaleteDisponivel, error) {
-	query := `
+// colunasProduto são as colunas lidas por escanearProduto, na mesma ordem
+const colunasProduto = `id, trader_id, cavalete_id, nome_customizado, preco_venda, descricao,
+			   visivel, destaque, ordem_exibicao, status, cliente, cliente_id, reserva_observacao, reservado_em,
+			   reserva_expira_em, vendido_em, valor_venda, created_at, updated_at,
+			   EXISTS(SELECT 1 FROM cavaletes rc WHERE rc.id = cavalete_id AND rc.removido_na_origem_em IS NOT NULL)`
+
+// escanearProduto lê uma linha com as colunasProduto seguidas das colunas extras
+func escanearProduto(row interface{ Scan(...interface{}) error }, p *models.ProdutoAprovado, extras ...interface{}) error {
+	return row.Scan(append([]interface{}{
+		&p.ID, &p.TraderID, &p.CavaleteID, &p.NomeCustomizado, &p.PrecoVenda,
+		&p.Descricao, &p.Visivel, &p.Destaque, &p.OrdemExibicao, &p.Status,
+		&p.Cliente, &p.ClienteID, &p.ReservaObservacao, &p.ReservadoEm, &p.ReservaExpiraEm,
+		&p.VendidoEm, &p.ValorVenda, &p.CreatedAt, &p.UpdatedAt, &p.RemovidoNaOrigem,
+	}, extras...)...)
+}
+
+// ListarCavaletesDisponiveis lista cavaletes disponíveis para aprovação, opcionalmente
+// apenas os de um fornecedor
+func (s *ProdutosService) ListarCavaletesDisponiveis(ctx context.Context, traderID uuid.UUID, fornecedorID *uuid.UUID, params *paginacao.Parametros) ([]models.CavaleteDisponivel, *paginacao.Pagina, error) {
+	where := `
+		WHERE o.situacao = 'ativa' AND o.trader_id = $1
+			AND o.deleted_at IS NULL AND c.deleted_at IS NULL`
+	args := []interface{}{traderID}
+	if fornecedorID != nil {
+		args = append(args, *fornecedorID)
+		where += fmt.Sprintf(" AND o.fornecedor_id = $%d", len(args))
+	}
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM cavaletes c JOIN ofertas o ON c.oferta_id = o.id`+where, args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar cavaletes disponíveis")
+			return nil, nil, fmt.Errorf("erro ao buscar cavaletes disponíveis")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("c.created_at", "c.id", len(args)+1)
+	if condicao != "" {
+		where += " AND " + condicao
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
 		SELECT 
 			c.id, c.oferta_id, c.codigo, c.bloco, c.nome_material, c.nome_espessura,
-			c.nome_classificacao, c.nome_acabamento, c.comprimento, c.altura, c.largura,
+			c.produto_cliente, c.espessura_cliente, c.nome_classificacao, c.nome_acabamento, c.comprimento, c.altura, c.largura,
 			c.metragem, c.peso, c.tipo_metragem, c.imagem_principal, c.imagens_adicionais,
 			c.created_at, c.updated_at,
-			o.trader_id, o.nome_empresa,
+			c.espessura_mm, c.comprimento_m, c.altura_m, c.metragem_m2, c.peso_kg, c.unidade_dimensoes,
+			o.trader_id, o.nome_empresa, o.fornecedor_id,
 			CASE WHEN pa.id IS NOT NULL THEN true ELSE false END as ja_aprovado
 		FROM cavaletes c
 		JOIN ofertas o ON c.oferta_id = o.id
-		LEFT JOIN produtos_aprovados pa ON pa.cavalete_id = c.id AND pa.trader_id = $1
-		WHERE o.situacao = 'ativa' AND o.trader_id = $1
-		ORDER BY c.created_at DESC
-		LIMIT $2 OFFSET $3
-	`
-
-	rows, err := s.db.Query(query, traderID, limit, offset)
+		LEFT JOIN produtos_aprovados pa ON pa.cavalete_id = c.id AND pa.trader_id = $1 AND pa.deleted_at IS NULL
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao buscar cavaletes disponíveis")
-		return nil, fmt.Errorf("erro ao buscar cavaletes disponíveis")
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar cavaletes disponíveis")
+		return nil, nil, fmt.Errorf("erro ao buscar cavaletes disponíveis")
 	}
 	defer rows.Close()
 
-	var cavaletes []models.CavaleteDisponivel
+	cavaletes := []models.CavaleteDisponivel{}
 	for rows.Next() {
 		var c models.CavaleteDisponivel
 		err := rows.Scan(
 			&c.ID, &c.OfertaID, &c.Codigo, &c.Bloco, &c.NomeMaterial, &c.NomeEspessura,
-			&c.NomeClassificacao, &c.NomeAcabamento, &c.Comprimento, &c.Altura, &c.Largura,
+			&c.ProdutoCliente, &c.EspessuraCliente, &c.NomeClassificacao, &c.NomeAcabamento, &c.Comprimento, &c.Altura, &c.Largura,
 			&c.Metragem, &c.Peso, &c.TipoMetragem, &c.ImagemPrincipal, &c.ImagensAdicionais,
 			&c.CreatedAt, &c.UpdatedAt,
-			&c.TraderID, &c.NomeEmpresa, &c.JaAprovado,
+			&c.Medidas.EspessuraMM, &c.Medidas.ComprimentoM, &c.Medidas.AlturaM,
+			&c.Medidas.MetragemM2, &c.Medidas.PesoKg, &c.Medidas.UnidadeDimensoes,
+			&c.TraderID, &c.NomeEmpresa, &c.FornecedorID, &c.JaAprovado,
 		)
 		if err != nil {
-			logrus.WithError(err).Error("Erro ao escanear cavalete disponível")
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear cavalete disponível")
 			continue
 		}
 		cavaletes = append(cavaletes, c)
 	}
 
-	return cavaletes, nil
+	cavaletes, pagina := paginacao.Montar(cavaletes, params, func(c models.CavaleteDisponivel) (time.Time, uuid.UUID) {
+		id, _ := uuid.Parse(c.ID)
+		return c.CreatedAt, id
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return cavaletes, pagina, nil
 }
 
 // AprovarProduto aprova um cavalete como produto do trader
-func (s *ProdutosService) AprovarProduto(traderID uuid.UUID, request *models.ProdutoAprovarRequest) (*models.ProdutoAprovado, error) {
-	// Verifica se o cavalete existe e está disponível
-	var cavaleteExists bool
-	err := s.db.QueryRow(`
-		SELECT EXISTS(
-			SELECT 1 FROM cavaletes_disponiveis cd
-			JOIN ofertas o ON cd.oferta_id = o.uuid_link
-			WHERE cd.id = $1 AND o.situacao = 'ativa'
-		)
-	`, request.CavaleteID).Scan(&cavaleteExists)
-
+func (s *ProdutosService) AprovarProduto(ctx context.Context, traderID uuid.UUID, request *models.ProdutoAprovarRequest) (*models.ProdutoAprovado, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao verificar cavalete")
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
 		return nil, fmt.Errorf("erro interno do servidor")
 	}
+	defer tx.Rollback()
+
+	// Verifica se o cavalete pertence a uma oferta ativa do trader. A linha fica travada
+	// até o fim da transação, o que serializa aprovações simultâneas do mesmo cavalete.
+	var cavaleteID uuid.UUID
+	err = tx.QueryRowContext(ctx, `
+		SELECT c.id FROM cavaletes c
+		JOIN ofertas o ON c.oferta_id = o.id
+		WHERE c.id = $1 AND o.trader_id = $2 AND o.situacao = 'ativa'
+			AND o.deleted_at IS NULL AND c.deleted_at IS NULL
+		FOR UPDATE OF c
+	`, request.CavaleteID, traderID).Scan(&cavaleteID)
 
-	if !cavaleteExists {
+	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("cavalete não encontrado ou não disponível")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar cavalete")
+		return nil, fmt.Errorf("erro interno do servidor")
 	}
 
 	// Verifica se já foi aprovado pelo trader
 	var jaAprovado bool
-	err = s.db.QueryRow(`
+	err = tx.QueryRowContext(ctx, `
 		SELECT EXISTS(
 			SELECT 1 FROM produtos_aprovados
-			WHERE trader_id = $1 AND cavalete_id = $2
+			WHERE trader_id = $1 AND cavalete_id = $2 AND deleted_at IS NULL
 		)
 	`, traderID, request.CavaleteID).Scan(&jaAprovado)
 
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao verificar produto já aprovado")
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar produto já aprovado")
 		return nil, fmt.Errorf("erro interno do servidor")
 	}
 
@@ -107,14 +176,14 @@ func (s *ProdutosService) AprovarProduto(traderID uuid.UUID, request *models.Pro
 
 	// Busca a próxima ordem de exibição
 	var proximaOrdem int
-	err = s.db.QueryRow(`
+	err = tx.QueryRowContext(ctx, `
 		SELECT COALESCE(MAX(ordem_exibicao), 0) + 1
 		FROM produtos_aprovados
-		WHERE trader_id = $1
+		WHERE trader_id = $1 AND deleted_at IS NULL
 	`, traderID).Scan(&proximaOrdem)
 
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao buscar próxima ordem")
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar próxima ordem")
 		return nil, fmt.Errorf("erro interno do servidor")
 	}
 
@@ -129,6 +198,7 @@ func (s *ProdutosService) AprovarProduto(traderID uuid.UUID, request *models.Pro
 		Visivel:         true, // Padrão visível
 		Destaque:        false, // Padrão sem destaque
 		OrdemExibicao:   proximaOrdem,
+		Status:          models.StatusProdutoDisponivel,
 	}
 
 	// Aplica configurações opcionais
@@ -144,21 +214,39 @@ func (s *ProdutosService) AprovarProduto(traderID uuid.UUID, request *models.Pro
 			id, trader_id, cavalete_id, nome_customizado, preco_venda, descricao,
 			visivel, destaque, ordem_exibicao, created_at, updated_at
 		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW(), NOW())
+		RETURNING created_at, updated_at
 	`
 
-	_, err = s.db.Exec(query,
+	err = tx.QueryRowContext(ctx, query,
 		produto.ID, produto.TraderID, produto.CavaleteID, produto.NomeCustomizado,
 		produto.PrecoVenda, produto.Descricao, produto.Visivel, produto.Destaque,
 		produto.OrdemExibicao,
-	)
+	).Scan(&produto.CreatedAt, &produto.UpdatedAt)
 
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao inserir produto aprovado")
+		logs.Do(ctx).WithError(err).Error("Erro ao inserir produto aprovado")
 		return nil, fmt.Errorf("erro ao aprovar produto")
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"trader_id":   traderID,
+	if err := registrarPreco(ctx, tx, produto.ID, nil, produto.PrecoVenda); err != nil {
+		return nil, err
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoProdutoAprovado, "produto", produto.ID.String(), nil, produto); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventos.registrarProduto(ctx, tx, models.EventoProdutoAprovado, models.AcaoProdutoAprovado, produto); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da aprovação")
+		return nil, fmt.Errorf("erro ao aprovar produto")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":     traderID,
 		"produto_id":  produto.ID,
 		"cavalete_id": request.CavaleteID,
 	}).Info("Produto aprovado com sucesso")
@@ -166,60 +254,73 @@ func (s *ProdutosService) AprovarProduto(traderID uuid.UUID, request *models.Pro
 	return produto, nil
 }
 
-// ListarProdutosAprovados lista produtos aprovados do trader
-func (s *ProdutosService) ListarProdutosAprovados(traderID uuid.UUID, limit, offset int) ([]models.ProdutoAprovado, error) {
-	query := `
-		SELECT id, trader_id, cavalete_id, nome_customizado, preco_venda, descricao,
-			   visivel, destaque, ordem_exibicao, created_at, updated_at
+// ListarProdutosAprovados lista produtos aprovados do trader, opcionalmente filtrando pelo status de venda
+func (s *ProdutosService) ListarProdutosAprovados(ctx context.Context, traderID uuid.UUID, status string, params *paginacao.Parametros) ([]models.ProdutoAprovado, *paginacao.Pagina, error) {
+	where := "WHERE trader_id = $1 AND deleted_at IS NULL"
+	args := []interface{}{traderID}
+	if status != "" {
+		where += " AND status = $2"
+		args = append(args, status)
+	}
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM produtos_aprovados "+where, args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar produtos aprovados")
+			return nil, nil, fmt.Errorf("erro ao buscar produtos aprovados")
+		}
+	}
+
+	condicao, argsCursor, orderBy := params.Keyset("created_at", "id", len(args)+1)
+	if condicao != "" {
+		where += " AND " + condicao
+		args = append(args, argsCursor...)
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM produtos_aprovados
-		WHERE trader_id = $1
-		ORDER BY ordem_exibicao ASC, created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, colunasProduto, where, orderBy, len(args)+1, len(args)+2)
+	args = append(args, limit, offset)
 
-	rows, err := s.db.Query(query, traderID, limit, offset)
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao buscar produtos aprovados")
-		return nil, fmt.Errorf("erro ao buscar produtos aprovados")
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produtos aprovados")
+		return nil, nil, fmt.Errorf("erro ao buscar produtos aprovados")
 	}
 	defer rows.Close()
 
-	var produtos []models.ProdutoAprovado
+	produtos := []models.ProdutoAprovado{}
 	for rows.Next() {
 		var p models.ProdutoAprovado
-		err := rows.Scan(
-			&p.ID, &p.TraderID, &p.CavaleteID, &p.NomeCustomizado, &p.PrecoVenda,
-			&p.Descricao, &p.Visivel, &p.Destaque, &p.OrdemExibicao,
-			&p.CreatedAt, &p.UpdatedAt,
-		)
-		if err != nil {
-			logrus.WithError(err).Error("Erro ao escanear produto aprovado")
+		if err := escanearProduto(rows, &p); err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear produto aprovado")
 			continue
 		}
 		produtos = append(produtos, p)
 	}
 
-	return produtos, nil
+	produtos, pagina := paginacao.Montar(produtos, params, func(p models.ProdutoAprovado) (time.Time, uuid.UUID) {
+		return p.CreatedAt, p.ID
+	})
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return produtos, pagina, nil
 }
 
 // AtualizarProduto atualiza um produto aprovado
-func (s *ProdutosService) AtualizarProduto(traderID, produtoID uuid.UUID, request *models.ProdutoAtualizarRequest) (*models.ProdutoAprovado, error) {
-	// Verifica se o produto existe e pertence ao trader
-	var exists bool
-	err := s.db.QueryRow(`
-		SELECT EXISTS(
-			SELECT 1 FROM produtos_aprovados
-			WHERE id = $1 AND trader_id = $2
-		)
-	`, produtoID, traderID).Scan(&exists)
-
+func (s *ProdutosService) AtualizarProduto(ctx context.Context, traderID, produtoID uuid.UUID, request *models.ProdutoAtualizarRequest) (*models.ProdutoAprovado, error) {
+	// Verifica se o produto existe e pertence ao trader, guardando o estado anterior para auditoria
+	antes, err := s.BuscarProduto(ctx, traderID, produtoID)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao verificar produto")
-		return nil, fmt.Errorf("erro interno do servidor")
-	}
-
-	if !exists {
-		return nil, fmt.Errorf("produto não encontrado")
+		return nil, err
 	}
 
 	// Constrói a query de atualização dinamicamente
@@ -274,61 +375,106 @@ func (s *ProdutosService) AtualizarProduto(traderID, produtoID uuid.UUID, reques
 	query := fmt.Sprintf(`
 		UPDATE produtos_aprovados
 		SET %s
-		WHERE id = $%d AND trader_id = $%d
+		WHERE id = $%d AND trader_id = $%d AND deleted_at IS NULL
 	`, strings.Join(setParts, ", "), argIndex, argIndex+1)
 
-	_, err = s.db.Exec(query, args...)
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao atualizar produto")
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar produto")
+		return nil, fmt.Errorf("erro ao atualizar produto")
+	}
+
+	// Registra a alteração de preço no histórico do produto
+	if request.PrecoVenda != nil && *request.PrecoVenda > 0 && *request.PrecoVenda != antes.PrecoVenda {
+		if err := registrarPreco(ctx, tx, produtoID, &antes.PrecoVenda, *request.PrecoVenda); err != nil {
+			return nil, err
+		}
+	}
+
+	depois := &models.ProdutoAprovado{}
+	err = escanearProduto(tx.QueryRowContext(ctx, `
+		SELECT `+colunasProduto+`
+		FROM produtos_aprovados
+		WHERE id = $1
+	`, produtoID), depois)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produto atualizado")
+		return nil, fmt.Errorf("erro ao atualizar produto")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoProdutoAtualizado, "produto", produtoID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventos.registrarProduto(ctx, tx, models.EventoProdutoAtualizado, models.AcaoProdutoAtualizado, depois); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da atualização do produto")
 		return nil, fmt.Errorf("erro ao atualizar produto")
 	}
 
-	// Retorna o produto atualizado
-	return s.BuscarProduto(traderID, produtoID)
+	return depois, nil
 }
 
 // BuscarProduto busca um produto específico do trader
-func (s *ProdutosService) BuscarProduto(traderID, produtoID uuid.UUID) (*models.ProdutoAprovado, error) {
+func (s *ProdutosService) BuscarProduto(ctx context.Context, traderID, produtoID uuid.UUID) (*models.ProdutoAprovado, error) {
 	var produto models.ProdutoAprovado
 
 	query := `
-		SELECT id, trader_id, cavalete_id, nome_customizado, preco_venda, descricao,
-			   visivel, destaque, ordem_exibicao, created_at, updated_at
+		SELECT ` + colunasProduto + `
 		FROM produtos_aprovados
-		WHERE id = $1 AND trader_id = $2
+		WHERE id = $1 AND trader_id = $2 AND deleted_at IS NULL
 	`
 
-	err := s.db.QueryRow(query, produtoID, traderID).Scan(
-		&produto.ID, &produto.TraderID, &produto.CavaleteID, &produto.NomeCustomizado,
-		&produto.PrecoVenda, &produto.Descricao, &produto.Visivel, &produto.Destaque,
-		&produto.OrdemExibicao, &produto.CreatedAt, &produto.UpdatedAt,
-	)
+	err := escanearProduto(s.db.QueryRowContext(ctx, query, produtoID, traderID), &produto)
 
 	if err == sql.ErrNoRows {
 		return nil, fmt.Errorf("produto não encontrado")
 	} else if err != nil {
-		logrus.WithError(err).Error("Erro ao buscar produto")
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produto")
 		return nil, fmt.Errorf("erro interno do servidor")
 	}
 
 	return &produto, nil
 }
 
-// RemoverProduto remove um produto aprovado
-func (s *ProdutosService) RemoverProduto(traderID, produtoID uuid.UUID) error {
-	result, err := s.db.Exec(`
-		DELETE FROM produtos_aprovados
-		WHERE id = $1 AND trader_id = $2
+// RemoverProduto remove (soft delete) um produto aprovado
+func (s *ProdutosService) RemoverProduto(ctx context.Context, traderID, produtoID uuid.UUID) error {
+	antes, err := s.BuscarProduto(ctx, traderID, produtoID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE produtos_aprovados
+		SET deleted_at = NOW()
+		WHERE id = $1 AND trader_id = $2 AND deleted_at IS NULL
 	`, produtoID, traderID)
 
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao remover produto")
+		logs.Do(ctx).WithError(err).Error("Erro ao remover produto")
 		return fmt.Errorf("erro ao remover produto")
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao verificar linhas afetadas")
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar linhas afetadas")
 		return fmt.Errorf("erro interno do servidor")
 	}
 
@@ -336,168 +482,358 @@ func (s *ProdutosService) RemoverProduto(traderID, produtoID uuid.UUID) error {
 		return fmt.Errorf("produto não encontrado")
 	}
 
-	logrus.WithFields(logrus.Fields{
-		"trader_id":  traderID,
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoProdutoRemovido, "produto", produtoID.String(), antes, nil); err != nil {
+		return err
+	}
+
+	if err := s.eventos.registrarProduto(ctx, tx, models.EventoProdutoRemovido, models.AcaoProdutoRemovido, antes); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da remoção")
+		return fmt.Errorf("erro ao remover produto")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":    traderID,
 		"produto_id": produtoID,
 	}).Info("Produto removido com sucesso")
 
 	return nil
 }
 
-// ListarVitrinePublica lista produtos da vitrine pública
-func (s *ProdutosService) ListarVitrinePublica(limit, offset int, destaque bool) ([]models.VitrinePublica, error) {
-	query := `
-		SELECT * FROM vitrine_publica
-		WHERE ($3 = false OR destaque = true)
-		ORDER BY 
-			CASE WHEN destaque THEN ordem_exibicao ELSE 999999 END ASC,
-			ordem_exibicao ASC,
-			created_at DESC
-		LIMIT $1 OFFSET $2
-	`
+// ordenacoesVitrine mapeia as ordenações aceitas para cláusulas ORDER BY
+var ordenacoesVitrine = map[string]string{
+	models.OrdenacaoVitrineDestaque:     "CASE WHEN destaque THEN ordem_exibicao ELSE 999999 END ASC, ordem_exibicao ASC, created_at DESC",
+	models.OrdenacaoVitrinePrecoAsc:     "preco_venda ASC, created_at DESC",
+	models.OrdenacaoVitrinePrecoDesc:    "preco_venda DESC, created_at DESC",
+	models.OrdenacaoVitrineRecentes:     "created_at DESC, id DESC",
+	models.OrdenacaoVitrineMetragemDesc: "metragem_m2 DESC NULLS LAST, created_at DESC",
+	models.OrdenacaoVitrineNome:         "nome_customizado ASC, created_at DESC",
+}
 
-	rows, err := s.db.Query(query, limit, offset, destaque)
+// OrdenacaoVitrineValida verifica se a ordenação informada é suportada
+func OrdenacaoVitrineValida(ordenacao string) bool {
+	_, ok := ordenacoesVitrine[ordenacao]
+	return ok
+}
+
+// ListarVitrinePublica lista produtos da vitrine pública aplicando filtros, busca e ordenação.
+// A paginação por cursor só é possível na ordenação por mais recentes; nas demais, por offset.
+func (s *ProdutosService) ListarVitrinePublica(ctx context.Context, filtro *models.FiltroVitrine) ([]models.VitrinePublica, *paginacao.Pagina, error) {
+	// Constrói o WHERE dinamicamente
+	conditions := []string{}
+	args := []interface{}{}
+	argIndex := 1
+
+	adicionar := func(condition string, value interface{}) {
+		conditions = append(conditions, strings.ReplaceAll(condition, "$?", fmt.Sprintf("$%d", argIndex)))
+		args = append(args, value)
+		argIndex++
+	}
+
+	if filtro.TraderID != nil {
+		adicionar("trader_id = $?", *filtro.TraderID)
+	}
+	if filtro.TraderSlug != "" {
+		adicionar("trader_id IN (SELECT id FROM traders WHERE slug = $?)", filtro.TraderSlug)
+	}
+	if filtro.FornecedorID != nil {
+		adicionar("fornecedor_id = $?", *filtro.FornecedorID)
+	}
+	if filtro.Destaque {
+		conditions = append(conditions, "destaque = true")
+	}
+	if filtro.Material != "" {
+		adicionar("(nome_material ILIKE $? OR produto_cliente ILIKE $?)", "%"+filtro.Material+"%")
+	}
+	if filtro.Espessura != "" {
+		if mm, ok := medidas.EspessuraMM(filtro.Espessura); ok {
+			adicionar("espessura_mm = $?", mm)
+		} else {
+			adicionar("lower(nome_espessura) = lower($?)", filtro.Espessura)
+		}
+	}
+	if filtro.Classificacao != "" {
+		adicionar("lower(nome_classificacao) = lower($?)", filtro.Classificacao)
+	}
+	if filtro.Acabamento != "" {
+		adicionar("lower(nome_acabamento) = lower($?)", filtro.Acabamento)
+	}
+	if filtro.ComprimentoMin != nil {
+		adicionar("comprimento_m >= $?", *filtro.ComprimentoMin)
+	}
+	if filtro.ComprimentoMax != nil {
+		adicionar("comprimento_m <= $?", *filtro.ComprimentoMax)
+	}
+	if filtro.AlturaMin != nil {
+		adicionar("altura_m >= $?", *filtro.AlturaMin)
+	}
+	if filtro.AlturaMax != nil {
+		adicionar("altura_m <= $?", *filtro.AlturaMax)
+	}
+	if filtro.MetragemMin != nil {
+		adicionar("metragem_m2 >= $?", *filtro.MetragemMin)
+	}
+	if filtro.MetragemMax != nil {
+		adicionar("metragem_m2 <= $?", *filtro.MetragemMax)
+	}
+	if filtro.PrecoMin != nil {
+		adicionar("preco_venda >= $?", *filtro.PrecoMin)
+	}
+	if filtro.PrecoMax != nil {
+		adicionar("preco_venda <= $?", *filtro.PrecoMax)
+	}
+	if filtro.Busca != "" {
+		adicionar("(nome_customizado ILIKE $? OR descricao ILIKE $?)", "%"+filtro.Busca+"%")
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	params := &filtro.Paginacao
+
+	var total int
+	if params.IncluirTotal {
+		err := s.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM vitrine_publica "+where, args...).Scan(&total)
+		if err != nil {
+			logs.Do(ctx).WithError(err).Error("Erro ao contar produtos da vitrine pública")
+			return nil, nil, fmt.Errorf("erro ao buscar vitrine pública")
+		}
+	}
+
+	orderBy, ok := ordenacoesVitrine[filtro.Ordenacao]
+	if !ok {
+		orderBy = ordenacoesVitrine[models.OrdenacaoVitrineDestaque]
+	}
+
+	// O cursor codifica apenas (created_at, id); nas demais ordenações ele não indicaria
+	// a posição na listagem
+	keyset := filtro.Ordenacao == models.OrdenacaoVitrineRecentes
+	if params.Cursor != nil && !keyset {
+		return nil, nil, fmt.Errorf("paginação por cursor disponível apenas com ordenacao=recentes")
+	}
+	if keyset {
+		var condicao string
+		var argsCursor []interface{}
+		condicao, argsCursor, orderBy = params.Keyset("created_at", "id", argIndex)
+		if condicao != "" {
+			if where == "" {
+				where = "WHERE " + condicao
+			} else {
+				where += " AND " + condicao
+			}
+			args = append(args, argsCursor...)
+			argIndex += len(argsCursor)
+		}
+	} else {
+		orderBy += ", id"
+	}
+	limit, offset := params.LimitOffset()
+
+	query := fmt.Sprintf(`
+		SELECT id, trader_id, nome_customizado, preco_venda, descricao, destaque, ordem_exibicao,
+			   codigo, bloco, nome_material, nome_espessura, produto_cliente, espessura_cliente,
+			   nome_classificacao, nome_acabamento,
+			   comprimento, altura, largura, metragem, peso, tipo_metragem,
+			   espessura_mm, comprimento_m, altura_m, metragem_m2, peso_kg, unidade_dimensoes,
+			   imagem_principal, imagens_adicionais, trader_nome, trader_empresa, fornecedor_id,
+			   status, created_at, updated_at
+		FROM vitrine_publica
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d
+	`, where, orderBy, argIndex, argIndex+1)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao buscar vitrine pública")
-		return nil, fmt.Errorf("erro ao buscar vitrine pública")
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar vitrine pública")
+		return nil, nil, fmt.Errorf("erro ao buscar vitrine pública")
 	}
 	defer rows.Close()
 
-	var produtos []models.VitrinePublica
+	produtos := []models.VitrinePublica{}
 	for rows.Next() {
 		var p models.VitrinePublica
 		err := rows.Scan(
 			&p.ID, &p.TraderID, &p.NomeCustomizado, &p.PrecoVenda, &p.Descricao,
 			&p.Destaque, &p.OrdemExibicao, &p.Codigo, &p.Bloco, &p.NomeMaterial,
-			&p.NomeEspessura, &p.NomeClassificacao, &p.NomeAcabamento,
+			&p.NomeEspessura, &p.ProdutoCliente, &p.EspessuraCliente, &p.NomeClassificacao, &p.NomeAcabamento,
 			&p.Comprimento, &p.Altura, &p.Largura, &p.Metragem, &p.Peso,
-			&p.TipoMetragem, &p.ImagemPrincipal, &p.ImagensAdicionais,
-			&p.TraderNome, &p.TraderEmpresa, &p.CreatedAt, &p.UpdatedAt,
+			&p.TipoMetragem, &p.Medidas.EspessuraMM, &p.Medidas.ComprimentoM, &p.Medidas.AlturaM,
+			&p.Medidas.MetragemM2, &p.Medidas.PesoKg, &p.Medidas.UnidadeDimensoes,
+			&p.ImagemPrincipal, &p.ImagensAdicionais,
+			&p.TraderNome, &p.TraderEmpresa, &p.FornecedorID, &p.Status, &p.CreatedAt, &p.UpdatedAt,
 		)
 		if err != nil {
-			logrus.WithError(err).Error("Erro ao escanear produto da vitrine")
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear produto da vitrine")
 			continue
 		}
 		produtos = append(produtos, p)
 	}
 
-	return produtos, nil
+	var chave func(models.VitrinePublica) (time.Time, uuid.UUID)
+	if keyset {
+		chave = func(p models.VitrinePublica) (time.Time, uuid.UUID) {
+			return p.CreatedAt, p.ID
+		}
+	}
+
+	produtos, pagina := paginacao.Montar(produtos, params, chave)
+	if params.IncluirTotal {
+		pagina.Total = &total
+	}
+
+	return produtos, pagina, nil
+}
+
+// slugsReservados não podem ser usados por traders pois colidem com rotas de /vitrine
+var slugsReservados = map[string]bool{
+	"publica": true,
+	"slug":    true,
+}
+
+var slugRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// BuscarTraderPorSlug busca os dados públicos de um trader ativo pelo slug da vitrine
+func (s *ProdutosService) BuscarTraderPorSlug(ctx context.Context, slug string) (*models.VitrineTrader, error) {
+	var trader models.VitrineTrader
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, slug, nome, empresa
+		FROM traders
+		WHERE slug = $1 AND ativo = true
+	`, slug).Scan(&trader.ID, &trader.Slug, &trader.Nome, &trader.Empresa)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("vitrine não encontrada")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar trader pelo slug")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	return &trader, nil
+}
+
+// AtualizarSlug altera o slug da vitrine pública do trader
+func (s *ProdutosService) AtualizarSlug(ctx context.Context, traderID uuid.UUID, slug string) (*models.VitrineTrader, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if !slugRegex.MatchString(slug) || slugsReservados[slug] {
+		return nil, fmt.Errorf("slug inválido")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	var anterior string
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(slug, '') FROM traders WHERE id = $1 FOR UPDATE`, traderID).Scan(&anterior)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("trader não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar trader")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	var emUso bool
+	err = tx.QueryRowContext(ctx, `
+		SELECT EXISTS(SELECT 1 FROM traders WHERE slug = $1 AND id <> $2)
+	`, slug, traderID).Scan(&emUso)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao verificar slug")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	if emUso {
+		return nil, fmt.Errorf("slug já está em uso")
+	}
+
+	var trader models.VitrineTrader
+	err = tx.QueryRowContext(ctx, `
+		UPDATE traders SET slug = $1 WHERE id = $2
+		RETURNING id, slug, nome, empresa
+	`, slug, traderID).Scan(&trader.ID, &trader.Slug, &trader.Nome, &trader.Empresa)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao atualizar slug")
+		return nil, fmt.Errorf("erro ao atualizar slug")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, models.AcaoSlugAtualizado, "trader", traderID.String(),
+		map[string]string{"slug": anterior}, map[string]string{"slug": slug}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da atualização do slug")
+		return nil, fmt.Errorf("erro ao atualizar slug")
+	}
+
+	return &trader, nil
 }
 
 // ObterEstatisticas retorna estatísticas dos produtos do trader
-func (s *ProdutosService) ObterEstatisticas(traderID uuid.UUID) (*models.EstatisticasProdutos, error) {
+func (s *ProdutosService) ObterEstatisticas(ctx context.Context, traderID uuid.UUID) (*models.EstatisticasProdutos, error) {
 	var stats models.EstatisticasProdutos
 
-	// Log para debug
-	logrus.WithField("trader_id", traderID).Info("Buscando estatísticas para trader")
-
 	// Query para contar produtos aprovados do trader
-	queryProdutos := `SELECT COUNT(*) FROM produtos_aprovados WHERE trader_id = $1`
+	queryProdutos := `SELECT COUNT(*) FROM produtos_aprovados WHERE trader_id = $1 AND deleted_at IS NULL`
 	
-	err := s.db.QueryRow(queryProdutos, traderID).Scan(&stats.TotalProdutos)
+	err := s.db.QueryRowContext(ctx, queryProdutos, traderID).Scan(&stats.TotalProdutos)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao contar produtos aprovados")
+		logs.Do(ctx).WithError(err).Error("Erro ao contar produtos aprovados")
 		return nil, fmt.Errorf("erro ao buscar estatísticas")
 	}
 
-	// Query para contar produtos visíveis (assumindo que todos os aprovados são visíveis)
-	stats.ProdutosVisiveis = stats.TotalProdutos
+	// Query para contar produtos visíveis na vitrine
+	queryVisiveis := `SELECT COUNT(*) FROM produtos_aprovados WHERE trader_id = $1 AND visivel = true AND deleted_at IS NULL`
+
+	err = s.db.QueryRowContext(ctx, queryVisiveis, traderID).Scan(&stats.ProdutosVisiveis)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao contar produtos visíveis")
+		return nil, fmt.Errorf("erro ao buscar estatísticas")
+	}
 
 	// Query para contar produtos em destaque (assumindo campo destaque ou similar)
-	queryDestaque := `SELECT COUNT(*) FROM produtos_aprovados WHERE trader_id = $1 AND destaque = true`
+	queryDestaque := `SELECT COUNT(*) FROM produtos_aprovados WHERE trader_id = $1 AND destaque = true AND deleted_at IS NULL`
 	
-	err = s.db.QueryRow(queryDestaque, traderID).Scan(&stats.ProdutosDestaque)
+	err = s.db.QueryRowContext(ctx, queryDestaque, traderID).Scan(&stats.ProdutosDestaque)
 	if err != nil {
 		// Se não existe campo destaque, definir como 0
-		logrus.WithError(err).Warn("Campo destaque não encontrado, definindo como 0")
+		logs.Do(ctx).WithError(err).Warn("Campo destaque não encontrado, definindo como 0")
 		stats.ProdutosDestaque = 0
 	}
 
-	// Query para contar cavaletes disponíveis
+	// Query para contar cavaletes das ofertas ativas do trader ainda não aprovados por ele
 	queryCavaletes := `
-		SELECT COUNT(*) 
-		FROM cavaletes c 
-		WHERE c.id NOT IN (
-			SELECT DISTINCT cavalete_id 
-			FROM produtos_aprovados 
-			WHERE cavalete_id IS NOT NULL
-		)`
-	
-	err = s.db.QueryRow(queryCavaletes).Scan(&stats.CavaletesDisponiveis)
+		SELECT COUNT(*)
+		FROM cavaletes c
+		JOIN ofertas o ON o.id = c.oferta_id
+		WHERE o.trader_id = $1 AND o.situacao = 'ativa' AND o.deleted_at IS NULL
+			AND c.deleted_at IS NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM produtos_aprovados pa
+				WHERE pa.cavalete_id = c.id AND pa.trader_id = $1 AND pa.deleted_at IS NULL
+			)`
+
+	err = s.db.QueryRowContext(ctx, queryCavaletes, traderID).Scan(&stats.CavaletesDisponiveis)
 	if err != nil {
-		logrus.WithError(err).Error("Erro ao contar cavaletes disponíveis")
+		logs.Do(ctx).WithError(err).Error("Erro ao contar cavaletes disponíveis")
 		return nil, fmt.Errorf("erro ao buscar estatísticas")
 	}
 
-	logrus.WithFields(logrus.Fields{
+	logs.Do(ctx).WithFields(logrus.Fields{
 		"total_produtos":         stats.TotalProdutos,
 		"produtos_visiveis":      stats.ProdutosVisiveis,
 		"produtos_destaque":      stats.ProdutosDestaque,
 		"cavaletes_disponiveis":  stats.CavaletesDisponiveis,
-	}).Info("Estatísticas calculadas com sucesso")
+	}).Debug("Estatísticas calculadas com sucesso")
 
 	return &stats, nil
 }
-
-// LimparTodosRegistros remove todos os registros do banco de dados
-func (s *ProdutosService) LimparTodosRegistros() error {
-	logrus.Info("Iniciando limpeza completa do banco de dados")
-
-	// Lista de tabelas para limpar na ordem correta (respeitando foreign keys)
-	tabelas := []string{
-		"produtos_aprovados",
-		"cavaletes", 
-		"ofertas",
-		"traders",
-	}
-
-	// Iniciar transação
-	tx, err := s.db.Begin()
-	if err != nil {
-		logrus.WithError(err).Error("Erro ao iniciar transação para limpeza")
-		return fmt.Errorf("erro ao iniciar transação: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Limpar cada tabela
-	for _, tabela := range tabelas {
-		query := fmt.Sprintf("DELETE FROM %s", tabela)
-		
-		result, err := tx.Exec(query)
-		if err != nil {
-			logrus.WithError(err).WithField("tabela", tabela).Error("Erro ao limpar tabela")
-			return fmt.Errorf("erro ao limpar tabela %s: %w", tabela, err)
-		}
-
-		rowsAffected, _ := result.RowsAffected()
-		logrus.WithFields(logrus.Fields{
-			"tabela": tabela,
-			"registros_removidos": rowsAffected,
-		}).Info("Tabela limpa com sucesso")
-	}
-
-	// Resetar sequências (auto increment)
-	sequencias := []string{
-		"ALTER SEQUENCE traders_id_seq RESTART WITH 1",
-		"ALTER SEQUENCE ofertas_id_seq RESTART WITH 1", 
-		"ALTER SEQUENCE cavaletes_id_seq RESTART WITH 1",
-		"ALTER SEQUENCE produtos_aprovados_id_seq RESTART WITH 1",
-	}
-
-	for _, seq := range sequencias {
-		_, err := tx.Exec(seq)
-		if err != nil {
-			// Log do erro mas não falha a operação, pois as sequências podem não existir
-			logrus.WithError(err).WithField("sequencia", seq).Warn("Erro ao resetar sequência")
-		}
-	}
-
-	// Commit da transação
-	if err := tx.Commit(); err != nil {
-		logrus.WithError(err).Error("Erro ao fazer commit da limpeza")
-		return fmt.Errorf("erro ao fazer commit: %w", err)
-	}
-
-	logrus.Info("Limpeza completa do banco de dados concluída com sucesso")
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,318 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"mobgran-importer-go/internal/logs"
+	"mobgran-importer-go/internal/models"
+)
+
+// ReservasService controla o ciclo de venda dos produtos aprovados:
+// disponível → reservado → vendido, com liberação manual ou por expiração
+type ReservasService struct {
+	db             *sql.DB
+	auditoria      *AuditoriaService
+	eventos        *EventosService
+	validadePadrao time.Duration
+	validadeMaxima time.Duration
+}
+
+// NewReservasService cria uma nova instância do ReservasService. validadePadrao é usada
+// quando a reserva não informa expiração; as mudanças de status são gravadas como
+// eventos produto.atualizado, se informado.
+func NewReservasService(db *sql.DB, auditoria *AuditoriaService, eventos *EventosService, validadePadrao time.Duration) *ReservasService {
+	return &ReservasService{
+		db:             db,
+		auditoria:      auditoria,
+		eventos:        eventos,
+		validadePadrao: validadePadrao,
+		validadeMaxima: 30 * 24 * time.Hour,
+	}
+}
+
+// setReserva são as alterações de uma reserva: $3 cliente, $4 observação, $5 expiração,
+// $6 cadastro do cliente
+const setReserva = `
+	status = 'reservado', cliente = $3, reserva_observacao = $4, reservado_em = NOW(),
+	reserva_expira_em = $5, cliente_id = $6, updated_at = NOW()
+`
+
+// Reservar reserva um produto disponível para um cliente até a data de expiração. Com
+// cliente_id, a reserva fica vinculada ao cadastro e, sem nome, usa o nome cadastrado.
+func (s *ReservasService) Reservar(ctx context.Context, traderID, produtoID uuid.UUID, request *models.ReservarProdutoRequest) (*models.ProdutoAprovado, error) {
+	expiraEm, err := s.calcularExpiracao(request.ExpiraEm, request.ValidadeHoras)
+	if err != nil {
+		return nil, err
+	}
+
+	cliente := request.Cliente
+	if request.ClienteID != nil {
+		nome, err := nomeDoCliente(ctx, s.db, traderID, *request.ClienteID)
+		if err != nil {
+			return nil, err
+		}
+		if cliente == "" {
+			cliente = nome
+		}
+	}
+
+	return s.transicionar(ctx, traderID, produtoID, models.AcaoProdutoReservado,
+		[]string{models.StatusProdutoDisponivel}, setReserva, cliente, request.Observacao, expiraEm, request.ClienteID)
+}
+
+// reservarTx reserva o produto dentro de uma transação já aberta
+func (s *ReservasService) reservarTx(ctx context.Context, tx *sql.Tx, traderID, produtoID uuid.UUID, cliente string, clienteID *uuid.UUID, observacao *string, expiraEm time.Time) (*models.ProdutoAprovado, error) {
+	return s.transicionarTx(ctx, tx, traderID, produtoID, models.AcaoProdutoReservado,
+		[]string{models.StatusProdutoDisponivel}, setReserva, cliente, observacao, expiraEm, clienteID)
+}
+
+// calcularExpiracao resolve a expiração de uma reserva: data explícita, validade em
+// horas ou, sem nenhuma das duas, a validade padrão
+func (s *ReservasService) calcularExpiracao(expira *time.Time, validadeHoras *int) (time.Time, error) {
+	agora := time.Now()
+	expiraEm := agora.Add(s.validadePadrao)
+	switch {
+	case expira != nil:
+		expiraEm = *expira
+	case validadeHoras != nil:
+		expiraEm = agora.Add(time.Duration(*validadeHoras) * time.Hour)
+	}
+
+	if !expiraEm.After(agora) {
+		return time.Time{}, fmt.Errorf("a expiração da reserva deve ser no futuro")
+	}
+	if expiraEm.Sub(agora) > s.validadeMaxima {
+		return time.Time{}, fmt.Errorf("a expiração da reserva deve ser em até %d dias", int(s.validadeMaxima.Hours()/24))
+	}
+
+	return expiraEm, nil
+}
+
+// Liberar cancela a reserva, devolvendo o produto para disponível
+func (s *ReservasService) Liberar(ctx context.Context, traderID, produtoID uuid.UUID) (*models.ProdutoAprovado, error) {
+	return s.transicionar(ctx, traderID, produtoID, models.AcaoReservaLiberada,
+		[]string{models.StatusProdutoReservado}, `
+		status = 'disponivel', cliente = NULL, cliente_id = NULL, reserva_observacao = NULL,
+		reservado_em = NULL, reserva_expira_em = NULL, updated_at = NOW()
+	`)
+}
+
+// MarcarVendido registra a venda de um produto disponível ou reservado. O produto
+// vendido deixa de aparecer na vitrine pública. Um novo cliente informado só pelo nome
+// desfaz o vínculo com o cadastro do cliente da reserva.
+func (s *ReservasService) MarcarVendido(ctx context.Context, traderID, produtoID uuid.UUID, request *models.VenderProdutoRequest) (*models.ProdutoAprovado, error) {
+	cliente := request.Cliente
+	if request.ClienteID != nil {
+		nome, err := nomeDoCliente(ctx, s.db, traderID, *request.ClienteID)
+		if err != nil {
+			return nil, err
+		}
+		if cliente == nil {
+			cliente = &nome
+		}
+	}
+
+	return s.transicionar(ctx, traderID, produtoID, models.AcaoProdutoVendido,
+		[]string{models.StatusProdutoDisponivel, models.StatusProdutoReservado}, `
+		status = 'vendido', cliente = COALESCE($3, cliente), valor_venda = COALESCE($4, preco_venda),
+		cliente_id = CASE WHEN $3::text IS NULL THEN cliente_id ELSE $5::uuid END,
+		vendido_em = NOW(), reserva_expira_em = NULL, updated_at = NOW()
+	`, cliente, request.ValorVenda, request.ClienteID)
+}
+
+// ExpirarReservas libera as reservas vencidas. As linhas são travadas com SKIP LOCKED,
+// então várias instâncias da API podem executá-lo ao mesmo tempo sem conflito.
+func (s *ReservasService) ExpirarReservas(ctx context.Context) (int, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return 0, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		UPDATE produtos_aprovados pa
+		SET status = 'disponivel', cliente = NULL, cliente_id = NULL, reserva_observacao = NULL,
+			reservado_em = NULL, reserva_expira_em = NULL, updated_at = NOW()
+		FROM (
+			SELECT id, cliente, cliente_id, reserva_expira_em
+			FROM produtos_aprovados
+			WHERE status = 'reservado' AND reserva_expira_em <= NOW() AND deleted_at IS NULL
+			FOR UPDATE SKIP LOCKED
+		) anterior
+		WHERE pa.id = anterior.id
+		RETURNING pa.id, pa.trader_id, anterior.cliente, anterior.cliente_id, anterior.reserva_expira_em
+	`)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao expirar reservas")
+		return 0, fmt.Errorf("erro ao expirar reservas")
+	}
+
+	type expirada struct {
+		produtoID uuid.UUID
+		traderID  uuid.UUID
+		cliente   *string
+		clienteID *uuid.UUID
+		expiraEm  time.Time
+	}
+
+	expiradas := []expirada{}
+	for rows.Next() {
+		var e expirada
+		if err := rows.Scan(&e.produtoID, &e.traderID, &e.cliente, &e.clienteID, &e.expiraEm); err != nil {
+			rows.Close()
+			logs.Do(ctx).WithError(err).Error("Erro ao escanear reserva expirada")
+			return 0, fmt.Errorf("erro ao expirar reservas")
+		}
+		expiradas = append(expiradas, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao expirar reservas")
+		return 0, fmt.Errorf("erro ao expirar reservas")
+	}
+
+	produtoIDs := make([]string, 0, len(expiradas))
+	for _, e := range expiradas {
+		produtoIDs = append(produtoIDs, e.produtoID.String())
+		antes := map[string]interface{}{
+			"status":            models.StatusProdutoReservado,
+			"cliente":           e.cliente,
+			"cliente_id":        e.clienteID,
+			"reserva_expira_em": e.expiraEm,
+		}
+		depois := map[string]interface{}{"status": models.StatusProdutoDisponivel}
+		if err := s.auditoria.Registrar(ctx, tx, models.AcaoReservaExpirada, "produto", e.produtoID.String(), antes, depois); err != nil {
+			return 0, err
+		}
+	}
+
+	if err := s.eventos.registrarProdutos(ctx, tx, models.EventoProdutoAtualizado, models.AcaoReservaExpirada, produtoIDs); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da expiração de reservas")
+		return 0, fmt.Errorf("erro ao expirar reservas")
+	}
+
+	if len(expiradas) > 0 {
+		logs.Do(ctx).WithField("expiradas", len(expiradas)).Info("Reservas vencidas liberadas")
+	}
+
+	return len(expiradas), nil
+}
+
+// ExecutarExpiracao expira reservas vencidas periodicamente até o contexto ser cancelado
+func (s *ReservasService) ExecutarExpiracao(ctx context.Context, intervalo time.Duration) {
+	logs.Do(ctx).WithField("intervalo", intervalo.String()).Info("Worker de expiração de reservas iniciado")
+
+	ticker := time.NewTicker(intervalo)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ExpirarReservas(ctx); err != nil && ctx.Err() == nil {
+			logs.Do(ctx).WithError(err).Warn("Falha ao expirar reservas; nova tentativa no próximo ciclo")
+		}
+
+		select {
+		case <-ctx.Done():
+			logs.Do(ctx).Info("Worker de expiração de reservas finalizado")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// transicionar aplica a mudança de status em uma transação própria
+func (s *ReservasService) transicionar(ctx context.Context, traderID, produtoID uuid.UUID, acao string, permitidos []string, set string, args ...interface{}) (*models.ProdutoAprovado, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao iniciar transação")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+	defer tx.Rollback()
+
+	produto, err := s.transicionarTx(ctx, tx, traderID, produtoID, acao, permitidos, set, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao fazer commit da mudança de status")
+		return nil, fmt.Errorf("erro ao atualizar status do produto")
+	}
+
+	logs.Do(ctx).WithFields(logrus.Fields{
+		"user_id":    traderID,
+		"produto_id": produtoID,
+		"acao":       acao,
+		"status":     produto.Status,
+	}).Info("Status do produto atualizado")
+
+	return produto, nil
+}
+
+// transicionarTx aplica a mudança de status quando o status atual está entre os
+// permitidos, auditando o estado anterior e o novo na transação informada. Os
+// argumentos extras começam em $3.
+func (s *ReservasService) transicionarTx(ctx context.Context, tx *sql.Tx, traderID, produtoID uuid.UUID, acao string, permitidos []string, set string, args ...interface{}) (*models.ProdutoAprovado, error) {
+	var antes models.ProdutoAprovado
+	err := escanearProduto(tx.QueryRowContext(ctx, `
+		SELECT `+colunasProduto+`
+		FROM produtos_aprovados
+		WHERE id = $1 AND trader_id = $2 AND deleted_at IS NULL
+		FOR UPDATE
+	`, produtoID, traderID), &antes)
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("produto não encontrado")
+	} else if err != nil {
+		logs.Do(ctx).WithError(err).Error("Erro ao buscar produto")
+		return nil, fmt.Errorf("erro interno do servidor")
+	}
+
+	permitido := false
+	for _, status := range permitidos {
+		if antes.Status == status {
+			permitido = true
+			break
+		}
+	}
+	if !permitido {
+		return nil, fmt.Errorf("operação não permitida: produto está %s", antes.Status)
+	}
+
+	// O cavalete que saiu do Mobgran não pode mais ser reservado nem vendido; a reserva
+	// feita antes da remoção ainda pode ser liberada
+	if antes.RemovidoNaOrigem && acao != models.AcaoReservaLiberada {
+		return nil, fmt.Errorf("operação não permitida: o cavalete do produto foi removido na origem")
+	}
+
+	var depois models.ProdutoAprovado
+	err = escanearProduto(tx.QueryRowContext(ctx, `
+		UPDATE produtos_aprovados
+		SET `+set+`
+		WHERE id = $1 AND trader_id = $2
+		RETURNING `+colunasProduto,
+		append([]interface{}{produtoID, traderID}, args...)...), &depois)
+	if err != nil {
+		logs.Do(ctx).WithError(err).WithField("acao", acao).Error("Erro ao atualizar status do produto")
+		return nil, fmt.Errorf("erro ao atualizar status do produto")
+	}
+
+	if err := s.auditoria.Registrar(ctx, tx, acao, "produto", produtoID.String(), antes, depois); err != nil {
+		return nil, err
+	}
+
+	if err := s.eventos.registrarProduto(ctx, tx, models.EventoProdutoAtualizado, acao, &depois); err != nil {
+		return nil, err
+	}
+
+	return &depois, nil
+}
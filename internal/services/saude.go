@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/internal/versao"
+	"mobgran-importer-go/pkg/database"
+)
+
+const (
+	// timeoutVerificacaoBanco limita o ping e as consultas da verificação do banco
+	timeoutVerificacaoBanco = 2 * time.Second
+	// saturacaoPoolDegradada é a fração do pool de conexões em uso a partir da qual o
+	// pool é informado como degradado
+	saturacaoPoolDegradada = 0.9
+	// atrasoFilaDegradada é o atraso da notificação vencida mais antiga a partir do qual
+	// a fila é informada como degradada (worker parado ou sem dar conta)
+	atrasoFilaDegradada = 10 * time.Minute
+	// timeoutSupabase limita a verificação do Supabase Auth
+	timeoutSupabase = 3 * time.Second
+	// validadeVerificacaoSupabase evita consultar o Supabase a cada sonda de prontidão
+	validadeVerificacaoSupabase = 30 * time.Second
+)
+
+// SaudeService verifica as dependências da API para a sonda de prontidão. O banco e
+// as migrations são críticos: sem eles a API não atende. Pool, Supabase Auth, API do
+// Mobgran e fila de notificações degradam a API sem tirá-la de operação.
+type SaudeService struct {
+	postgres     *database.PostgresClient
+	supabase     *SupabaseAuthService
+	importer     *MobgranImporter
+	notificacoes *NotificacoesService
+
+	mu                 sync.Mutex
+	supabaseVerificado time.Time
+	supabaseResultado  models.Verificacao
+}
+
+// NewSaudeService cria uma nova instância do SaudeService
+func NewSaudeService(postgres *database.PostgresClient, supabase *SupabaseAuthService, importer *MobgranImporter, notificacoes *NotificacoesService) *SaudeService {
+	return &SaudeService{
+		postgres:     postgres,
+		supabase:     supabase,
+		importer:     importer,
+		notificacoes: notificacoes,
+	}
+}
+
+// Prontidao verifica todas as dependências e resume a situação da API
+func (s *SaudeService) Prontidao(ctx context.Context) *models.Prontidao {
+	info := versao.Atual()
+	prontidao := &models.Prontidao{
+		Versao: info.Versao,
+		Commit: info.Commit,
+		Verificacoes: map[string]models.Verificacao{
+			"banco":             s.verificarBanco(ctx),
+			"migrations":        s.verificarMigrations(ctx),
+			"pool_conexoes":     s.verificarPool(),
+			"supabase_auth":     s.verificarSupabase(),
+			"api_mobgran":       s.verificarAPIMobgran(),
+			"fila_notificacoes": s.verificarFila(ctx),
+		},
+		VerificadoEm: time.Now().UTC(),
+	}
+
+	prontidao.Status = models.SaudeSaudavel
+	for _, verificacao := range prontidao.Verificacoes {
+		switch {
+		case verificacao.Status == models.VerificacaoOK:
+		case verificacao.Critica && verificacao.Status == models.VerificacaoFalha:
+			prontidao.Status = models.SaudeIndisponivel
+		case prontidao.Status == models.SaudeSaudavel:
+			prontidao.Status = models.SaudeDegradada
+		}
+	}
+	return prontidao
+}
+
+func (s *SaudeService) verificarBanco(ctx context.Context) models.Verificacao {
+	ctx, cancel := context.WithTimeout(ctx, timeoutVerificacaoBanco)
+	defer cancel()
+
+	inicio := time.Now()
+	err := s.postgres.DB.PingContext(ctx)
+	return resultadoVerificacao(true, inicio, err, nil)
+}
+
+func (s *SaudeService) verificarMigrations(ctx context.Context) models.Verificacao {
+	ctx, cancel := context.WithTimeout(ctx, timeoutVerificacaoBanco)
+	defer cancel()
+
+	inicio := time.Now()
+	status, err := s.postgres.StatusMigrations(ctx)
+	if err != nil {
+		return resultadoVerificacao(true, inicio, err, nil)
+	}
+
+	detalhes := map[string]interface{}{"aplicadas": status.Aplicadas, "total": status.Total}
+	if len(status.Pendentes) > 0 {
+		detalhes["pendentes"] = status.Pendentes
+		return resultadoVerificacao(true, inicio, fmt.Errorf("%d migration(s) pendente(s)", len(status.Pendentes)), detalhes)
+	}
+	return resultadoVerificacao(true, inicio, nil, detalhes)
+}
+
+func (s *SaudeService) verificarPool() models.Verificacao {
+	inicio := time.Now()
+	stats := s.postgres.DB.Stats()
+
+	detalhes := map[string]interface{}{
+		"abertas":         stats.OpenConnections,
+		"em_uso":          stats.InUse,
+		"ociosas":         stats.Idle,
+		"maximo":          stats.MaxOpenConnections,
+		"esperas":         stats.WaitCount,
+		"espera_total_ms": stats.WaitDuration.Milliseconds(),
+	}
+
+	verificacao := resultadoVerificacao(false, inicio, nil, detalhes)
+	if stats.MaxOpenConnections > 0 {
+		saturacao := float64(stats.InUse) / float64(stats.MaxOpenConnections)
+		detalhes["saturacao"] = saturacao
+		if saturacao >= saturacaoPoolDegradada {
+			verificacao.Status = models.VerificacaoDegradada
+			verificacao.Erro = "pool de conexões próximo do limite"
+		}
+	}
+	return verificacao
+}
+
+func (s *SaudeService) verificarSupabase() models.Verificacao {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.supabaseVerificado) < validadeVerificacaoSupabase {
+		return s.supabaseResultado
+	}
+
+	inicio := time.Now()
+	s.supabaseResultado = resultadoVerificacao(false, inicio, s.supabase.VerificarDisponibilidade(timeoutSupabase), nil)
+	s.supabaseVerificado = time.Now()
+	return s.supabaseResultado
+}
+
+func (s *SaudeService) verificarAPIMobgran() models.Verificacao {
+	inicio := time.Now()
+	estado := s.importer.EstadoCircuito()
+
+	detalhes := map[string]interface{}{
+		"circuito":            estado.Estado,
+		"falhas_consecutivas": estado.FalhasConsecutivas,
+	}
+	if estado.AbertoAte != nil {
+		detalhes["aberto_ate"] = estado.AbertoAte.UTC()
+	}
+
+	verificacao := resultadoVerificacao(false, inicio, nil, detalhes)
+	if estado.Estado != models.CircuitoFechado {
+		verificacao.Status = models.VerificacaoDegradada
+		verificacao.Erro = estado.UltimoErro
+	}
+	return verificacao
+}
+
+func (s *SaudeService) verificarFila(ctx context.Context) models.Verificacao {
+	ctx, cancel := context.WithTimeout(ctx, timeoutVerificacaoBanco)
+	defer cancel()
+
+	inicio := time.Now()
+	fila, err := s.notificacoes.Fila(ctx)
+	if err != nil {
+		return resultadoVerificacao(false, inicio, err, nil)
+	}
+
+	verificacao := resultadoVerificacao(false, inicio, nil, map[string]interface{}{
+		"pendentes":       fila.Pendentes,
+		"vencidas":        fila.Vencidas,
+		"atraso_segundos": fila.AtrasoSegundos,
+	})
+	if time.Duration(fila.AtrasoSegundos)*time.Second >= atrasoFilaDegradada {
+		verificacao.Status = models.VerificacaoDegradada
+		verificacao.Erro = "notificações vencidas aguardando entrega"
+	}
+	return verificacao
+}
+
+// resultadoVerificacao monta a verificação a partir do erro da consulta à dependência
+func resultadoVerificacao(critica bool, inicio time.Time, err error, detalhes map[string]interface{}) models.Verificacao {
+	verificacao := models.Verificacao{
+		Status:     models.VerificacaoOK,
+		Critica:    critica,
+		LatenciaMs: time.Since(inicio).Milliseconds(),
+		Detalhes:   detalhes,
+	}
+	if err != nil {
+		verificacao.Status = models.VerificacaoFalha
+		verificacao.Erro = err.Error()
+	}
+	return verificacao
+}
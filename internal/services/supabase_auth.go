@@ -1,6 +1,9 @@
 package services
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"mobgran-importer-go/internal/config"
 	"mobgran-importer-go/internal/models"
@@ -23,6 +26,15 @@ func NewSupabaseAuthService(cfg *config.Config, logger *logrus.Logger) *Supabase
 	}
 }
 
+// VerificarDisponibilidade verifica se o Supabase Auth, usado no login e na validação
+// das sessões, está acessível
+func (s *SupabaseAuthService) VerificarDisponibilidade(timeout time.Duration) error {
+	if s.config.SupabaseURL == "" {
+		return fmt.Errorf("SUPABASE_URL não configurada")
+	}
+	return s.authClient.Disponivel(timeout)
+}
+
 func (s *SupabaseAuthService) CriarUsuarioAdmin(email, password string, userData map[string]interface{}) (*models.SupabaseAuthResponse, error) {
 	s.logger.WithFields(logrus.Fields{
 		"email": email,
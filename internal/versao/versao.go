@@ -0,0 +1,58 @@
+// Package versao identifica o build em execução. Os valores são injetados na
+// compilação com -ldflags, por exemplo:
+//
+//	go build -ldflags "-X mobgran-importer-go/internal/versao.Versao=1.4.0 \
+//	  -X mobgran-importer-go/internal/versao.Commit=$(git rev-parse --short HEAD) \
+//	  -X mobgran-importer-go/internal/versao.DataBuild=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+package versao
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Valores do build, substituídos via -ldflags -X
+var (
+	Versao    = "dev"
+	Commit    = ""
+	DataBuild = ""
+)
+
+// Info descreve o build em execução
+type Info struct {
+	Versao    string `json:"versao"`
+	Commit    string `json:"commit,omitempty"`
+	DataBuild string `json:"data_build,omitempty"`
+	GoVersao  string `json:"go_versao"`
+}
+
+// Atual retorna as informações do build. Sem -ldflags, commit e data vêm dos dados de
+// controle de versão gravados pelo go build, quando disponíveis.
+func Atual() Info {
+	info := Info{
+		Versao:    Versao,
+		Commit:    Commit,
+		DataBuild: DataBuild,
+		GoVersao:  runtime.Version(),
+	}
+
+	if info.Commit != "" && info.DataBuild != "" {
+		return info
+	}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	for _, s := range build.Settings {
+		switch {
+		case s.Key == "vcs.revision" && info.Commit == "":
+			info.Commit = s.Value
+			if len(info.Commit) > 12 {
+				info.Commit = info.Commit[:12]
+			}
+		case s.Key == "vcs.time" && info.DataBuild == "":
+			info.DataBuild = s.Value
+		}
+	}
+	return info
+}
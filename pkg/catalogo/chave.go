@@ -0,0 +1,72 @@
+// Package catalogo gera as chaves de comparação dos nomes de materiais, que cada
+// fornecedor escreve de um jeito ("Branco Siena", "BRANCO SIENNA", "Siena Branco"),
+// e dos nomes das empresas fornecedoras.
+package catalogo
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// semAcento associa as letras acentuadas do português à letra base
+var semAcento = map[rune]rune{
+	'á': 'a', 'à': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ó': 'o', 'ò': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n', 'ý': 'y',
+}
+
+// palavrasIgnoradas não distinguem materiais ("Verde da Ubatuba" = "Verde Ubatuba")
+var palavrasIgnoradas = map[string]bool{
+	"de": true, "da": true, "do": true, "das": true, "dos": true, "e": true,
+}
+
+// Chave reduz o nome a uma forma canônica para comparação: minúsculas sem acentos,
+// sem pontuação, sem letras repetidas em sequência ("sienna" = "siena"), sem
+// preposições e com as palavras em ordem alfabética ("Siena Branco" = "Branco Siena")
+func Chave(nome string) string {
+	palavras := palavrasNormalizadas(nome)
+	sort.Strings(palavras)
+	return strings.Join(palavras, " ")
+}
+
+// palavrasNormalizadas separa o nome em palavras minúsculas, sem acentos, pontuação,
+// letras repetidas e preposições, na ordem original
+func palavrasNormalizadas(nome string) []string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(nome) {
+		if base, ok := semAcento[r]; ok {
+			r = base
+		}
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteRune(' ')
+		}
+	}
+
+	palavras := []string{}
+	for _, palavra := range strings.Fields(sb.String()) {
+		if palavrasIgnoradas[palavra] {
+			continue
+		}
+		palavras = append(palavras, semRepeticao(palavra))
+	}
+	return palavras
+}
+
+// semRepeticao remove letras repetidas em sequência
+func semRepeticao(palavra string) string {
+	var sb strings.Builder
+	var anterior rune
+	for _, r := range palavra {
+		if r != anterior || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+		anterior = r
+	}
+	return sb.String()
+}
@@ -0,0 +1,39 @@
+package catalogo
+
+import (
+	"sort"
+	"strings"
+)
+
+// naturezasJuridicas são os sufixos societários que não distinguem empresas
+// ("Granitos Serra Ltda" = "GRANITOS SERRA LTDA - ME" = "Granitos Serra")
+var naturezasJuridicas = map[string]bool{
+	"ltda": true, "me": true, "epp": true, "eireli": true, "mei": true,
+	"sa": true, "cia": true, "limitada": true,
+}
+
+// ChaveEmpresa reduz o nome da empresa a uma forma canônica para comparação, como
+// Chave, desconsiderando também a natureza jurídica (Ltda, ME, EPP, S/A)
+func ChaveEmpresa(nome string) string {
+	palavras := palavrasNormalizadas(nome)
+
+	// "S/A" e "S.A." viram as palavras "s" e "a"
+	for i := 0; i+1 < len(palavras); i++ {
+		if palavras[i] == "s" && palavras[i+1] == "a" {
+			palavras = append(palavras[:i], append([]string{"sa"}, palavras[i+2:]...)...)
+		}
+	}
+
+	restantes := []string{}
+	for _, palavra := range palavras {
+		if !naturezasJuridicas[palavra] {
+			restantes = append(restantes, palavra)
+		}
+	}
+	if len(restantes) == 0 {
+		return Chave(nome)
+	}
+
+	sort.Strings(restantes)
+	return strings.Join(restantes, " ")
+}
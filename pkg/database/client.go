@@ -1,29 +1,41 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/google/uuid"
-	_ "github.com/lib/pq" // PostgreSQL driver
+	"github.com/lib/pq" // PostgreSQL driver
 	"github.com/sirupsen/logrus"
+	"mobgran-importer-go/internal/logs"
 	"mobgran-importer-go/internal/models"
+	"mobgran-importer-go/pkg/medidas"
 )
 
 // Client representa o cliente PostgreSQL
 type Client struct {
 	db     *sql.DB
+	tx     *sql.Tx         // transação de ComTransacao; nil executa direto no pool
+	ctx    context.Context // contexto de ComContexto (cancelamento e trace); nil usa Background
 	logger *logrus.Logger
 }
 
+// conexao é o que os comandos do cliente usam: o pool ou a transação em andamento
+type conexao interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
 // NewClient cria uma nova instância do cliente PostgreSQL
 func NewClient(host, port, dbname, user, password, sslmode string, logger *logrus.Logger) (*Client, error) {
 	dsn := fmt.Sprintf("host=%s port=%s dbname=%s user=%s password=%s sslmode=%s",
 		host, port, dbname, user, password, sslmode)
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := abrir(dsn)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao conectar com PostgreSQL: %w", err)
 	}
@@ -46,6 +58,47 @@ func NewClient(host, port, dbname, user, password, sslmode string, logger *logru
 	}, nil
 }
 
+// NewClientFromDB cria um cliente reaproveitando um pool de conexões já aberto
+func NewClientFromDB(db *sql.DB, logger *logrus.Logger) *Client {
+	return &Client{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ComTransacao retorna um cliente que executa os comandos na transação informada; o
+// commit e o rollback continuam com quem abriu a transação
+func (c *Client) ComTransacao(tx *sql.Tx) *Client {
+	return &Client{db: c.db, tx: tx, ctx: c.ctx, logger: c.logger}
+}
+
+// ComContexto retorna um cliente que executa os comandos com o contexto informado: os
+// comandos são cancelados junto com ele e aparecem como spans do trace em andamento
+func (c *Client) ComContexto(ctx context.Context) *Client {
+	return &Client{db: c.db, tx: c.tx, ctx: ctx, logger: c.logger}
+}
+
+// contexto retorna o contexto dos comandos
+func (c *Client) contexto() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
+// log retorna o logger do contexto dos comandos (request_id, user_id, oferta_id e trace)
+func (c *Client) log() *logrus.Entry {
+	return logs.Do(c.contexto())
+}
+
+// conn retorna a transação em andamento ou, fora de uma, o pool
+func (c *Client) conn() conexao {
+	if c.tx != nil {
+		return c.tx
+	}
+	return c.db
+}
+
 // Close fecha a conexão com o banco
 func (c *Client) Close() error {
 	return c.db.Close()
@@ -56,25 +109,26 @@ func (c *Client) GetDB() *sql.DB {
 	return c.db
 }
 
-// VerificarOfertaExistente verifica se uma oferta já existe pelo UUID
-func (c *Client) VerificarOfertaExistente(ofertaUUID string) (*string, error) {
+// VerificarOfertaExistente verifica se o trader já importou a oferta do UUID. O mesmo
+// link importado por outro trader é outra oferta e nunca é retornado.
+func (c *Client) VerificarOfertaExistente(ofertaUUID, traderID string) (*string, error) {
 	var id string
-	query := "SELECT id FROM ofertas WHERE uuid_link = $1"
-	
-	err := c.db.QueryRow(query, ofertaUUID).Scan(&id)
+	query := "SELECT id FROM ofertas WHERE uuid_link = $1 AND trader_id = $2"
+
+	err := c.conn().QueryRowContext(c.contexto(), query, ofertaUUID, traderID).Scan(&id)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil // Oferta não existe
 		}
-		c.logger.WithError(err).Error("Erro ao verificar oferta existente")
+		c.log().WithError(err).Error("Erro ao verificar oferta existente")
 		return nil, err
 	}
 
 	return &id, nil
 }
 
-// SalvarOferta salva uma nova oferta no banco
-func (c *Client) SalvarOferta(ofertaUUID string, dados *models.MobgranResponse) (*string, error) {
+// SalvarOferta salva uma nova oferta do trader no banco
+func (c *Client) SalvarOferta(ofertaUUID, traderID string, dados *models.MobgranResponse) (*string, error) {
 	// Serializar dados completos para JSON
 	dadosJSON, err := json.Marshal(dados)
 	if err != nil {
@@ -83,110 +137,304 @@ func (c *Client) SalvarOferta(ofertaUUID string, dados *models.MobgranResponse)
 
 	id := uuid.New().String()
 	query := `
-		INSERT INTO ofertas (id, uuid_link, situacao, nome_empresa, url_logo, dados_completos)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO ofertas (id, uuid_link, trader_id, situacao, nome_empresa, url_logo, dados_completos)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		RETURNING id`
 
-	err = c.db.QueryRow(query, id, ofertaUUID, dados.Situacao, dados.NomeEmpresa, dados.URLLogo, dadosJSON).Scan(&id)
+	err = c.conn().QueryRowContext(c.contexto(), query, id, ofertaUUID, traderID, dados.Situacao, dados.NomeEmpresa, dados.URLLogo, dadosJSON).Scan(&id)
 	if err != nil {
-		c.logger.WithError(err).Error("Erro ao salvar oferta")
+		c.log().WithError(err).Error("Erro ao salvar oferta")
 		return nil, err
 	}
 
-	c.logger.WithField("oferta_id", id).Info("Oferta salva com sucesso")
+	c.log().WithField("oferta_id", id).Info("Oferta salva com sucesso")
 	return &id, nil
 }
 
+// BuscarResumoOferta retorna os dados principais de uma oferta e a quantidade de cavaletes
+func (c *Client) BuscarResumoOferta(ofertaID string) (map[string]interface{}, error) {
+	var uuidLink, situacao, nomeEmpresa sql.NullString
+	var totalCavaletes int
+
+	query := `
+		SELECT o.uuid_link, o.situacao, o.nome_empresa,
+			(SELECT COUNT(*) FROM cavaletes c WHERE c.oferta_id = o.id)
+		FROM ofertas o
+		WHERE o.id = $1`
+
+	err := c.conn().QueryRowContext(c.contexto(), query, ofertaID).Scan(&uuidLink, &situacao, &nomeEmpresa, &totalCavaletes)
+	if err != nil {
+		c.log().WithError(err).Error("Erro ao buscar resumo da oferta")
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"uuid_link":       uuidLink.String,
+		"situacao":        situacao.String,
+		"nome_empresa":    nomeEmpresa.String,
+		"total_cavaletes": totalCavaletes,
+	}, nil
+}
+
+// imagemPrincipalJSON serializa a imagem principal do cavalete, NULL quando não houver
+func imagemPrincipalJSON(cavalete *models.Cavalete) (sql.NullString, error) {
+	if cavalete.ImagemPrincipal == nil ||
+		(cavalete.ImagemPrincipal.Nome == "" && cavalete.ImagemPrincipal.URL == "" && cavalete.ImagemPrincipal.URLMin == "") {
+		return sql.NullString{Valid: false}, nil // NULL no PostgreSQL
+	}
+
+	// Para JSONB, usar sql.NullString para garantir que NULL seja passado corretamente
+	jsonBytes, err := json.Marshal(cavalete.ImagemPrincipal)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("erro ao serializar imagem principal: %w", err)
+	}
+	return sql.NullString{String: string(jsonBytes), Valid: true}, nil
+}
+
+// medidasCavalete normaliza as medidas do cavalete; os valores brutos chegam sem unidade
+// e as medidas normalizadas são gravadas ao lado deles
+func medidasCavalete(cavalete *models.Cavalete) medidas.Resultado {
+	return medidas.Normalizar(medidas.Entrada{
+		Espessura:   cavalete.NomeEspessura,
+		Comprimento: cavalete.Comprimento,
+		Altura:      cavalete.Altura,
+		Metragem:    cavalete.Metragem,
+		Peso:        cavalete.Peso,
+		Pecas:       len(cavalete.Itens),
+	})
+}
+
 // SalvarCavalete salva um cavalete no banco
 func (c *Client) SalvarCavalete(ofertaID string, cavalete *models.Cavalete) (*string, error) {
-	// Log detalhado do cavalete recebido
-	c.logger.WithFields(logrus.Fields{
-		"cavalete_codigo": cavalete.Codigo,
-		"imagem_principal_ptr": fmt.Sprintf("%p", cavalete.ImagemPrincipal),
-		"imagem_principal_nil": cavalete.ImagemPrincipal == nil,
-	}).Debug("Cavalete recebido para salvamento")
-
-	// Se ImagemPrincipal não é nil, vamos ver seus valores
-	if cavalete.ImagemPrincipal != nil {
-		c.logger.WithFields(logrus.Fields{
-			"nome": cavalete.ImagemPrincipal.Nome,
-			"url": cavalete.ImagemPrincipal.URL,
-			"url_min": cavalete.ImagemPrincipal.URLMin,
-		}).Debug("Valores de ImagemPrincipal")
-	}
-
-	// Serializar imagem principal para JSON ou usar NULL
-	var imagemPrincipalJSON sql.NullString
-	if cavalete.ImagemPrincipal != nil && 
-		(cavalete.ImagemPrincipal.Nome != "" || cavalete.ImagemPrincipal.URL != "" || cavalete.ImagemPrincipal.URLMin != "") {
-		// Para JSONB, usar sql.NullString para garantir que NULL seja passado corretamente
-		jsonBytes, err := json.Marshal(cavalete.ImagemPrincipal)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao serializar imagem principal: %w", err)
-		}
-		imagemPrincipalJSON = sql.NullString{String: string(jsonBytes), Valid: true}
-		c.logger.WithField("imagem_principal", string(jsonBytes)).Debug("Imagem principal definida com dados válidos")
-	} else {
-		imagemPrincipalJSON = sql.NullString{Valid: false} // NULL no PostgreSQL
-		c.logger.Debug("Imagem principal é nil ou vazia, usando NULL")
+	imagemPrincipal, err := imagemPrincipalJSON(cavalete)
+	if err != nil {
+		return nil, err
 	}
+	normalizadas := medidasCavalete(cavalete)
 
 	id := uuid.New().String()
 	query := `
 		INSERT INTO cavaletes (
 			id, oferta_id, codigo, bloco, nome_material, nome_espessura,
-			comprimento, altura, metragem, imagem_principal, quantidade_itens
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+			comprimento, altura, metragem, imagem_principal, quantidade_itens,
+			peso, tipo_metragem, espessura_mm, comprimento_m, altura_m, metragem_m2, peso_kg, unidade_dimensoes,
+			medidas_normalizadas_em
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, NULLIF($12::numeric, 0), $13, $14, $15, $16, $17, $18, $19, NOW())
 		RETURNING id`
 
-	c.logger.WithFields(logrus.Fields{
-		"cavalete_codigo": cavalete.Codigo,
-		"imagem_principal_type": fmt.Sprintf("%T", imagemPrincipalJSON),
-		"imagem_principal_value": imagemPrincipalJSON,
-		"imagem_principal_is_valid": imagemPrincipalJSON.Valid,
-	}).Debug("Executando query de inserção")
-
-	err := c.db.QueryRow(query,
+	err = c.conn().QueryRowContext(c.contexto(), query,
 		id, ofertaID, cavalete.Codigo, cavalete.Bloco, cavalete.NomeMaterial,
 		cavalete.NomeEspessura, cavalete.Comprimento, cavalete.Altura,
-		cavalete.Metragem, imagemPrincipalJSON, len(cavalete.Itens),
+		cavalete.Metragem, imagemPrincipal, len(cavalete.Itens),
+		cavalete.Peso, normalizadas.TipoMetragem, normalizadas.EspessuraMM, normalizadas.ComprimentoM,
+		normalizadas.AlturaM, normalizadas.MetragemM2, normalizadas.PesoKg, normalizadas.UnidadeDimensoes,
 	).Scan(&id)
 
 	if err != nil {
-		c.logger.WithError(err).WithFields(logrus.Fields{
+		c.log().WithError(err).WithFields(logrus.Fields{
+			"oferta_id":       ofertaID,
 			"cavalete_codigo": cavalete.Codigo,
-			"query_params": fmt.Sprintf("id=%s, ofertaID=%s, codigo=%s, bloco=%s, nomeMaterial=%s, nomeEspessura=%s, comprimento=%f, altura=%f, metragem=%f, imagemPrincipal=%v, quantidadeItens=%d",
-				id, ofertaID, cavalete.Codigo, cavalete.Bloco, cavalete.NomeMaterial,
-				cavalete.NomeEspessura, cavalete.Comprimento, cavalete.Altura,
-				cavalete.Metragem, imagemPrincipalJSON, len(cavalete.Itens)),
 		}).Error("Erro ao salvar cavalete")
 		return nil, err
 	}
 
-	c.logger.WithField("cavalete_id", id).Info("Cavalete salvo com sucesso")
+	c.log().WithFields(logrus.Fields{
+		"cavalete_id":     id,
+		"cavalete_codigo": cavalete.Codigo,
+	}).Debug("Cavalete salvo")
 	return &id, nil
 }
 
+// CavaletesDaOferta retorna os IDs dos cavaletes da oferta pelo código, incluindo os
+// removidos na origem, que voltam a ficar ativos se reaparecerem na reimportação.
+// Cavaletes arquivados com a oferta ficam de fora.
+func (c *Client) CavaletesDaOferta(ofertaID string) (map[string]string, error) {
+	rows, err := c.conn().QueryContext(c.contexto(), `
+		SELECT id, codigo
+		FROM cavaletes
+		WHERE oferta_id = $1 AND (deleted_at IS NULL OR removido_na_origem_em IS NOT NULL)
+		ORDER BY deleted_at NULLS FIRST, created_at`, ofertaID)
+	if err != nil {
+		c.log().WithError(err).WithField("oferta_id", ofertaID).Error("Erro ao buscar cavaletes da oferta")
+		return nil, err
+	}
+	defer rows.Close()
+
+	cavaletes := map[string]string{}
+	for rows.Next() {
+		var id, codigo string
+		if err := rows.Scan(&id, &codigo); err != nil {
+			c.log().WithError(err).Error("Erro ao escanear cavalete da oferta")
+			return nil, err
+		}
+		// Com códigos repetidos, prevalece o cavalete ativo mais antigo; os demais são
+		// tratados como removidos na origem
+		if _, ok := cavaletes[codigo]; !ok {
+			cavaletes[codigo] = id
+		}
+	}
+
+	return cavaletes, rows.Err()
+}
+
+// AtualizarCavalete regrava os dados do cavalete vindos da reimportação mantendo o ID,
+// e com ele os produtos aprovados, orçamentos e histórico de preços. Um cavalete removido
+// na origem que reaparece volta a ficar ativo com os produtos arquivados junto com ele.
+func (c *Client) AtualizarCavalete(cavaleteID string, cavalete *models.Cavalete) error {
+	imagemPrincipal, err := imagemPrincipalJSON(cavalete)
+	if err != nil {
+		return err
+	}
+	normalizadas := medidasCavalete(cavalete)
+
+	var removidoEm sql.NullTime
+	err = c.conn().QueryRowContext(c.contexto(), `
+		SELECT removido_na_origem_em FROM cavaletes WHERE id = $1`, cavaleteID).Scan(&removidoEm)
+	if err != nil {
+		c.log().WithError(err).WithField("cavalete_id", cavaleteID).Error("Erro ao buscar cavalete")
+		return err
+	}
+
+	query := `
+		UPDATE cavaletes
+		SET bloco = $2, nome_material = $3, nome_espessura = $4,
+			comprimento = $5, altura = $6, metragem = $7, imagem_principal = $8, quantidade_itens = $9,
+			peso = NULLIF($10::numeric, 0), tipo_metragem = $11, espessura_mm = $12, comprimento_m = $13,
+			altura_m = $14, metragem_m2 = $15, peso_kg = $16, unidade_dimensoes = $17,
+			medidas_normalizadas_em = NOW(), medidas_erro = NULL, deleted_at = NULL, removido_na_origem_em = NULL, updated_at = NOW()
+		WHERE id = $1`
+
+	_, err = c.conn().ExecContext(c.contexto(), query,
+		cavaleteID, cavalete.Bloco, cavalete.NomeMaterial, cavalete.NomeEspessura,
+		cavalete.Comprimento, cavalete.Altura, cavalete.Metragem, imagemPrincipal, len(cavalete.Itens),
+		cavalete.Peso, normalizadas.TipoMetragem, normalizadas.EspessuraMM, normalizadas.ComprimentoM,
+		normalizadas.AlturaM, normalizadas.MetragemM2, normalizadas.PesoKg, normalizadas.UnidadeDimensoes,
+	)
+	if err != nil {
+		c.log().WithError(err).WithFields(logrus.Fields{
+			"cavalete_id":     cavaleteID,
+			"cavalete_codigo": cavalete.Codigo,
+		}).Error("Erro ao atualizar cavalete")
+		return err
+	}
+
+	if removidoEm.Valid {
+		_, err = c.conn().ExecContext(c.contexto(), `
+			UPDATE produtos_aprovados
+			SET deleted_at = NULL, updated_at = NOW()
+			WHERE cavalete_id = $1 AND deleted_at = $2`, cavaleteID, removidoEm.Time)
+		if err != nil {
+			c.log().WithError(err).WithField("cavalete_id", cavaleteID).Error("Erro ao restaurar produtos do cavalete")
+			return err
+		}
+		c.log().WithFields(logrus.Fields{
+			"cavalete_id":     cavaleteID,
+			"cavalete_codigo": cavalete.Codigo,
+		}).Info("Cavalete voltou à origem e foi restaurado")
+	}
+
+	c.log().WithFields(logrus.Fields{
+		"cavalete_id":     cavaleteID,
+		"cavalete_codigo": cavalete.Codigo,
+	}).Debug("Cavalete atualizado")
+	return nil
+}
+
+// RemoverItens remove os itens do cavalete antes de regravá-los na reimportação
+func (c *Client) RemoverItens(cavaleteID string) error {
+	_, err := c.conn().ExecContext(c.contexto(), "DELETE FROM itens WHERE cavalete_id = $1", cavaleteID)
+	if err != nil {
+		c.log().WithError(err).WithField("cavalete_id", cavaleteID).Error("Erro ao remover itens")
+		return err
+	}
+	return nil
+}
+
+// MarcarCavaletesRemovidosNaOrigem arquiva os cavaletes que não vieram na reimportação,
+// marcando quando saíram da origem, e os produtos disponíveis sobre eles. Os registros são
+// arquivados, não apagados: orçamentos e histórico de preços continuam apontando para eles.
+// Produtos reservados ou vendidos continuam ativos para que a venda possa ser acompanhada.
+// Retorna os cavaletes marcados nesta chamada (os já removidos em importações anteriores
+// ficam de fora).
+func (c *Client) MarcarCavaletesRemovidosNaOrigem(cavaleteIDs []string) ([]string, error) {
+	if len(cavaleteIDs) == 0 {
+		return nil, nil
+	}
+
+	rows, err := c.conn().QueryContext(c.contexto(), `
+		UPDATE cavaletes
+		SET deleted_at = NOW(), removido_na_origem_em = NOW(), updated_at = NOW()
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL
+		RETURNING id`, pq.Array(cavaleteIDs))
+	if err != nil {
+		c.log().WithError(err).Error("Erro ao marcar cavaletes removidos na origem")
+		return nil, err
+	}
+	marcados := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			c.log().WithError(err).Error("Erro ao escanear cavalete removido na origem")
+			return nil, err
+		}
+		marcados = append(marcados, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(marcados) == 0 {
+		return marcados, nil
+	}
+
+	_, err = c.conn().ExecContext(c.contexto(), `
+		UPDATE produtos_aprovados
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE cavalete_id = ANY($1::uuid[]) AND deleted_at IS NULL AND status = 'disponivel'`, pq.Array(marcados))
+	if err != nil {
+		c.log().WithError(err).Error("Erro ao arquivar produtos de cavaletes removidos na origem")
+		return nil, err
+	}
+
+	c.log().WithField("total_cavaletes", len(marcados)).Info("Cavaletes removidos na origem arquivados")
+	return marcados, nil
+}
+
 // SalvarItem salva um item no banco
 func (c *Client) SalvarItem(cavaleteID string, item *models.Item) error {
+	normalizadas := medidas.Normalizar(medidas.Entrada{
+		Espessura:   item.NomeEspessura,
+		Comprimento: item.Comprimento,
+		Altura:      item.Altura,
+		Metragem:    item.Metragem,
+		Peso:        item.Peso,
+	})
+
 	id := uuid.New().String()
 	query := `
 		INSERT INTO itens (
 			id, cavalete_id, codigo, bloco, nome_espessura, nome_classificacao,
-			comprimento, altura, metragem
-		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`
+			comprimento, altura, metragem,
+			peso, tipo_metragem, espessura_mm, comprimento_m, altura_m, metragem_m2, peso_kg, unidade_dimensoes
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10::numeric, 0), $11, $12, $13, $14, $15, $16, $17)`
 
-	_, err := c.db.Exec(query,
+	_, err := c.conn().ExecContext(c.contexto(), query,
 		id, cavaleteID, item.Codigo, item.Bloco, item.NomeEspessura,
 		item.NomeClassificacao, item.Comprimento, item.Altura, item.Metragem,
+		item.Peso, normalizadas.TipoMetragem, normalizadas.EspessuraMM, normalizadas.ComprimentoM,
+		normalizadas.AlturaM, normalizadas.MetragemM2, normalizadas.PesoKg, normalizadas.UnidadeDimensoes,
 	)
 
 	if err != nil {
-		c.logger.WithError(err).Error("Erro ao salvar item")
+		c.log().WithError(err).Error("Erro ao salvar item")
 		return err
 	}
 
-	c.logger.WithField("item_id", id).Info("Item salvo com sucesso")
+	c.log().WithFields(logrus.Fields{
+		"item_id":     id,
+		"cavalete_id": cavaleteID,
+	}).Debug("Item salvo")
 	return nil
 }
 
@@ -203,9 +451,9 @@ func (c *Client) AtualizarOferta(ofertaID string, dados *models.MobgranResponse)
 		SET situacao = $2, nome_empresa = $3, url_logo = $4, dados_completos = $5, updated_at = NOW()
 		WHERE id = $1`
 
-	result, err := c.db.Exec(query, ofertaID, dados.Situacao, dados.NomeEmpresa, dados.URLLogo, dadosJSON)
+	result, err := c.conn().ExecContext(c.contexto(), query, ofertaID, dados.Situacao, dados.NomeEmpresa, dados.URLLogo, dadosJSON)
 	if err != nil {
-		c.logger.WithError(err).Error("Erro ao atualizar oferta")
+		c.log().WithError(err).Error("Erro ao atualizar oferta")
 		return err
 	}
 
@@ -214,39 +462,6 @@ func (c *Client) AtualizarOferta(ofertaID string, dados *models.MobgranResponse)
 		return fmt.Errorf("nenhuma oferta encontrada com ID: %s", ofertaID)
 	}
 
-	c.logger.WithField("oferta_id", ofertaID).Info("Oferta atualizada com sucesso")
+	c.log().WithField("oferta_id", ofertaID).Info("Oferta atualizada com sucesso")
 	return nil
 }
-
-// RemoverCavaletesEItens remove todos os cavaletes e itens de uma oferta
-func (c *Client) RemoverCavaletesEItens(ofertaID string) error {
-	// Iniciar transação
-	tx, err := c.db.Begin()
-	if err != nil {
-		return fmt.Errorf("erro ao iniciar transação: %w", err)
-	}
-	defer tx.Rollback()
-
-	// Remover itens (CASCADE vai cuidar disso, mas vamos ser explícitos)
-	_, err = tx.Exec("DELETE FROM itens WHERE cavalete_id IN (SELECT id FROM cavaletes WHERE oferta_id = $1)", ofertaID)
-	if err != nil {
-		c.logger.WithError(err).Error("Erro ao remover itens")
-		return err
-	}
-
-	// Remover cavaletes
-	_, err = tx.Exec("DELETE FROM cavaletes WHERE oferta_id = $1", ofertaID)
-	if err != nil {
-		c.logger.WithError(err).Error("Erro ao remover cavaletes")
-		return err
-	}
-
-	// Commit da transação
-	if err = tx.Commit(); err != nil {
-		c.logger.WithError(err).Error("Erro ao fazer commit da transação")
-		return err
-	}
-
-	c.logger.WithField("oferta_id", ofertaID).Info("Cavaletes e itens removidos com sucesso")
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,34 @@
+-- Migration: 004_create_api_keys.sql
+-- Descrição: Cria tabela de chaves de API para integrações máquina-a-máquina
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+
+    -- Identificação
+    nome VARCHAR(255) NOT NULL,
+    prefixo VARCHAR(32) NOT NULL,
+
+    -- Hash SHA-256 da chave (a chave em texto puro nunca é armazenada)
+    chave_hash VARCHAR(64) UNIQUE NOT NULL,
+
+    -- Permissões
+    escopos TEXT[] NOT NULL DEFAULT '{}',
+
+    -- Ciclo de vida
+    expira_em TIMESTAMP WITH TIME ZONE,
+    ultimo_uso_em TIMESTAMP WITH TIME ZONE,
+    revogada_em TIMESTAMP WITH TIME ZONE,
+
+    -- Metadados
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices
+CREATE INDEX IF NOT EXISTS idx_api_keys_trader_id ON api_keys(trader_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_chave_hash ON api_keys(chave_hash);
+
+-- Comentários
+COMMENT ON TABLE api_keys IS 'Chaves de API de traders para integrações (ERP, site)';
+COMMENT ON COLUMN api_keys.chave_hash IS 'Hash SHA-256 da chave de API';
+COMMENT ON COLUMN api_keys.escopos IS 'Permissões concedidas à chave';
@@ -0,0 +1,39 @@
+-- Migration: 005_create_auditoria.sql
+-- Descrição: Cria trilha de auditoria das operações que alteram dados
+
+CREATE TABLE IF NOT EXISTS auditoria (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+    -- Quem executou a operação
+    ator_id VARCHAR(255),
+    ator_email VARCHAR(255),
+    ator_tipo VARCHAR(20) NOT NULL DEFAULT 'usuario',
+    api_key_id UUID,
+
+    -- O que foi feito
+    acao VARCHAR(100) NOT NULL,
+    entidade VARCHAR(100) NOT NULL,
+    entidade_id VARCHAR(255),
+
+    -- Valores antes e depois da alteração
+    dados_antes JSONB,
+    dados_depois JSONB,
+
+    -- Correlação com a requisição HTTP
+    request_id VARCHAR(128),
+
+    -- Metadados
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Índices para os filtros da consulta
+CREATE INDEX IF NOT EXISTS idx_auditoria_created_at ON auditoria(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_auditoria_ator_id ON auditoria(ator_id);
+CREATE INDEX IF NOT EXISTS idx_auditoria_entidade ON auditoria(entidade, entidade_id);
+CREATE INDEX IF NOT EXISTS idx_auditoria_acao ON auditoria(acao);
+CREATE INDEX IF NOT EXISTS idx_auditoria_request_id ON auditoria(request_id);
+
+-- Comentários
+COMMENT ON TABLE auditoria IS 'Trilha de auditoria de operações que alteram dados';
+COMMENT ON COLUMN auditoria.ator_tipo IS 'usuario, api_key ou sistema';
+COMMENT ON COLUMN auditoria.request_id IS 'X-Request-ID da requisição que originou a operação';
@@ -0,0 +1,79 @@
+-- Migration: 006_soft_delete.sql
+-- Descrição: Adiciona soft delete (deleted_at) em ofertas, cavaletes e produtos aprovados
+
+ALTER TABLE ofertas ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Índices parciais para os registros ativos e para a purga de arquivados
+CREATE INDEX IF NOT EXISTS idx_ofertas_ativas ON ofertas(trader_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_cavaletes_ativos ON cavaletes(oferta_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_produtos_ativos ON produtos_aprovados(trader_id) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_ofertas_deleted_at ON ofertas(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_cavaletes_deleted_at ON cavaletes(deleted_at) WHERE deleted_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_produtos_deleted_at ON produtos_aprovados(deleted_at) WHERE deleted_at IS NOT NULL;
+
+-- Um cavalete só pode ter um produto ativo por trader; produtos removidos podem ser reaprovados
+ALTER TABLE produtos_aprovados DROP CONSTRAINT IF EXISTS unique_trader_cavalete;
+ALTER TABLE produtos_aprovados DROP CONSTRAINT IF EXISTS produtos_aprovados_trader_id_cavalete_id_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_produtos_trader_cavalete_ativo
+    ON produtos_aprovados(trader_id, cavalete_id) WHERE deleted_at IS NULL;
+
+-- Views passam a ignorar registros arquivados
+DROP VIEW IF EXISTS cavaletes_disponiveis;
+CREATE VIEW cavaletes_disponiveis AS
+SELECT
+    c.*,
+    o.trader_id,
+    o.nome_empresa,
+    CASE WHEN pa.id IS NOT NULL THEN TRUE ELSE FALSE END as ja_aprovado
+FROM cavaletes c
+INNER JOIN ofertas o ON c.oferta_id = o.id
+LEFT JOIN produtos_aprovados pa ON c.id = pa.cavalete_id AND pa.deleted_at IS NULL
+WHERE o.trader_id IS NOT NULL
+  AND o.deleted_at IS NULL
+  AND c.deleted_at IS NULL;
+
+DROP VIEW IF EXISTS vitrine_publica;
+CREATE VIEW vitrine_publica AS
+SELECT
+    pa.id,
+    pa.trader_id,
+    pa.nome_customizado,
+    pa.preco_venda,
+    pa.descricao,
+    pa.destaque,
+    pa.ordem_exibicao,
+    c.codigo,
+    c.bloco,
+    c.nome_material,
+    c.nome_espessura,
+    c.nome_classificacao,
+    c.nome_acabamento,
+    c.comprimento,
+    c.altura,
+    c.largura,
+    c.metragem,
+    c.peso,
+    c.tipo_metragem,
+    c.imagem_principal,
+    c.imagens_adicionais,
+    t.nome as trader_nome,
+    t.empresa as trader_empresa,
+    pa.created_at,
+    pa.updated_at
+FROM produtos_aprovados pa
+INNER JOIN cavaletes c ON pa.cavalete_id = c.id
+INNER JOIN ofertas o ON c.oferta_id = o.id
+INNER JOIN traders t ON pa.trader_id = t.id
+WHERE pa.visivel = TRUE
+  AND t.ativo = TRUE
+  AND pa.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+ORDER BY pa.destaque DESC, pa.ordem_exibicao ASC, pa.created_at DESC;
+
+-- Comentários
+COMMENT ON COLUMN ofertas.deleted_at IS 'Data de arquivamento (soft delete)';
+COMMENT ON COLUMN cavaletes.deleted_at IS 'Data de arquivamento (soft delete)';
+COMMENT ON COLUMN produtos_aprovados.deleted_at IS 'Data de remoção/arquivamento (soft delete)';
@@ -0,0 +1,46 @@
+-- Migration: 007_trader_slug.sql
+-- Descrição: Adiciona slug público aos traders para a vitrine compartilhável
+
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS slug VARCHAR(100);
+
+-- Gera um slug a partir de um texto livre (empresa ou nome)
+CREATE OR REPLACE FUNCTION gerar_slug(texto TEXT)
+RETURNS TEXT AS $$
+BEGIN
+    RETURN trim(both '-' from lower(regexp_replace(
+        translate(texto,
+            'áàâãäéèêëíìîïóòôõöúùûüçñÁÀÂÃÄÉÈÊËÍÌÎÏÓÒÔÕÖÚÙÛÜÇÑ',
+            'aaaaaeeeeiiiiooooouuuucnAAAAAEEEEIIIIOOOOOUUUUCN'),
+        '[^a-zA-Z0-9]+', '-', 'g')));
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Preenche traders existentes; o sufixo do id garante unicidade
+UPDATE traders
+SET slug = gerar_slug(COALESCE(NULLIF(empresa, ''), nome)) || '-' || left(id::text, 8)
+WHERE slug IS NULL;
+
+-- Novos traders recebem slug automaticamente
+CREATE OR REPLACE FUNCTION definir_slug_trader()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.slug IS NULL OR NEW.slug = '' THEN
+        NEW.slug = gerar_slug(COALESCE(NULLIF(NEW.empresa, ''), NEW.nome)) || '-' || left(NEW.id::text, 8);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS definir_slug_trader ON traders;
+CREATE TRIGGER definir_slug_trader
+    BEFORE INSERT ON traders
+    FOR EACH ROW
+    EXECUTE FUNCTION definir_slug_trader();
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_traders_slug ON traders(slug);
+
+-- Índices para os filtros da vitrine
+CREATE INDEX IF NOT EXISTS idx_cavaletes_espessura ON cavaletes(lower(nome_espessura));
+CREATE INDEX IF NOT EXISTS idx_produtos_preco_venda ON produtos_aprovados(preco_venda);
+
+COMMENT ON COLUMN traders.slug IS 'Identificador público da vitrine do trader (/vitrine/{slug})';
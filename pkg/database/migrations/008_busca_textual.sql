@@ -0,0 +1,46 @@
+-- Migration: 008_busca_textual.sql
+-- Descrição: Busca textual (português, sem acentos) sobre cavaletes e produtos aprovados
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- Configuração de busca em português que ignora acentos ("mármore" encontra "marmore")
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'portugues_sem_acento') THEN
+        CREATE TEXT SEARCH CONFIGURATION portugues_sem_acento (COPY = portuguese);
+        ALTER TEXT SEARCH CONFIGURATION portugues_sem_acento
+            ALTER MAPPING FOR hword, hword_part, word
+            WITH unaccent, portuguese_stem;
+    END IF;
+END
+$$;
+
+-- Vetor de busca dos cavaletes: material pesa mais que classificação/acabamento,
+-- que pesam mais que bloco/código e observações
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS busca_vetor tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('portugues_sem_acento'::regconfig, coalesce(nome_material, '')), 'A') ||
+        setweight(to_tsvector('portugues_sem_acento'::regconfig,
+            coalesce(nome_classificacao, '') || ' ' || coalesce(nome_acabamento, '') || ' ' || coalesce(nome_espessura, '')), 'B') ||
+        setweight(to_tsvector('portugues_sem_acento'::regconfig,
+            coalesce(bloco, '') || ' ' || coalesce(codigo, '')), 'C') ||
+        setweight(to_tsvector('portugues_sem_acento'::regconfig,
+            coalesce(descricao_chapas, '') || ' ' || coalesce(observacao, '')), 'D')
+    ) STORED;
+
+-- Vetor de busca dos produtos aprovados (nome e descrição definidos pelo trader)
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS busca_vetor tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('portugues_sem_acento'::regconfig, coalesce(nome_customizado, '')), 'A') ||
+        setweight(to_tsvector('portugues_sem_acento'::regconfig, coalesce(descricao, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_cavaletes_busca_vetor ON cavaletes USING GIN(busca_vetor);
+CREATE INDEX IF NOT EXISTS idx_produtos_busca_vetor ON produtos_aprovados USING GIN(busca_vetor);
+
+-- Índices para as facetas
+CREATE INDEX IF NOT EXISTS idx_cavaletes_material ON cavaletes(nome_material);
+CREATE INDEX IF NOT EXISTS idx_cavaletes_classificacao ON cavaletes(nome_classificacao);
+
+COMMENT ON COLUMN cavaletes.busca_vetor IS 'Vetor de busca textual (material, classificação, acabamento, espessura, bloco, código, observações)';
+COMMENT ON COLUMN produtos_aprovados.busca_vetor IS 'Vetor de busca textual (nome customizado e descrição)';
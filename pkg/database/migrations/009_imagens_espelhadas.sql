@@ -0,0 +1,28 @@
+-- Migration: 009_imagens_espelhadas.sql
+-- Descrição: Cópias locais das imagens dos cavaletes hospedadas no Mobgran e suas miniaturas
+
+CREATE TABLE IF NOT EXISTS imagens_espelhadas (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+
+    -- Endereço original no Mobgran; a mesma imagem é baixada apenas uma vez
+    url_origem TEXT NOT NULL UNIQUE,
+
+    -- Chaves no armazenamento (local ou S3)
+    chave_original TEXT NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    largura INTEGER,
+    altura INTEGER,
+    tamanho_bytes BIGINT NOT NULL DEFAULT 0,
+    miniaturas JSONB NOT NULL DEFAULT '{}',
+
+    -- Metadados
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Cavaletes com imagem ainda não espelhada (reprocessamento)
+CREATE INDEX IF NOT EXISTS idx_cavaletes_imagem_pendente ON cavaletes(created_at)
+    WHERE imagem_principal IS NOT NULL AND (imagem_principal->>'urlOrigem') IS NULL;
+
+COMMENT ON TABLE imagens_espelhadas IS 'Imagens externas copiadas para o armazenamento da aplicação';
+COMMENT ON COLUMN imagens_espelhadas.miniaturas IS 'Chaves das miniaturas por tamanho (pequena, media, grande)';
@@ -0,0 +1,95 @@
+-- Migration: 010_imagens_produtos.sql
+-- Descrição: Galeria de imagens enviadas pelos traders para os produtos aprovados
+
+CREATE TABLE IF NOT EXISTS imagens_produtos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    produto_id UUID NOT NULL REFERENCES produtos_aprovados(id) ON DELETE CASCADE,
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+
+    -- Arquivo original e miniaturas no armazenamento (chaves) e seus endereços públicos
+    chave_original TEXT NOT NULL,
+    chaves_miniaturas JSONB NOT NULL DEFAULT '{}',
+    url TEXT NOT NULL,
+    miniaturas JSONB NOT NULL DEFAULT '{}',
+
+    -- Metadados do arquivo
+    nome_arquivo VARCHAR(255),
+    content_type VARCHAR(100) NOT NULL,
+    largura INTEGER,
+    altura INTEGER,
+    tamanho_bytes BIGINT NOT NULL DEFAULT 0,
+
+    -- Exibição
+    ordem INTEGER NOT NULL DEFAULT 0,
+    capa BOOLEAN NOT NULL DEFAULT FALSE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_imagens_produtos_produto ON imagens_produtos(produto_id, ordem);
+
+-- Cada produto tem no máximo uma capa
+CREATE UNIQUE INDEX IF NOT EXISTS idx_imagens_produtos_capa ON imagens_produtos(produto_id) WHERE capa;
+
+-- A vitrine passa a exibir a capa enviada pelo trader como imagem principal e a galeria
+-- como imagens adicionais, mantendo a imagem do cavalete quando não há envio
+CREATE OR REPLACE VIEW vitrine_publica AS
+SELECT
+    pa.id,
+    pa.trader_id,
+    pa.nome_customizado,
+    pa.preco_venda,
+    pa.descricao,
+    pa.destaque,
+    pa.ordem_exibicao,
+    c.codigo,
+    c.bloco,
+    c.nome_material,
+    c.nome_espessura,
+    c.nome_classificacao,
+    c.nome_acabamento,
+    c.comprimento,
+    c.altura,
+    c.largura,
+    c.metragem,
+    c.peso,
+    c.tipo_metragem,
+    COALESCE(
+        (SELECT jsonb_build_object(
+                    'nome', ip.nome_arquivo,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id AND ip.capa),
+        c.imagem_principal
+    ) AS imagem_principal,
+    COALESCE(
+        (SELECT jsonb_agg(jsonb_build_object(
+                    'id', ip.id,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas,
+                    'capa', ip.capa) ORDER BY ip.ordem, ip.created_at)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id),
+        c.imagens_adicionais
+    ) AS imagens_adicionais,
+    t.nome as trader_nome,
+    t.empresa as trader_empresa,
+    pa.created_at,
+    pa.updated_at
+FROM produtos_aprovados pa
+INNER JOIN cavaletes c ON pa.cavalete_id = c.id
+INNER JOIN ofertas o ON c.oferta_id = o.id
+INNER JOIN traders t ON pa.trader_id = t.id
+WHERE pa.visivel = TRUE
+  AND t.ativo = TRUE
+  AND pa.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+ORDER BY pa.destaque DESC, pa.ordem_exibicao ASC, pa.created_at DESC;
+
+COMMENT ON TABLE imagens_produtos IS 'Imagens enviadas pelos traders para seus produtos';
+COMMENT ON COLUMN imagens_produtos.chaves_miniaturas IS 'Chaves das miniaturas no armazenamento, por tamanho';
+COMMENT ON COLUMN imagens_produtos.miniaturas IS 'URLs públicas das miniaturas, por tamanho';
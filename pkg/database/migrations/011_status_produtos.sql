@@ -0,0 +1,89 @@
+-- Migration: 011_status_produtos.sql
+-- Descrição: Ciclo de vida de venda dos produtos aprovados (disponível, reservado, vendido)
+
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'disponivel';
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS cliente VARCHAR(255);
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS reserva_observacao TEXT;
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS reservado_em TIMESTAMP WITH TIME ZONE;
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS reserva_expira_em TIMESTAMP WITH TIME ZONE;
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS vendido_em TIMESTAMP WITH TIME ZONE;
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS valor_venda DECIMAL(12,2);
+
+DO $$
+BEGIN
+    ALTER TABLE produtos_aprovados ADD CONSTRAINT chk_produtos_aprovados_status
+        CHECK (status IN ('disponivel', 'reservado', 'vendido'));
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_produtos_aprovados_status ON produtos_aprovados(trader_id, status) WHERE deleted_at IS NULL;
+
+-- Usado pelo worker que expira as reservas vencidas
+CREATE INDEX IF NOT EXISTS idx_produtos_reservas_expiracao
+    ON produtos_aprovados(reserva_expira_em) WHERE status = 'reservado';
+
+-- Produtos vendidos saem da vitrine; os reservados continuam visíveis com o status
+DROP VIEW IF EXISTS vitrine_publica;
+CREATE VIEW vitrine_publica AS
+SELECT
+    pa.id,
+    pa.trader_id,
+    pa.nome_customizado,
+    pa.preco_venda,
+    pa.descricao,
+    pa.destaque,
+    pa.ordem_exibicao,
+    c.codigo,
+    c.bloco,
+    c.nome_material,
+    c.nome_espessura,
+    c.nome_classificacao,
+    c.nome_acabamento,
+    c.comprimento,
+    c.altura,
+    c.largura,
+    c.metragem,
+    c.peso,
+    c.tipo_metragem,
+    COALESCE(
+        (SELECT jsonb_build_object(
+                    'nome', ip.nome_arquivo,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id AND ip.capa),
+        c.imagem_principal
+    ) AS imagem_principal,
+    COALESCE(
+        (SELECT jsonb_agg(jsonb_build_object(
+                    'id', ip.id,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas,
+                    'capa', ip.capa) ORDER BY ip.ordem, ip.created_at)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id),
+        c.imagens_adicionais
+    ) AS imagens_adicionais,
+    t.nome as trader_nome,
+    t.empresa as trader_empresa,
+    pa.created_at,
+    pa.updated_at,
+    pa.status
+FROM produtos_aprovados pa
+INNER JOIN cavaletes c ON pa.cavalete_id = c.id
+INNER JOIN ofertas o ON c.oferta_id = o.id
+INNER JOIN traders t ON pa.trader_id = t.id
+WHERE pa.visivel = TRUE
+  AND t.ativo = TRUE
+  AND pa.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+  AND pa.status <> 'vendido'
+ORDER BY pa.destaque DESC, pa.ordem_exibicao ASC, pa.created_at DESC;
+
+COMMENT ON COLUMN produtos_aprovados.status IS 'Situação de venda: disponivel, reservado ou vendido';
+COMMENT ON COLUMN produtos_aprovados.cliente IS 'Cliente da reserva ou da venda';
+COMMENT ON COLUMN produtos_aprovados.reserva_expira_em IS 'Após esta data a reserva é liberada automaticamente';
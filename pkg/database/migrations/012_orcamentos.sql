@@ -0,0 +1,59 @@
+-- Migration: 012_orcamentos.sql
+-- Descrição: Orçamentos de clientes montados a partir dos produtos aprovados
+
+CREATE TABLE IF NOT EXISTS orcamentos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+
+    -- Numeração sequencial por trader, exibida ao cliente
+    numero INTEGER NOT NULL,
+
+    cliente VARCHAR(255) NOT NULL,
+    cliente_contato VARCHAR(255),
+    observacoes TEXT,
+    validade DATE,
+
+    status VARCHAR(20) NOT NULL DEFAULT 'rascunho'
+        CHECK (status IN ('rascunho', 'enviado', 'aceito', 'recusado')),
+
+    -- Descontos sobre o subtotal: percentual e valor fixo
+    desconto_percentual DECIMAL(5,2) NOT NULL DEFAULT 0 CHECK (desconto_percentual BETWEEN 0 AND 100),
+    desconto_valor DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (desconto_valor >= 0),
+
+    enviado_em TIMESTAMP WITH TIME ZONE,
+    respondido_em TIMESTAMP WITH TIME ZONE,
+    convertido_em TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE (trader_id, numero)
+);
+
+CREATE TABLE IF NOT EXISTS orcamento_itens (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    orcamento_id UUID NOT NULL REFERENCES orcamentos(id) ON DELETE CASCADE,
+    produto_id UUID NOT NULL REFERENCES produtos_aprovados(id) ON DELETE CASCADE,
+
+    -- Descrição copiada do produto no momento da inclusão
+    descricao VARCHAR(255) NOT NULL,
+
+    -- Quantidade em m² e preço negociado por m²
+    quantidade_m2 DECIMAL(12,4) NOT NULL CHECK (quantidade_m2 > 0),
+    preco_m2 DECIMAL(12,2) NOT NULL CHECK (preco_m2 >= 0),
+
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    UNIQUE (orcamento_id, produto_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_orcamentos_trader ON orcamentos(trader_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_orcamentos_status ON orcamentos(trader_id, status);
+CREATE INDEX IF NOT EXISTS idx_orcamento_itens_orcamento ON orcamento_itens(orcamento_id);
+CREATE INDEX IF NOT EXISTS idx_orcamento_itens_produto ON orcamento_itens(produto_id);
+
+COMMENT ON TABLE orcamentos IS 'Orçamentos enviados pelos traders a clientes (arquitetos, marmorarias)';
+COMMENT ON COLUMN orcamentos.status IS 'rascunho (editável), enviado, aceito ou recusado';
+COMMENT ON COLUMN orcamentos.convertido_em IS 'Quando o orçamento aceito foi convertido em reservas';
+COMMENT ON TABLE orcamento_itens IS 'Produtos aprovados incluídos no orçamento com quantidade e preço negociado';
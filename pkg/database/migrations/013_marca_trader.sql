@@ -0,0 +1,13 @@
+-- Migration: 013_marca_trader.sql
+-- Descrição: Dados de marca dos traders usados nos PDFs (fichas técnicas e orçamentos)
+
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS documento VARCHAR(20);
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS endereco TEXT;
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS site VARCHAR(255);
+
+-- Logotipo no armazenamento (chave) e seu endereço público
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS logo_chave TEXT;
+ALTER TABLE traders ADD COLUMN IF NOT EXISTS logo_url TEXT;
+
+COMMENT ON COLUMN traders.documento IS 'CNPJ ou CPF exibido no cabeçalho dos PDFs';
+COMMENT ON COLUMN traders.logo_chave IS 'Chave do logotipo no armazenamento de arquivos';
@@ -0,0 +1,60 @@
+-- Migration: 014_clientes.sql
+-- Descrição: Cadastro de clientes (compradores) dos traders, vinculado a reservas, vendas e orçamentos
+
+CREATE TABLE IF NOT EXISTS clientes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+    nome VARCHAR(255) NOT NULL,
+
+    -- CPF ou CNPJ apenas com dígitos, validado pela aplicação
+    documento VARCHAR(14),
+    tipo_documento VARCHAR(4),
+
+    email VARCHAR(255),
+    telefone VARCHAR(20),
+
+    -- Endereço
+    cep VARCHAR(8),
+    logradouro VARCHAR(255),
+    numero VARCHAR(20),
+    complemento VARCHAR(255),
+    bairro VARCHAR(100),
+    cidade VARCHAR(100),
+    uf CHAR(2),
+
+    observacoes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_clientes_tipo_documento CHECK (tipo_documento IN ('cpf', 'cnpj'))
+);
+
+-- Um documento por cliente de cada trader
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clientes_trader_documento
+    ON clientes(trader_id, documento) WHERE documento IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_clientes_trader_created ON clientes(trader_id, created_at DESC, id DESC);
+
+-- Pessoas de contato do cliente
+CREATE TABLE IF NOT EXISTS cliente_contatos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cliente_id UUID NOT NULL REFERENCES clientes(id) ON DELETE CASCADE,
+    nome VARCHAR(255) NOT NULL,
+    cargo VARCHAR(100),
+    email VARCHAR(255),
+    telefone VARCHAR(20),
+    principal BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_cliente_contatos_cliente ON cliente_contatos(cliente_id);
+
+-- Vínculo das reservas/vendas e dos orçamentos com o cadastro. O nome em "cliente"
+-- continua sendo gravado, preservando o histórico se o cadastro for removido.
+ALTER TABLE produtos_aprovados ADD COLUMN IF NOT EXISTS cliente_id UUID REFERENCES clientes(id) ON DELETE SET NULL;
+ALTER TABLE orcamentos ADD COLUMN IF NOT EXISTS cliente_id UUID REFERENCES clientes(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_produtos_aprovados_cliente ON produtos_aprovados(cliente_id) WHERE cliente_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_orcamentos_cliente ON orcamentos(cliente_id) WHERE cliente_id IS NOT NULL;
+
+COMMENT ON TABLE clientes IS 'Clientes (compradores) cadastrados por cada trader';
+COMMENT ON COLUMN clientes.documento IS 'CPF ou CNPJ, apenas dígitos';
@@ -0,0 +1,98 @@
+-- Migration: 015_medidas_normalizadas.sql
+-- Descrição: Medidas normalizadas dos cavaletes e itens, gravadas ao lado dos valores
+-- brutos do Mobgran (espessura em mm, dimensões em m, metragem em m², peso em kg)
+
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS espessura_mm DECIMAL(6,2);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS comprimento_m DECIMAL(10,3);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS altura_m DECIMAL(10,3);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS metragem_m2 DECIMAL(10,3);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS peso_kg DECIMAL(12,2);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS unidade_dimensoes VARCHAR(2);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS medidas_normalizadas_em TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE itens ADD COLUMN IF NOT EXISTS espessura_mm DECIMAL(6,2);
+ALTER TABLE itens ADD COLUMN IF NOT EXISTS comprimento_m DECIMAL(10,3);
+ALTER TABLE itens ADD COLUMN IF NOT EXISTS altura_m DECIMAL(10,3);
+ALTER TABLE itens ADD COLUMN IF NOT EXISTS metragem_m2 DECIMAL(10,3);
+ALTER TABLE itens ADD COLUMN IF NOT EXISTS peso_kg DECIMAL(12,2);
+ALTER TABLE itens ADD COLUMN IF NOT EXISTS unidade_dimensoes VARCHAR(2);
+
+-- Usado pelos filtros de espessura e metragem da busca e da vitrine
+CREATE INDEX IF NOT EXISTS idx_cavaletes_espessura_mm ON cavaletes(espessura_mm) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_cavaletes_metragem_m2 ON cavaletes(metragem_m2) WHERE deleted_at IS NULL;
+
+-- Cavaletes importados antes desta migration são normalizados em segundo plano
+CREATE INDEX IF NOT EXISTS idx_cavaletes_medidas_pendentes ON cavaletes(created_at) WHERE medidas_normalizadas_em IS NULL;
+
+-- A vitrine passa a expor as medidas normalizadas
+DROP VIEW IF EXISTS vitrine_publica;
+CREATE VIEW vitrine_publica AS
+SELECT
+    pa.id,
+    pa.trader_id,
+    pa.nome_customizado,
+    pa.preco_venda,
+    pa.descricao,
+    pa.destaque,
+    pa.ordem_exibicao,
+    c.codigo,
+    c.bloco,
+    c.nome_material,
+    c.nome_espessura,
+    c.nome_classificacao,
+    c.nome_acabamento,
+    c.comprimento,
+    c.altura,
+    c.largura,
+    c.metragem,
+    c.peso,
+    c.tipo_metragem,
+    COALESCE(
+        (SELECT jsonb_build_object(
+                    'nome', ip.nome_arquivo,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id AND ip.capa),
+        c.imagem_principal
+    ) AS imagem_principal,
+    COALESCE(
+        (SELECT jsonb_agg(jsonb_build_object(
+                    'id', ip.id,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas,
+                    'capa', ip.capa) ORDER BY ip.ordem, ip.created_at)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id),
+        c.imagens_adicionais
+    ) AS imagens_adicionais,
+    t.nome as trader_nome,
+    t.empresa as trader_empresa,
+    pa.created_at,
+    pa.updated_at,
+    pa.status,
+    c.espessura_mm,
+    c.comprimento_m,
+    c.altura_m,
+    c.metragem_m2,
+    c.peso_kg,
+    c.unidade_dimensoes
+FROM produtos_aprovados pa
+INNER JOIN cavaletes c ON pa.cavalete_id = c.id
+INNER JOIN ofertas o ON c.oferta_id = o.id
+INNER JOIN traders t ON pa.trader_id = t.id
+WHERE pa.visivel = TRUE
+  AND t.ativo = TRUE
+  AND pa.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+  AND pa.status <> 'vendido'
+ORDER BY pa.destaque DESC, pa.ordem_exibicao ASC, pa.created_at DESC;
+
+COMMENT ON COLUMN cavaletes.espessura_mm IS 'Espessura em milímetros interpretada de nome_espessura';
+COMMENT ON COLUMN cavaletes.metragem_m2 IS 'Metragem em m²: a informada pelo Mobgran ou, na falta dela, calculada pelas dimensões';
+COMMENT ON COLUMN cavaletes.unidade_dimensoes IS 'Unidade deduzida de comprimento e altura brutos: m, cm ou mm';
+COMMENT ON COLUMN cavaletes.medidas_normalizadas_em IS 'Quando as medidas normalizadas foram calculadas; NULL indica pendência';
+COMMENT ON COLUMN cavaletes.tipo_metragem IS 'Origem da metragem normalizada: informada ou calculada';
@@ -0,0 +1,141 @@
+-- Migration: 016_catalogo_materiais.sql
+-- Descrição: Catálogo curado de materiais e espessuras, com sinônimos e mapeamentos
+-- por trader dos nomes importados de cada fornecedor
+
+CREATE TABLE IF NOT EXISTS catalogo_materiais (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    nome VARCHAR(255) NOT NULL,
+    -- Forma canônica do nome para comparação (pkg/catalogo.Chave)
+    chave VARCHAR(255) NOT NULL,
+    tipo VARCHAR(50),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_catalogo_materiais_chave ON catalogo_materiais(chave);
+
+CREATE TABLE IF NOT EXISTS catalogo_sinonimos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    material_id UUID NOT NULL REFERENCES catalogo_materiais(id) ON DELETE CASCADE,
+    sinonimo VARCHAR(255) NOT NULL,
+    chave VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_catalogo_sinonimos_chave ON catalogo_sinonimos(chave);
+CREATE INDEX IF NOT EXISTS idx_catalogo_sinonimos_material ON catalogo_sinonimos(material_id);
+
+CREATE TABLE IF NOT EXISTS catalogo_espessuras (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    nome VARCHAR(50) NOT NULL,
+    espessura_mm DECIMAL(6,2) NOT NULL UNIQUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO catalogo_espessuras (nome, espessura_mm) VALUES
+    ('1 cm', 10), ('1,2 cm', 12), ('1,5 cm', 15), ('1,8 cm', 18),
+    ('2 cm', 20), ('3 cm', 30), ('4 cm', 40), ('5 cm', 50)
+ON CONFLICT (espessura_mm) DO NOTHING;
+
+-- Correspondências confirmadas ou corrigidas pelo trader; prevalecem sobre a
+-- correspondência automática pelo catálogo
+CREATE TABLE IF NOT EXISTS mapeamentos_catalogo (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+    tipo VARCHAR(20) NOT NULL,
+    valor_origem VARCHAR(255) NOT NULL,
+    chave VARCHAR(255) NOT NULL,
+    material_id UUID REFERENCES catalogo_materiais(id) ON DELETE CASCADE,
+    espessura_id UUID REFERENCES catalogo_espessuras(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+
+    CONSTRAINT chk_mapeamentos_catalogo_tipo CHECK (
+        (tipo = 'material' AND material_id IS NOT NULL AND espessura_id IS NULL) OR
+        (tipo = 'espessura' AND espessura_id IS NOT NULL AND material_id IS NULL)
+    )
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_mapeamentos_catalogo_chave ON mapeamentos_catalogo(trader_id, tipo, chave);
+
+-- Correspondência aplicada a cada cavalete; produto_cliente e espessura_cliente
+-- recebem os nomes do catálogo
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS material_id UUID REFERENCES catalogo_materiais(id) ON DELETE SET NULL;
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS espessura_id UUID REFERENCES catalogo_espessuras(id) ON DELETE SET NULL;
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS origem_material VARCHAR(20);
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS origem_espessura VARCHAR(20);
+
+CREATE INDEX IF NOT EXISTS idx_cavaletes_material_id ON cavaletes(material_id) WHERE deleted_at IS NULL;
+
+-- A vitrine passa a expor os nomes do catálogo
+DROP VIEW IF EXISTS vitrine_publica;
+CREATE VIEW vitrine_publica AS
+SELECT
+    pa.id,
+    pa.trader_id,
+    pa.nome_customizado,
+    pa.preco_venda,
+    pa.descricao,
+    pa.destaque,
+    pa.ordem_exibicao,
+    c.codigo,
+    c.bloco,
+    c.nome_material,
+    c.nome_espessura,
+    c.nome_classificacao,
+    c.nome_acabamento,
+    c.comprimento,
+    c.altura,
+    c.largura,
+    c.metragem,
+    c.peso,
+    c.tipo_metragem,
+    COALESCE(
+        (SELECT jsonb_build_object(
+                    'nome', ip.nome_arquivo,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id AND ip.capa),
+        c.imagem_principal
+    ) AS imagem_principal,
+    COALESCE(
+        (SELECT jsonb_agg(jsonb_build_object(
+                    'id', ip.id,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas,
+                    'capa', ip.capa) ORDER BY ip.ordem, ip.created_at)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id),
+        c.imagens_adicionais
+    ) AS imagens_adicionais,
+    t.nome as trader_nome,
+    t.empresa as trader_empresa,
+    pa.created_at,
+    pa.updated_at,
+    pa.status,
+    c.espessura_mm,
+    c.comprimento_m,
+    c.altura_m,
+    c.metragem_m2,
+    c.peso_kg,
+    c.unidade_dimensoes,
+    c.produto_cliente,
+    c.espessura_cliente
+FROM produtos_aprovados pa
+INNER JOIN cavaletes c ON pa.cavalete_id = c.id
+INNER JOIN ofertas o ON c.oferta_id = o.id
+INNER JOIN traders t ON pa.trader_id = t.id
+WHERE pa.visivel = TRUE
+  AND t.ativo = TRUE
+  AND pa.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+  AND pa.status <> 'vendido'
+ORDER BY pa.destaque DESC, pa.ordem_exibicao ASC, pa.created_at DESC;
+
+COMMENT ON COLUMN cavaletes.produto_cliente IS 'Nome do material no catálogo correspondente a nome_material';
+COMMENT ON COLUMN cavaletes.espessura_cliente IS 'Nome da espessura no catálogo correspondente a nome_espessura';
+COMMENT ON COLUMN cavaletes.origem_material IS 'Como o material foi associado: automatico (catálogo) ou trader (mapeamento confirmado)';
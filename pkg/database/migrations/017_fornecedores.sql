@@ -0,0 +1,108 @@
+-- Migration: 017_fornecedores.sql
+-- Descrição: Cadastro de fornecedores (empresas de origem das ofertas do Mobgran),
+-- vinculados às ofertas na importação pelo nome da empresa
+
+CREATE TABLE IF NOT EXISTS fornecedores (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    nome VARCHAR(255) NOT NULL,
+    url_logo TEXT,
+    documento VARCHAR(14),
+    contato VARCHAR(255),
+    email VARCHAR(255),
+    telefone VARCHAR(20),
+    site VARCHAR(255),
+    cidade VARCHAR(100),
+    uf CHAR(2),
+    observacoes TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Grafias de nome_empresa conhecidas de cada fornecedor; ao mesclar fornecedores
+-- duplicados, as grafias passam para o fornecedor mantido
+CREATE TABLE IF NOT EXISTS fornecedores_nomes (
+    -- Forma canônica do nome para comparação (pkg/catalogo.ChaveEmpresa)
+    chave VARCHAR(255) PRIMARY KEY,
+    fornecedor_id UUID NOT NULL REFERENCES fornecedores(id) ON DELETE CASCADE,
+    nome VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_fornecedores_nomes_fornecedor ON fornecedores_nomes(fornecedor_id);
+
+ALTER TABLE ofertas ADD COLUMN IF NOT EXISTS fornecedor_id UUID REFERENCES fornecedores(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_ofertas_fornecedor_id ON ofertas(fornecedor_id);
+CREATE INDEX IF NOT EXISTS idx_ofertas_fornecedor_pendente ON ofertas(created_at) WHERE fornecedor_id IS NULL;
+
+-- A vitrine passa a permitir o filtro por fornecedor
+DROP VIEW IF EXISTS vitrine_publica;
+CREATE VIEW vitrine_publica AS
+SELECT
+    pa.id,
+    pa.trader_id,
+    pa.nome_customizado,
+    pa.preco_venda,
+    pa.descricao,
+    pa.destaque,
+    pa.ordem_exibicao,
+    c.codigo,
+    c.bloco,
+    c.nome_material,
+    c.nome_espessura,
+    c.nome_classificacao,
+    c.nome_acabamento,
+    c.comprimento,
+    c.altura,
+    c.largura,
+    c.metragem,
+    c.peso,
+    c.tipo_metragem,
+    COALESCE(
+        (SELECT jsonb_build_object(
+                    'nome', ip.nome_arquivo,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id AND ip.capa),
+        c.imagem_principal
+    ) AS imagem_principal,
+    COALESCE(
+        (SELECT jsonb_agg(jsonb_build_object(
+                    'id', ip.id,
+                    'url', ip.url,
+                    'urlMin', COALESCE(ip.miniaturas->>'media', ip.url),
+                    'miniaturas', ip.miniaturas,
+                    'capa', ip.capa) ORDER BY ip.ordem, ip.created_at)
+         FROM imagens_produtos ip
+         WHERE ip.produto_id = pa.id),
+        c.imagens_adicionais
+    ) AS imagens_adicionais,
+    t.nome as trader_nome,
+    t.empresa as trader_empresa,
+    pa.created_at,
+    pa.updated_at,
+    pa.status,
+    c.espessura_mm,
+    c.comprimento_m,
+    c.altura_m,
+    c.metragem_m2,
+    c.peso_kg,
+    c.unidade_dimensoes,
+    c.produto_cliente,
+    c.espessura_cliente,
+    o.fornecedor_id
+FROM produtos_aprovados pa
+INNER JOIN cavaletes c ON pa.cavalete_id = c.id
+INNER JOIN ofertas o ON c.oferta_id = o.id
+INNER JOIN traders t ON pa.trader_id = t.id
+WHERE pa.visivel = TRUE
+  AND t.ativo = TRUE
+  AND pa.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND o.deleted_at IS NULL
+  AND pa.status <> 'vendido'
+ORDER BY pa.destaque DESC, pa.ordem_exibicao ASC, pa.created_at DESC;
+
+COMMENT ON COLUMN ofertas.fornecedor_id IS 'Fornecedor identificado pelo nome_empresa da oferta; NULL indica vínculo pendente';
@@ -0,0 +1,61 @@
+-- Migration: 018_historico.sql
+-- Descrição: Histórico de estoque (retrato dos cavaletes a cada importação da oferta)
+-- e histórico de preços dos produtos aprovados
+
+CREATE TABLE IF NOT EXISTS importacoes_oferta (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    oferta_id UUID NOT NULL REFERENCES ofertas(id) ON DELETE CASCADE,
+    situacao VARCHAR(100) NOT NULL,
+    cavaletes INTEGER NOT NULL DEFAULT 0,
+    metragem_m2 DECIMAL(12,3) NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_importacoes_oferta_oferta ON importacoes_oferta(oferta_id, created_at DESC);
+
+-- Os cavaletes são regravados a cada reimportação, com novos IDs; entre importações,
+-- o cavalete é identificado pelo código dentro da oferta
+CREATE TABLE IF NOT EXISTS historico_cavaletes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    importacao_id UUID NOT NULL REFERENCES importacoes_oferta(id) ON DELETE CASCADE,
+    oferta_id UUID NOT NULL REFERENCES ofertas(id) ON DELETE CASCADE,
+    codigo VARCHAR(255) NOT NULL,
+    bloco VARCHAR(255) NOT NULL,
+    nome_material VARCHAR(255) NOT NULL,
+    nome_espessura VARCHAR(255) NOT NULL,
+    metragem_m2 DECIMAL(10,3),
+    quantidade_itens INTEGER
+);
+
+CREATE INDEX IF NOT EXISTS idx_historico_cavaletes_importacao ON historico_cavaletes(importacao_id);
+CREATE INDEX IF NOT EXISTS idx_historico_cavaletes_codigo ON historico_cavaletes(oferta_id, codigo);
+
+CREATE TABLE IF NOT EXISTS historico_precos (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    produto_id UUID NOT NULL REFERENCES produtos_aprovados(id) ON DELETE CASCADE,
+    preco_anterior DECIMAL(10,2),
+    preco_novo DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_historico_precos_produto ON historico_precos(produto_id, created_at);
+
+-- Retrato inicial das ofertas já importadas, para que a próxima importação tenha com o
+-- que ser comparada
+INSERT INTO importacoes_oferta (oferta_id, situacao, cavaletes, metragem_m2, created_at)
+SELECT o.id, o.situacao, COUNT(c.id), COALESCE(SUM(COALESCE(c.metragem_m2, c.metragem)), 0), o.updated_at
+FROM ofertas o
+LEFT JOIN cavaletes c ON c.oferta_id = o.id AND c.deleted_at IS NULL
+WHERE NOT EXISTS (SELECT 1 FROM importacoes_oferta io WHERE io.oferta_id = o.id)
+GROUP BY o.id;
+
+INSERT INTO historico_cavaletes (importacao_id, oferta_id, codigo, bloco, nome_material, nome_espessura, metragem_m2, quantidade_itens)
+SELECT io.id, c.oferta_id, c.codigo, c.bloco, c.nome_material, c.nome_espessura,
+       COALESCE(c.metragem_m2, c.metragem), c.quantidade_itens
+FROM importacoes_oferta io
+JOIN cavaletes c ON c.oferta_id = io.oferta_id AND c.deleted_at IS NULL
+WHERE NOT EXISTS (SELECT 1 FROM historico_cavaletes hc WHERE hc.importacao_id = io.id);
+
+COMMENT ON TABLE importacoes_oferta IS 'Cada importação ou reimportação da oferta, com os totais do estoque';
+COMMENT ON TABLE historico_cavaletes IS 'Retrato dos cavaletes da oferta em cada importação; ausência indica cavalete indisponível';
+COMMENT ON TABLE historico_precos IS 'Alterações de preco_venda dos produtos aprovados; preco_anterior nulo indica o preço da aprovação';
@@ -0,0 +1,57 @@
+-- Migration: 019_estatisticas_trader.sql
+-- Descrição: Refaz vw_estatisticas_trader sem a multiplicação de linhas da junção de
+-- cavaletes com produtos, ignorando registros arquivados e com a metragem em m²
+
+CREATE INDEX IF NOT EXISTS idx_importacoes_oferta_created_at ON importacoes_oferta(created_at);
+CREATE INDEX IF NOT EXISTS idx_produtos_aprovados_trader_created ON produtos_aprovados(trader_id, created_at) WHERE deleted_at IS NULL;
+
+DROP VIEW IF EXISTS vw_estatisticas_trader;
+CREATE VIEW vw_estatisticas_trader AS
+SELECT
+    t.id as trader_id,
+    t.nome,
+    t.email,
+    t.empresa,
+
+    -- Ofertas importadas
+    COALESCE(imp.ofertas, 0) as total_ofertas_importadas,
+    COALESCE(imp.cavaletes, 0) as total_cavaletes_importados,
+    COALESCE(imp.metragem, 0) as metragem_total_importada,
+
+    -- Produtos aprovados
+    COALESCE(apr.produtos, 0) as total_produtos_aprovados,
+    COALESCE(apr.visiveis, 0) as produtos_visiveis,
+    COALESCE(apr.destaque, 0) as produtos_destaque,
+
+    -- Valores
+    COALESCE(apr.valor_total, 0) as valor_total_vitrine,
+    COALESCE(apr.preco_medio, 0) as preco_medio_vitrine,
+    COALESCE(apr.metragem, 0) as metragem_total_aprovada
+
+FROM traders t
+LEFT JOIN (
+    SELECT o.trader_id,
+        COUNT(DISTINCT o.id) as ofertas,
+        COUNT(c.id) as cavaletes,
+        COALESCE(SUM(COALESCE(c.metragem_m2, c.metragem)), 0) as metragem
+    FROM ofertas o
+    LEFT JOIN cavaletes c ON c.oferta_id = o.id AND c.deleted_at IS NULL
+    WHERE o.deleted_at IS NULL
+    GROUP BY o.trader_id
+) imp ON imp.trader_id = t.id
+LEFT JOIN (
+    SELECT pa.trader_id,
+        COUNT(*) as produtos,
+        COUNT(*) FILTER (WHERE pa.visivel) as visiveis,
+        COUNT(*) FILTER (WHERE pa.destaque) as destaque,
+        SUM(pa.preco_venda) as valor_total,
+        AVG(pa.preco_venda) as preco_medio,
+        COALESCE(SUM(COALESCE(c.metragem_m2, c.metragem)), 0) as metragem
+    FROM produtos_aprovados pa
+    JOIN cavaletes c ON c.id = pa.cavalete_id
+    WHERE pa.deleted_at IS NULL
+    GROUP BY pa.trader_id
+) apr ON apr.trader_id = t.id
+WHERE t.ativo = true;
+
+COMMENT ON VIEW vw_estatisticas_trader IS 'Estatísticas agregadas por trader';
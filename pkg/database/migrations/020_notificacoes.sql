@@ -0,0 +1,62 @@
+-- Migration: 020_notificacoes.sql
+-- Descrição: Assinaturas de notificação por evento (webhook ou e-mail), fila de
+-- notificações com novas tentativas e registro de cada tentativa de entrega
+
+CREATE TABLE IF NOT EXISTS assinaturas_notificacao (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+    canal VARCHAR(20) NOT NULL CHECK (canal IN ('webhook', 'email')),
+    destino TEXT NOT NULL,
+    segredo VARCHAR(100),
+    eventos TEXT[] NOT NULL,
+    ativa BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_assinaturas_notificacao_trader ON assinaturas_notificacao(trader_id) WHERE ativa;
+
+CREATE TABLE IF NOT EXISTS notificacoes (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    assinatura_id UUID NOT NULL REFERENCES assinaturas_notificacao(id) ON DELETE CASCADE,
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+    evento VARCHAR(100) NOT NULL,
+    chave VARCHAR(255) NOT NULL,
+    dados JSONB NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'pendente' CHECK (status IN ('pendente', 'entregue', 'falhou')),
+    tentativas INTEGER NOT NULL DEFAULT 0,
+    proxima_tentativa TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    ultimo_erro TEXT,
+    entregue_em TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    -- Um mesmo acontecimento (ex.: a reserva X vencendo em Y) gera uma única notificação
+    -- por assinatura, mesmo detectado várias vezes
+    CONSTRAINT unique_notificacao_chave UNIQUE (assinatura_id, chave)
+);
+
+CREATE INDEX IF NOT EXISTS idx_notificacoes_pendentes ON notificacoes(proxima_tentativa) WHERE status = 'pendente';
+CREATE INDEX IF NOT EXISTS idx_notificacoes_trader ON notificacoes(trader_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS entregas_notificacao (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    notificacao_id UUID NOT NULL REFERENCES notificacoes(id) ON DELETE CASCADE,
+    tentativa INTEGER NOT NULL,
+    sucesso BOOLEAN NOT NULL,
+    status_http INTEGER,
+    erro TEXT,
+    duracao_ms INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_entregas_notificacao ON entregas_notificacao(notificacao_id, tentativa);
+
+DROP TRIGGER IF EXISTS update_assinaturas_notificacao_updated_at ON assinaturas_notificacao;
+CREATE TRIGGER update_assinaturas_notificacao_updated_at
+    BEFORE UPDATE ON assinaturas_notificacao
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+COMMENT ON TABLE assinaturas_notificacao IS 'Destinos (webhook ou e-mail) que o trader cadastrou para cada tipo de evento';
+COMMENT ON COLUMN assinaturas_notificacao.segredo IS 'Segredo do HMAC-SHA256 que assina o corpo dos webhooks';
+COMMENT ON TABLE notificacoes IS 'Fila de notificações: pendentes são entregues pelo worker com novas tentativas espaçadas';
+COMMENT ON TABLE entregas_notificacao IS 'Registro de cada tentativa de entrega, com status HTTP e erro';
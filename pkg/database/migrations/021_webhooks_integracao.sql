@@ -0,0 +1,12 @@
+-- Migration: 021_webhooks_integracao.sql
+-- Descrição: Reenvio manual das notificações (fila de saída dos webhooks de integração).
+-- As tentativas de um reenvio são contadas a partir de tentativas_base, preservando a
+-- numeração do registro de entregas.
+
+ALTER TABLE notificacoes ADD COLUMN IF NOT EXISTS tentativas_base INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE notificacoes ADD COLUMN IF NOT EXISTS reenviada_em TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_notificacoes_assinatura ON notificacoes(assinatura_id, created_at);
+
+COMMENT ON COLUMN notificacoes.tentativas_base IS 'Tentativas feitas antes do último reenvio manual';
+COMMENT ON COLUMN notificacoes.reenviada_em IS 'Data do último reenvio manual';
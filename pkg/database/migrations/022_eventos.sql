@@ -0,0 +1,36 @@
+-- Migration: 022_eventos.sql
+-- Descrição: Fila de eventos de domínio (outbox) gravada na mesma transação das
+-- alterações, lida pelo stream SSE do painel do trader. Cada inserção avisa as
+-- instâncias da API por NOTIFY, entregue apenas quando a transação é confirmada.
+
+CREATE TABLE IF NOT EXISTS eventos (
+    id BIGSERIAL PRIMARY KEY,
+    trader_id UUID NOT NULL REFERENCES traders(id) ON DELETE CASCADE,
+    tipo VARCHAR(100) NOT NULL,
+    entidade VARCHAR(50) NOT NULL,
+    entidade_id VARCHAR(100),
+    dados JSONB NOT NULL DEFAULT '{}',
+    -- Transação que gravou o evento: a leitura só avança sobre transações encerradas,
+    -- para um id menor confirmado depois não ser pulado
+    transacao XID8 NOT NULL DEFAULT pg_current_xact_id(),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_eventos_trader ON eventos(trader_id, id);
+CREATE INDEX IF NOT EXISTS idx_eventos_created_at ON eventos(created_at);
+
+CREATE OR REPLACE FUNCTION notificar_evento()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM pg_notify('eventos', NEW.trader_id::text);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS notificar_evento_inserido ON eventos;
+CREATE TRIGGER notificar_evento_inserido
+    AFTER INSERT ON eventos
+    FOR EACH ROW EXECUTE FUNCTION notificar_evento();
+
+COMMENT ON TABLE eventos IS 'Eventos de domínio (outbox) gravados junto com as alterações; lidos pelo stream SSE';
+COMMENT ON COLUMN eventos.transacao IS 'Transação de origem; eventos de transações ainda abertas não são lidos';
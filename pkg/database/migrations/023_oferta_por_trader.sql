@@ -0,0 +1,13 @@
+-- Migration: 023_oferta_por_trader.sql
+-- Descrição: O link do Mobgran passa a ser único por trader. Com a unicidade global, a
+-- reimportação de um link por outro trader atualizava (e apagava os cavaletes e produtos
+-- aprovados) da oferta de quem o importou primeiro; agora cada trader tem a sua cópia.
+
+ALTER TABLE ofertas DROP CONSTRAINT IF EXISTS ofertas_uuid_link_key;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'unique_oferta_trader_uuid_link') THEN
+        ALTER TABLE ofertas ADD CONSTRAINT unique_oferta_trader_uuid_link UNIQUE (trader_id, uuid_link);
+    END IF;
+END $$;
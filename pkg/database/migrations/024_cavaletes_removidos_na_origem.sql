@@ -0,0 +1,11 @@
+-- Migration: 024_cavaletes_removidos_na_origem.sql
+-- Descrição: A reimportação passa a atualizar os cavaletes pelo código em vez de apagá-los
+-- e regravá-los; os que não vêm mais do Mobgran são arquivados e marcados com a data em
+-- que saíram da origem, preservando os produtos aprovados, orçamentos e histórico de preços.
+
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS removido_na_origem_em TIMESTAMP WITH TIME ZONE;
+
+-- Cavaletes da oferta identificados pelo código entre importações
+CREATE INDEX IF NOT EXISTS idx_cavaletes_oferta_codigo ON cavaletes(oferta_id, codigo);
+
+COMMENT ON COLUMN cavaletes.removido_na_origem_em IS 'Quando o cavalete deixou de vir na reimportação da oferta (NULL enquanto estiver na origem)';
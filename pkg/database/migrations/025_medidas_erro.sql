@@ -0,0 +1,8 @@
+-- Migration: 025_medidas_erro.sql
+-- Descrição: Cavaletes cuja normalização de medidas falha (por exemplo, valor fora da
+-- precisão das colunas normalizadas) são marcados com o erro para que a normalização em
+-- segundo plano siga para os próximos em vez de parar sempre no mesmo cavalete.
+
+ALTER TABLE cavaletes ADD COLUMN IF NOT EXISTS medidas_erro TEXT;
+
+COMMENT ON COLUMN cavaletes.medidas_erro IS 'Erro da última tentativa de normalizar as medidas (NULL quando normalizadas com sucesso); zere medidas_normalizadas_em para tentar de novo';
@@ -3,14 +3,20 @@ package database
 import (
 	"context"
 	"database/sql"
+	"database/sql/driver"
 	"embed"
 	"fmt"
-	"log"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/XSAM/otelsql"
 	_ "github.com/lib/pq"
+	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"mobgran-importer-go/internal/models"
 )
 
 //go:embed migrations/*.sql
@@ -23,7 +29,7 @@ type PostgresClient struct {
 
 // NewPostgresClient cria uma nova conexão com PostgreSQL
 func NewPostgresClient(connString string) (*PostgresClient, error) {
-	db, err := sql.Open("postgres", connString)
+	db, err := abrir(connString)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao abrir conexão: %w", err)
 	}
@@ -41,11 +47,28 @@ func NewPostgresClient(connString string) (*PostgresClient, error) {
 		return nil, fmt.Errorf("erro ao conectar ao PostgreSQL: %w", err)
 	}
 
-	log.Println("✅ Conectado ao PostgreSQL com sucesso!")
+	logrus.Info("Conectado ao PostgreSQL com sucesso")
 
 	return &PostgresClient{DB: db}, nil
 }
 
+// abrir abre o pool de conexões com o driver instrumentado pelo OpenTelemetry: cada
+// comando executado com o contexto de um trace vira um span com a query. Comandos sem
+// trace (workers, migrations) não geram spans.
+func abrir(dsn string) (*sql.DB, error) {
+	return otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemNamePostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitConnectorConnect: true,
+			OmitRows:             true,
+			SpanFilter: func(ctx context.Context, _ otelsql.Method, _ string, _ []driver.NamedValue) bool {
+				return trace.SpanContextFromContext(ctx).IsValid()
+			},
+		}),
+	)
+}
+
 // Close fecha a conexão com o banco
 func (c *PostgresClient) Close() error {
 	return c.DB.Close()
@@ -53,46 +76,83 @@ func (c *PostgresClient) Close() error {
 
 // RunMigrations executa todas as migrations pendentes
 func (c *PostgresClient) RunMigrations() error {
-	log.Println("🚀 INICIANDO EXECUÇÃO DE MIGRATIONS - MÉTODO CHAMADO!")
-	log.Println("🔄 Iniciando execução de migrations...")
+	logrus.Info("Iniciando execução de migrations")
 
 	// Cria tabela de controle de migrations
 	if err := c.createMigrationsTable(); err != nil {
 		return fmt.Errorf("erro ao criar tabela de migrations: %w", err)
 	}
-	log.Println("✅ Tabela de migrations criada/verificada")
 
-	// Lista arquivos de migration
-	log.Println("🔍 Tentando ler diretório de migrations...")
-	entries, err := migrationsFS.ReadDir("migrations")
+	filenames, err := migrationsEmbutidas()
 	if err != nil {
-		log.Printf("❌ Erro ao ler diretório de migrations: %v", err)
-		return fmt.Errorf("erro ao ler diretório de migrations: %w", err)
-	}
-	log.Printf("📁 Encontrados %d arquivos de migration", len(entries))
-
-	// Ordena por nome para garantir ordem de execução
-	var filenames []string
-	for _, entry := range entries {
-		if strings.HasSuffix(entry.Name(), ".sql") {
-			filenames = append(filenames, entry.Name())
-			log.Printf("📄 Migration encontrada: %s", entry.Name())
-		}
+		return err
 	}
-	sort.Strings(filenames)
 
 	// Executa cada migration
 	for _, filename := range filenames {
-		log.Printf("🔄 Processando migration: %s", filename)
 		if err := c.runMigration(filename); err != nil {
 			return fmt.Errorf("erro ao executar migration %s: %w", filename, err)
 		}
 	}
 
-	log.Println("✅ Migrations executadas com sucesso!")
+	logrus.WithField("migrations", len(filenames)).Info("Migrations executadas com sucesso")
 	return nil
 }
 
+// StatusMigrations compara as migrations embutidas no binário com as registradas em
+// schema_migrations
+func (c *PostgresClient) StatusMigrations(ctx context.Context) (*models.StatusMigrations, error) {
+	filenames, err := migrationsEmbutidas()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.DB.QueryContext(ctx, "SELECT filename FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao consultar migrations aplicadas: %w", err)
+	}
+	defer rows.Close()
+
+	aplicadas := map[string]bool{}
+	for rows.Next() {
+		var filename string
+		if err := rows.Scan(&filename); err != nil {
+			return nil, fmt.Errorf("erro ao ler migration aplicada: %w", err)
+		}
+		aplicadas[filename] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao ler migrations aplicadas: %w", err)
+	}
+
+	status := &models.StatusMigrations{Total: len(filenames)}
+	for _, filename := range filenames {
+		if aplicadas[filename] {
+			status.Aplicadas++
+		} else {
+			status.Pendentes = append(status.Pendentes, filename)
+		}
+	}
+	return status, nil
+}
+
+// migrationsEmbutidas lista os arquivos de migration em ordem de execução (por nome)
+func migrationsEmbutidas() ([]string, error) {
+	entries, err := migrationsFS.ReadDir("migrations")
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler diretório de migrations: %w", err)
+	}
+
+	var filenames []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".sql") {
+			filenames = append(filenames, entry.Name())
+		}
+	}
+	sort.Strings(filenames)
+	return filenames, nil
+}
+
 // createMigrationsTable cria a tabela de controle de migrations
 func (c *PostgresClient) createMigrationsTable() error {
 	query := `
@@ -108,7 +168,7 @@ func (c *PostgresClient) createMigrationsTable() error {
 
 // runMigration executa uma migration específica se ainda não foi executada
 func (c *PostgresClient) runMigration(filename string) error {
-	log.Printf("🔍 Verificando se migration %s já foi executada...", filename)
+	logger := logrus.WithField("migration", filename)
 
 	// Verifica se já foi executada
 	var exists bool
@@ -117,56 +177,53 @@ func (c *PostgresClient) runMigration(filename string) error {
 		filename,
 	).Scan(&exists)
 	if err != nil {
-		log.Printf("❌ Erro ao verificar migration %s: %v", filename, err)
+		logger.WithError(err).Error("Erro ao verificar migration")
 		return err
 	}
 
 	if exists {
-		log.Printf("⏭️  Migration %s já executada, pulando...", filename)
+		logger.Debug("Migration já executada")
 		return nil
 	}
 
-	log.Printf("🚀 Executando migration %s...", filename)
+	logger.Info("Executando migration")
 
 	// Lê o arquivo SQL
 	content, err := migrationsFS.ReadFile("migrations/" + filename)
 	if err != nil {
-		log.Printf("❌ Erro ao ler arquivo %s: %v", filename, err)
+		logger.WithError(err).Error("Erro ao ler arquivo da migration")
 		return err
 	}
-	log.Printf("📖 Conteúdo da migration %s lido com sucesso (%d bytes)", filename, len(content))
 
 	// Executa em uma transação
 	tx, err := c.DB.Begin()
 	if err != nil {
-		log.Printf("❌ Erro ao iniciar transação para %s: %v", filename, err)
+		logger.WithError(err).Error("Erro ao iniciar transação da migration")
 		return err
 	}
 	defer tx.Rollback()
 
 	// Executa o SQL
-	log.Printf("▶️  Executando migration: %s", filename)
 	if _, err := tx.Exec(string(content)); err != nil {
-		log.Printf("❌ Erro ao executar SQL da migration %s: %v", filename, err)
+		logger.WithError(err).Error("Erro ao executar SQL da migration")
 		return fmt.Errorf("erro ao executar SQL: %w", err)
 	}
 
 	// Registra na tabela de controle
-	log.Printf("✅ Migration %s registrada como executada", filename)
 	if _, err := tx.Exec(
 		"INSERT INTO schema_migrations (filename) VALUES ($1)",
 		filename,
 	); err != nil {
-		log.Printf("❌ Erro ao registrar migration %s: %v", filename, err)
+		logger.WithError(err).Error("Erro ao registrar migration")
 		return err
 	}
 
 	if err := tx.Commit(); err != nil {
-		log.Printf("❌ Erro ao confirmar transação da migration %s: %v", filename, err)
+		logger.WithError(err).Error("Erro ao confirmar transação da migration")
 		return err
 	}
 
-	log.Printf("✅ Migration %s executada com sucesso!", filename)
+	logger.Info("Migration executada com sucesso")
 	return nil
 }
 
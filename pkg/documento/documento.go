@@ -0,0 +1,101 @@
+// Package documento valida e normaliza documentos de pessoas físicas (CPF) e
+// jurídicas (CNPJ) pelos dígitos verificadores.
+package documento
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tipos de documento
+const (
+	TipoCPF  = "cpf"
+	TipoCNPJ = "cnpj"
+)
+
+// Normalizar remove a pontuação do documento e valida os dígitos verificadores,
+// retornando o tipo (cpf ou cnpj) e apenas os dígitos
+func Normalizar(documento string) (string, string, error) {
+	digitos := ApenasDigitos(documento)
+
+	switch len(digitos) {
+	case 11:
+		if !CPFValido(digitos) {
+			return "", "", fmt.Errorf("CPF inválido")
+		}
+		return TipoCPF, digitos, nil
+	case 14:
+		if !CNPJValido(digitos) {
+			return "", "", fmt.Errorf("CNPJ inválido")
+		}
+		return TipoCNPJ, digitos, nil
+	}
+
+	return "", "", fmt.Errorf("documento deve ser um CPF (11 dígitos) ou CNPJ (14 dígitos)")
+}
+
+// CPFValido verifica os dígitos verificadores de um CPF com 11 dígitos
+func CPFValido(cpf string) bool {
+	if len(cpf) != 11 || !somenteDigitos(cpf) || repetido(cpf) {
+		return false
+	}
+
+	return digitoVerificador(cpf[:9], []int{10, 9, 8, 7, 6, 5, 4, 3, 2}) == cpf[9] &&
+		digitoVerificador(cpf[:10], []int{11, 10, 9, 8, 7, 6, 5, 4, 3, 2}) == cpf[10]
+}
+
+// CNPJValido verifica os dígitos verificadores de um CNPJ com 14 dígitos
+func CNPJValido(cnpj string) bool {
+	if len(cnpj) != 14 || !somenteDigitos(cnpj) || repetido(cnpj) {
+		return false
+	}
+
+	return digitoVerificador(cnpj[:12], []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == cnpj[12] &&
+		digitoVerificador(cnpj[:13], []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}) == cnpj[13]
+}
+
+// Formatar aplica a máscara usual ao documento já normalizado
+func Formatar(digitos string) string {
+	switch len(digitos) {
+	case 11:
+		return fmt.Sprintf("%s.%s.%s-%s", digitos[:3], digitos[3:6], digitos[6:9], digitos[9:])
+	case 14:
+		return fmt.Sprintf("%s.%s.%s/%s-%s", digitos[:2], digitos[2:5], digitos[5:8], digitos[8:12], digitos[12:])
+	}
+	return digitos
+}
+
+// digitoVerificador calcula o dígito do módulo 11 com os pesos informados
+func digitoVerificador(base string, pesos []int) byte {
+	soma := 0
+	for i, peso := range pesos {
+		soma += int(base[i]-'0') * peso
+	}
+
+	resto := soma % 11
+	if resto < 2 {
+		return '0'
+	}
+	return byte('0' + 11 - resto)
+}
+
+// ApenasDigitos descarta tudo o que não for dígito (pontuação de documentos, CEPs, telefones)
+func ApenasDigitos(texto string) string {
+	var sb strings.Builder
+	for _, r := range texto {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func somenteDigitos(texto string) bool {
+	return ApenasDigitos(texto) == texto
+}
+
+// repetido identifica sequências como 111.111.111-11, que passam no cálculo mas não
+// são documentos válidos
+func repetido(digitos string) bool {
+	return strings.Count(digitos, digitos[:1]) == len(digitos)
+}
@@ -0,0 +1,142 @@
+package documento
+
+import "testing"
+
+func TestCPFValido(t *testing.T) {
+	casos := []struct {
+		nome   string
+		cpf    string
+		valido bool
+	}{
+		{"válido", "52998224725", true},
+		{"outro válido", "11144477735", true},
+		{"primeiro dígito errado", "52998224715", false},
+		{"segundo dígito errado", "52998224726", false},
+		{"dígitos trocados", "52998224752", false},
+		{"zeros repetidos", "00000000000", false},
+		{"uns repetidos", "11111111111", false},
+		{"noves repetidos", "99999999999", false},
+		{"com pontuação", "529.982.247-25", false},
+		{"com letra", "5299822472a", false},
+		{"curto", "5299822472", false},
+		{"longo", "529982247250", false},
+		{"vazio", "", false},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if valido := CPFValido(c.cpf); valido != c.valido {
+				t.Errorf("CPFValido(%q) = %v, esperado %v", c.cpf, valido, c.valido)
+			}
+		})
+	}
+}
+
+func TestCNPJValido(t *testing.T) {
+	casos := []struct {
+		nome   string
+		cnpj   string
+		valido bool
+	}{
+		{"válido", "11222333000181", true},
+		{"válido de filial", "11444777000161", true},
+		{"primeiro dígito errado", "11222333000191", false},
+		{"segundo dígito errado", "11222333000182", false},
+		{"zeros repetidos", "00000000000000", false},
+		{"uns repetidos", "11111111111111", false},
+		{"com pontuação", "11.222.333/0001-81", false},
+		{"com letra", "1122233300018a", false},
+		{"curto", "1122233300018", false},
+		{"longo", "112223330001810", false},
+		{"vazio", "", false},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if valido := CNPJValido(c.cnpj); valido != c.valido {
+				t.Errorf("CNPJValido(%q) = %v, esperado %v", c.cnpj, valido, c.valido)
+			}
+		})
+	}
+}
+
+func TestNormalizar(t *testing.T) {
+	casos := []struct {
+		nome      string
+		documento string
+		tipo      string
+		digitos   string
+		erro      string
+	}{
+		{"CPF sem pontuação", "52998224725", TipoCPF, "52998224725", ""},
+		{"CPF com pontuação", "529.982.247-25", TipoCPF, "52998224725", ""},
+		{"CPF com espaços", " 529 982 247 25 ", TipoCPF, "52998224725", ""},
+		{"CNPJ sem pontuação", "11222333000181", TipoCNPJ, "11222333000181", ""},
+		{"CNPJ com pontuação", "11.222.333/0001-81", TipoCNPJ, "11222333000181", ""},
+		{"CPF com dígito errado", "529.982.247-26", "", "", "CPF inválido"},
+		{"CPF repetido", "111.111.111-11", "", "", "CPF inválido"},
+		{"CNPJ com dígito errado", "11.222.333/0001-82", "", "", "CNPJ inválido"},
+		{"CNPJ repetido", "00.000.000/0000-00", "", "", "CNPJ inválido"},
+		{"tamanho de CPF incompleto", "529.982.247-2", "", "", "documento deve ser um CPF (11 dígitos) ou CNPJ (14 dígitos)"},
+		{"tamanho entre CPF e CNPJ", "529982247250", "", "", "documento deve ser um CPF (11 dígitos) ou CNPJ (14 dígitos)"},
+		{"tamanho maior que CNPJ", "112223330001810", "", "", "documento deve ser um CPF (11 dígitos) ou CNPJ (14 dígitos)"},
+		{"sem dígitos", "abc", "", "", "documento deve ser um CPF (11 dígitos) ou CNPJ (14 dígitos)"},
+		{"vazio", "", "", "", "documento deve ser um CPF (11 dígitos) ou CNPJ (14 dígitos)"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			tipo, digitos, err := Normalizar(c.documento)
+			if c.erro != "" {
+				if err == nil || err.Error() != c.erro {
+					t.Fatalf("Normalizar(%q): erro = %v, esperado %q", c.documento, err, c.erro)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalizar(%q): erro inesperado %v", c.documento, err)
+			}
+			if tipo != c.tipo || digitos != c.digitos {
+				t.Errorf("Normalizar(%q) = (%q, %q), esperado (%q, %q)", c.documento, tipo, digitos, c.tipo, c.digitos)
+			}
+		})
+	}
+}
+
+func TestFormatar(t *testing.T) {
+	casos := []struct {
+		digitos  string
+		esperado string
+	}{
+		{"52998224725", "529.982.247-25"},
+		{"11222333000181", "11.222.333/0001-81"},
+		{"123", "123"},
+		{"", ""},
+	}
+
+	for _, c := range casos {
+		if formatado := Formatar(c.digitos); formatado != c.esperado {
+			t.Errorf("Formatar(%q) = %q, esperado %q", c.digitos, formatado, c.esperado)
+		}
+	}
+}
+
+func TestApenasDigitos(t *testing.T) {
+	casos := []struct {
+		texto    string
+		esperado string
+	}{
+		{"529.982.247-25", "52998224725"},
+		{"11.222.333/0001-81", "11222333000181"},
+		{"(27) 99999-0000", "27999990000"},
+		{"29.100-000", "29100000"},
+		{"sem números", ""},
+		{"１２３", ""}, // dígitos de largura total não são aceitos
+	}
+
+	for _, c := range casos {
+		if digitos := ApenasDigitos(c.texto); digitos != c.esperado {
+			t.Errorf("ApenasDigitos(%q) = %q, esperado %q", c.texto, digitos, c.esperado)
+		}
+	}
+}
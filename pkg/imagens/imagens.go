@@ -0,0 +1,121 @@
+package imagens
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+
+	// Decodificadores registrados para image.Decode
+	_ "image/gif"
+	_ "image/png"
+)
+
+// QualidadeJPEG é a qualidade usada ao codificar miniaturas
+const QualidadeJPEG = 85
+
+// LimitePixels é a maior área (largura × altura) decodificada. Um arquivo pequeno pode
+// declarar dimensões enormes e, decodificado, ocupar gigabytes de memória; 40 MP cobrem
+// as fotos das câmeras e celulares atuais.
+const LimitePixels = 40_000_000
+
+// ErrImagemGrande indica uma imagem com mais pixels que LimitePixels
+var ErrImagemGrande = errors.New("imagem excede o limite de dimensões")
+
+// Dimensoes lê a largura e a altura declaradas no cabeçalho da imagem, sem decodificá-la,
+// e recusa imagens acima de LimitePixels
+func Dimensoes(dados []byte) (int, int, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(dados))
+	if err != nil {
+		return 0, 0, fmt.Errorf("formato de imagem não suportado: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > LimitePixels {
+		return cfg.Width, cfg.Height, ErrImagemGrande
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+// Decodificar lê uma imagem JPEG, PNG ou GIF, retornando também o formato detectado.
+// As dimensões são conferidas antes da decodificação (ver Dimensoes).
+func Decodificar(dados []byte) (image.Image, string, error) {
+	if _, _, err := Dimensoes(dados); err != nil {
+		return nil, "", err
+	}
+
+	img, formato, err := image.Decode(bytes.NewReader(dados))
+	if err != nil {
+		return nil, "", fmt.Errorf("formato de imagem não suportado: %w", err)
+	}
+	return img, formato, nil
+}
+
+// Redimensionar reduz a imagem para caber em um quadrado de lado máximo, mantendo a
+// proporção. Imagens menores não são ampliadas. Usa média de área (box filter), que
+// produz miniaturas sem serrilhado na redução.
+func Redimensionar(src image.Image, maximo int) image.Image {
+	b := src.Bounds()
+	largura, altura := b.Dx(), b.Dy()
+
+	novaLargura, novaAltura := largura, altura
+	if largura > maximo || altura > maximo {
+		if largura >= altura {
+			novaLargura = maximo
+			novaAltura = max(1, altura*maximo/largura)
+		} else {
+			novaAltura = maximo
+			novaLargura = max(1, largura*maximo/altura)
+		}
+	}
+
+	// Achata a transparência sobre fundo branco, já que o JPEG não tem canal alfa
+	origem := image.NewRGBA(image.Rect(0, 0, largura, altura))
+	draw.Draw(origem, origem.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(origem, origem.Bounds(), src, b.Min, draw.Over)
+
+	if novaLargura == largura && novaAltura == altura {
+		return origem
+	}
+
+	destino := image.NewRGBA(image.Rect(0, 0, novaLargura, novaAltura))
+	for y := 0; y < novaAltura; y++ {
+		y0 := y * altura / novaAltura
+		y1 := max(y0+1, (y+1)*altura/novaAltura)
+
+		for x := 0; x < novaLargura; x++ {
+			x0 := x * largura / novaLargura
+			x1 := max(x0+1, (x+1)*largura/novaLargura)
+
+			var r, g, bl, n uint32
+			for sy := y0; sy < y1; sy++ {
+				offset := origem.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint32(origem.Pix[offset])
+					g += uint32(origem.Pix[offset+1])
+					bl += uint32(origem.Pix[offset+2])
+					offset += 4
+					n++
+				}
+			}
+
+			d := destino.PixOffset(x, y)
+			destino.Pix[d] = uint8(r / n)
+			destino.Pix[d+1] = uint8(g / n)
+			destino.Pix[d+2] = uint8(bl / n)
+			destino.Pix[d+3] = 0xff
+		}
+	}
+
+	return destino
+}
+
+// CodificarJPEG codifica a imagem em JPEG
+func CodificarJPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: QualidadeJPEG}); err != nil {
+		return nil, fmt.Errorf("erro ao codificar JPEG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,221 @@
+// Package medidas normaliza as medidas dos cavaletes importados, que chegam do Mobgran
+// sem unidade declarada: espessuras em texto ("2cm", "20 mm"), dimensões em metros,
+// centímetros ou milímetros, metragem e peso.
+package medidas
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Unidades das dimensões informadas
+const (
+	UnidadeMetro      = "m"
+	UnidadeCentimetro = "cm"
+	UnidadeMilimetro  = "mm"
+)
+
+// Origem da metragem normalizada
+const (
+	MetragemInformada = "informada"
+	MetragemCalculada = "calculada"
+)
+
+// DensidadePedra é a densidade média de granitos e mármores em kg/m³, usada para
+// estimar o peso e identificar a unidade do peso informado
+const DensidadePedra = 2700.0
+
+// Limites aceitos para a espessura de uma chapa, em milímetros
+const (
+	espessuraMinimaMM = 3.0
+	espessuraMaximaMM = 300.0
+)
+
+var padraoEspessura = regexp.MustCompile(`(?i)(\d+(?:[.,]\d+)?)\s*(mm|cm|m)?\b`)
+
+// Entrada são os valores brutos de um cavalete ou item
+type Entrada struct {
+	Espessura   string
+	Comprimento float64
+	Altura      float64
+	Metragem    float64
+	Peso        float64
+	// Pecas é a quantidade de chapas medidas por Comprimento x Altura (mínimo 1)
+	Pecas int
+}
+
+// Resultado são as medidas normalizadas; campos nil não puderam ser determinados
+type Resultado struct {
+	EspessuraMM      *float64
+	ComprimentoM     *float64
+	AlturaM          *float64
+	MetragemM2       *float64
+	PesoKg           *float64
+	UnidadeDimensoes *string
+	TipoMetragem     *string
+}
+
+// Normalizar converte os valores brutos para milímetros (espessura), metros
+// (dimensões), metros quadrados (metragem) e quilos (peso)
+func Normalizar(e Entrada) Resultado {
+	var r Resultado
+
+	if mm, ok := EspessuraMM(e.Espessura); ok {
+		r.EspessuraMM = &mm
+	}
+
+	area := 0.0
+	if unidade := UnidadeDimensoes(e.Comprimento, e.Altura); unidade != "" {
+		r.UnidadeDimensoes = &unidade
+		if e.Comprimento > 0 {
+			v := ParaMetros(e.Comprimento, unidade)
+			r.ComprimentoM = &v
+		}
+		if e.Altura > 0 {
+			v := ParaMetros(e.Altura, unidade)
+			r.AlturaM = &v
+		}
+		if r.ComprimentoM != nil && r.AlturaM != nil {
+			area = Area(*r.ComprimentoM, *r.AlturaM) * float64(max(e.Pecas, 1))
+		}
+	}
+
+	if m2, tipo := Metragem(e.Metragem, area); m2 > 0 {
+		r.MetragemM2 = &m2
+		r.TipoMetragem = &tipo
+	}
+
+	estimado := 0.0
+	if r.MetragemM2 != nil && r.EspessuraMM != nil {
+		estimado = PesoEstimadoKg(*r.MetragemM2, *r.EspessuraMM)
+	}
+	if kg := PesoKg(e.Peso, estimado); kg > 0 {
+		r.PesoKg = &kg
+	}
+
+	return r
+}
+
+// EspessuraMM interpreta a espessura em texto e a converte para milímetros. Sem
+// unidade, valores abaixo de 10 são tratados como centímetros ("2" = 20 mm).
+func EspessuraMM(texto string) (float64, bool) {
+	m := padraoEspessura.FindStringSubmatch(strings.TrimSpace(texto))
+	if m == nil {
+		return 0, false
+	}
+
+	valor, err := strconv.ParseFloat(strings.ReplaceAll(m[1], ",", "."), 64)
+	if err != nil || valor <= 0 {
+		return 0, false
+	}
+
+	switch strings.ToLower(m[2]) {
+	case UnidadeMilimetro:
+	case UnidadeCentimetro:
+		valor *= 10
+	case UnidadeMetro:
+		valor *= 1000
+	default:
+		if valor < 10 {
+			valor *= 10
+		}
+	}
+
+	valor = arredondar(valor, 2)
+	if valor < espessuraMinimaMM || valor > espessuraMaximaMM {
+		return 0, false
+	}
+	return valor, true
+}
+
+// UnidadeDimensoes deduz a unidade das dimensões de uma chapa pela maior delas: chapas
+// medem de poucos centímetros a cerca de 4 m, então valores abaixo de 20 estão em
+// metros e acima de 1000 em milímetros
+func UnidadeDimensoes(comprimento, altura float64) string {
+	maior := math.Max(comprimento, altura)
+	switch {
+	case maior <= 0:
+		return ""
+	case maior < 20:
+		return UnidadeMetro
+	case maior < 1000:
+		return UnidadeCentimetro
+	default:
+		return UnidadeMilimetro
+	}
+}
+
+// ParaMetros converte um valor da unidade informada para metros
+func ParaMetros(valor float64, unidade string) float64 {
+	switch unidade {
+	case UnidadeCentimetro:
+		valor /= 100
+	case UnidadeMilimetro:
+		valor /= 1000
+	}
+	return arredondar(valor, 3)
+}
+
+// Area calcula a área em m² a partir das dimensões em metros
+func Area(comprimentoM, alturaM float64) float64 {
+	return arredondar(comprimentoM*alturaM, 3)
+}
+
+// Metragem escolhe a metragem normalizada: a informada, convertida de cm² ou mm²
+// quando for desproporcional à área calculada, ou a calculada quando não houver
+// metragem informada
+func Metragem(informada, calculada float64) (float64, string) {
+	if informada <= 0 {
+		if calculada > 0 {
+			return arredondar(calculada, 3), MetragemCalculada
+		}
+		return 0, ""
+	}
+
+	if calculada > 0 {
+		informada /= fatorMaisProximo(informada, calculada, 1, 1e4, 1e6)
+	}
+	return arredondar(informada, 3), MetragemInformada
+}
+
+// PesoEstimadoKg estima o peso em kg pela área e espessura com a densidade média da pedra
+func PesoEstimadoKg(areaM2, espessuraMM float64) float64 {
+	return arredondar(areaM2*espessuraMM/1000*DensidadePedra, 2)
+}
+
+// PesoKg normaliza o peso informado para quilos. Com uma estimativa, escolhe entre
+// quilos e toneladas a unidade mais próxima dela; sem estimativa, valores abaixo de 50
+// são tratados como toneladas, já que uma única chapa pesa mais do que isso.
+func PesoKg(informado, estimadoKg float64) float64 {
+	if informado <= 0 {
+		return 0
+	}
+
+	if estimadoKg > 0 {
+		return arredondar(informado*fatorMaisProximo(estimadoKg, informado, 1, 1000), 2)
+	}
+	if informado < 50 {
+		informado *= 1000
+	}
+	return arredondar(informado, 2)
+}
+
+// fatorMaisProximo retorna o fator que, dividindo valor, mais o aproxima da referência
+// em escala logarítmica
+func fatorMaisProximo(valor, referencia float64, fatores ...float64) float64 {
+	melhor, menorDistancia := fatores[0], math.Inf(1)
+	for _, fator := range fatores {
+		distancia := math.Abs(math.Log(valor / fator / referencia))
+		if distancia < menorDistancia {
+			melhor, menorDistancia = fator, distancia
+		}
+	}
+	return melhor
+}
+
+func arredondar(valor float64, casas int) float64 {
+	p := math.Pow(10, float64(casas))
+	return math.Round(valor*p) / p
+}
@@ -0,0 +1,269 @@
+package medidas
+
+import (
+	"math"
+	"testing"
+)
+
+// quase compara valores já arredondados, tolerando o erro de representação do float64
+func quase(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEspessuraMM(t *testing.T) {
+	casos := []struct {
+		texto  string
+		mm     float64
+		valida bool
+	}{
+		{"2cm", 20, true},
+		{"2 CM", 20, true},
+		{"20 mm", 20, true},
+		{"20mm", 20, true},
+		{"1,5 cm", 15, true},
+		{"0.02m", 20, true},
+		{"Granito 3cm polido", 30, true},
+		// Sem unidade, abaixo de 10 é centímetro e a partir de 10 é milímetro
+		{"2", 20, true},
+		{"3", 30, true},
+		{"9.5", 95, true},
+		{"10", 10, true},
+		{"20", 20, true},
+		{"300 mm", 300, true},
+		{"2.5mm", 0, false},
+		{"0.2", 0, false},
+		{"301mm", 0, false},
+		{"2 m", 0, false},
+		{"0", 0, false},
+		{"abc", 0, false},
+		{"", 0, false},
+	}
+
+	for _, c := range casos {
+		mm, valida := EspessuraMM(c.texto)
+		if valida != c.valida || !quase(mm, c.mm) {
+			t.Errorf("EspessuraMM(%q) = (%v, %v), esperado (%v, %v)", c.texto, mm, valida, c.mm, c.valida)
+		}
+	}
+}
+
+func TestUnidadeDimensoes(t *testing.T) {
+	casos := []struct {
+		comprimento float64
+		altura      float64
+		esperado    string
+	}{
+		{0, 0, ""},
+		{-1, 0, ""},
+		{3.2, 1.9, UnidadeMetro},
+		{1.9, 3.2, UnidadeMetro},
+		{19.99, 1, UnidadeMetro},
+		{20, 1, UnidadeCentimetro},
+		{320, 190, UnidadeCentimetro},
+		{0, 190, UnidadeCentimetro},
+		{999, 500, UnidadeCentimetro},
+		{1000, 500, UnidadeMilimetro},
+		{3200, 1900, UnidadeMilimetro},
+	}
+
+	for _, c := range casos {
+		if unidade := UnidadeDimensoes(c.comprimento, c.altura); unidade != c.esperado {
+			t.Errorf("UnidadeDimensoes(%v, %v) = %q, esperado %q", c.comprimento, c.altura, unidade, c.esperado)
+		}
+	}
+}
+
+func TestParaMetros(t *testing.T) {
+	casos := []struct {
+		valor    float64
+		unidade  string
+		esperado float64
+	}{
+		{3.2, UnidadeMetro, 3.2},
+		{320, UnidadeCentimetro, 3.2},
+		{3200, UnidadeMilimetro, 3.2},
+		{1, UnidadeCentimetro, 0.01},
+		{1, UnidadeMilimetro, 0.001},
+		{0.4, UnidadeMilimetro, 0},
+		{3.14159, UnidadeMetro, 3.142},
+	}
+
+	for _, c := range casos {
+		if metros := ParaMetros(c.valor, c.unidade); !quase(metros, c.esperado) {
+			t.Errorf("ParaMetros(%v, %q) = %v, esperado %v", c.valor, c.unidade, metros, c.esperado)
+		}
+	}
+}
+
+func TestArea(t *testing.T) {
+	casos := []struct {
+		comprimento float64
+		altura      float64
+		esperado    float64
+	}{
+		{3.2, 1.9, 6.08},
+		{1.234, 0.567, 0.7},
+		{0, 1.9, 0},
+	}
+
+	for _, c := range casos {
+		if area := Area(c.comprimento, c.altura); !quase(area, c.esperado) {
+			t.Errorf("Area(%v, %v) = %v, esperado %v", c.comprimento, c.altura, area, c.esperado)
+		}
+	}
+}
+
+func TestMetragem(t *testing.T) {
+	casos := []struct {
+		nome      string
+		informada float64
+		calculada float64
+		m2        float64
+		tipo      string
+	}{
+		{"sem dados", 0, 0, 0, ""},
+		{"só calculada", 0, 6.08, 6.08, MetragemCalculada},
+		{"só informada", 6.08, 0, 6.08, MetragemInformada},
+		{"informada próxima da calculada", 6.1, 6.08, 6.1, MetragemInformada},
+		{"informada em cm²", 60800, 6.08, 6.08, MetragemInformada},
+		{"informada em mm²", 6080000, 6.08, 6.08, MetragemInformada},
+		{"informada maior, mas ainda em m²", 12.5, 6.08, 12.5, MetragemInformada},
+		// 5000 está mais perto de 6,08 como cm² (0,5) do que como m² ou mm²
+		{"ambígua fica com o fator mais próximo", 5000, 6.08, 0.5, MetragemInformada},
+		{"informada arredondada", 6.08049, 0, 6.08, MetragemInformada},
+		{"informada negativa usa a calculada", -1, 6.08, 6.08, MetragemCalculada},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			m2, tipo := Metragem(c.informada, c.calculada)
+			if !quase(m2, c.m2) || tipo != c.tipo {
+				t.Errorf("Metragem(%v, %v) = (%v, %q), esperado (%v, %q)", c.informada, c.calculada, m2, tipo, c.m2, c.tipo)
+			}
+		})
+	}
+}
+
+func TestPesoKg(t *testing.T) {
+	// 6,08 m² de chapa de 20 mm pesam cerca de 328 kg
+	estimado := PesoEstimadoKg(6.08, 20)
+	if !quase(estimado, 328.32) {
+		t.Fatalf("PesoEstimadoKg(6.08, 20) = %v, esperado 328.32", estimado)
+	}
+
+	casos := []struct {
+		nome       string
+		informado  float64
+		estimadoKg float64
+		esperado   float64
+	}{
+		{"sem peso", 0, estimado, 0},
+		{"negativo", -5, estimado, 0},
+		{"em quilos perto da estimativa", 330, estimado, 330},
+		{"em toneladas perto da estimativa", 0.33, estimado, 330},
+		{"sem estimativa, abaixo de 50 é tonelada", 30, 0, 30000},
+		{"sem estimativa, a partir de 50 é quilo", 50, 0, 50},
+		{"sem estimativa, quilos", 1200, 0, 1200},
+		{"toneladas arredondadas", 1.234567, 0, 1234.57},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			if kg := PesoKg(c.informado, c.estimadoKg); !quase(kg, c.esperado) {
+				t.Errorf("PesoKg(%v, %v) = %v, esperado %v", c.informado, c.estimadoKg, kg, c.esperado)
+			}
+		})
+	}
+}
+
+func TestNormalizar(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+	s := func(v string) *string { return &v }
+
+	casos := []struct {
+		nome     string
+		entrada  Entrada
+		esperado Resultado
+	}{
+		{
+			"vazio", Entrada{}, Resultado{},
+		},
+		{
+			// Sem peso informado, a estimativa só serve para escolher a unidade
+			"chapa em centímetros sem peso",
+			Entrada{Espessura: "2cm", Comprimento: 320, Altura: 190},
+			Resultado{
+				EspessuraMM: f(20), ComprimentoM: f(3.2), AlturaM: f(1.9), MetragemM2: f(6.08),
+				UnidadeDimensoes: s(UnidadeCentimetro), TipoMetragem: s(MetragemCalculada),
+			},
+		},
+		{
+			"várias peças multiplicam a área",
+			Entrada{Espessura: "20 mm", Comprimento: 3200, Altura: 1900, Pecas: 3, Peso: 0.985},
+			Resultado{
+				EspessuraMM: f(20), ComprimentoM: f(3.2), AlturaM: f(1.9), MetragemM2: f(18.24),
+				PesoKg: f(985), UnidadeDimensoes: s(UnidadeMilimetro), TipoMetragem: s(MetragemCalculada),
+			},
+		},
+		{
+			"metragem informada em cm² e peso em toneladas",
+			Entrada{Espessura: "2", Comprimento: 3.2, Altura: 1.9, Metragem: 60800, Peso: 0.33, Pecas: 1},
+			Resultado{
+				EspessuraMM: f(20), ComprimentoM: f(3.2), AlturaM: f(1.9), MetragemM2: f(6.08),
+				PesoKg: f(330), UnidadeDimensoes: s(UnidadeMetro), TipoMetragem: s(MetragemInformada),
+			},
+		},
+		{
+			"sem espessura não estima o peso",
+			Entrada{Comprimento: 3.2, Altura: 1.9, Metragem: 6.08, Peso: 2},
+			Resultado{
+				ComprimentoM: f(3.2), AlturaM: f(1.9), MetragemM2: f(6.08),
+				PesoKg: f(2000), UnidadeDimensoes: s(UnidadeMetro), TipoMetragem: s(MetragemInformada),
+			},
+		},
+		{
+			"só uma dimensão",
+			Entrada{Espessura: "3cm", Comprimento: 320},
+			Resultado{EspessuraMM: f(30), ComprimentoM: f(3.2), UnidadeDimensoes: s(UnidadeCentimetro)},
+		},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			r := Normalizar(c.entrada)
+			conferirFloat(t, "espessura_mm", r.EspessuraMM, c.esperado.EspessuraMM)
+			conferirFloat(t, "comprimento_m", r.ComprimentoM, c.esperado.ComprimentoM)
+			conferirFloat(t, "altura_m", r.AlturaM, c.esperado.AlturaM)
+			conferirFloat(t, "metragem_m2", r.MetragemM2, c.esperado.MetragemM2)
+			conferirFloat(t, "peso_kg", r.PesoKg, c.esperado.PesoKg)
+			conferirString(t, "unidade_dimensoes", r.UnidadeDimensoes, c.esperado.UnidadeDimensoes)
+			conferirString(t, "tipo_metragem", r.TipoMetragem, c.esperado.TipoMetragem)
+		})
+	}
+}
+
+func conferirFloat(t *testing.T, campo string, valor, esperado *float64) {
+	t.Helper()
+	switch {
+	case valor == nil && esperado == nil:
+	case valor == nil || esperado == nil:
+		t.Errorf("%s = %v, esperado %v", campo, ponteiro(valor), ponteiro(esperado))
+	case !quase(*valor, *esperado):
+		t.Errorf("%s = %v, esperado %v", campo, *valor, *esperado)
+	}
+}
+
+func conferirString(t *testing.T, campo string, valor, esperado *string) {
+	t.Helper()
+	if (valor == nil) != (esperado == nil) || (valor != nil && *valor != *esperado) {
+		t.Errorf("%s = %v, esperado %v", campo, ponteiro(valor), ponteiro(esperado))
+	}
+}
+
+// ponteiro formata um valor opcional para as mensagens de erro
+func ponteiro[T any](v *T) interface{} {
+	if v == nil {
+		return "nil"
+	}
+	return *v
+}
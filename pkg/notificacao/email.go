@@ -0,0 +1,101 @@
+package notificacao
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTP envia e-mails em texto simples. Com Usuario vazio, o envio é feito sem
+// autenticação (servidores locais de teste, como o Mailpit).
+type SMTP struct {
+	Host      string
+	Porta     string
+	Usuario   string
+	Senha     string
+	Remetente string
+	Timeout   time.Duration
+}
+
+// Configurado indica se há servidor e remetente para enviar e-mails
+func (s *SMTP) Configurado() bool {
+	return s != nil && s.Host != "" && s.Remetente != ""
+}
+
+// Enviar entrega a mensagem ao destinatário, usando STARTTLS quando o servidor oferece
+func (s *SMTP) Enviar(ctx context.Context, para, assunto, corpo string) error {
+	if !s.Configurado() {
+		return fmt.Errorf("SMTP não configurado")
+	}
+
+	timeout := s.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(s.Host, s.Porta))
+	if err != nil {
+		return fmt.Errorf("erro ao conectar ao SMTP: %w", err)
+	}
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	cliente, err := smtp.NewClient(conn, s.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("erro ao iniciar sessão SMTP: %w", err)
+	}
+	defer cliente.Close()
+
+	if ok, _ := cliente.Extension("STARTTLS"); ok {
+		if err := cliente.StartTLS(&tls.Config{ServerName: s.Host}); err != nil {
+			return fmt.Errorf("erro no STARTTLS: %w", err)
+		}
+	}
+	if s.Usuario != "" {
+		if err := cliente.Auth(smtp.PlainAuth("", s.Usuario, s.Senha, s.Host)); err != nil {
+			return fmt.Errorf("erro de autenticação SMTP: %w", err)
+		}
+	}
+
+	if err := cliente.Mail(s.Remetente); err != nil {
+		return fmt.Errorf("remetente recusado: %w", err)
+	}
+	if err := cliente.Rcpt(para); err != nil {
+		return fmt.Errorf("destinatário recusado: %w", err)
+	}
+
+	w, err := cliente.Data()
+	if err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+	if _, err := w.Write(montarMensagem(s.Remetente, para, assunto, corpo)); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("erro ao enviar mensagem: %w", err)
+	}
+
+	return cliente.Quit()
+}
+
+// montarMensagem monta a mensagem RFC 5322 em UTF-8, com o assunto codificado
+func montarMensagem(de, para, assunto, corpo string) []byte {
+	var sb strings.Builder
+	sb.WriteString("From: " + de + "\r\n")
+	sb.WriteString("To: " + para + "\r\n")
+	sb.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", assunto) + "\r\n")
+	sb.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	sb.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(strings.ReplaceAll(strings.ReplaceAll(corpo, "\r\n", "\n"), "\n", "\r\n"))
+	sb.WriteString("\r\n")
+	return []byte(sb.String())
+}
@@ -0,0 +1,190 @@
+package notificacao
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mensagemSMTP é o que o servidor SMTP falso recebeu em uma sessão
+type mensagemSMTP struct {
+	de    string
+	para  []string
+	dados string
+}
+
+// servidorSMTP sobe um servidor SMTP mínimo, sem STARTTLS nem autenticação, que recusa
+// os destinatários em recusados e guarda as mensagens recebidas
+func servidorSMTP(t *testing.T, recusados ...string) (string, string, func() []mensagemSMTP) {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("erro ao abrir porta: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	var mensagens []mensagemSMTP
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				atenderSMTP(conn, recusados, func(m mensagemSMTP) {
+					mu.Lock()
+					mensagens = append(mensagens, m)
+					mu.Unlock()
+				})
+			}()
+		}
+	}()
+
+	host, porta, _ := net.SplitHostPort(ln.Addr().String())
+	return host, porta, func() []mensagemSMTP {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]mensagemSMTP(nil), mensagens...)
+	}
+}
+
+// atenderSMTP conduz uma sessão, registrando a mensagem antes de confirmar o DATA para que
+// ela já esteja disponível quando o Enviar retornar
+func atenderSMTP(conn net.Conn, recusados []string, registrar func(mensagemSMTP)) {
+	leitor := bufio.NewReader(conn)
+	responder := func(linha string) { conn.Write([]byte(linha + "\r\n")) }
+
+	responder("220 teste ESMTP")
+	var m mensagemSMTP
+	for {
+		linha, err := leitor.ReadString('\n')
+		if err != nil {
+			break
+		}
+		comando := strings.TrimRight(linha, "\r\n")
+		maiusculo := strings.ToUpper(comando)
+
+		switch {
+		case strings.HasPrefix(maiusculo, "EHLO"), strings.HasPrefix(maiusculo, "HELO"):
+			responder("250 teste")
+		case strings.HasPrefix(maiusculo, "MAIL FROM:"):
+			m.de = strings.Trim(comando[len("MAIL FROM:"):], "<> ")
+			responder("250 OK")
+		case strings.HasPrefix(maiusculo, "RCPT TO:"):
+			para := strings.Trim(comando[len("RCPT TO:"):], "<> ")
+			recusado := false
+			for _, r := range recusados {
+				if r == para {
+					recusado = true
+				}
+			}
+			if recusado {
+				responder("550 destinatário desconhecido")
+				continue
+			}
+			m.para = append(m.para, para)
+			responder("250 OK")
+		case maiusculo == "DATA":
+			responder("354 envie a mensagem")
+			var dados strings.Builder
+			for {
+				l, err := leitor.ReadString('\n')
+				if err != nil || l == ".\r\n" {
+					break
+				}
+				dados.WriteString(l)
+			}
+			m.dados = dados.String()
+			registrar(m)
+			responder("250 OK")
+		case maiusculo == "QUIT":
+			responder("221 até logo")
+			return
+		default:
+			responder("250 OK")
+		}
+	}
+}
+
+func TestSMTPEnviar(t *testing.T) {
+	host, porta, mensagens := servidorSMTP(t)
+	s := &SMTP{Host: host, Porta: porta, Remetente: "notificacoes@mobgran.local", Timeout: 5 * time.Second}
+
+	err := s.Enviar(context.Background(), "trader@exemplo.com", "Reserva perto de vencer às 10h", "Linha 1\nLinha 2")
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+
+	recebidas := mensagens()
+	if len(recebidas) != 1 {
+		t.Fatalf("mensagens = %d, esperado 1", len(recebidas))
+	}
+	m := recebidas[0]
+	if m.de != "notificacoes@mobgran.local" {
+		t.Errorf("remetente = %q", m.de)
+	}
+	if len(m.para) != 1 || m.para[0] != "trader@exemplo.com" {
+		t.Errorf("destinatários = %v", m.para)
+	}
+
+	trechos := []string{
+		"From: notificacoes@mobgran.local\r\n",
+		"To: trader@exemplo.com\r\n",
+		"Subject: =?utf-8?q?Reserva_perto_de_vencer_=C3=A0s_10h?=\r\n",
+		"Content-Type: text/plain; charset=utf-8\r\n",
+		"\r\n\r\nLinha 1\r\nLinha 2\r\n",
+	}
+	for _, trecho := range trechos {
+		if !strings.Contains(m.dados, trecho) {
+			t.Errorf("mensagem sem %q:\n%s", trecho, m.dados)
+		}
+	}
+}
+
+func TestSMTPEnviarErros(t *testing.T) {
+	host, porta, mensagens := servidorSMTP(t, "recusado@exemplo.com")
+
+	casos := []struct {
+		nome   string
+		smtp   *SMTP
+		para   string
+		trecho string
+	}{
+		{"não configurado", &SMTP{}, "trader@exemplo.com", "SMTP não configurado"},
+		{"destinatário recusado", &SMTP{Host: host, Porta: porta, Remetente: "notificacoes@mobgran.local", Timeout: 5 * time.Second}, "recusado@exemplo.com", "destinatário recusado"},
+		{"servidor indisponível", &SMTP{Host: "127.0.0.1", Porta: portaFechada(t), Remetente: "notificacoes@mobgran.local", Timeout: time.Second}, "trader@exemplo.com", "erro ao conectar ao SMTP"},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			err := c.smtp.Enviar(context.Background(), c.para, "Assunto", "Corpo")
+			if err == nil || !strings.Contains(err.Error(), c.trecho) {
+				t.Fatalf("erro = %v, esperado contendo %q", err, c.trecho)
+			}
+		})
+	}
+
+	if n := len(mensagens()); n != 0 {
+		t.Errorf("mensagens = %d, esperado nenhuma", n)
+	}
+}
+
+// portaFechada retorna uma porta local sem servidor escutando
+func portaFechada(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("erro ao abrir porta: %v", err)
+	}
+	_, porta, _ := net.SplitHostPort(ln.Addr().String())
+	ln.Close()
+	return porta
+}
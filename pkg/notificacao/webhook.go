@@ -0,0 +1,182 @@
+// Package notificacao entrega as notificações aos traders: webhooks com o corpo assinado
+// por HMAC-SHA256 e e-mails por SMTP.
+package notificacao
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// Cabeçalhos enviados em cada webhook
+const (
+	CabecalhoEvento     = "X-Mobgran-Evento"
+	CabecalhoEntrega    = "X-Mobgran-Entrega"
+	CabecalhoTimestamp  = "X-Mobgran-Timestamp"
+	CabecalhoAssinatura = "X-Mobgran-Assinatura"
+	// CabecalhoIdempotencia repete o id da entrega, igual em todas as tentativas e
+	// reenvios, para o receptor descartar duplicadas
+	CabecalhoIdempotencia = "Idempotency-Key"
+)
+
+// Assinar calcula a assinatura do webhook: HMAC-SHA256, com o segredo da assinatura,
+// de "<timestamp>.<corpo>", no formato "sha256=<hex>". O receptor recalcula com o
+// timestamp do cabeçalho e rejeita mensagens antigas para evitar reenvios forjados.
+func Assinar(segredo string, timestamp int64, corpo []byte) string {
+	mac := hmac.New(sha256.New, []byte(segredo))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(corpo)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ErrDestinoBloqueado indica um webhook apontando para a rede interna do servidor
+var ErrDestinoBloqueado = errors.New("destino do webhook em rede interna não é permitido")
+
+// redesBloqueadas completa as verificações de net.IP com faixas que não são privadas
+// pela RFC 1918 mas também não são endereços públicos
+var redesBloqueadas = []*net.IPNet{
+	mustParseCIDR("0.0.0.0/8"),     // "esta rede"
+	mustParseCIDR("100.64.0.0/10"), // CGNAT, usado por provedores de nuvem internamente
+	mustParseCIDR("192.0.0.0/24"),  // atribuições de protocolo da IANA
+	mustParseCIDR("198.18.0.0/15"), // testes de desempenho de rede
+}
+
+func mustParseCIDR(cidr string) *net.IPNet {
+	_, rede, err := net.ParseCIDR(cidr)
+	if err != nil {
+		panic(err)
+	}
+	return rede
+}
+
+// EnderecoBloqueado informa se o IP não pode receber webhooks: loopback, redes privadas,
+// link-local (inclusive o serviço de metadados das nuvens em 169.254.169.254), multicast
+// e endereços não especificados
+func EnderecoBloqueado(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return true
+	}
+	for _, rede := range redesBloqueadas {
+		if rede.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ControlarDestino é o Control do net.Dialer que recusa endereços bloqueados. É chamado
+// com o endereço já resolvido, então também vale para nomes que passam a apontar para a
+// rede interna depois do cadastro (DNS rebinding) e para cada salto de redirecionamento.
+func ControlarDestino(_, endereco string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(endereco)
+	if err != nil {
+		return ErrDestinoBloqueado
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || EnderecoBloqueado(ip) {
+		return ErrDestinoBloqueado
+	}
+	return nil
+}
+
+// Webhook envia notificações por HTTP POST
+type Webhook struct {
+	client              *http.Client
+	permitirRedeInterna bool
+}
+
+// NovoWebhook cria o cliente de webhooks com o tempo limite por envio. Sem
+// permitirRedeInterna (só para desenvolvimento), conexões a endereços bloqueados são
+// recusadas; redirecionamentos nunca são seguidos e a resposta 3xx conta como falha.
+func NovoWebhook(timeout time.Duration, permitirRedeInterna bool) *Webhook {
+	dialer := &net.Dialer{Timeout: timeout, KeepAlive: 30 * time.Second}
+	if !permitirRedeInterna {
+		dialer.Control = ControlarDestino
+	}
+
+	transporte := http.DefaultTransport.(*http.Transport).Clone()
+	// Sem proxy: o dialer precisa ver o endereço do destino, não o do proxy
+	transporte.Proxy = nil
+	transporte.DialContext = dialer.DialContext
+
+	return &Webhook{
+		client: &http.Client{
+			Timeout:   timeout,
+			Transport: transporte,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		permitirRedeInterna: permitirRedeInterna,
+	}
+}
+
+// ValidarDestino confere a URL do webhook (http ou https) e, sem permitirRedeInterna,
+// resolve o host e recusa nomes com algum endereço bloqueado. Retorna a URL normalizada.
+func (w *Webhook) ValidarDestino(ctx context.Context, destino string) (string, error) {
+	u, err := url.Parse(destino)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return "", fmt.Errorf("URL do webhook inválida")
+	}
+	if w.permitirRedeInterna {
+		return u.String(), nil
+	}
+
+	ips, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(ips) == 0 {
+		return "", fmt.Errorf("não foi possível resolver o host do webhook")
+	}
+	for _, ip := range ips {
+		if EnderecoBloqueado(ip.IP) {
+			return "", ErrDestinoBloqueado
+		}
+	}
+	return u.String(), nil
+}
+
+// Enviar faz o POST do corpo JSON assinado e retorna o status HTTP da resposta. Respostas
+// fora da faixa 2xx são erro; o status é retornado também nesse caso.
+func (w *Webhook) Enviar(ctx context.Context, url, segredo, evento, entregaID string, corpo []byte) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(corpo))
+	if err != nil {
+		return 0, fmt.Errorf("URL do webhook inválida: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "mobgran-importer-go")
+	req.Header.Set(CabecalhoEvento, evento)
+	req.Header.Set(CabecalhoEntrega, entregaID)
+	req.Header.Set(CabecalhoIdempotencia, entregaID)
+	req.Header.Set(CabecalhoTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(CabecalhoAssinatura, Assinar(segredo, timestamp, corpo))
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrDestinoBloqueado) {
+			return 0, ErrDestinoBloqueado
+		}
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("webhook respondeu %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
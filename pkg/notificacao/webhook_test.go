@@ -0,0 +1,256 @@
+package notificacao
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recebida guarda o que o receptor de teste recebeu em cada requisição
+type recebida struct {
+	cabecalhos http.Header
+	corpo      []byte
+}
+
+// receptor sobe um servidor que responde com os status informados, em ordem (o último
+// se repete), e guarda as requisições recebidas
+func receptor(t *testing.T, status ...int) (*httptest.Server, func() []recebida) {
+	t.Helper()
+
+	var mu sync.Mutex
+	var recebidas []recebida
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		corpo, _ := io.ReadAll(r.Body)
+
+		mu.Lock()
+		recebidas = append(recebidas, recebida{cabecalhos: r.Header.Clone(), corpo: corpo})
+		resposta := status[min(len(recebidas), len(status))-1]
+		mu.Unlock()
+
+		w.WriteHeader(resposta)
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, func() []recebida {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]recebida(nil), recebidas...)
+	}
+}
+
+func TestAssinar(t *testing.T) {
+	corpo := []byte(`{"evento":"teste"}`)
+
+	assinatura := Assinar("segredo", 1700000000, corpo)
+	if assinatura != Assinar("segredo", 1700000000, corpo) {
+		t.Fatal("assinatura deveria ser determinística")
+	}
+	if len(assinatura) != len("sha256=")+64 || assinatura[:7] != "sha256=" {
+		t.Fatalf("formato inesperado: %q", assinatura)
+	}
+
+	variacoes := []struct {
+		nome       string
+		assinatura string
+	}{
+		{"outro segredo", Assinar("outro", 1700000000, corpo)},
+		{"outro timestamp", Assinar("segredo", 1700000001, corpo)},
+		{"outro corpo", Assinar("segredo", 1700000000, []byte(`{"evento":"outro"}`))},
+	}
+	for _, v := range variacoes {
+		if v.assinatura == assinatura {
+			t.Errorf("%s: assinatura deveria mudar", v.nome)
+		}
+	}
+}
+
+func TestWebhookEnviar(t *testing.T) {
+	srv, recebidas := receptor(t, http.StatusNoContent)
+	corpo := []byte(`{"evento":"produto.aprovado"}`)
+
+	status, err := NovoWebhook(5*time.Second, true).Enviar(context.Background(), srv.URL, "segredo", "produto.aprovado", "entrega-1", corpo)
+	if err != nil {
+		t.Fatalf("erro inesperado: %v", err)
+	}
+	if status != http.StatusNoContent {
+		t.Fatalf("status = %d, esperado %d", status, http.StatusNoContent)
+	}
+
+	r := recebidas()
+	if len(r) != 1 {
+		t.Fatalf("requisições = %d, esperado 1", len(r))
+	}
+	if string(r[0].corpo) != string(corpo) {
+		t.Errorf("corpo = %s, esperado %s", r[0].corpo, corpo)
+	}
+
+	cabecalhos := map[string]string{
+		"Content-Type":        "application/json",
+		CabecalhoEvento:       "produto.aprovado",
+		CabecalhoEntrega:      "entrega-1",
+		CabecalhoIdempotencia: "entrega-1",
+	}
+	for nome, esperado := range cabecalhos {
+		if valor := r[0].cabecalhos.Get(nome); valor != esperado {
+			t.Errorf("%s = %q, esperado %q", nome, valor, esperado)
+		}
+	}
+
+	// O receptor confere a assinatura com o timestamp do cabeçalho
+	timestamp, err := strconv.ParseInt(r[0].cabecalhos.Get(CabecalhoTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("timestamp inválido: %v", err)
+	}
+	if assinatura := r[0].cabecalhos.Get(CabecalhoAssinatura); assinatura != Assinar("segredo", timestamp, corpo) {
+		t.Errorf("assinatura %q não confere", assinatura)
+	}
+}
+
+func TestWebhookEnviarRespostaForaDe2xx(t *testing.T) {
+	casos := []struct {
+		nome   string
+		status int
+	}{
+		{"erro do receptor", http.StatusInternalServerError},
+		{"não encontrado", http.StatusNotFound},
+		{"limite de requisições", http.StatusTooManyRequests},
+		{"redirecionamento não seguido", http.StatusFound},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			srv, recebidas := receptor(t, c.status)
+
+			status, err := NovoWebhook(5*time.Second, true).Enviar(context.Background(), srv.URL, "segredo", "teste", "entrega-1", []byte(`{}`))
+			if err == nil {
+				t.Fatal("esperado erro para resposta fora da faixa 2xx")
+			}
+			if status != c.status {
+				t.Errorf("status = %d, esperado %d", status, c.status)
+			}
+			if n := len(recebidas()); n != 1 {
+				t.Errorf("requisições = %d, esperado 1", n)
+			}
+		})
+	}
+}
+
+func TestWebhookEnviarNovasTentativas(t *testing.T) {
+	srv, recebidas := receptor(t, http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK)
+	webhook := NovoWebhook(5*time.Second, true)
+	corpo := []byte(`{"evento":"oferta.importada"}`)
+
+	// Cada tentativa é um novo Enviar com o mesmo id de entrega, como faz o worker
+	esperados := []struct {
+		status int
+		erro   bool
+	}{
+		{http.StatusServiceUnavailable, true},
+		{http.StatusBadGateway, true},
+		{http.StatusOK, false},
+	}
+	for i, e := range esperados {
+		status, err := webhook.Enviar(context.Background(), srv.URL, "segredo", "oferta.importada", "entrega-1", corpo)
+		if status != e.status || (err != nil) != e.erro {
+			t.Fatalf("tentativa %d: status = %d, erro = %v; esperado %d, erro %v", i+1, status, err, e.status, e.erro)
+		}
+	}
+
+	r := recebidas()
+	if len(r) != len(esperados) {
+		t.Fatalf("requisições = %d, esperado %d", len(r), len(esperados))
+	}
+	for i, req := range r {
+		if chave := req.cabecalhos.Get(CabecalhoIdempotencia); chave != "entrega-1" {
+			t.Errorf("tentativa %d: %s = %q, esperado o mesmo id em todas", i+1, CabecalhoIdempotencia, chave)
+		}
+		timestamp, _ := strconv.ParseInt(req.cabecalhos.Get(CabecalhoTimestamp), 10, 64)
+		if req.cabecalhos.Get(CabecalhoAssinatura) != Assinar("segredo", timestamp, corpo) {
+			t.Errorf("tentativa %d: assinatura não confere", i+1)
+		}
+	}
+}
+
+func TestWebhookEnviarRedeInternaBloqueada(t *testing.T) {
+	srv, recebidas := receptor(t, http.StatusOK)
+
+	_, err := NovoWebhook(5*time.Second, false).Enviar(context.Background(), srv.URL, "segredo", "teste", "entrega-1", []byte(`{}`))
+	if !errors.Is(err, ErrDestinoBloqueado) {
+		t.Fatalf("erro = %v, esperado %v", err, ErrDestinoBloqueado)
+	}
+	if n := len(recebidas()); n != 0 {
+		t.Errorf("requisições = %d, esperado nenhuma", n)
+	}
+}
+
+func TestEnderecoBloqueado(t *testing.T) {
+	casos := []struct {
+		ip        string
+		bloqueado bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.0.10", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00:ec2::254", true},
+		{"100.64.0.1", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"224.0.0.1", true},
+		{"::ffff:127.0.0.1", true},
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+		{"2606:4700:4700::1111", false},
+	}
+
+	for _, c := range casos {
+		if bloqueado := EnderecoBloqueado(net.ParseIP(c.ip)); bloqueado != c.bloqueado {
+			t.Errorf("EnderecoBloqueado(%s) = %v, esperado %v", c.ip, bloqueado, c.bloqueado)
+		}
+	}
+}
+
+func TestWebhookValidarDestino(t *testing.T) {
+	casos := []struct {
+		nome     string
+		destino  string
+		esperado string
+		erro     bool
+	}{
+		{"IP público", "https://93.184.216.34/hook", "https://93.184.216.34/hook", false},
+		{"loopback", "http://127.0.0.1:8080/hook", "", true},
+		{"metadados da nuvem", "http://169.254.169.254/latest/meta-data/", "", true},
+		{"rede privada", "https://10.0.0.5/hook", "", true},
+		{"IPv6 loopback", "http://[::1]/hook", "", true},
+		{"esquema inválido", "ftp://93.184.216.34/hook", "", true},
+		{"sem host", "https:///hook", "", true},
+	}
+
+	webhook := NovoWebhook(5*time.Second, false)
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			destino, err := webhook.ValidarDestino(context.Background(), c.destino)
+			if (err != nil) != c.erro {
+				t.Fatalf("erro = %v, esperado erro %v", err, c.erro)
+			}
+			if destino != c.esperado {
+				t.Errorf("destino = %q, esperado %q", destino, c.esperado)
+			}
+		})
+	}
+
+	// Em desenvolvimento a rede interna é liberada
+	if _, err := NovoWebhook(5*time.Second, true).ValidarDestino(context.Background(), "http://127.0.0.1:8080/hook"); err != nil {
+		t.Errorf("com a rede interna permitida: erro inesperado %v", err)
+	}
+}
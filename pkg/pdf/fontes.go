@@ -0,0 +1,119 @@
+package pdf
+
+// Fonte identifica uma das fontes padrão do PDF usadas pelo gerador. As fontes padrão
+// não precisam ser embutidas: todo leitor de PDF as possui.
+type Fonte int
+
+const (
+	Helvetica Fonte = iota
+	HelveticaNegrito
+)
+
+// nomesFontes são os BaseFont das fontes padrão
+var nomesFontes = map[Fonte]string{
+	Helvetica:        "Helvetica",
+	HelveticaNegrito: "Helvetica-Bold",
+}
+
+// larguras são as larguras dos caracteres ASCII 32–126 em milésimos do tamanho da fonte,
+// conforme as métricas AFM das fontes padrão
+var larguras = map[Fonte][95]int{
+	Helvetica: {
+		278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278, // espaço a /
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556, // 0 a 9
+		278, 278, 584, 584, 584, 556, 1015, // : a @
+		667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, // A a M
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, // N a Z
+		278, 278, 278, 469, 556, 333, // [ a `
+		556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, // a a m
+		556, 556, 556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, // n a z
+		334, 260, 334, 584, // { a ~
+	},
+	HelveticaNegrito: {
+		278, 333, 474, 556, 556, 889, 722, 238, 333, 333, 389, 584, 278, 333, 278, 278,
+		556, 556, 556, 556, 556, 556, 556, 556, 556, 556,
+		333, 333, 584, 584, 584, 611, 975,
+		722, 722, 722, 722, 667, 611, 778, 722, 278, 556, 722, 611, 833,
+		722, 778, 667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611,
+		333, 278, 333, 584, 556, 333,
+		556, 611, 556, 611, 556, 333, 611, 611, 278, 278, 556, 278, 889,
+		611, 611, 611, 611, 389, 556, 333, 611, 556, 778, 556, 556, 500,
+		389, 280, 389, 584,
+	},
+}
+
+// letraBase associa as letras acentuadas do Latin-1 à letra sem acento, que tem a
+// mesma largura nas fontes Helvetica
+var letraBase = map[rune]rune{
+	'À': 'A', 'Á': 'A', 'Â': 'A', 'Ã': 'A', 'Ä': 'A', 'Å': 'A',
+	'Ç': 'C', 'È': 'E', 'É': 'E', 'Ê': 'E', 'Ë': 'E',
+	'Ì': 'I', 'Í': 'I', 'Î': 'I', 'Ï': 'I', 'Ñ': 'N',
+	'Ò': 'O', 'Ó': 'O', 'Ô': 'O', 'Õ': 'O', 'Ö': 'O',
+	'Ù': 'U', 'Ú': 'U', 'Û': 'U', 'Ü': 'U', 'Ý': 'Y',
+	'à': 'a', 'á': 'a', 'â': 'a', 'ã': 'a', 'ä': 'a', 'å': 'a',
+	'ç': 'c', 'è': 'e', 'é': 'e', 'ê': 'e', 'ë': 'e',
+	'ì': 'i', 'í': 'i', 'î': 'i', 'ï': 'i', 'ñ': 'n',
+	'ò': 'o', 'ó': 'o', 'ô': 'o', 'õ': 'o', 'ö': 'o',
+	'ù': 'u', 'ú': 'u', 'û': 'u', 'ü': 'u', 'ý': 'y', 'ÿ': 'y',
+}
+
+// winAnsiEspeciais são os caracteres fora do Latin-1 presentes no WinAnsiEncoding
+var winAnsiEspeciais = map[rune]byte{
+	'€': 0x80, '‚': 0x82, '„': 0x84, '…': 0x85, '•': 0x95,
+	'‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '–': 0x96, '—': 0x97,
+}
+
+// larguraRune retorna a largura do caractere em milésimos do tamanho da fonte
+func larguraRune(fonte Fonte, r rune) int {
+	if base, ok := letraBase[r]; ok {
+		r = base
+	}
+	if r >= 32 && r <= 126 {
+		return larguras[fonte][r-32]
+	}
+
+	switch r {
+	case 'º', 'ª', '°':
+		return 365
+	case '²', '³':
+		return 333
+	case '·':
+		return 278
+	case '×':
+		return 584
+	case '–', '•':
+		return 556
+	case '—':
+		return 1000
+	case '…':
+		return 1000
+	case '‘', '’', '‚':
+		return 222
+	case '“', '”', '„':
+		return 333
+	case '€':
+		return 556
+	}
+	return 556
+}
+
+// codificarWinAnsi converte o texto para o WinAnsiEncoding usado pelas fontes padrão.
+// Caracteres sem representação viram "?".
+func codificarWinAnsi(texto string) []byte {
+	saida := make([]byte, 0, len(texto))
+	for _, r := range texto {
+		switch {
+		case r < 0x80:
+			saida = append(saida, byte(r))
+		case r >= 0xA0 && r <= 0xFF:
+			saida = append(saida, byte(r))
+		default:
+			if b, ok := winAnsiEspeciais[r]; ok {
+				saida = append(saida, b)
+			} else {
+				saida = append(saida, '?')
+			}
+		}
+	}
+	return saida
+}
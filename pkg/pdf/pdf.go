@@ -0,0 +1,347 @@
+// Package pdf implementa um gerador mínimo de documentos PDF em Go puro: texto com as
+// fontes padrão Helvetica, linhas, retângulos e imagens JPEG, suficiente para fichas
+// técnicas e orçamentos sem depender de serviços externos.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dimensões de uma página A4 em pontos
+const (
+	LarguraA4 = 595.28
+	AlturaA4  = 841.89
+)
+
+// Cor é uma cor RGB com componentes de 0 a 255
+type Cor struct {
+	R, G, B uint8
+}
+
+// Cores usadas com frequência
+var (
+	Preto  = Cor{0, 0, 0}
+	Branco = Cor{255, 255, 255}
+)
+
+// imagemPDF é uma imagem JPEG pronta para ser embutida como XObject
+type imagemPDF struct {
+	dados         []byte
+	largura       int
+	altura        int
+	espacoDeCores string
+}
+
+// Documento acumula páginas e recursos de um PDF. As coordenadas usam a origem no
+// canto superior esquerdo da página, com y crescendo para baixo.
+type Documento struct {
+	largura float64
+	altura  float64
+	paginas []*bytes.Buffer
+	atual   int
+	imagens []imagemPDF
+	titulo  string
+}
+
+// NovoDocumento cria um documento A4 vazio
+func NovoDocumento(titulo string) *Documento {
+	return &Documento{largura: LarguraA4, altura: AlturaA4, titulo: titulo}
+}
+
+// Largura retorna a largura da página em pontos
+func (d *Documento) Largura() float64 {
+	return d.largura
+}
+
+// Altura retorna a altura da página em pontos
+func (d *Documento) Altura() float64 {
+	return d.altura
+}
+
+// NovaPagina inicia uma nova página; os desenhos seguintes vão para ela
+func (d *Documento) NovaPagina() {
+	d.paginas = append(d.paginas, &bytes.Buffer{})
+	d.atual = len(d.paginas) - 1
+}
+
+// TotalPaginas retorna o número de páginas do documento
+func (d *Documento) TotalPaginas() int {
+	return len(d.paginas)
+}
+
+// SelecionarPagina volta a desenhar na página informada (a partir de 1), útil para
+// rodapés que dependem do total de páginas
+func (d *Documento) SelecionarPagina(numero int) {
+	if numero < 1 || numero > len(d.paginas) {
+		return
+	}
+	d.atual = numero - 1
+}
+
+// pagina retorna o conteúdo da página atual, criando a primeira se necessário
+func (d *Documento) pagina() *bytes.Buffer {
+	if len(d.paginas) == 0 {
+		d.NovaPagina()
+	}
+	return d.paginas[d.atual]
+}
+
+// Texto escreve uma linha de texto com a linha de base em y
+func (d *Documento) Texto(x, y float64, texto string, fonte Fonte, tamanho float64, cor Cor) {
+	fmt.Fprintf(d.pagina(), "BT %s rg /F%d %s Tf %s %s Td (%s) Tj ET\n",
+		corPDF(cor), fonte+1, num(tamanho), num(x), num(d.altura-y), escaparTexto(codificarWinAnsi(texto)))
+}
+
+// TextoDireita escreve o texto alinhado à direita de x
+func (d *Documento) TextoDireita(x, y float64, texto string, fonte Fonte, tamanho float64, cor Cor) {
+	d.Texto(x-LarguraTexto(texto, fonte, tamanho), y, texto, fonte, tamanho, cor)
+}
+
+// Linha traça um segmento de reta
+func (d *Documento) Linha(x1, y1, x2, y2, espessura float64, cor Cor) {
+	fmt.Fprintf(d.pagina(), "%s RG %s w %s %s m %s %s l S\n",
+		corPDF(cor), num(espessura), num(x1), num(d.altura-y1), num(x2), num(d.altura-y2))
+}
+
+// Retangulo preenche um retângulo cujo canto superior esquerdo é (x, y)
+func (d *Documento) Retangulo(x, y, largura, altura float64, cor Cor) {
+	fmt.Fprintf(d.pagina(), "%s rg %s %s %s %s re f\n",
+		corPDF(cor), num(x), num(d.altura-y-altura), num(largura), num(altura))
+}
+
+// Imagem desenha a imagem ajustada ao retângulo informado, preservando a proporção e
+// alinhando-a ao canto superior esquerdo. Retorna a largura e a altura ocupadas.
+func (d *Documento) Imagem(img image.Image, x, y, largura, altura float64) (float64, float64, error) {
+	limites := img.Bounds()
+	if limites.Dx() == 0 || limites.Dy() == 0 {
+		return 0, 0, fmt.Errorf("imagem vazia")
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return 0, 0, fmt.Errorf("erro ao codificar imagem: %w", err)
+	}
+
+	espaco := "DeviceRGB"
+	if _, ok := img.(*image.Gray); ok {
+		espaco = "DeviceGray"
+	}
+	d.imagens = append(d.imagens, imagemPDF{
+		dados:         buf.Bytes(),
+		largura:       limites.Dx(),
+		altura:        limites.Dy(),
+		espacoDeCores: espaco,
+	})
+
+	escala := largura / float64(limites.Dx())
+	if e := altura / float64(limites.Dy()); e < escala {
+		escala = e
+	}
+	w := float64(limites.Dx()) * escala
+	h := float64(limites.Dy()) * escala
+
+	fmt.Fprintf(d.pagina(), "q %s 0 0 %s %s %s cm /Im%d Do Q\n",
+		num(w), num(h), num(x), num(d.altura-y-h), len(d.imagens))
+	return w, h, nil
+}
+
+// LarguraTexto retorna a largura do texto em pontos
+func LarguraTexto(texto string, fonte Fonte, tamanho float64) float64 {
+	total := 0
+	for _, r := range texto {
+		total += larguraRune(fonte, r)
+	}
+	return float64(total) * tamanho / 1000
+}
+
+// QuebrarTexto divide o texto em linhas que cabem na largura informada, respeitando
+// as quebras de linha existentes. Palavras maiores que a largura são cortadas.
+func QuebrarTexto(texto string, fonte Fonte, tamanho, largura float64) []string {
+	linhas := []string{}
+	for _, paragrafo := range strings.Split(texto, "\n") {
+		atual := ""
+		for _, palavra := range strings.Fields(paragrafo) {
+			candidata := palavra
+			if atual != "" {
+				candidata = atual + " " + palavra
+			}
+			if LarguraTexto(candidata, fonte, tamanho) <= largura {
+				atual = candidata
+				continue
+			}
+			if atual != "" {
+				linhas = append(linhas, atual)
+			}
+			atual = palavra
+			// Um único caractere mais largo que a linha fica sozinho, sem gerar linha vazia
+			for utf8.RuneCountInString(atual) > 1 && LarguraTexto(atual, fonte, tamanho) > largura {
+				corte := cortarPalavra(atual, fonte, tamanho, largura)
+				linhas = append(linhas, atual[:corte])
+				atual = atual[corte:]
+			}
+		}
+		linhas = append(linhas, atual)
+	}
+	return linhas
+}
+
+// cortarPalavra retorna quantos bytes da palavra cabem na largura (ao menos um caractere)
+func cortarPalavra(palavra string, fonte Fonte, tamanho, largura float64) int {
+	soma := 0.0
+	for i, r := range palavra {
+		soma += float64(larguraRune(fonte, r)) * tamanho / 1000
+		if soma > largura && i > 0 {
+			return i
+		}
+	}
+	return len(palavra)
+}
+
+// Bytes gera o arquivo PDF completo
+func (d *Documento) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := d.Escrever(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Escrever gera o arquivo PDF no writer informado
+func (d *Documento) Escrever(w io.Writer) error {
+	if len(d.paginas) == 0 {
+		d.NovaPagina()
+	}
+
+	// Numeração dos objetos: 1 catálogo, 2 árvore de páginas, 3 informações,
+	// fontes, imagens e, por fim, um par página/conteúdo para cada página
+	const primeiraFonte = 4
+	primeiraImagem := primeiraFonte + len(nomesFontes)
+	primeiraPagina := primeiraImagem + len(d.imagens)
+	totalObjetos := primeiraPagina + 2*len(d.paginas) - 1
+
+	e := &escritor{offsets: make([]int, totalObjetos+1)}
+	e.escrever([]byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n"))
+
+	e.objeto(1, []byte("<< /Type /Catalog /Pages 2 0 R >>"))
+
+	kids := make([]string, len(d.paginas))
+	for i := range d.paginas {
+		kids[i] = fmt.Sprintf("%d 0 R", primeiraPagina+2*i)
+	}
+	e.objeto(2, []byte(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.paginas))))
+
+	e.objeto(3, []byte(fmt.Sprintf("<< /Title (%s) /Producer (mobgran-importer-go) >>", escaparTexto(codificarWinAnsi(d.titulo)))))
+
+	fontes := make([]string, len(nomesFontes))
+	for i := 0; i < len(nomesFontes); i++ {
+		e.objeto(primeiraFonte+i, []byte(fmt.Sprintf(
+			"<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", nomesFontes[Fonte(i)])))
+		fontes[i] = fmt.Sprintf("/F%d %d 0 R", i+1, primeiraFonte+i)
+	}
+
+	imagens := make([]string, len(d.imagens))
+	for i, img := range d.imagens {
+		cabecalho := fmt.Sprintf(
+			"<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /%s /BitsPerComponent 8 /Filter /DCTDecode /Length %d >>",
+			img.largura, img.altura, img.espacoDeCores, len(img.dados))
+		e.fluxo(primeiraImagem+i, cabecalho, img.dados)
+		imagens[i] = fmt.Sprintf("/Im%d %d 0 R", i+1, primeiraImagem+i)
+	}
+
+	recursos := fmt.Sprintf("<< /Font << %s >>", strings.Join(fontes, " "))
+	if len(imagens) > 0 {
+		recursos += fmt.Sprintf(" /XObject << %s >>", strings.Join(imagens, " "))
+	}
+	recursos += " >>"
+
+	for i, conteudo := range d.paginas {
+		numero := primeiraPagina + 2*i
+		e.objeto(numero, []byte(fmt.Sprintf(
+			"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources %s /Contents %d 0 R >>",
+			num(d.largura), num(d.altura), recursos, numero+1)))
+
+		var comprimido bytes.Buffer
+		z := zlib.NewWriter(&comprimido)
+		if _, err := z.Write(conteudo.Bytes()); err != nil {
+			return fmt.Errorf("erro ao comprimir página: %w", err)
+		}
+		if err := z.Close(); err != nil {
+			return fmt.Errorf("erro ao comprimir página: %w", err)
+		}
+		e.fluxo(numero+1, fmt.Sprintf("<< /Filter /FlateDecode /Length %d >>", comprimido.Len()), comprimido.Bytes())
+	}
+
+	inicioXref := e.buf.Len()
+	fmt.Fprintf(&e.buf, "xref\n0 %d\n0000000000 65535 f \n", totalObjetos+1)
+	for i := 1; i <= totalObjetos; i++ {
+		fmt.Fprintf(&e.buf, "%010d 00000 n \n", e.offsets[i])
+	}
+	fmt.Fprintf(&e.buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", totalObjetos+1, inicioXref)
+
+	_, err := w.Write(e.buf.Bytes())
+	return err
+}
+
+// escritor acumula o arquivo registrando a posição de cada objeto para a tabela xref
+type escritor struct {
+	buf     bytes.Buffer
+	offsets []int
+}
+
+func (e *escritor) escrever(b []byte) {
+	e.buf.Write(b)
+}
+
+func (e *escritor) objeto(numero int, conteudo []byte) {
+	e.offsets[numero] = e.buf.Len()
+	fmt.Fprintf(&e.buf, "%d 0 obj\n", numero)
+	e.buf.Write(conteudo)
+	e.buf.WriteString("\nendobj\n")
+}
+
+func (e *escritor) fluxo(numero int, dicionario string, dados []byte) {
+	e.offsets[numero] = e.buf.Len()
+	fmt.Fprintf(&e.buf, "%d 0 obj\n%s\nstream\n", numero, dicionario)
+	e.buf.Write(dados)
+	e.buf.WriteString("\nendstream\nendobj\n")
+}
+
+// escaparTexto escapa os delimitadores de string literal do PDF
+func escaparTexto(b []byte) string {
+	var sb strings.Builder
+	for _, c := range b {
+		switch c {
+		case '\\', '(', ')':
+			sb.WriteByte('\\')
+			sb.WriteByte(c)
+		case '\r', '\n':
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
+
+// corPDF formata a cor como operandos RGB entre 0 e 1
+func corPDF(c Cor) string {
+	return fmt.Sprintf("%s %s %s", num(float64(c.R)/255), num(float64(c.G)/255), num(float64(c.B)/255))
+}
+
+// num formata um número com até duas casas decimais, sem zeros à direita
+func num(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(s, "0")
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || s == "-" || s == "-0" {
+		return "0"
+	}
+	return s
+}
@@ -0,0 +1,294 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// documentoDeTeste monta um documento com duas páginas, texto acentuado e uma imagem
+func documentoDeTeste(t *testing.T) []byte {
+	t.Helper()
+
+	d := NovoDocumento("Ficha (técnica) – Mármore")
+	d.Texto(40, 60, "Mármore (polido) \\ 2cm", Helvetica, 12, Preto)
+	d.Linha(40, 70, 200, 70, 1, Cor{128, 128, 128})
+
+	img := image.NewRGBA(image.Rect(0, 0, 4, 3))
+	for x := 0; x < 4; x++ {
+		for y := 0; y < 3; y++ {
+			img.Set(x, y, color.RGBA{uint8(x * 60), uint8(y * 80), 100, 255})
+		}
+	}
+	if _, _, err := d.Imagem(img, 40, 100, 80, 80); err != nil {
+		t.Fatalf("erro ao desenhar imagem: %v", err)
+	}
+
+	d.NovaPagina()
+	d.Retangulo(40, 40, 100, 20, Cor{200, 0, 0})
+	d.TextoDireita(500, 60, "Página 2", HelveticaNegrito, 10, Branco)
+
+	saida, err := d.Bytes()
+	if err != nil {
+		t.Fatalf("erro ao gerar PDF: %v", err)
+	}
+	return saida
+}
+
+// lerXref interpreta o trailer e a tabela xref, retornando os offsets dos objetos
+func lerXref(t *testing.T, saida []byte) []int {
+	t.Helper()
+
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(saida)
+	if startxref == nil {
+		t.Fatalf("PDF sem startxref/%%%%EOF no final:\n%q", saida[max(0, len(saida)-80):])
+	}
+	inicio, _ := strconv.Atoi(string(startxref[1]))
+	if inicio >= len(saida) || !bytes.HasPrefix(saida[inicio:], []byte("xref\n")) {
+		t.Fatalf("startxref %d não aponta para a tabela xref", inicio)
+	}
+
+	cabecalho := regexp.MustCompile(`^xref\n0 (\d+)\n0000000000 65535 f \n`).FindSubmatch(saida[inicio:])
+	if cabecalho == nil {
+		t.Fatalf("tabela xref malformada: %q", saida[inicio:min(len(saida), inicio+60)])
+	}
+	total, _ := strconv.Atoi(string(cabecalho[1]))
+
+	entradas := saida[inicio+len(cabecalho[0]):]
+	offsets := make([]int, total)
+	for i := 1; i < total; i++ {
+		// Cada entrada tem exatamente 20 bytes, incluindo o espaço e o \n finais
+		entrada := string(entradas[(i-1)*20 : i*20])
+		if !regexp.MustCompile(`^\d{10} 00000 n \n$`).MatchString(entrada) {
+			t.Fatalf("entrada %d da xref malformada: %q", i, entrada)
+		}
+		offsets[i], _ = strconv.Atoi(entrada[:10])
+	}
+
+	trailer := fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>", total)
+	if !bytes.HasPrefix(entradas[(total-1)*20:], []byte(trailer)) {
+		t.Errorf("trailer esperado %q", trailer)
+	}
+	return offsets
+}
+
+func TestEscreverXref(t *testing.T) {
+	saida := documentoDeTeste(t)
+
+	if !bytes.HasPrefix(saida, []byte("%PDF-1.4\n")) {
+		t.Fatalf("cabeçalho inesperado: %q", saida[:12])
+	}
+
+	offsets := lerXref(t, saida)
+	// catálogo, páginas, informações, 2 fontes, 1 imagem e 2 pares página/conteúdo
+	if len(offsets)-1 != 10 {
+		t.Fatalf("objetos = %d, esperado 10", len(offsets)-1)
+	}
+
+	for numero := 1; numero < len(offsets); numero++ {
+		esperado := fmt.Sprintf("%d 0 obj\n", numero)
+		if !bytes.HasPrefix(saida[offsets[numero]:], []byte(esperado)) {
+			t.Errorf("offset %d do objeto %d aponta para %q", offsets[numero], numero,
+				saida[offsets[numero]:min(len(saida), offsets[numero]+len(esperado))])
+		}
+	}
+
+	if !bytes.Contains(saida, []byte("/Type /Pages /Kids [7 0 R 9 0 R] /Count 2")) {
+		t.Error("árvore de páginas não referencia as duas páginas")
+	}
+}
+
+func TestEscreverFluxos(t *testing.T) {
+	saida := documentoDeTeste(t)
+	offsets := lerXref(t, saida)
+
+	comprimento := regexp.MustCompile(`/Length (\d+) >>\nstream\n`)
+	conteudos := []string{}
+	for numero := 1; numero < len(offsets); numero++ {
+		objeto := saida[offsets[numero]:]
+		fim := bytes.Index(objeto, []byte("endobj\n"))
+		if !bytes.Contains(objeto[:fim], []byte("stream\n")) {
+			continue
+		}
+
+		m := comprimento.FindSubmatchIndex(objeto[:fim])
+		if m == nil {
+			t.Fatalf("objeto %d: fluxo sem /Length", numero)
+		}
+		tamanho, _ := strconv.Atoi(string(objeto[m[2]:m[3]]))
+		dados := objeto[m[1] : m[1]+tamanho]
+		if !bytes.HasPrefix(objeto[m[1]+tamanho:], []byte("\nendstream\nendobj\n")) {
+			t.Fatalf("objeto %d: /Length %d não termina no endstream", numero, tamanho)
+		}
+
+		if !bytes.Contains(objeto[:m[0]], []byte("/FlateDecode")) {
+			continue
+		}
+		z, err := zlib.NewReader(bytes.NewReader(dados))
+		if err != nil {
+			t.Fatalf("objeto %d: conteúdo não é zlib: %v", numero, err)
+		}
+		conteudo, err := io.ReadAll(z)
+		if err != nil {
+			t.Fatalf("objeto %d: erro ao descomprimir: %v", numero, err)
+		}
+		conteudos = append(conteudos, string(conteudo))
+	}
+
+	if len(conteudos) != 2 {
+		t.Fatalf("páginas com conteúdo = %d, esperado 2", len(conteudos))
+	}
+
+	// Texto em WinAnsi com os delimitadores escapados; y invertido a partir da base da página
+	texto := "BT 0 0 0 rg /F1 12 Tf 40 781.89 Td (M\xe1rmore \\(polido\\) \\\\ 2cm) Tj ET\n"
+	if !strings.Contains(conteudos[0], texto) {
+		t.Errorf("página 1 sem %q:\n%q", texto, conteudos[0])
+	}
+	if !strings.Contains(conteudos[0], "/Im1 Do") {
+		t.Errorf("página 1 sem a imagem:\n%q", conteudos[0])
+	}
+	if !strings.Contains(conteudos[1], "/F2 10 Tf") || !strings.Contains(conteudos[1], "(P\xe1gina 2) Tj") {
+		t.Errorf("página 2 sem o texto em negrito:\n%q", conteudos[1])
+	}
+
+	titulo := "/Title (Ficha \\(t\xe9cnica\\) \x96 M\xe1rmore)"
+	if !bytes.Contains(saida, []byte(titulo)) {
+		t.Errorf("informações sem %q", titulo)
+	}
+}
+
+func TestCodificarWinAnsi(t *testing.T) {
+	casos := []struct {
+		texto    string
+		esperado string
+	}{
+		{"Granito", "Granito"},
+		{"ação", "a\xe7\xe3o"},
+		{"MÁRMORE ÇÃO", "M\xc1RMORE \xc7\xc3O"},
+		{"2,5 m² × 1,8 m", "2,5 m\xb2 \xd7 1,8 m"},
+		{"R$ 10 – € 2 — ok…", "R$ 10 \x96 \x80 2 \x97 ok\x85"},
+		{"“aspas” ‘simples’", "\x93aspas\x94 \x91simples\x92"},
+		{"中文", "??"},
+		{"pedra 🪨", "pedra ?"},
+	}
+
+	for _, c := range casos {
+		if codificado := string(codificarWinAnsi(c.texto)); codificado != c.esperado {
+			t.Errorf("codificarWinAnsi(%q) = %q, esperado %q", c.texto, codificado, c.esperado)
+		}
+	}
+}
+
+func TestEscaparTexto(t *testing.T) {
+	casos := []struct {
+		texto    string
+		esperado string
+	}{
+		{"simples", "simples"},
+		{"(parênteses)", `\(par` + "\xea" + `nteses\)`},
+		{`barra \ invertida`, `barra \\ invertida`},
+		{`\(`, `\\\(`},
+		{"duas\nlinhas\r\n", "duas linhas  "},
+		{"", ""},
+	}
+
+	for _, c := range casos {
+		if escapado := escaparTexto(codificarWinAnsi(c.texto)); escapado != c.esperado {
+			t.Errorf("escaparTexto(%q) = %q, esperado %q", c.texto, escapado, c.esperado)
+		}
+	}
+}
+
+func TestNum(t *testing.T) {
+	casos := []struct {
+		valor    float64
+		esperado string
+	}{
+		{0, "0"},
+		{10, "10"},
+		{100, "100"},
+		{595.28, "595.28"},
+		{841.89, "841.89"},
+		{1.5, "1.5"},
+		{1.005, "1"},
+		{2.999, "3"},
+		{-3.25, "-3.25"},
+		{-0.001, "0"},
+		{0.001, "0"},
+	}
+
+	for _, c := range casos {
+		if formatado := num(c.valor); formatado != c.esperado {
+			t.Errorf("num(%v) = %q, esperado %q", c.valor, formatado, c.esperado)
+		}
+	}
+}
+
+func TestLarguraTexto(t *testing.T) {
+	// "a" tem 556 milésimos e o espaço 278 na Helvetica
+	casos := []struct {
+		texto    string
+		fonte    Fonte
+		tamanho  float64
+		esperado float64
+	}{
+		{"", Helvetica, 10, 0},
+		{"a", Helvetica, 10, 5.56},
+		{"a a", Helvetica, 10, 13.9},
+		{"ação", Helvetica, 10, LarguraTexto("acao", Helvetica, 10)},
+		{"ÁÉÍ", HelveticaNegrito, 12, LarguraTexto("AEI", HelveticaNegrito, 12)},
+	}
+
+	for _, c := range casos {
+		if largura := LarguraTexto(c.texto, c.fonte, c.tamanho); fmt.Sprintf("%.4f", largura) != fmt.Sprintf("%.4f", c.esperado) {
+			t.Errorf("LarguraTexto(%q) = %v, esperado %v", c.texto, largura, c.esperado)
+		}
+	}
+}
+
+func TestQuebrarTexto(t *testing.T) {
+	// Na Helvetica 10, "a" e "ã" ocupam 5,56 pt e o espaço 2,78 pt
+	casos := []struct {
+		nome     string
+		texto    string
+		largura  float64
+		esperado []string
+	}{
+		{"vazio", "", 100, []string{""}},
+		{"cabe inteiro", "um dois", 100, []string{"um dois"}},
+		{"quebra entre palavras", "aaaa aaaa", 30, []string{"aaaa", "aaaa"}},
+		{"espaços repetidos", "a   b", 100, []string{"a b"}},
+		{"quebras existentes", "linha um\nlinha dois", 100, []string{"linha um", "linha dois"}},
+		{"parágrafo vazio", "a\n\nb", 100, []string{"a", "", "b"}},
+		{"palavra longa cortada", "aaaaaaaaaa", 20, []string{"aaa", "aaa", "aaa", "a"}},
+		{"palavra longa depois de outra", "aa aaaaaaa", 20, []string{"aa", "aaa", "aaa", "a"}},
+		{"palavra longa acentuada", "ããããã", 20, []string{"ããã", "ãã"}},
+		{"parênteses", "(a) (b)", 15, []string{"(a)", "(b)"}},
+		{"largura menor que um caractere", "aa", 1, []string{"a", "a"}},
+	}
+
+	for _, c := range casos {
+		t.Run(c.nome, func(t *testing.T) {
+			linhas := QuebrarTexto(c.texto, Helvetica, 10, c.largura)
+			if strings.Join(linhas, "|") != strings.Join(c.esperado, "|") || len(linhas) != len(c.esperado) {
+				t.Fatalf("QuebrarTexto(%q, %v) = %q, esperado %q", c.texto, c.largura, linhas, c.esperado)
+			}
+			for _, linha := range linhas {
+				if !utf8.ValidString(linha) {
+					t.Errorf("linha %q cortada no meio de um caractere", linha)
+				}
+				if utf8.RuneCountInString(linha) > 1 && LarguraTexto(linha, Helvetica, 10) > c.largura {
+					t.Errorf("linha %q excede a largura %v", linha, c.largura)
+				}
+			}
+		})
+	}
+}
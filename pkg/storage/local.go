@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// RotaLocal é o caminho em que a API serve os arquivos do driver local
+const RotaLocal = "/midia"
+
+// Local armazena os objetos no sistema de arquivos
+type Local struct {
+	diretorio  string
+	urlPublica string
+}
+
+// NovoLocal cria um armazenamento local no diretório informado, criando-o se necessário
+func NovoLocal(diretorio, urlPublica string) (*Local, error) {
+	if diretorio == "" {
+		return nil, fmt.Errorf("diretório do armazenamento local não configurado")
+	}
+
+	if err := os.MkdirAll(diretorio, 0o755); err != nil {
+		return nil, fmt.Errorf("erro ao criar diretório de armazenamento: %w", err)
+	}
+
+	if urlPublica == "" {
+		urlPublica = RotaLocal
+	}
+
+	return &Local{
+		diretorio:  diretorio,
+		urlPublica: strings.TrimRight(urlPublica, "/"),
+	}, nil
+}
+
+// Diretorio retorna o diretório raiz dos arquivos, para ser servido pela API
+func (l *Local) Diretorio() string {
+	return l.diretorio
+}
+
+// Salvar grava o arquivo de forma atômica (arquivo temporário + rename)
+func (l *Local) Salvar(ctx context.Context, chave string, dados []byte, contentType string) error {
+	if err := validarChave(chave); err != nil {
+		return err
+	}
+
+	caminho := filepath.Join(l.diretorio, filepath.FromSlash(chave))
+	if err := os.MkdirAll(filepath.Dir(caminho), 0o755); err != nil {
+		return fmt.Errorf("erro ao criar diretório: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(caminho), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("erro ao criar arquivo temporário: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(dados); err != nil {
+		temp.Close()
+		return fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+
+	if err := os.Chmod(temp.Name(), 0o644); err != nil {
+		return fmt.Errorf("erro ao ajustar permissões do arquivo: %w", err)
+	}
+
+	if err := os.Rename(temp.Name(), caminho); err != nil {
+		return fmt.Errorf("erro ao mover arquivo: %w", err)
+	}
+
+	return nil
+}
+
+// Ler retorna o conteúdo do arquivo
+func (l *Local) Ler(ctx context.Context, chave string) ([]byte, error) {
+	if err := validarChave(chave); err != nil {
+		return nil, err
+	}
+
+	dados, err := os.ReadFile(filepath.Join(l.diretorio, filepath.FromSlash(chave)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNaoEncontrado
+	}
+	return dados, err
+}
+
+// Remover apaga o arquivo
+func (l *Local) Remover(ctx context.Context, chave string) error {
+	if err := validarChave(chave); err != nil {
+		return err
+	}
+
+	err := os.Remove(filepath.Join(l.diretorio, filepath.FromSlash(chave)))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// URL retorna o endereço público do arquivo
+func (l *Local) URL(chave string) string {
+	return l.urlPublica + "/" + chave
+}
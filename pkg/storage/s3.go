@@ -0,0 +1,278 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3 armazena os objetos em um bucket S3 ou compatível, assinando as requisições com
+// AWS Signature Version 4
+type S3 struct {
+	endpoint        *url.URL
+	regiao          string
+	bucket          string
+	accessKeyID     string
+	secretAccessKey string
+	pathStyle       bool
+	urlPublica      string
+	httpClient      *http.Client
+}
+
+// NovoS3 cria um armazenamento S3 a partir da configuração
+func NovoS3(cfg Config) (*S3, error) {
+	if cfg.S3Bucket == "" || cfg.S3AccessKeyID == "" || cfg.S3SecretAccessKey == "" {
+		return nil, fmt.Errorf("S3_BUCKET, S3_ACCESS_KEY_ID e S3_SECRET_ACCESS_KEY são obrigatórios para o driver s3")
+	}
+
+	regiao := cfg.S3Regiao
+	if regiao == "" {
+		regiao = "us-east-1"
+	}
+
+	endpointStr := cfg.S3Endpoint
+	if endpointStr == "" {
+		endpointStr = fmt.Sprintf("https://s3.%s.amazonaws.com", regiao)
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(endpointStr, "/"))
+	if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("S3_ENDPOINT inválido: %s", endpointStr)
+	}
+
+	s := &S3{
+		endpoint:        endpoint,
+		regiao:          regiao,
+		bucket:          cfg.S3Bucket,
+		accessKeyID:     cfg.S3AccessKeyID,
+		secretAccessKey: cfg.S3SecretAccessKey,
+		pathStyle:       cfg.S3PathStyle,
+		httpClient:      &http.Client{Timeout: 60 * time.Second},
+	}
+
+	s.urlPublica = strings.TrimRight(cfg.URLPublica, "/")
+	if s.urlPublica == "" {
+		s.urlPublica = strings.TrimSuffix(s.urlObjeto("x").String(), "/x")
+	}
+
+	return s, nil
+}
+
+// Salvar envia o objeto com PUT
+func (s *S3) Salvar(ctx context.Context, chave string, dados []byte, contentType string) error {
+	if err := validarChave(chave); err != nil {
+		return err
+	}
+
+	resp, err := s.requisitar(ctx, http.MethodPut, chave, dados, contentType)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		corpo, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("S3 retornou status %d ao salvar %s: %s", resp.StatusCode, chave, string(corpo))
+	}
+
+	return nil
+}
+
+// Ler baixa o objeto com GET
+func (s *S3) Ler(ctx context.Context, chave string) ([]byte, error) {
+	if err := validarChave(chave); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.requisitar(ctx, http.MethodGet, chave, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNaoEncontrado
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("S3 retornou status %d ao ler %s", resp.StatusCode, chave)
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// Remover apaga o objeto com DELETE
+func (s *S3) Remover(ctx context.Context, chave string) error {
+	if err := validarChave(chave); err != nil {
+		return err
+	}
+
+	resp, err := s.requisitar(ctx, http.MethodDelete, chave, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("S3 retornou status %d ao remover %s", resp.StatusCode, chave)
+	}
+
+	return nil
+}
+
+// URL retorna o endereço público do objeto (bucket público ou CDN configurada)
+func (s *S3) URL(chave string) string {
+	return s.urlPublica + "/" + chave
+}
+
+// urlObjeto monta a URL do objeto no estilo de caminho ou de host virtual
+func (s *S3) urlObjeto(chave string) *url.URL {
+	u := *s.endpoint
+	if s.pathStyle {
+		u.Path = u.Path + "/" + s.bucket + "/" + chave
+	} else {
+		u.Host = s.bucket + "." + u.Host
+		u.Path = u.Path + "/" + chave
+	}
+	return &u
+}
+
+// requisitar executa uma requisição assinada para o objeto
+func (s *S3) requisitar(ctx context.Context, metodo, chave string, corpo []byte, contentType string) (*http.Response, error) {
+	u := s.urlObjeto(chave)
+
+	req, err := http.NewRequestWithContext(ctx, metodo, u.String(), bytes.NewReader(corpo))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao criar requisição S3: %w", err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	s.assinar(req, u, corpo, time.Now().UTC())
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("erro na requisição S3: %w", err)
+	}
+	return resp, nil
+}
+
+// assinar adiciona os cabeçalhos da AWS Signature Version 4. São assinados o host e
+// todos os cabeçalhos já definidos na requisição (Content-Type, X-Amz-*), e a query
+// string entra na requisição canônica.
+func (s *S3) assinar(req *http.Request, u *url.URL, corpo []byte, agora time.Time) {
+	amzDate := agora.Format("20060102T150405Z")
+	data := agora.Format("20060102")
+	hashCorpo := sha256Hex(corpo)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hashCorpo)
+
+	cabecalhos := map[string]string{"host": u.Host}
+	for nome, valores := range req.Header {
+		nome = strings.ToLower(nome)
+		if nome == "authorization" {
+			continue
+		}
+		normalizados := make([]string, len(valores))
+		for i, v := range valores {
+			normalizados[i] = strings.Join(strings.Fields(v), " ")
+		}
+		cabecalhos[nome] = strings.Join(normalizados, ",")
+	}
+
+	nomes := make([]string, 0, len(cabecalhos))
+	for nome := range cabecalhos {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+
+	var canonicos strings.Builder
+	for _, nome := range nomes {
+		canonicos.WriteString(nome + ":" + cabecalhos[nome] + "\n")
+	}
+	assinados := strings.Join(nomes, ";")
+
+	requisicaoCanonica := strings.Join([]string{
+		req.Method,
+		caminhoCanonico(u.Path),
+		queryCanonica(u.Query()),
+		canonicos.String(),
+		assinados,
+		hashCorpo,
+	}, "\n")
+
+	escopo := data + "/" + s.regiao + "/s3/aws4_request"
+	textoAssinar := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		escopo,
+		sha256Hex([]byte(requisicaoCanonica)),
+	}, "\n")
+
+	chave := hmacSHA256([]byte("AWS4"+s.secretAccessKey), data)
+	chave = hmacSHA256(chave, s.regiao)
+	chave = hmacSHA256(chave, "s3")
+	chave = hmacSHA256(chave, "aws4_request")
+	assinatura := hex.EncodeToString(hmacSHA256(chave, textoAssinar))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKeyID, escopo, assinados, assinatura,
+	))
+}
+
+// caminhoCanonico codifica o caminho conforme a SigV4: apenas caracteres não reservados
+// da RFC 3986 e a barra ficam sem codificação. No S3 o caminho é codificado uma única vez.
+func caminhoCanonico(caminho string) string {
+	return codificarURI(caminho, false)
+}
+
+// queryCanonica ordena os parâmetros por nome e valor e os codifica conforme a SigV4;
+// parâmetros sem valor ("?lifecycle") viram "lifecycle="
+func queryCanonica(query url.Values) string {
+	pares := make([]string, 0, len(query))
+	for nome, valores := range query {
+		for _, valor := range valores {
+			pares = append(pares, codificarURI(nome, true)+"="+codificarURI(valor, true))
+		}
+	}
+	sort.Strings(pares)
+	return strings.Join(pares, "&")
+}
+
+// codificarURI aplica a codificação de URI da SigV4 byte a byte (UTF-8), mantendo os
+// caracteres não reservados e, fora da query string, a barra
+func codificarURI(texto string, codificarBarra bool) string {
+	var b strings.Builder
+	for i := 0; i < len(texto); i++ {
+		c := texto[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' || (c == '/' && !codificarBarra) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(dados []byte) string {
+	soma := sha256.Sum256(dados)
+	return hex.EncodeToString(soma[:])
+}
+
+func hmacSHA256(chave []byte, dados string) []byte {
+	mac := hmac.New(sha256.New, chave)
+	mac.Write([]byte(dados))
+	return mac.Sum(nil)
+}
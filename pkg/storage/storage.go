@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Drivers de armazenamento suportados
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+// ErrNaoEncontrado indica que o objeto não existe no armazenamento
+var ErrNaoEncontrado = errors.New("objeto não encontrado")
+
+// BlobStore armazena arquivos binários (imagens, miniaturas) identificados por uma chave
+// no formato de caminho relativo (ex.: imagens/ab/abcd.../original.jpg)
+type BlobStore interface {
+	// Salvar grava o conteúdo na chave informada, substituindo o existente
+	Salvar(ctx context.Context, chave string, dados []byte, contentType string) error
+	// Ler retorna o conteúdo armazenado na chave, ou ErrNaoEncontrado
+	Ler(ctx context.Context, chave string) ([]byte, error)
+	// Remover apaga o objeto; remover um objeto inexistente não é erro
+	Remover(ctx context.Context, chave string) error
+	// URL retorna o endereço público do objeto
+	URL(chave string) string
+}
+
+// Config reúne as opções dos drivers de armazenamento
+type Config struct {
+	Driver string
+
+	// Local
+	DiretorioLocal string
+
+	// URL pública base dos objetos (ex.: https://cdn.exemplo.com); no driver local,
+	// o padrão é o caminho servido pela própria API
+	URLPublica string
+
+	// S3 (ou compatível: MinIO, R2, Spaces)
+	S3Endpoint        string
+	S3Regiao          string
+	S3Bucket          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+	S3PathStyle       bool
+}
+
+// Novo cria o BlobStore do driver configurado
+func Novo(cfg Config) (BlobStore, error) {
+	switch cfg.Driver {
+	case "", DriverLocal:
+		return NovoLocal(cfg.DiretorioLocal, cfg.URLPublica)
+	case DriverS3:
+		return NovoS3(cfg)
+	default:
+		return nil, fmt.Errorf("driver de armazenamento desconhecido: %s", cfg.Driver)
+	}
+}
+
+// validarChave impede chaves vazias, absolutas ou que escapem do diretório base
+func validarChave(chave string) error {
+	if chave == "" || strings.HasPrefix(chave, "/") || strings.Contains(chave, "..") || strings.Contains(chave, "\\") {
+		return fmt.Errorf("chave de armazenamento inválida: %q", chave)
+	}
+	return nil
+}
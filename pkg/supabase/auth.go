@@ -2,6 +2,7 @@ package supabase
 
 import (
 	"fmt"
+	"net/http"
 	"strings"
 	"time"
 
@@ -213,6 +214,12 @@ func (a *AuthClient) SignOut(token string) error {
 }
 
 // Função auxiliar para converter int64 para time.Time
+// Disponivel verifica se o Supabase Auth está respondendo, com o tempo limite informado
+func (a *AuthClient) Disponivel(timeout time.Duration) error {
+	_, err := a.client.WithClient(http.Client{Timeout: timeout}).HealthCheck()
+	return err
+}
+
 func int64ToTime(timestamp int64) time.Time {
 	return time.Unix(timestamp, 0)
 }
\ No newline at end of file